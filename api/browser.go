@@ -20,21 +20,29 @@
 // Package api provides a client for the RadioBrowser API (https://api.radio-browser.info/).
 // It allows searching for radio stations by various criteria, fetching station details,
 // and reporting station clicks. The implementation uses DNS-based server discovery
-// for load balancing across RadioBrowser API servers.
+// for load balancing across RadioBrowser API servers, failing over to the next
+// mirror when one is unreachable or returns a server error.
 package api
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/data"
 )
 
+// DefaultRequestTimeout is the per-mirror request timeout used when none is configured.
+const DefaultRequestTimeout = 15 * time.Second
+
+// RadioBrowserService is the RadioBrowser API client.
+// Every method takes a context; cancelling it aborts the in-flight request.
 type RadioBrowserService interface {
 	// GetStations retrieves a list of radio stations from the RadioBrowser API based on the provided StationQuery, searchTerm, order, reverse, offset, limit and hideBroken parameters.
 	// If stationQuery is not StationQueryAll, the searchTerm is used to filter the results.
@@ -45,6 +53,7 @@ type RadioBrowserService interface {
 	// The hideBroken parameter specifies whether to exclude broken stations from the results.
 	// Returns a slice of Station structs and an error if any occurred.
 	GetStations(
+		ctx context.Context,
 		stationQuery common.StationQuery,
 		searchTerm string,
 		order string,
@@ -53,188 +62,413 @@ type RadioBrowserService interface {
 		limit uint64,
 		hideBroken bool,
 	) ([]common.Station, error)
+	// SearchStations retrieves radio stations matching all of the criteria in params
+	// using the /json/stations/search endpoint.
+	// Unlike GetStations, several filters (tags, country, codec, bitrate...) can be combined.
+	// Returns a slice of Station structs and an error if any occurred.
+	SearchStations(ctx context.Context, params common.StationSearchParams) ([]common.Station, error)
+	// GetFacets retrieves a facet listing (/json/countries, /json/tags...) with the number of
+	// stations for each value, most used values first.
+	// If filter is not empty, only values containing it are returned.
+	// The limit parameter specifies the maximum number of values to return (0 means no limit).
+	// Values used only by broken stations are excluded.
+	GetFacets(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error)
+	// GetStationList retrieves one of RadioBrowser's ready-made lists (/json/stations/topclick,
+	// /json/stations/lastchange...), which come in their own fixed order.
+	// The offset and limit parameters select the range of results to return.
+	// Broken stations are excluded.
+	GetStationList(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error)
 	// ClickStation sends a POST request to the RadioBrowser API to increment the click count of a given station.
 	// It takes a Station struct as input and returns a ClickStationResponse struct and an error.
-	ClickStation(station common.Station) (common.ClickStationResponse, error)
+	ClickStation(ctx context.Context, station common.Station) (common.ClickStationResponse, error)
 	// GetStationsByUUIDs fetches multiple stations by their UUIDs in a single API request.
 	// Uses the query parameter format: GET /json/stations/byuuid?uuids=UUID1,UUID2,UUID3
 	// Returns an empty slice if no UUIDs are provided.
-	GetStationsByUUIDs(uuids []uuid.UUID) ([]common.Station, error)
+	GetStationsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error)
 	// VoteStation sends a POST request to the RadioBrowser API to vote for a given station.
 	// Note: The same IP can only vote for a station once every 10 minutes.
 	// It takes a Station struct as input and returns a VoteStationResponse struct and an error.
-	VoteStation(station common.Station) (common.VoteStationResponse, error)
+	VoteStation(ctx context.Context, station common.Station) (common.VoteStationResponse, error)
+	// CurrentMirror returns the host of the RadioBrowser mirror used for this session.
+	// The mirror list is discovered via DNS on first use, so this call may block.
+	CurrentMirror(ctx context.Context) string
 }
 
 type RadioBrowserImpl struct {
 	// The HTTP client used to make requests to the Radio Browser API.
 	httpClient HTTPClientService
-	// The base URL for the Radio Browser API.)
-	baseUrl url.URL
+	// The DNS resolver used to discover Radio Browser API mirrors.
+	resolver DNSResolverService
+	// Randomizes the discovered mirror list (replaceable in tests).
+	shuffle func(hosts []string)
+	// The maximum time a single mirror may take to answer before the next one is tried.
+	requestTimeout time.Duration
+	// The backoff applied to GET requests when every mirror failed.
+	retry retryPolicy
+	// Waits between retries (replaceable in tests).
+	sleep func(ctx context.Context, d time.Duration) error
+
+	// Guards the mirror list and the active mirror index.
+	mu sync.Mutex
+	// The base URLs of the known mirrors, nil until discovery has run.
+	mirrors []url.URL
+	// The index of the mirror currently in use (cached for the session).
+	activeMirror int
 }
 
-// NewRadioBrowser returns a new instance of RadioBrowserService with the default HTTP client.
-func NewRadioBrowser() (RadioBrowserService, error) {
-	return NewRadioBrowserWithDependencies(http.DefaultClient)
+// NewRadioBrowser returns a new instance of RadioBrowserService with the default HTTP client,
+// the system DNS resolver and the given per-mirror request timeout.
+func NewRadioBrowser(requestTimeout time.Duration) (RadioBrowserService, error) {
+	return NewRadioBrowserWithDependencies(http.DefaultClient, netResolver{}, requestTimeout)
 }
 
-// NewRadioBrowserWithDependencies creates a new instance of RadioBrowserService with the provided HTTP client.
+// NewRadioBrowserWithDependencies creates a new instance of RadioBrowserService with the provided
+// HTTP client, DNS resolver and per-mirror request timeout (DefaultRequestTimeout if not positive).
+// Mirrors are discovered lazily on the first request.
 // Returns an error if URL parsing fails.
 func NewRadioBrowserWithDependencies(
 	httpClient HTTPClientService,
+	resolver DNSResolverService,
+	requestTimeout time.Duration,
 ) (RadioBrowserService, error) {
-	browser := &RadioBrowserImpl{
-		httpClient: httpClient,
-	}
-
-	url, err := url.Parse("https://all.api.radio-browser.info/json")
-	if err != nil {
+	if _, err := mirrorBaseURL(radioBrowserFallbackHost); err != nil {
 		return nil, err
 	}
-	browser.baseUrl = *url
-	return browser, nil
+	if requestTimeout <= 0 {
+		requestTimeout = DefaultRequestTimeout
+	}
+	return &RadioBrowserImpl{
+		httpClient:     httpClient,
+		resolver:       resolver,
+		shuffle:        shuffleHosts,
+		requestTimeout: requestTimeout,
+		retry:          defaultRetryPolicy,
+		sleep:          sleepContext,
+	}, nil
 }
 
-func (radioBrowser *RadioBrowserImpl) GetStations(
-	stationQuery common.StationQuery,
-	searchTerm string,
-	order string,
-	reverse bool,
-	offset uint64,
-	limit uint64,
-	hideBroken bool,
-) ([]common.Station, error) {
+// mirrorBaseURL returns the JSON API base URL for the given mirror host.
+func mirrorBaseURL(host string) (*url.URL, error) {
+	return url.Parse("https://" + host + "/json")
+}
 
-	url := radioBrowser.baseUrl.JoinPath("/stations")
-	if stationQuery != common.StationQueryAll {
-		url = url.JoinPath("/" + string(stationQuery) + "/" + searchTerm)
+// loadMirrors discovers and randomizes the mirror list on first use.
+// The round-robin fallback host is always appended as the last resort.
+// If ctx is cancelled during discovery nothing is cached, so discovery runs again next time.
+// Must be called with mu held.
+func (radioBrowser *RadioBrowserImpl) loadMirrors(ctx context.Context) {
+	if radioBrowser.mirrors != nil {
+		return
 	}
 
-	query := url.Query()
-	query.Set("order", order)
-	query.Set("reverse", boolToString(reverse))
-	query.Set("offset", uint64ToString(offset))
-	query.Set("limit", uint64ToString(limit))
-	query.Set("hidebroken", boolToString(hideBroken))
-	url.RawQuery = query.Encode()
+	hosts := discoverMirrors(ctx, radioBrowser.resolver)
+	if ctx.Err() != nil {
+		return
+	}
+	radioBrowser.shuffle(hosts)
+	hosts = append(hosts, radioBrowserFallbackHost)
+
+	mirrors := make([]url.URL, 0, len(hosts))
+	for _, host := range hosts {
+		u, err := mirrorBaseURL(host)
+		if err != nil {
+			continue
+		}
+		mirrors = append(mirrors, *u)
+	}
+	radioBrowser.mirrors = mirrors
+	radioBrowser.activeMirror = 0
+}
 
-	headers := make(map[string]string)
-	headers["User-Agent"] = data.UserAgent
-	headers["Accept"] = "application/json"
+// mirrorsFromActive returns the mirror list rotated so that the active mirror comes first,
+// along with the index of the active mirror in the unrotated list.
+func (radioBrowser *RadioBrowserImpl) mirrorsFromActive(ctx context.Context) ([]url.URL, int) {
+	radioBrowser.mu.Lock()
+	defer radioBrowser.mu.Unlock()
 
-	var stations []common.Station
+	radioBrowser.loadMirrors(ctx)
 
-	req, err := http.NewRequest("GET", url.String(), nil)
-	if err != nil {
-		return nil, err
+	n := len(radioBrowser.mirrors)
+	rotated := make([]url.URL, 0, n)
+	for i := 0; i < n; i++ {
+		rotated = append(rotated, radioBrowser.mirrors[(radioBrowser.activeMirror+i)%n])
 	}
+	return rotated, radioBrowser.activeMirror
+}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
+// setActiveMirror makes the mirror at the given index the one used for subsequent requests.
+func (radioBrowser *RadioBrowserImpl) setActiveMirror(index int) {
+	radioBrowser.mu.Lock()
+	defer radioBrowser.mu.Unlock()
+	if len(radioBrowser.mirrors) > 0 {
+		radioBrowser.activeMirror = index % len(radioBrowser.mirrors)
 	}
+}
 
-	result, err := radioBrowser.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
+func (radioBrowser *RadioBrowserImpl) CurrentMirror(ctx context.Context) string {
+	radioBrowser.mu.Lock()
+	defer radioBrowser.mu.Unlock()
 
-	defer result.Body.Close()
+	radioBrowser.loadMirrors(ctx)
+	if len(radioBrowser.mirrors) == 0 {
+		return ""
+	}
+	return radioBrowser.mirrors[radioBrowser.activeMirror].Host
+}
 
-	if result.StatusCode != 200 {
-		return nil, fmt.Errorf("API request failed with status %d", result.StatusCode)
+// doRequest sends a request to the active mirror and decodes the JSON response into out.
+// The request URL is produced by buildURL from the mirror's base URL.
+// Connection errors, timeouts, 429 and 5xx responses cause the next mirror to be tried; the
+// first mirror that answers becomes the active one for the rest of the session.
+// GET requests are idempotent, so if every mirror fails they are retried with exponential
+// backoff according to the retry policy.
+// Failures are returned as *APIError. If ctx is cancelled, the in-flight request is
+// aborted and ctx.Err() is returned as is.
+func (radioBrowser *RadioBrowserImpl) doRequest(
+	ctx context.Context,
+	method string,
+	buildURL func(baseUrl url.URL) *url.URL,
+	out interface{},
+) error {
+
+	for retry := 0; ; retry++ {
+
+		exhausted, err := radioBrowser.tryMirrors(ctx, method, buildURL, out)
+		if !exhausted || method != http.MethodGet {
+			return err
+		}
+
+		delay, ok := radioBrowser.retry.delay(retry, err)
+		if !ok {
+			return err
+		}
+
+		if sleepErr := radioBrowser.sleep(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
 	}
+}
 
-	err = json.NewDecoder(result.Body).Decode(&stations)
+// tryMirrors sends the request to each mirror in turn, starting from the active one.
+// Returns exhausted=true if every mirror failed with a retryable error.
+func (radioBrowser *RadioBrowserImpl) tryMirrors(
+	ctx context.Context,
+	method string,
+	buildURL func(baseUrl url.URL) *url.URL,
+	out interface{},
+) (bool, error) {
 
-	if err != nil {
-		return nil, err
+	mirrors, first := radioBrowser.mirrorsFromActive(ctx)
+
+	var lastErr error
+
+	for i, baseUrl := range mirrors {
+
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		retry, err := radioBrowser.doMirrorRequest(ctx, method, buildURL(baseUrl).String(), out)
+		if retry {
+			lastErr = err
+			continue
+		}
+
+		radioBrowser.setActiveMirror(first + i)
+		return false, err
 	}
 
-	return stations, nil
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
 
+	return true, lastErr
 }
 
-func (radioBrowser *RadioBrowserImpl) ClickStation(station common.Station) (common.ClickStationResponse, error) {
-
-	url := radioBrowser.baseUrl.JoinPath("/url/" + station.StationUuid.String())
+// doMirrorRequest sends a single request bounded by the request timeout and decodes the response.
+// Returns retry=true if the mirror failed in a way that another mirror might not
+// (connection error, timeout, 429, 5xx), unless ctx itself was cancelled.
+func (radioBrowser *RadioBrowserImpl) doMirrorRequest(
+	ctx context.Context,
+	method string,
+	requestUrl string,
+	out interface{},
+) (bool, error) {
 
-	headers := make(map[string]string)
-	headers["User-Agent"] = data.UserAgent
-	headers["Accept"] = "application/json"
+	attemptCtx, cancel := context.WithTimeout(ctx, radioBrowser.requestTimeout)
+	defer cancel()
 
-	req, err := http.NewRequest("POST", url.String(), nil)
+	req, err := http.NewRequestWithContext(attemptCtx, method, requestUrl, nil)
 	if err != nil {
-		return common.ClickStationResponse{}, err
+		return false, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	req.Header.Set("User-Agent", data.UserAgent)
+	req.Header.Set("Accept", "application/json")
 
 	result, err := radioBrowser.httpClient.Do(req)
 	if err != nil {
-		return common.ClickStationResponse{}, err
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, classifyTransportError(err)
 	}
-
 	defer result.Body.Close()
 
-	if result.StatusCode != 200 {
-		return common.ClickStationResponse{}, fmt.Errorf("API request failed with status %d", result.StatusCode)
+	if result.StatusCode != http.StatusOK {
+		apiErr := statusError(result)
+		return apiErr.Retryable(), apiErr
 	}
 
-	var response common.ClickStationResponse
-	err = json.NewDecoder(result.Body).Decode(&response)
-
-	if err != nil {
-		return common.ClickStationResponse{}, err
+	if err := json.NewDecoder(result.Body).Decode(out); err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		if attemptCtx.Err() != nil {
+			// The mirror stalled while sending the body
+			return true, &APIError{Kind: ErrorKindTimeout, Err: err}
+		}
+		return false, &APIError{Kind: ErrorKindMalformedResponse, Err: err}
 	}
 
-	return response, nil
+	return false, nil
 }
 
-func (radioBrowser *RadioBrowserImpl) GetStationsByUUIDs(uuids []uuid.UUID) ([]common.Station, error) {
-	if len(uuids) == 0 {
-		return []common.Station{}, nil
-	}
+func (radioBrowser *RadioBrowserImpl) GetStations(
+	ctx context.Context,
+	stationQuery common.StationQuery,
+	searchTerm string,
+	order string,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+	hideBroken bool,
+) ([]common.Station, error) {
 
-	url := radioBrowser.baseUrl.JoinPath("/stations/byuuid")
+	var stations []common.Station
 
-	// Build comma-separated UUID list
-	uuidStrings := make([]string, len(uuids))
-	for i, u := range uuids {
-		uuidStrings[i] = u.String()
+	err := radioBrowser.doRequest(ctx, "GET", func(baseUrl url.URL) *url.URL {
+		url := baseUrl.JoinPath("/stations")
+		if stationQuery != common.StationQueryAll {
+			url = url.JoinPath("/" + string(stationQuery) + "/" + searchTerm)
+		}
+
+		query := url.Query()
+		query.Set("order", order)
+		query.Set("reverse", boolToString(reverse))
+		query.Set("offset", uint64ToString(offset))
+		query.Set("limit", uint64ToString(limit))
+		query.Set("hidebroken", boolToString(hideBroken))
+		url.RawQuery = query.Encode()
+		return url
+	}, &stations)
+
+	if err != nil {
+		return nil, err
 	}
 
-	query := url.Query()
-	query.Set("uuids", strings.Join(uuidStrings, ","))
-	url.RawQuery = query.Encode()
+	return stations, nil
+
+}
+
+func (radioBrowser *RadioBrowserImpl) SearchStations(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+
+	var stations []common.Station
 
-	headers := make(map[string]string)
-	headers["User-Agent"] = data.UserAgent
-	headers["Accept"] = "application/json"
+	err := radioBrowser.doRequest(ctx, "GET", func(baseUrl url.URL) *url.URL {
+		url := baseUrl.JoinPath("/stations/search")
+
+		query := url.Query()
+		setIfNotEmpty := func(key string, value string) {
+			if value != "" {
+				query.Set(key, value)
+			}
+		}
+		setIfNotEmpty("name", params.Name)
+		setIfNotEmpty("tagList", strings.Join(params.TagList, ","))
+		setIfNotEmpty("countrycode", params.CountryCode)
+		setIfNotEmpty("state", params.State)
+		setIfNotEmpty("language", params.Language)
+		setIfNotEmpty("codec", params.Codec)
+		if params.BitrateMin > 0 {
+			query.Set("bitrateMin", uint64ToString(params.BitrateMin))
+		}
+		if params.BitrateMax > 0 {
+			query.Set("bitrateMax", uint64ToString(params.BitrateMax))
+		}
+		if params.HasGeoInfo != nil {
+			query.Set("has_geo_info", boolToString(*params.HasGeoInfo))
+		}
+		if params.IsHttps != nil {
+			query.Set("is_https", boolToString(*params.IsHttps))
+		}
+		if params.Near != nil {
+			query.Set("geo_lat", float64ToString(params.Near.Lat))
+			query.Set("geo_long", float64ToString(params.Near.Long))
+			if params.GeoDistance > 0 {
+				query.Set("geo_distance", uint64ToString(params.GeoDistance))
+			}
+		}
+		setIfNotEmpty("order", string(params.Order))
+		query.Set("reverse", boolToString(params.Reverse))
+		query.Set("offset", uint64ToString(params.Offset))
+		query.Set("limit", uint64ToString(params.Limit))
+		query.Set("hidebroken", boolToString(params.HideBroken))
+		url.RawQuery = query.Encode()
+		return url
+	}, &stations)
 
-	req, err := http.NewRequest("GET", url.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	return stations, nil
+}
+
+func (radioBrowser *RadioBrowserImpl) GetFacets(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+
+	var facets []common.Facet
+
+	err := radioBrowser.doRequest(ctx, "GET", func(baseUrl url.URL) *url.URL {
+		// The filter is a single path segment, even if it contains a slash (e.g. "drum/bass")
+		segment := url.PathEscape(filter)
+		url := baseUrl.JoinPath("/" + string(kind))
+		if filter != "" {
+			url = url.JoinPath(segment)
+		}
+
+		query := url.Query()
+		query.Set("order", "stationcount")
+		query.Set("reverse", "true")
+		query.Set("hidebroken", "true")
+		if limit > 0 {
+			query.Set("limit", uint64ToString(limit))
+		}
+		url.RawQuery = query.Encode()
+		return url
+	}, &facets)
 
-	result, err := radioBrowser.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 
-	defer result.Body.Close()
+	return facets, nil
+}
 
-	if result.StatusCode != 200 {
-		return nil, fmt.Errorf("API request failed with status %d", result.StatusCode)
-	}
+func (radioBrowser *RadioBrowserImpl) GetStationList(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
 
 	var stations []common.Station
-	err = json.NewDecoder(result.Body).Decode(&stations)
+
+	err := radioBrowser.doRequest(ctx, "GET", func(baseUrl url.URL) *url.URL {
+		url := baseUrl.JoinPath("/stations/" + string(list))
+		query := url.Query()
+		query.Set("offset", uint64ToString(offset))
+		query.Set("limit", uint64ToString(limit))
+		query.Set("hidebroken", "true")
+		url.RawQuery = query.Encode()
+		return url
+	}, &stations)
+
 	if err != nil {
 		return nil, err
 	}
@@ -242,36 +476,56 @@ func (radioBrowser *RadioBrowserImpl) GetStationsByUUIDs(uuids []uuid.UUID) ([]c
 	return stations, nil
 }
 
-func (radioBrowser *RadioBrowserImpl) VoteStation(station common.Station) (common.VoteStationResponse, error) {
+func (radioBrowser *RadioBrowserImpl) ClickStation(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
 
-	url := radioBrowser.baseUrl.JoinPath("/vote/" + station.StationUuid.String())
+	var response common.ClickStationResponse
 
-	headers := make(map[string]string)
-	headers["User-Agent"] = data.UserAgent
-	headers["Accept"] = "application/json"
+	err := radioBrowser.doRequest(ctx, "POST", func(baseUrl url.URL) *url.URL {
+		return baseUrl.JoinPath("/url/" + station.StationUuid.String())
+	}, &response)
 
-	req, err := http.NewRequest("POST", url.String(), nil)
 	if err != nil {
-		return common.VoteStationResponse{}, err
+		return common.ClickStationResponse{}, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	return response, nil
+}
+
+func (radioBrowser *RadioBrowserImpl) GetStationsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+	if len(uuids) == 0 {
+		return []common.Station{}, nil
 	}
 
-	result, err := radioBrowser.httpClient.Do(req)
-	if err != nil {
-		return common.VoteStationResponse{}, err
+	// Build comma-separated UUID list
+	uuidStrings := make([]string, len(uuids))
+	for i, u := range uuids {
+		uuidStrings[i] = u.String()
 	}
 
-	defer result.Body.Close()
+	var stations []common.Station
+
+	err := radioBrowser.doRequest(ctx, "GET", func(baseUrl url.URL) *url.URL {
+		url := baseUrl.JoinPath("/stations/byuuid")
+		query := url.Query()
+		query.Set("uuids", strings.Join(uuidStrings, ","))
+		url.RawQuery = query.Encode()
+		return url
+	}, &stations)
 
-	if result.StatusCode != 200 {
-		return common.VoteStationResponse{}, fmt.Errorf("API request failed with status %d", result.StatusCode)
+	if err != nil {
+		return nil, err
 	}
 
+	return stations, nil
+}
+
+func (radioBrowser *RadioBrowserImpl) VoteStation(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
+
 	var response common.VoteStationResponse
-	err = json.NewDecoder(result.Body).Decode(&response)
+
+	err := radioBrowser.doRequest(ctx, "POST", func(baseUrl url.URL) *url.URL {
+		return baseUrl.JoinPath("/vote/" + station.StationUuid.String())
+	}, &response)
 
 	if err != nil {
 		return common.VoteStationResponse{}, err
@@ -21,9 +21,14 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/data"
@@ -33,6 +38,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMain(m *testing.M) {
+	// Keep the retry backoff short so that error-path tests stay fast
+	defaultRetryPolicy.baseDelay = time.Millisecond
+	os.Exit(m.Run())
+}
+
 func TestBrowserImplGetStations(t *testing.T) {
 
 	// Note: Search term set to "searchTerm" in all test cases
@@ -136,11 +147,11 @@ func TestBrowserImplGetStations(t *testing.T) {
 				},
 			}
 
-			browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+			browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 
 			assert.NoError(t, err)
 
-			_, err = browser.GetStations(tc.queryType, "searchTerm", "name", false, 0, 10, true)
+			_, err = browser.GetStations(context.Background(), tc.queryType, "searchTerm", "name", false, 0, 10, true)
 
 			assert.NoError(t, err)
 
@@ -177,10 +188,10 @@ func TestBrowserImplClickStation(t *testing.T) {
 		},
 	}
 
-	radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+	radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 	assert.NoError(t, err)
 
-	response, err := radioBrowser.ClickStation(station)
+	response, err := radioBrowser.ClickStation(context.Background(), station)
 	assert.NoError(t, err)
 
 	assert.Equal(t, true, response.Ok)
@@ -195,10 +206,10 @@ func TestBrowserImplGetStationsByUUIDs(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		stations, err := browser.GetStationsByUUIDs([]uuid.UUID{})
+		stations, err := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{})
 		assert.NoError(t, err)
 		assert.Empty(t, stations)
 	})
@@ -228,10 +239,10 @@ func TestBrowserImplGetStationsByUUIDs(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		stations, err := browser.GetStationsByUUIDs([]uuid.UUID{uuid1, uuid2})
+		stations, err := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid1, uuid2})
 		assert.NoError(t, err)
 		assert.Len(t, stations, 2)
 		assert.Equal(t, "Station 1", stations[0].Name)
@@ -249,10 +260,10 @@ func TestBrowserImplGetStationsByUUIDs(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStationsByUUIDs([]uuid.UUID{uuid.New()})
+		_, err = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "500")
 	})
@@ -266,10 +277,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "connection refused")
 	})
@@ -285,10 +296,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "400")
 	})
@@ -304,10 +315,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "500")
 	})
@@ -323,10 +334,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "503")
 	})
@@ -342,10 +353,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.Error(t, err)
 	})
 
@@ -360,10 +371,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		stations, err := browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, true)
+		stations, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, true)
 		assert.NoError(t, err)
 		assert.Empty(t, stations)
 	})
@@ -375,13 +386,13 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
 		station := common.Station{
 			StationUuid: uuid.MustParse("941ef6f1-0699-4821-95b1-2b678e3ff62e"),
 		}
-		_, err = browser.ClickStation(station)
+		_, err = browser.ClickStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "timeout")
 	})
@@ -397,13 +408,13 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
 		station := common.Station{
 			StationUuid: uuid.MustParse("941ef6f1-0699-4821-95b1-2b678e3ff62e"),
 		}
-		_, err = browser.ClickStation(station)
+		_, err = browser.ClickStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "404")
 	})
@@ -419,13 +430,13 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
 		station := common.Station{
 			StationUuid: uuid.MustParse("941ef6f1-0699-4821-95b1-2b678e3ff62e"),
 		}
-		_, err = browser.ClickStation(station)
+		_, err = browser.ClickStation(context.Background(), station)
 		assert.Error(t, err)
 	})
 
@@ -436,10 +447,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStationsByUUIDs([]uuid.UUID{uuid.New()})
+		_, err = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "dns lookup failed")
 	})
@@ -455,10 +466,10 @@ func TestBrowserImpl_ErrorHandling(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStationsByUUIDs([]uuid.UUID{uuid.New()})
+		_, err = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
 		assert.Error(t, err)
 	})
 }
@@ -489,10 +500,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		response, err := radioBrowser.VoteStation(station)
+		response, err := radioBrowser.VoteStation(context.Background(), station)
 		assert.NoError(t, err)
 		assert.True(t, response.Ok)
 		assert.Equal(t, "voted for station successfully", response.Message)
@@ -516,10 +527,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		response, err := radioBrowser.VoteStation(station)
+		response, err := radioBrowser.VoteStation(context.Background(), station)
 		assert.NoError(t, err)
 		assert.False(t, response.Ok)
 		assert.Contains(t, response.Message, "10 minutes")
@@ -536,10 +547,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "connection refused")
 	})
@@ -559,10 +570,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "404")
 	})
@@ -582,10 +593,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "500")
 	})
@@ -605,10 +616,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "429")
 	})
@@ -628,10 +639,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err)
 	})
 
@@ -650,10 +661,10 @@ func TestBrowserImplVoteStation(t *testing.T) {
 			},
 		}
 
-		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		radioBrowser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = radioBrowser.VoteStation(station)
+		_, err = radioBrowser.VoteStation(context.Background(), station)
 		assert.Error(t, err) // EOF error from JSON decoder
 	})
 }
@@ -673,10 +684,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		stations, err := browser.GetStations(common.StationQueryByName, "", "name", false, 0, 10, true)
+		stations, err := browser.GetStations(context.Background(), common.StationQueryByName, "", "name", false, 0, 10, true)
 		assert.NoError(t, err)
 		assert.Empty(t, stations)
 	})
@@ -694,10 +705,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test station", "name", false, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test station", "name", false, 0, 10, true)
 		assert.NoError(t, err)
 	})
 
@@ -713,10 +724,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 0, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 0, true)
 		assert.NoError(t, err)
 	})
 
@@ -732,10 +743,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 999999, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 999999, 10, true)
 		assert.NoError(t, err)
 	})
 
@@ -751,10 +762,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", true, 0, 10, true)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", true, 0, 10, true)
 		assert.NoError(t, err)
 	})
 
@@ -770,10 +781,10 @@ func TestGetStations_EdgeCases(t *testing.T) {
 			},
 		}
 
-		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient)
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
 		assert.NoError(t, err)
 
-		_, err = browser.GetStations(common.StationQueryByName, "test", "name", false, 0, 10, false)
+		_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "name", false, 0, 10, false)
 		assert.NoError(t, err)
 	})
 }
@@ -786,3 +797,725 @@ type networkError struct {
 func (e *networkError) Error() string {
 	return e.message
 }
+
+func TestBrowserImpl_MirrorFailover(t *testing.T) {
+
+	srvResolver := func(targets ...string) *mocks.MockDNSResolverService {
+		return &mocks.MockDNSResolverService{
+			LookupSRVFunc: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+				records := make([]*net.SRV, len(targets))
+				for i, target := range targets {
+					records[i] = &net.SRV{Target: target + "."}
+				}
+				return "", records, nil
+			},
+		}
+	}
+
+	newBrowser := func(t *testing.T, client *mocks.MockHttpClient, resolver DNSResolverService) *RadioBrowserImpl {
+		browser, err := NewRadioBrowserWithDependencies(client, resolver, 0)
+		assert.NoError(t, err)
+		impl := browser.(*RadioBrowserImpl)
+		impl.shuffle = func(hosts []string) {} // keep DNS order for deterministic tests
+		impl.retry = retryPolicy{}             // a single round over the mirrors
+		return impl
+	}
+
+	t.Run("uses the round-robin host when discovery fails", func(t *testing.T) {
+		browser := newBrowser(t, &mocks.MockHttpClient{}, &mocks.MockDNSResolverService{})
+		assert.Equal(t, "all.api.radio-browser.info", browser.CurrentMirror(context.Background()))
+	})
+
+	t.Run("uses the first discovered mirror", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "de1.api.radio-browser.info", req.URL.Host)
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser := newBrowser(t, &mockHttpClient, srvResolver("de1.api.radio-browser.info", "nl1.api.radio-browser.info"))
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "de1.api.radio-browser.info", browser.CurrentMirror(context.Background()))
+	})
+
+	t.Run("fails over to the next mirror on connection errors and caches it", func(t *testing.T) {
+		var hosts []string
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				hosts = append(hosts, req.URL.Host)
+				if req.URL.Host == "de1.api.radio-browser.info" {
+					return nil, &networkError{message: "connection refused"}
+				}
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser := newBrowser(t, &mockHttpClient, srvResolver("de1.api.radio-browser.info", "nl1.api.radio-browser.info"))
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"de1.api.radio-browser.info", "nl1.api.radio-browser.info"}, hosts)
+		assert.Equal(t, "nl1.api.radio-browser.info", browser.CurrentMirror(context.Background()))
+
+		// Subsequent requests go straight to the working mirror
+		hosts = nil
+		_, err = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"nl1.api.radio-browser.info"}, hosts)
+	})
+
+	t.Run("fails over on 5xx responses for POST endpoints", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				if req.URL.Host == "de1.api.radio-browser.info" {
+					return &http.Response{
+						StatusCode: 502,
+						Body:       io.NopCloser(bytes.NewReader([]byte(`Bad Gateway`))),
+					}, nil
+				}
+				assert.Equal(t, "/json/vote/941ef6f1-0699-4821-95b1-2b678e3ff62e", req.URL.Path)
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"ok": true, "message": "voted"}`))),
+				}, nil
+			},
+		}
+
+		browser := newBrowser(t, &mockHttpClient, srvResolver("de1.api.radio-browser.info", "nl1.api.radio-browser.info"))
+
+		response, err := browser.VoteStation(context.Background(), common.Station{
+			StationUuid: uuid.MustParse("941ef6f1-0699-4821-95b1-2b678e3ff62e"),
+		})
+		assert.NoError(t, err)
+		assert.True(t, response.Ok)
+		assert.Equal(t, "nl1.api.radio-browser.info", browser.CurrentMirror(context.Background()))
+	})
+
+	t.Run("does not fail over on 4xx responses", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return &http.Response{
+					StatusCode: 404,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`Not Found`))),
+				}, nil
+			},
+		}
+
+		browser := newBrowser(t, &mockHttpClient, srvResolver("de1.api.radio-browser.info", "nl1.api.radio-browser.info"))
+
+		_, err := browser.ClickStation(context.Background(), common.Station{StationUuid: uuid.New()})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("tries every mirror and the round-robin host before giving up", func(t *testing.T) {
+		var hosts []string
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				hosts = append(hosts, req.URL.Host)
+				return nil, &networkError{message: "connection refused"}
+			},
+		}
+
+		browser := newBrowser(t, &mockHttpClient, srvResolver("de1.api.radio-browser.info", "nl1.api.radio-browser.info"))
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "connection refused")
+		assert.Equal(t, []string{
+			"de1.api.radio-browser.info",
+			"nl1.api.radio-browser.info",
+			"all.api.radio-browser.info",
+		}, hosts)
+	})
+}
+
+func TestBrowserImpl_Context(t *testing.T) {
+
+	srvResolver := &mocks.MockDNSResolverService{
+		LookupSRVFunc: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			return "", []*net.SRV{{Target: "de1.api.radio-browser.info."}, {Target: "nl1.api.radio-browser.info."}}, nil
+		},
+	}
+
+	// blockingClient waits for the request context to be done, like a hung mirror would.
+	blockingClient := func(hosts *[]string) *mocks.MockHttpClient {
+		return &mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				*hosts = append(*hosts, req.URL.Host)
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			},
+		}
+	}
+
+	t.Run("passes the context to the HTTP request", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+		var got interface{}
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				got = req.Context().Value(ctxKey{})
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.GetStations(ctx, common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "value", got)
+	})
+
+	t.Run("returns the context error without trying other mirrors when cancelled", func(t *testing.T) {
+		var hosts []string
+
+		browser, err := NewRadioBrowserWithDependencies(blockingClient(&hosts), srvResolver, time.Minute)
+		assert.NoError(t, err)
+		browser.(*RadioBrowserImpl).shuffle = func(hosts []string) {}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err = browser.SearchStations(ctx, common.StationSearchParams{Limit: 10})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"de1.api.radio-browser.info"}, hosts)
+	})
+
+	t.Run("fails over to the next mirror when a mirror exceeds the request timeout", func(t *testing.T) {
+		var hosts []string
+
+		browser, err := NewRadioBrowserWithDependencies(blockingClient(&hosts), srvResolver, 5*time.Millisecond)
+		assert.NoError(t, err)
+		browser.(*RadioBrowserImpl).shuffle = func(hosts []string) {}
+		browser.(*RadioBrowserImpl).retry = retryPolicy{}
+
+		_, err = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []string{
+			"de1.api.radio-browser.info",
+			"nl1.api.radio-browser.info",
+			"all.api.radio-browser.info",
+		}, hosts)
+	})
+
+	t.Run("does not cache mirrors discovered with a cancelled context", func(t *testing.T) {
+		lookups := 0
+		resolver := &mocks.MockDNSResolverService{
+			LookupSRVFunc: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+				lookups++
+				return "", nil, ctx.Err()
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mocks.MockHttpClient{}, resolver, 0)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.Equal(t, "", browser.CurrentMirror(ctx))
+		assert.Equal(t, "all.api.radio-browser.info", browser.CurrentMirror(context.Background()))
+		assert.Equal(t, 2, lookups)
+	})
+}
+
+func TestBrowserImplSearchStations(t *testing.T) {
+
+	t.Run("builds the search URL with all criteria", func(t *testing.T) {
+		yes := true
+		no := false
+
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "GET", req.Method)
+				assert.Equal(t, "/json/stations/search", req.URL.Path)
+				assert.Equal(t, "application/json", req.Header.Get("Accept"))
+				assert.Equal(t, data.UserAgent, req.Header.Get("User-Agent"))
+
+				query := req.URL.Query()
+				assert.Equal(t, "radio", query.Get("name"))
+				assert.Equal(t, "jazz,smooth", query.Get("tagList"))
+				assert.Equal(t, "DE", query.Get("countrycode"))
+				assert.Equal(t, "Berlin", query.Get("state"))
+				assert.Equal(t, "german", query.Get("language"))
+				assert.Equal(t, "AAC", query.Get("codec"))
+				assert.Equal(t, "128", query.Get("bitrateMin"))
+				assert.Equal(t, "320", query.Get("bitrateMax"))
+				assert.Equal(t, "true", query.Get("has_geo_info"))
+				assert.Equal(t, "false", query.Get("is_https"))
+				assert.Equal(t, "votes", query.Get("order"))
+				assert.Equal(t, "true", query.Get("reverse"))
+				assert.Equal(t, "20", query.Get("offset"))
+				assert.Equal(t, "100", query.Get("limit"))
+				assert.Equal(t, "true", query.Get("hidebroken"))
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[{"name": "Jazz Radio"}]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		stations, err := browser.SearchStations(context.Background(), common.StationSearchParams{
+			Name:        "radio",
+			TagList:     []string{"jazz", "smooth"},
+			CountryCode: "DE",
+			State:       "Berlin",
+			Language:    "german",
+			Codec:       "AAC",
+			BitrateMin:  128,
+			BitrateMax:  320,
+			HasGeoInfo:  &yes,
+			IsHttps:     &no,
+			Order:       common.StationOrderVotes,
+			Reverse:     true,
+			Offset:      20,
+			Limit:       100,
+			HideBroken:  true,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+		assert.Equal(t, "Jazz Radio", stations[0].Name)
+	})
+
+	t.Run("omits unset criteria", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				query := req.URL.Query()
+				for _, key := range []string{"name", "tagList", "countrycode", "state", "language", "codec", "bitrateMin", "bitrateMax", "has_geo_info", "is_https", "order", "geo_lat", "geo_long", "geo_distance"} {
+					assert.False(t, query.Has(key), "unexpected query parameter %s", key)
+				}
+				assert.Equal(t, "10", query.Get("limit"))
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		stations, err := browser.SearchStations(context.Background(), common.StationSearchParams{Limit: 10})
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+	})
+
+	t.Run("adds the geographic criteria", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				query := req.URL.Query()
+				assert.Equal(t, "52.52", query.Get("geo_lat"))
+				assert.Equal(t, "-13.405", query.Get("geo_long"))
+				assert.Equal(t, "25000", query.Get("geo_distance"))
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.SearchStations(context.Background(), common.StationSearchParams{
+			Near:        &common.GeoPoint{Lat: 52.52, Long: -13.405},
+			GeoDistance: 25000,
+			Limit:       10,
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("handles HTTP errors", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 400,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`Bad Request`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.SearchStations(context.Background(), common.StationSearchParams{Limit: 10})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+}
+
+func TestBrowserImpl_TypedErrors(t *testing.T) {
+
+	respond := func(status int, body string, header http.Header) *mocks.MockHttpClient {
+		return &mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: status,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+				}, nil
+			},
+		}
+	}
+
+	fail := func(err error) *mocks.MockHttpClient {
+		return &mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return nil, err
+			},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		client     *mocks.MockHttpClient
+		kind       ErrorKind
+		statusCode int
+		retryAfter time.Duration
+	}{
+		{"connection refused", fail(&net.OpError{Op: "dial", Err: &networkError{message: "connection refused"}}), ErrorKindNetworkUnreachable, 0, 0},
+		{"DNS failure", fail(&net.DNSError{Err: "no such host", Name: "all.api.radio-browser.info", IsNotFound: true}), ErrorKindDNS, 0, 0},
+		{"timeout", fail(context.DeadlineExceeded), ErrorKindTimeout, 0, 0},
+		{"rate limited", respond(429, "Too Many Requests", http.Header{"Retry-After": []string{"5"}}), ErrorKindRateLimited, 429, 5 * time.Second},
+		{"server error", respond(502, "Bad Gateway", nil), ErrorKindServer, 502, 0},
+		{"malformed response", respond(200, "{not json", nil), ErrorKindMalformedResponse, 0, 0},
+		{"unexpected status", respond(404, "Not Found", nil), ErrorKindUnexpectedStatus, 404, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			browser, err := NewRadioBrowserWithDependencies(tc.client, &mocks.MockDNSResolverService{}, 0)
+			assert.NoError(t, err)
+			browser.(*RadioBrowserImpl).retry = retryPolicy{}
+
+			_, err = browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+
+			var apiErr *APIError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tc.kind, apiErr.Kind)
+			assert.Equal(t, tc.statusCode, apiErr.StatusCode)
+			assert.Equal(t, tc.retryAfter, apiErr.RetryAfter)
+		})
+	}
+
+	t.Run("returns context.Canceled unwrapped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				cancel()
+				return nil, req.Context().Err()
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.GetStations(ctx, common.StationQueryByName, "test", "votes", true, 0, 10, true)
+
+		var apiErr *APIError
+		assert.False(t, errors.As(err, &apiErr))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestBrowserImpl_Backoff(t *testing.T) {
+
+	newBrowser := func(t *testing.T, client *mocks.MockHttpClient, delays *[]time.Duration) RadioBrowserService {
+		browser, err := NewRadioBrowserWithDependencies(client, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+		impl := browser.(*RadioBrowserImpl)
+		impl.retry = retryPolicy{maxRetries: 3, baseDelay: time.Second, maxDelay: 10 * time.Second}
+		impl.sleep = func(ctx context.Context, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		}
+		return browser
+	}
+
+	t.Run("retries GET requests with exponential backoff until they succeed", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls < 3 {
+					return &http.Response{StatusCode: 503, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+				}
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`[]`)))}, nil
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+	})
+
+	t.Run("gives up after the maximum number of retries", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return nil, &networkError{message: "connection refused"}
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.SearchStations(context.Background(), common.StationSearchParams{Limit: 10})
+		assert.Error(t, err)
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, delays)
+	})
+
+	t.Run("waits for Retry-After when rate limited", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return &http.Response{
+						StatusCode: 429,
+						Header:     http.Header{"Retry-After": []string{"7"}},
+						Body:       io.NopCloser(bytes.NewReader(nil)),
+					}, nil
+				}
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`[]`)))}, nil
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{uuid.New()})
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{7 * time.Second}, delays)
+	})
+
+	t.Run("does not retry when Retry-After exceeds the maximum delay", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return &http.Response{
+					StatusCode: 429,
+					Header:     http.Header{"Retry-After": []string{"120"}},
+					Body:       io.NopCloser(bytes.NewReader(nil)),
+				}, nil
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, delays)
+	})
+
+	t.Run("does not retry non-retryable errors", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return &http.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader([]byte(`{invalid`)))}, nil
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.GetStations(context.Background(), common.StationQueryByName, "test", "votes", true, 0, 10, true)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, delays)
+	})
+
+	t.Run("does not retry POST requests", func(t *testing.T) {
+		calls := 0
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				return &http.Response{StatusCode: 500, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+			},
+		}
+
+		var delays []time.Duration
+		browser := newBrowser(t, &mockHttpClient, &delays)
+
+		_, err := browser.VoteStation(context.Background(), common.Station{StationUuid: uuid.New()})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, delays)
+	})
+}
+
+func TestBrowserImplGetFacets(t *testing.T) {
+
+	t.Run("builds the facet URL sorted by station count", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "GET", req.Method)
+				assert.Equal(t, "/json/countries", req.URL.Path)
+
+				query := req.URL.Query()
+				assert.Equal(t, "stationcount", query.Get("order"))
+				assert.Equal(t, "true", query.Get("reverse"))
+				assert.Equal(t, "true", query.Get("hidebroken"))
+				assert.Equal(t, "500", query.Get("limit"))
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[{"name": "Germany", "iso_3166_1": "DE", "stationcount": 3521}]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		facets, err := browser.GetFacets(context.Background(), common.FacetCountries, "", 500)
+
+		assert.NoError(t, err)
+		assert.Len(t, facets, 1)
+		assert.Equal(t, "Germany", facets[0].Name)
+		assert.Equal(t, "DE", facets[0].Iso3166_1)
+		assert.Equal(t, uint64(3521), facets[0].StationCount)
+	})
+
+	t.Run("appends the filter to the path and omits a zero limit", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/json/tags/jazz", req.URL.Path)
+				assert.False(t, req.URL.Query().Has("limit"))
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		facets, err := browser.GetFacets(context.Background(), common.FacetTags, "jazz", 0)
+		assert.NoError(t, err)
+		assert.Empty(t, facets)
+	})
+
+	t.Run("escapes the filter as a single path segment", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "/json/tags/drum%2Fbass%20%3F", req.URL.EscapedPath())
+				assert.Equal(t, "/json/tags/drum/bass ?", req.URL.Path)
+				assert.Equal(t, "stationcount", req.URL.Query().Get("order"))
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.GetFacets(context.Background(), common.FacetTags, "drum/bass ?", 0)
+		assert.NoError(t, err)
+	})
+
+	t.Run("handles HTTP errors", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 404,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`Not Found`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.GetFacets(context.Background(), common.FacetCodecs, "", 0)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+}
+
+func TestBrowserImplGetStationList(t *testing.T) {
+
+	t.Run("builds the list URL with the requested range", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				assert.Equal(t, "GET", req.Method)
+				assert.Equal(t, "/json/stations/topclick", req.URL.Path)
+
+				query := req.URL.Query()
+				assert.Equal(t, "50", query.Get("offset"))
+				assert.Equal(t, "25", query.Get("limit"))
+				assert.Equal(t, "true", query.Get("hidebroken"))
+				assert.False(t, query.Has("order"))
+
+				return &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`[{"stationuuid": "941ef6f1-0699-4821-95b1-2b678e3ff62e", "name": "Trending FM"}]`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		stations, err := browser.GetStationList(context.Background(), common.StationListTopClick, 50, 25)
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+		assert.Equal(t, "Trending FM", stations[0].Name)
+	})
+
+	t.Run("handles HTTP errors", func(t *testing.T) {
+		mockHttpClient := mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: 404,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`Not Found`))),
+				}, nil
+			},
+		}
+
+		browser, err := NewRadioBrowserWithDependencies(&mockHttpClient, &mocks.MockDNSResolverService{}, 0)
+		assert.NoError(t, err)
+
+		_, err = browser.GetStationList(context.Background(), common.StationListLastChange, 0, 10)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// ResponseCache persists API responses between sessions.
+type ResponseCache interface {
+	// GetCachedResponse returns the response stored under key and when it was stored.
+	// found is false if nothing is stored under key.
+	GetCachedResponse(key string) (data []byte, storedAt time.Time, found bool)
+	// PutCachedResponse stores a response under key, replacing any previous one.
+	PutCachedResponse(key string, data []byte, storedAt time.Time) error
+}
+
+// CacheTTLs holds how long cached responses are served before the API is asked again.
+type CacheTTLs struct {
+	// Search applies to station searches and lists (GetStations, SearchStations, GetStationList).
+	Search time.Duration
+	// Station applies to single stations looked up by UUID (GetStationsByUUIDs).
+	Station time.Duration
+	// Facet applies to facet listings (GetFacets).
+	Facet time.Duration
+}
+
+// DefaultCacheTTLs keeps searches for 10 minutes, stations for an hour and facets for a day.
+var DefaultCacheTTLs = CacheTTLs{
+	Search:  10 * time.Minute,
+	Station: time.Hour,
+	Facet:   24 * time.Hour,
+}
+
+type forceRefreshKey struct{}
+
+// WithForceRefresh returns a context that makes a CachedRadioBrowser skip fresh cached
+// responses and ask the API. Stale data is not served if that request fails.
+func WithForceRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceRefreshKey{}, true)
+}
+
+// IsForceRefresh reports whether ctx was created by WithForceRefresh.
+func IsForceRefresh(ctx context.Context) bool {
+	force, _ := ctx.Value(forceRefreshKey{}).(bool)
+	return force
+}
+
+// CachedRadioBrowser is a RadioBrowserService that reuses responses stored in a ResponseCache.
+// Fresh responses (younger than their TTL) are served without asking the API. When the API
+// can't be reached, expired responses are served instead of failing.
+// Clicks and votes are never cached.
+type CachedRadioBrowser struct {
+	inner RadioBrowserService
+	cache ResponseCache
+	ttls  CacheTTLs
+	// Returns the current time (replaceable in tests).
+	now func() time.Time
+}
+
+// NewCachedRadioBrowser returns a RadioBrowserService that caches the responses of inner.
+// Zero TTLs are replaced with the ones in DefaultCacheTTLs.
+func NewCachedRadioBrowser(inner RadioBrowserService, cache ResponseCache, ttls CacheTTLs) RadioBrowserService {
+	if ttls.Search <= 0 {
+		ttls.Search = DefaultCacheTTLs.Search
+	}
+	if ttls.Station <= 0 {
+		ttls.Station = DefaultCacheTTLs.Station
+	}
+	if ttls.Facet <= 0 {
+		ttls.Facet = DefaultCacheTTLs.Facet
+	}
+	return &CachedRadioBrowser{
+		inner: inner,
+		cache: cache,
+		ttls:  ttls,
+		now:   time.Now,
+	}
+}
+
+// GetStations returns cached results for the same query if they are fresh enough.
+// The search term is escaped in the cache key, so that it can't be mistaken for the other parameters.
+func (c *CachedRadioBrowser) GetStations(
+	ctx context.Context,
+	stationQuery common.StationQuery,
+	searchTerm string,
+	order string,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+	hideBroken bool,
+) ([]common.Station, error) {
+	key := fmt.Sprintf("stations/%s/%s?order=%s&reverse=%t&offset=%d&limit=%d&hidebroken=%t",
+		stationQuery, url.PathEscape(searchTerm), order, reverse, offset, limit, hideBroken)
+	return cached(ctx, c, key, c.ttls.Search, func() ([]common.Station, error) {
+		return c.inner.GetStations(ctx, stationQuery, searchTerm, order, reverse, offset, limit, hideBroken)
+	})
+}
+
+// SearchStations returns cached results for the same criteria if they are fresh enough.
+func (c *CachedRadioBrowser) SearchStations(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return c.inner.SearchStations(ctx, params)
+	}
+	return cached(ctx, c, "search/"+string(encoded), c.ttls.Search, func() ([]common.Station, error) {
+		return c.inner.SearchStations(ctx, params)
+	})
+}
+
+// GetFacets returns a cached listing for the same kind and filter if it is fresh enough.
+func (c *CachedRadioBrowser) GetFacets(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+	key := fmt.Sprintf("facets/%s/%s?limit=%d", kind, url.PathEscape(filter), limit)
+	return cached(ctx, c, key, c.ttls.Facet, func() ([]common.Facet, error) {
+		return c.inner.GetFacets(ctx, kind, filter, limit)
+	})
+}
+
+// GetStationList returns cached results for the same list and range if they are fresh enough.
+func (c *CachedRadioBrowser) GetStationList(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+	key := fmt.Sprintf("lists/%s?offset=%d&limit=%d", list, offset, limit)
+	return cached(ctx, c, key, c.ttls.Search, func() ([]common.Station, error) {
+		return c.inner.GetStationList(ctx, list, offset, limit)
+	})
+}
+
+// GetStationsByUUIDs caches every station on its own, so that looking up a different
+// set of stations (e.g. after adding a bookmark) only misses for the new ones.
+// The API is asked for all of them if any is missing or expired.
+func (c *CachedRadioBrowser) GetStationsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+	if len(uuids) == 0 {
+		return c.inner.GetStationsByUUIDs(ctx, uuids)
+	}
+
+	now := c.now()
+	cachedStations := make([]common.Station, 0, len(uuids))
+	allFresh := true
+	for _, id := range uuids {
+		data, storedAt, found := c.cache.GetCachedResponse(stationCacheKey(id))
+		if !found {
+			allFresh = false
+			continue
+		}
+		var station common.Station
+		if err := json.Unmarshal(data, &station); err != nil {
+			allFresh = false
+			continue
+		}
+		if now.Sub(storedAt) >= c.ttls.Station {
+			allFresh = false
+		}
+		cachedStations = append(cachedStations, station)
+	}
+
+	if allFresh && !IsForceRefresh(ctx) {
+		return cachedStations, nil
+	}
+
+	stations, err := c.inner.GetStationsByUUIDs(ctx, uuids)
+	if err != nil {
+		if len(cachedStations) > 0 && servesStale(ctx, err) {
+			return cachedStations, nil
+		}
+		return nil, err
+	}
+
+	for _, station := range stations {
+		if data, err := json.Marshal(station); err == nil {
+			_ = c.cache.PutCachedResponse(stationCacheKey(station.StationUuid), data, now)
+		}
+	}
+	return stations, nil
+}
+
+// ClickStation is passed through to the API.
+func (c *CachedRadioBrowser) ClickStation(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+	return c.inner.ClickStation(ctx, station)
+}
+
+// VoteStation is passed through to the API.
+func (c *CachedRadioBrowser) VoteStation(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
+	return c.inner.VoteStation(ctx, station)
+}
+
+// CurrentMirror is passed through to the API client.
+func (c *CachedRadioBrowser) CurrentMirror(ctx context.Context) string {
+	return c.inner.CurrentMirror(ctx)
+}
+
+// stationCacheKey returns the cache key of a single station.
+func stationCacheKey(id uuid.UUID) string {
+	return "station/" + strings.ToLower(id.String())
+}
+
+// cached serves the response stored under key if it is younger than ttl; otherwise it calls
+// fetch and stores the result. If fetch fails because the API can't be reached, the
+// expired response is served instead.
+func cached[T any](ctx context.Context, c *CachedRadioBrowser, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	var stale T
+	hasStale := false
+
+	if data, storedAt, found := c.cache.GetCachedResponse(key); found {
+		var value T
+		if err := json.Unmarshal(data, &value); err == nil {
+			if c.now().Sub(storedAt) < ttl && !IsForceRefresh(ctx) {
+				return value, nil
+			}
+			stale, hasStale = value, true
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		if hasStale && servesStale(ctx, err) {
+			return stale, nil
+		}
+		return value, err
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		_ = c.cache.PutCachedResponse(key, data, c.now())
+	}
+	return value, nil
+}
+
+// servesStale reports whether an expired cached response should be served after err:
+// only when the API couldn't be reached, and neither the user cancelled nor asked for fresh data.
+func servesStale(ctx context.Context, err error) bool {
+	if IsForceRefresh(ctx) {
+		return false
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
@@ -0,0 +1,396 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheEntry struct {
+	data     []byte
+	storedAt time.Time
+}
+
+// newMapCache returns a MockResponseCache backed by a map.
+func newMapCache() (*mocks.MockResponseCache, map[string]cacheEntry) {
+	entries := map[string]cacheEntry{}
+	return &mocks.MockResponseCache{
+		GetCachedResponseFunc: func(key string) ([]byte, time.Time, bool) {
+			entry, found := entries[key]
+			return entry.data, entry.storedAt, found
+		},
+		PutCachedResponseFunc: func(key string, data []byte, storedAt time.Time) error {
+			entries[key] = cacheEntry{data: data, storedAt: storedAt}
+			return nil
+		},
+	}, entries
+}
+
+// newTestCachedBrowser returns a CachedRadioBrowser whose clock is controlled by the returned pointer.
+func newTestCachedBrowser(inner RadioBrowserService, cache ResponseCache) (*CachedRadioBrowser, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	browser := NewCachedRadioBrowser(inner, cache, CacheTTLs{}).(*CachedRadioBrowser)
+	browser.now = func() time.Time { return now }
+	return browser, &now
+}
+
+func TestCachedRadioBrowser_GetStations(t *testing.T) {
+
+	stations := []common.Station{
+		{StationUuid: uuid.New(), Name: "Jazz FM"},
+		{StationUuid: uuid.New(), Name: "Smooth Jazz"},
+	}
+
+	getStations := func(browser RadioBrowserService, ctx context.Context) ([]common.Station, error) {
+		return browser.GetStations(ctx, common.StationQueryByTag, "jazz", "votes", true, 0, 100, true)
+	}
+
+	t.Run("serves fresh responses from the cache", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				calls++
+				return stations, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, now := newTestCachedBrowser(inner, cache)
+
+		first, err := getStations(browser, context.Background())
+		assert.NoError(t, err)
+		*now = now.Add(DefaultCacheTTLs.Search - time.Second)
+		second, err := getStations(browser, context.Background())
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, stations, first)
+		assert.Equal(t, stations, second)
+	})
+
+	t.Run("asks the API again once the TTL expired", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				calls++
+				return stations, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, now := newTestCachedBrowser(inner, cache)
+
+		_, _ = getStations(browser, context.Background())
+		*now = now.Add(DefaultCacheTTLs.Search)
+		_, _ = getStations(browser, context.Background())
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("caches different queries separately", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				calls++
+				return stations, nil
+			},
+		}
+		cache, entries := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		_, _ = getStations(browser, context.Background())
+		_, _ = browser.GetStations(context.Background(), common.StationQueryByTag, "jazz", "votes", true, 100, 100, true)
+
+		assert.Equal(t, 2, calls)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("escapes the search term in the cache key", func(t *testing.T) {
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				return stations, nil
+			},
+		}
+		cache, entries := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		_, _ = browser.GetStations(context.Background(), common.StationQueryByTag, "drum/bass?order=name&", "votes", true, 0, 100, true)
+
+		assert.Contains(t, entries, "stations/bytag/drum%2Fbass%3Forder=name&?order=votes&reverse=true&offset=0&limit=100&hidebroken=true")
+	})
+
+	t.Run("serves stale responses when the API is unreachable", func(t *testing.T) {
+		fail := false
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				if fail {
+					return nil, &APIError{Kind: ErrorKindNetworkUnreachable}
+				}
+				return stations, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, now := newTestCachedBrowser(inner, cache)
+
+		_, _ = getStations(browser, context.Background())
+		fail = true
+		*now = now.Add(7 * 24 * time.Hour)
+
+		result, err := getStations(browser, context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, stations, result)
+	})
+
+	t.Run("does not hide errors that stale data would not fix", func(t *testing.T) {
+		fail := false
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				if fail {
+					return nil, context.Canceled
+				}
+				return stations, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, now := newTestCachedBrowser(inner, cache)
+
+		_, _ = getStations(browser, context.Background())
+		fail = true
+		*now = now.Add(time.Hour)
+
+		_, err := getStations(browser, context.Background())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("force refresh skips fresh responses and doesn't serve stale ones", func(t *testing.T) {
+		calls := 0
+		fail := false
+		inner := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				calls++
+				if fail {
+					return nil, &APIError{Kind: ErrorKindTimeout}
+				}
+				return stations, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		_, _ = getStations(browser, context.Background())
+		_, err := getStations(browser, WithForceRefresh(context.Background()))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		fail = true
+		_, err = getStations(browser, WithForceRefresh(context.Background()))
+		var apiErr *APIError
+		assert.ErrorAs(t, err, &apiErr)
+	})
+}
+
+func TestCachedRadioBrowser_SearchStations(t *testing.T) {
+
+	t.Run("caches by search criteria", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			SearchStationsFunc: func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+				calls++
+				return []common.Station{{StationUuid: uuid.New(), Name: params.Name}}, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		jazz, _ := browser.SearchStations(context.Background(), common.StationSearchParams{Name: "jazz"})
+		again, _ := browser.SearchStations(context.Background(), common.StationSearchParams{Name: "jazz"})
+		rock, _ := browser.SearchStations(context.Background(), common.StationSearchParams{Name: "rock"})
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, jazz, again)
+		assert.Equal(t, "rock", rock[0].Name)
+	})
+}
+
+func TestCachedRadioBrowser_GetFacets(t *testing.T) {
+
+	t.Run("keeps facets for a day", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				calls++
+				return []common.Facet{{Name: "jazz", StationCount: 42}}, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, now := newTestCachedBrowser(inner, cache)
+
+		_, _ = browser.GetFacets(context.Background(), common.FacetTags, "", 100)
+		*now = now.Add(23 * time.Hour)
+		facets, err := browser.GetFacets(context.Background(), common.FacetTags, "", 100)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, []common.Facet{{Name: "jazz", StationCount: 42}}, facets)
+	})
+
+	t.Run("escapes the filter in the cache key", func(t *testing.T) {
+		inner := &mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				return []common.Facet{{Name: "drum & bass", StationCount: 42}}, nil
+			},
+		}
+		cache, entries := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		_, _ = browser.GetFacets(context.Background(), common.FacetTags, "drum/bass ?", 100)
+
+		assert.Contains(t, entries, "facets/tags/drum%2Fbass%20%3F?limit=100")
+	})
+}
+
+func TestCachedRadioBrowser_GetStationList(t *testing.T) {
+
+	t.Run("caches by list and range", func(t *testing.T) {
+		calls := 0
+		inner := &mocks.MockRadioBrowserService{
+			GetStationListFunc: func(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+				calls++
+				return []common.Station{{StationUuid: uuid.New(), Name: string(list)}}, nil
+			},
+		}
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		first, _ := browser.GetStationList(context.Background(), common.StationListTopVote, 0, 50)
+		again, _ := browser.GetStationList(context.Background(), common.StationListTopVote, 0, 50)
+		_, _ = browser.GetStationList(context.Background(), common.StationListTopVote, 50, 50)
+		_, _ = browser.GetStationList(context.Background(), common.StationListLastClick, 0, 50)
+
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, first, again)
+	})
+}
+
+func TestCachedRadioBrowser_GetStationsByUUIDs(t *testing.T) {
+
+	first := common.Station{StationUuid: uuid.New(), Name: "First"}
+	second := common.Station{StationUuid: uuid.New(), Name: "Second"}
+	byUUID := map[uuid.UUID]common.Station{first.StationUuid: first, second.StationUuid: second}
+
+	newInner := func(calls *[][]uuid.UUID, err *error) *mocks.MockRadioBrowserService {
+		return &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				*calls = append(*calls, uuids)
+				if *err != nil {
+					return nil, *err
+				}
+				result := []common.Station{}
+				for _, id := range uuids {
+					result = append(result, byUUID[id])
+				}
+				return result, nil
+			},
+		}
+	}
+
+	t.Run("serves stations from the cache when all of them are fresh", func(t *testing.T) {
+		var calls [][]uuid.UUID
+		var err error
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(newInner(&calls, &err), cache)
+
+		_, _ = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid, second.StationUuid})
+		result, fetchErr := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{second.StationUuid})
+
+		assert.NoError(t, fetchErr)
+		assert.Len(t, calls, 1)
+		assert.Equal(t, []common.Station{second}, result)
+	})
+
+	t.Run("asks the API when a station is missing", func(t *testing.T) {
+		var calls [][]uuid.UUID
+		var err error
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(newInner(&calls, &err), cache)
+
+		_, _ = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid})
+		result, fetchErr := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid, second.StationUuid})
+
+		assert.NoError(t, fetchErr)
+		assert.Len(t, calls, 2)
+		assert.Equal(t, []common.Station{first, second}, result)
+	})
+
+	t.Run("serves whatever is cached when the API is unreachable", func(t *testing.T) {
+		var calls [][]uuid.UUID
+		var err error
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(newInner(&calls, &err), cache)
+
+		_, _ = browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid})
+		err = &APIError{Kind: ErrorKindDNS}
+		result, fetchErr := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid, second.StationUuid})
+
+		assert.NoError(t, fetchErr)
+		assert.Equal(t, []common.Station{first}, result)
+	})
+
+	t.Run("fails when nothing is cached", func(t *testing.T) {
+		var calls [][]uuid.UUID
+		err := error(&APIError{Kind: ErrorKindDNS})
+		cache, _ := newMapCache()
+		browser, _ := newTestCachedBrowser(newInner(&calls, &err), cache)
+
+		_, fetchErr := browser.GetStationsByUUIDs(context.Background(), []uuid.UUID{first.StationUuid})
+
+		assert.Error(t, fetchErr)
+	})
+}
+
+func TestCachedRadioBrowser_PassThrough(t *testing.T) {
+
+	t.Run("never caches votes", func(t *testing.T) {
+		votes := 0
+		inner := &mocks.MockRadioBrowserService{
+			VoteStationFunc: func(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
+				votes++
+				return common.VoteStationResponse{}, errors.New("already voted")
+			},
+		}
+		cache, entries := newMapCache()
+		browser, _ := newTestCachedBrowser(inner, cache)
+
+		_, err1 := browser.VoteStation(context.Background(), common.Station{})
+		_, err2 := browser.VoteStation(context.Background(), common.Station{})
+
+		assert.Error(t, err1)
+		assert.Error(t, err2)
+		assert.Equal(t, 2, votes)
+		assert.Empty(t, entries)
+	})
+}
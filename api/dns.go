@@ -0,0 +1,114 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"strings"
+)
+
+const (
+	// radioBrowserDomain is the domain under which RadioBrowser publishes its API mirrors.
+	radioBrowserDomain = "radio-browser.info"
+	// radioBrowserFallbackHost is the round-robin name used when no mirror can be discovered.
+	radioBrowserFallbackHost = "all.api.radio-browser.info"
+)
+
+// DNSResolverService abstracts the DNS lookups used to discover RadioBrowser API mirrors.
+// This allows for dependency injection and easier testing by swapping the system
+// resolver with mock implementations.
+type DNSResolverService interface {
+	// LookupSRV returns the SRV records for the given service, protocol and domain.
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	// LookupHost returns the addresses of the given host.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	// LookupAddr performs a reverse lookup of the given address.
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// netResolver is the production implementation using the net package.
+type netResolver struct{}
+
+func (netResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return net.DefaultResolver.LookupSRV(ctx, service, proto, name)
+}
+
+func (netResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return net.DefaultResolver.LookupHost(ctx, host)
+}
+
+func (netResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return net.DefaultResolver.LookupAddr(ctx, addr)
+}
+
+// discoverMirrors returns the RadioBrowser API hosts advertised via DNS.
+// The SRV record _api._tcp.radio-browser.info is queried first. If it yields nothing,
+// the addresses behind all.api.radio-browser.info are reverse-resolved instead, since
+// HTTPS requires talking to each mirror by its own name.
+// Only hosts under radio-browser.info are returned, without duplicates or trailing dots.
+func discoverMirrors(ctx context.Context, resolver DNSResolverService) []string {
+	hosts := []string{}
+	seen := make(map[string]bool)
+
+	add := func(host string) {
+		host = strings.ToLower(strings.TrimSuffix(host, "."))
+		if !strings.HasSuffix(host, "."+radioBrowserDomain) || seen[host] {
+			return
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+
+	if _, records, err := resolver.LookupSRV(ctx, "api", "tcp", radioBrowserDomain); err == nil {
+		for _, record := range records {
+			add(record.Target)
+		}
+	}
+
+	if len(hosts) > 0 {
+		return hosts
+	}
+
+	addrs, err := resolver.LookupHost(ctx, radioBrowserFallbackHost)
+	if err != nil {
+		return hosts
+	}
+	for _, addr := range addrs {
+		names, err := resolver.LookupAddr(ctx, addr)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			add(name)
+		}
+	}
+
+	return hosts
+}
+
+// shuffleHosts randomizes the order of hosts in place so that clients spread their
+// load across all mirrors.
+func shuffleHosts(hosts []string) {
+	rand.Shuffle(len(hosts), func(i, j int) {
+		hosts[i], hosts[j] = hosts[j], hosts[i]
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverMirrors(t *testing.T) {
+
+	t.Run("returns SRV targets without trailing dots", func(t *testing.T) {
+		resolver := mocks.MockDNSResolverService{
+			LookupSRVFunc: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+				assert.Equal(t, "api", service)
+				assert.Equal(t, "tcp", proto)
+				assert.Equal(t, "radio-browser.info", name)
+				return "", []*net.SRV{
+					{Target: "de1.api.radio-browser.info."},
+					{Target: "nl1.api.radio-browser.info."},
+				}, nil
+			},
+			LookupHostFunc: func(ctx context.Context, host string) ([]string, error) {
+				t.Error("A records should not be queried when SRV succeeds")
+				return nil, nil
+			},
+		}
+
+		hosts := discoverMirrors(context.Background(), &resolver)
+
+		assert.Equal(t, []string{"de1.api.radio-browser.info", "nl1.api.radio-browser.info"}, hosts)
+	})
+
+	t.Run("falls back to reverse-resolved A records when SRV fails", func(t *testing.T) {
+		resolver := mocks.MockDNSResolverService{
+			LookupHostFunc: func(ctx context.Context, host string) ([]string, error) {
+				assert.Equal(t, "all.api.radio-browser.info", host)
+				return []string{"1.2.3.4", "5.6.7.8"}, nil
+			},
+			LookupAddrFunc: func(ctx context.Context, addr string) ([]string, error) {
+				switch addr {
+				case "1.2.3.4":
+					return []string{"at1.api.radio-browser.info."}, nil
+				case "5.6.7.8":
+					return []string{"fi1.api.radio-browser.info."}, nil
+				}
+				return nil, errors.New("unexpected address")
+			},
+		}
+
+		hosts := discoverMirrors(context.Background(), &resolver)
+
+		assert.Equal(t, []string{"at1.api.radio-browser.info", "fi1.api.radio-browser.info"}, hosts)
+	})
+
+	t.Run("ignores hosts outside radio-browser.info and duplicates", func(t *testing.T) {
+		resolver := mocks.MockDNSResolverService{
+			LookupSRVFunc: func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+				return "", []*net.SRV{
+					{Target: "de1.api.radio-browser.info."},
+					{Target: "DE1.api.radio-browser.info"},
+					{Target: "evil.example.com."},
+				}, nil
+			},
+		}
+
+		hosts := discoverMirrors(context.Background(), &resolver)
+
+		assert.Equal(t, []string{"de1.api.radio-browser.info"}, hosts)
+	})
+
+	t.Run("returns an empty list when DNS is unavailable", func(t *testing.T) {
+		hosts := discoverMirrors(context.Background(), &mocks.MockDNSResolverService{})
+		assert.Empty(t, hosts)
+	})
+}
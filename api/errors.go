@@ -0,0 +1,165 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorKind classifies API failures so that callers can explain them to the user
+// and decide whether retrying makes sense.
+type ErrorKind int
+
+const (
+	// ErrorKindNetworkUnreachable means no connection to the API could be established.
+	ErrorKindNetworkUnreachable ErrorKind = iota
+	// ErrorKindDNS means the API host name could not be resolved.
+	ErrorKindDNS
+	// ErrorKindTimeout means the API did not answer within the request timeout.
+	ErrorKindTimeout
+	// ErrorKindRateLimited means the API answered 429 Too Many Requests.
+	ErrorKindRateLimited
+	// ErrorKindServer means the API answered with a 5xx status.
+	ErrorKindServer
+	// ErrorKindMalformedResponse means the API answered with a body that could not be decoded.
+	ErrorKindMalformedResponse
+	// ErrorKindUnexpectedStatus means the API answered with any other non-200 status.
+	ErrorKindUnexpectedStatus
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindNetworkUnreachable:
+		return "network unreachable"
+	case ErrorKindDNS:
+		return "DNS failure"
+	case ErrorKindTimeout:
+		return "timeout"
+	case ErrorKindRateLimited:
+		return "rate limited"
+	case ErrorKindServer:
+		return "server error"
+	case ErrorKindMalformedResponse:
+		return "malformed response"
+	case ErrorKindUnexpectedStatus:
+		return "unexpected status"
+	}
+	return "unknown"
+}
+
+// APIError is returned by RadioBrowserService methods when a request fails.
+// Cancellation via the request context is not wrapped: context.Canceled is returned as is.
+type APIError struct {
+	Kind ErrorKind
+	// StatusCode is the HTTP status code, or 0 if no response was received.
+	StatusCode int
+	// RetryAfter is the delay requested by the server for ErrorKindRateLimited, if any.
+	RetryAfter time.Duration
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.StatusCode != 0 && e.Err != nil:
+		return fmt.Sprintf("%s: API request failed with status %d: %v", e.Kind, e.StatusCode, e.Err)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("%s: API request failed with status %d", e.Kind, e.StatusCode)
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	return e.Kind.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable returns true if the same request may succeed on another mirror or a later attempt.
+func (e *APIError) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindMalformedResponse, ErrorKindUnexpectedStatus:
+		return false
+	}
+	return true
+}
+
+// classifyTransportError wraps an error returned by the HTTP client into an APIError.
+func classifyTransportError(err error) *APIError {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		if dnsErr.IsTimeout {
+			return &APIError{Kind: ErrorKindTimeout, Err: err}
+		}
+		return &APIError{Kind: ErrorKindDNS, Err: err}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &APIError{Kind: ErrorKindTimeout, Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &APIError{Kind: ErrorKindTimeout, Err: err}
+	}
+
+	return &APIError{Kind: ErrorKindNetworkUnreachable, Err: err}
+}
+
+// statusError returns the APIError for a non-200 response.
+func statusError(result *http.Response) *APIError {
+	switch {
+	case result.StatusCode == http.StatusTooManyRequests:
+		return &APIError{
+			Kind:       ErrorKindRateLimited,
+			StatusCode: result.StatusCode,
+			RetryAfter: parseRetryAfter(result.Header.Get("Retry-After"), time.Now()),
+		}
+	case result.StatusCode >= 500:
+		return &APIError{Kind: ErrorKindServer, StatusCode: result.StatusCode}
+	}
+	return &APIError{Kind: ErrorKindUnexpectedStatus, StatusCode: result.StatusCode}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds
+// or an HTTP date. Returns 0 if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
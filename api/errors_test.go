@@ -0,0 +1,79 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+
+	now := time.Date(2026, 1, 22, 18, 0, 0, 0, time.UTC)
+
+	t.Run("parses delay in seconds", func(t *testing.T) {
+		assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	})
+
+	t.Run("parses HTTP date", func(t *testing.T) {
+		date := now.Add(90 * time.Second).Format(http.TimeFormat)
+		assert.Equal(t, 90*time.Second, parseRetryAfter(date, now))
+	})
+
+	t.Run("returns zero for missing, invalid, negative or past values", func(t *testing.T) {
+		for _, value := range []string{"", "soon", "-5", now.Add(-time.Minute).Format(http.TimeFormat)} {
+			assert.Equal(t, time.Duration(0), parseRetryAfter(value, now), value)
+		}
+	})
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+
+	policy := retryPolicy{maxRetries: 5, baseDelay: time.Second, maxDelay: 5 * time.Second}
+
+	t.Run("doubles the delay and caps it", func(t *testing.T) {
+		err := &APIError{Kind: ErrorKindServer, StatusCode: 500}
+
+		var delays []time.Duration
+		for retry := 0; retry < 5; retry++ {
+			d, ok := policy.delay(retry, err)
+			assert.True(t, ok)
+			delays = append(delays, d)
+		}
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+		_, ok := policy.delay(5, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("refuses errors that are not retryable", func(t *testing.T) {
+		_, ok := policy.delay(0, &APIError{Kind: ErrorKindMalformedResponse})
+		assert.False(t, ok)
+
+		_, ok = policy.delay(0, &APIError{Kind: ErrorKindUnexpectedStatus, StatusCode: 404})
+		assert.False(t, ok)
+
+		_, ok = policy.delay(0, assert.AnError)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// retryPolicy controls the exponential backoff applied to idempotent (GET) requests
+// once every mirror has failed with a retryable error.
+type retryPolicy struct {
+	// maxRetries is the number of additional rounds over the mirror list.
+	maxRetries int
+	// baseDelay is the wait before the first retry; it doubles on every retry.
+	baseDelay time.Duration
+	// maxDelay caps the wait between retries. A server asking to wait longer
+	// (Retry-After) makes the request fail immediately instead.
+	maxDelay time.Duration
+}
+
+// defaultRetryPolicy retries twice, waiting 500ms then 1s.
+var defaultRetryPolicy = retryPolicy{
+	maxRetries: 2,
+	baseDelay:  500 * time.Millisecond,
+	maxDelay:   10 * time.Second,
+}
+
+// delay returns how long to wait before the given retry (0-based) after err,
+// and false if the request should not be retried at all.
+func (p retryPolicy) delay(retry int, err error) (time.Duration, bool) {
+	if retry >= p.maxRetries {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Retryable() {
+		return 0, false
+	}
+
+	d := p.baseDelay << retry
+	if d > p.maxDelay {
+		d = p.maxDelay
+	}
+
+	if apiErr.Kind == ErrorKindRateLimited && apiErr.RetryAfter > d {
+		if apiErr.RetryAfter > p.maxDelay {
+			return 0, false
+		}
+		d = apiErr.RetryAfter
+	}
+
+	return d, true
+}
+
+// sleepContext waits for d, returning early with ctx.Err() if ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -28,3 +28,7 @@ func boolToString(b bool) string {
 func uint64ToString(i uint64) string {
 	return strconv.FormatUint(i, 10)
 }
+
+func float64ToString(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -62,3 +62,23 @@ func TestUint64ToString(t *testing.T) {
 		})
 	}
 }
+
+func TestFloat64ToString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    float64
+		expected string
+	}{
+		{"zero", 0, "0"},
+		{"whole number", 13, "13"},
+		{"keeps every decimal", 52.5200066, "52.5200066"},
+		{"negative", -0.1278, "-0.1278"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := float64ToString(tt.input)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import "github.com/zi0p4tch0/radiogogo/i18n"
+
+// FacetKind identifies one of RadioBrowser's facet listings: the distinct values
+// of a station attribute, each with the number of stations using it.
+type FacetKind string
+
+// The following constants represent the facet listings exposed by the RadioBrowser API.
+const (
+	FacetCountries    FacetKind = "countries"    // Country names (with ISO 3166-1 code).
+	FacetCountryCodes FacetKind = "countrycodes" // ISO 3166-1 alpha-2 country codes.
+	FacetStates       FacetKind = "states"       // States/regions (with their country).
+	FacetLanguages    FacetKind = "languages"    // Languages (with ISO 639 code).
+	FacetTags         FacetKind = "tags"         // Free-form station tags.
+	FacetCodecs       FacetKind = "codecs"       // Stream codecs.
+)
+
+// AllFacetKinds returns every facet listing, in display order.
+func AllFacetKinds() []FacetKind {
+	return []FacetKind{
+		FacetCountries,
+		FacetCountryCodes,
+		FacetStates,
+		FacetLanguages,
+		FacetTags,
+		FacetCodecs,
+	}
+}
+
+func (k FacetKind) Render() string {
+	switch k {
+	case FacetCountries:
+		return i18n.T("facet_countries")
+	case FacetCountryCodes:
+		return i18n.T("facet_countrycodes")
+	case FacetStates:
+		return i18n.T("facet_states")
+	case FacetLanguages:
+		return i18n.T("facet_languages")
+	case FacetTags:
+		return i18n.T("facet_tags")
+	case FacetCodecs:
+		return i18n.T("facet_codecs")
+	}
+	return string(k)
+}
+
+// Facet is a single entry of a facet listing.
+// Only Name and StationCount are set for every kind; the other fields
+// are filled in by the listings that provide them.
+type Facet struct {
+	// Name is the facet value (country name, tag, codec...).
+	Name string `json:"name"`
+	// Iso3166_1 is the country code (countries only).
+	Iso3166_1 string `json:"iso_3166_1"`
+	// Iso639 is the language code (languages only).
+	Iso639 string `json:"iso_639"`
+	// Country is the country the state belongs to (states only).
+	Country string `json:"country"`
+	// StationCount is the number of stations with this value.
+	StationCount uint64 `json:"stationcount"`
+}
+
+// ExactQuery returns the station query and search term that list the stations
+// matching this facet value exactly.
+// Countries are searched by code when one is known, since names are not unique
+// across RadioBrowser's data.
+func (f Facet) ExactQuery(kind FacetKind) (StationQuery, string) {
+	switch kind {
+	case FacetCountries:
+		if f.Iso3166_1 != "" {
+			return StationQueryByCountryCodeExact, f.Iso3166_1
+		}
+		return StationQueryByCountryExact, f.Name
+	case FacetCountryCodes:
+		return StationQueryByCountryCodeExact, f.Name
+	case FacetStates:
+		return StationQueryByStateExact, f.Name
+	case FacetLanguages:
+		return StationQueryByLanguageExact, f.Name
+	case FacetTags:
+		return StationQueryByTagExact, f.Name
+	case FacetCodecs:
+		return StationQueryByCodecExact, f.Name
+	}
+	return StationQueryByName, f.Name
+}
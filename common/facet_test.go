@@ -0,0 +1,102 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFacet_JSONUnmarshal(t *testing.T) {
+	t.Run("parses country facet", func(t *testing.T) {
+		jsonData := `{"name": "Germany", "iso_3166_1": "DE", "stationcount": 3521}`
+
+		var facet Facet
+		err := json.Unmarshal([]byte(jsonData), &facet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Germany", facet.Name)
+		assert.Equal(t, "DE", facet.Iso3166_1)
+		assert.Equal(t, uint64(3521), facet.StationCount)
+	})
+
+	t.Run("parses state facet", func(t *testing.T) {
+		jsonData := `{"name": "Bavaria", "country": "Germany", "stationcount": 412}`
+
+		var facet Facet
+		err := json.Unmarshal([]byte(jsonData), &facet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bavaria", facet.Name)
+		assert.Equal(t, "Germany", facet.Country)
+		assert.Equal(t, uint64(412), facet.StationCount)
+	})
+
+	t.Run("parses language facet", func(t *testing.T) {
+		jsonData := `{"name": "german", "iso_639": "de", "stationcount": 2800}`
+
+		var facet Facet
+		err := json.Unmarshal([]byte(jsonData), &facet)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "german", facet.Name)
+		assert.Equal(t, "de", facet.Iso639)
+	})
+
+	t.Run("parses tag facet list", func(t *testing.T) {
+		jsonData := `[{"name": "pop", "stationcount": 5000}, {"name": "jazz", "stationcount": 1200}]`
+
+		var facets []Facet
+		err := json.Unmarshal([]byte(jsonData), &facets)
+
+		assert.NoError(t, err)
+		assert.Len(t, facets, 2)
+		assert.Equal(t, "jazz", facets[1].Name)
+		assert.Equal(t, uint64(1200), facets[1].StationCount)
+	})
+}
+
+func TestFacet_ExactQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          FacetKind
+		facet         Facet
+		expectedQuery StationQuery
+		expectedTerm  string
+	}{
+		{"country with code", FacetCountries, Facet{Name: "Germany", Iso3166_1: "DE"}, StationQueryByCountryCodeExact, "DE"},
+		{"country without code", FacetCountries, Facet{Name: "Germany"}, StationQueryByCountryExact, "Germany"},
+		{"country code", FacetCountryCodes, Facet{Name: "IT"}, StationQueryByCountryCodeExact, "IT"},
+		{"state", FacetStates, Facet{Name: "Bavaria", Country: "Germany"}, StationQueryByStateExact, "Bavaria"},
+		{"language", FacetLanguages, Facet{Name: "italian", Iso639: "it"}, StationQueryByLanguageExact, "italian"},
+		{"tag", FacetTags, Facet{Name: "jazz"}, StationQueryByTagExact, "jazz"},
+		{"codec", FacetCodecs, Facet{Name: "AAC"}, StationQueryByCodecExact, "AAC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, term := tt.facet.ExactQuery(tt.kind)
+			assert.Equal(t, tt.expectedQuery, query)
+			assert.Equal(t, tt.expectedTerm, term)
+		})
+	}
+}
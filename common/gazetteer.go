@@ -0,0 +1,199 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// City is an entry of the built-in gazetteer used to search for stations near a place.
+type City struct {
+	// Name is the English name of the city.
+	Name string
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country the city is in.
+	CountryCode string
+	// Location is the position of the city centre.
+	Location GeoPoint
+}
+
+// String formats the city as "Name, CC".
+func (c City) String() string {
+	return c.Name + ", " + c.CountryCode
+}
+
+// cities is a small gazetteer of capitals and large cities, enough to find stations
+// nearby without typing coordinates. It isn't meant to be exhaustive.
+var cities = []City{
+	// Europe
+	{"Amsterdam", "NL", GeoPoint{52.3676, 4.9041}},
+	{"Athens", "GR", GeoPoint{37.9838, 23.7275}},
+	{"Barcelona", "ES", GeoPoint{41.3874, 2.1686}},
+	{"Belgrade", "RS", GeoPoint{44.7866, 20.4489}},
+	{"Berlin", "DE", GeoPoint{52.5200, 13.4050}},
+	{"Bern", "CH", GeoPoint{46.9480, 7.4474}},
+	{"Bologna", "IT", GeoPoint{44.4949, 11.3426}},
+	{"Bratislava", "SK", GeoPoint{48.1486, 17.1077}},
+	{"Brussels", "BE", GeoPoint{50.8503, 4.3517}},
+	{"Bucharest", "RO", GeoPoint{44.4268, 26.1025}},
+	{"Budapest", "HU", GeoPoint{47.4979, 19.0402}},
+	{"Cologne", "DE", GeoPoint{50.9375, 6.9603}},
+	{"Copenhagen", "DK", GeoPoint{55.6761, 12.5683}},
+	{"Dublin", "IE", GeoPoint{53.3498, -6.2603}},
+	{"Edinburgh", "GB", GeoPoint{55.9533, -3.1883}},
+	{"Florence", "IT", GeoPoint{43.7696, 11.2558}},
+	{"Frankfurt", "DE", GeoPoint{50.1109, 8.6821}},
+	{"Geneva", "CH", GeoPoint{46.2044, 6.1432}},
+	{"Hamburg", "DE", GeoPoint{53.5511, 9.9937}},
+	{"Helsinki", "FI", GeoPoint{60.1699, 24.9384}},
+	{"Istanbul", "TR", GeoPoint{41.0082, 28.9784}},
+	{"Kyiv", "UA", GeoPoint{50.4501, 30.5234}},
+	{"Lisbon", "PT", GeoPoint{38.7223, -9.1393}},
+	{"Ljubljana", "SI", GeoPoint{46.0569, 14.5058}},
+	{"London", "GB", GeoPoint{51.5074, -0.1278}},
+	{"Lyon", "FR", GeoPoint{45.7640, 4.8357}},
+	{"Madrid", "ES", GeoPoint{40.4168, -3.7038}},
+	{"Manchester", "GB", GeoPoint{53.4808, -2.2426}},
+	{"Marseille", "FR", GeoPoint{43.2965, 5.3698}},
+	{"Milan", "IT", GeoPoint{45.4642, 9.1900}},
+	{"Minsk", "BY", GeoPoint{53.9006, 27.5590}},
+	{"Moscow", "RU", GeoPoint{55.7558, 37.6173}},
+	{"Munich", "DE", GeoPoint{48.1351, 11.5820}},
+	{"Naples", "IT", GeoPoint{40.8518, 14.2681}},
+	{"Oslo", "NO", GeoPoint{59.9139, 10.7522}},
+	{"Paris", "FR", GeoPoint{48.8566, 2.3522}},
+	{"Porto", "PT", GeoPoint{41.1579, -8.6291}},
+	{"Prague", "CZ", GeoPoint{50.0755, 14.4378}},
+	{"Reykjavik", "IS", GeoPoint{64.1466, -21.9426}},
+	{"Riga", "LV", GeoPoint{56.9496, 24.1052}},
+	{"Rome", "IT", GeoPoint{41.9028, 12.4964}},
+	{"Saint Petersburg", "RU", GeoPoint{59.9311, 30.3609}},
+	{"Seville", "ES", GeoPoint{37.3891, -5.9845}},
+	{"Sofia", "BG", GeoPoint{42.6977, 23.3219}},
+	{"Stockholm", "SE", GeoPoint{59.3293, 18.0686}},
+	{"Tallinn", "EE", GeoPoint{59.4370, 24.7536}},
+	{"Thessaloniki", "GR", GeoPoint{40.6401, 22.9444}},
+	{"Turin", "IT", GeoPoint{45.0703, 7.6869}},
+	{"Valencia", "ES", GeoPoint{39.4699, -0.3763}},
+	{"Vienna", "AT", GeoPoint{48.2082, 16.3738}},
+	{"Vilnius", "LT", GeoPoint{54.6872, 25.2797}},
+	{"Warsaw", "PL", GeoPoint{52.2297, 21.0122}},
+	{"Zagreb", "HR", GeoPoint{45.8150, 15.9819}},
+	{"Zürich", "CH", GeoPoint{47.3769, 8.5417}},
+
+	// Americas
+	{"Bogotá", "CO", GeoPoint{4.7110, -74.0721}},
+	{"Buenos Aires", "AR", GeoPoint{-34.6037, -58.3816}},
+	{"Chicago", "US", GeoPoint{41.8781, -87.6298}},
+	{"Havana", "CU", GeoPoint{23.1136, -82.3666}},
+	{"Lima", "PE", GeoPoint{-12.0464, -77.0428}},
+	{"Los Angeles", "US", GeoPoint{34.0522, -118.2437}},
+	{"Mexico City", "MX", GeoPoint{19.4326, -99.1332}},
+	{"Montreal", "CA", GeoPoint{45.5017, -73.5673}},
+	{"New York", "US", GeoPoint{40.7128, -74.0060}},
+	{"Rio de Janeiro", "BR", GeoPoint{-22.9068, -43.1729}},
+	{"San Francisco", "US", GeoPoint{37.7749, -122.4194}},
+	{"Santiago", "CL", GeoPoint{-33.4489, -70.6693}},
+	{"São Paulo", "BR", GeoPoint{-23.5505, -46.6333}},
+	{"Toronto", "CA", GeoPoint{43.6532, -79.3832}},
+	{"Vancouver", "CA", GeoPoint{49.2827, -123.1207}},
+	{"Washington", "US", GeoPoint{38.9072, -77.0369}},
+
+	// Asia and Oceania
+	{"Auckland", "NZ", GeoPoint{-36.8485, 174.7633}},
+	{"Bangkok", "TH", GeoPoint{13.7563, 100.5018}},
+	{"Beijing", "CN", GeoPoint{39.9042, 116.4074}},
+	{"Dubai", "AE", GeoPoint{25.2048, 55.2708}},
+	{"Hong Kong", "HK", GeoPoint{22.3193, 114.1694}},
+	{"Jakarta", "ID", GeoPoint{-6.2088, 106.8456}},
+	{"Manila", "PH", GeoPoint{14.5995, 120.9842}},
+	{"Melbourne", "AU", GeoPoint{-37.8136, 144.9631}},
+	{"Mumbai", "IN", GeoPoint{19.0760, 72.8777}},
+	{"New Delhi", "IN", GeoPoint{28.6139, 77.2090}},
+	{"Osaka", "JP", GeoPoint{34.6937, 135.5023}},
+	{"Seoul", "KR", GeoPoint{37.5665, 126.9780}},
+	{"Shanghai", "CN", GeoPoint{31.2304, 121.4737}},
+	{"Singapore", "SG", GeoPoint{1.3521, 103.8198}},
+	{"Sydney", "AU", GeoPoint{-33.8688, 151.2093}},
+	{"Taipei", "TW", GeoPoint{25.0330, 121.5654}},
+	{"Tel Aviv", "IL", GeoPoint{32.0853, 34.7818}},
+	{"Tokyo", "JP", GeoPoint{35.6762, 139.6503}},
+
+	// Africa
+	{"Cairo", "EG", GeoPoint{30.0444, 31.2357}},
+	{"Cape Town", "ZA", GeoPoint{-33.9249, 18.4241}},
+	{"Casablanca", "MA", GeoPoint{33.5731, -7.5898}},
+	{"Johannesburg", "ZA", GeoPoint{-26.2041, 28.0473}},
+	{"Lagos", "NG", GeoPoint{6.5244, 3.3792}},
+	{"Nairobi", "KE", GeoPoint{-1.2921, 36.8219}},
+}
+
+// Cities returns every city in the built-in gazetteer, sorted by name within each region.
+func Cities() []City {
+	return append([]City(nil), cities...)
+}
+
+// FindCities returns the cities whose name starts with query, ignoring case and accents
+// (so "sao" finds São Paulo). The query may end with a country code, as in "valencia, es".
+// Exact name matches come first; at most limit cities are returned (0 means no limit).
+func FindCities(query string, limit int) []City {
+	name, countryCode := query, ""
+	if i := strings.LastIndex(query, ","); i >= 0 {
+		name, countryCode = query[:i], strings.ToUpper(strings.TrimSpace(query[i+1:]))
+	}
+	name = foldCityName(name)
+	if name == "" {
+		return nil
+	}
+
+	var exact, prefix []City
+	for _, city := range cities {
+		if countryCode != "" && city.CountryCode != countryCode {
+			continue
+		}
+		folded := foldCityName(city.Name)
+		switch {
+		case folded == name:
+			exact = append(exact, city)
+		case strings.HasPrefix(folded, name):
+			prefix = append(prefix, city)
+		}
+	}
+
+	found := append(exact, prefix...)
+	if limit > 0 && len(found) > limit {
+		found = found[:limit]
+	}
+	return found
+}
+
+// foldCityName lowercases a name and strips accents and surrounding spaces for matching.
+func foldCityName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, name)
+	if err != nil {
+		folded = name
+	}
+	return strings.ToLower(strings.TrimSpace(folded))
+}
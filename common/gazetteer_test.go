@@ -0,0 +1,82 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCities(t *testing.T) {
+	t.Run("every city has a valid location and country code", func(t *testing.T) {
+		for _, city := range Cities() {
+			assert.True(t, city.Location.IsValid(), city.Name)
+			assert.Len(t, city.CountryCode, 2, city.Name)
+		}
+	})
+
+	t.Run("names are unique within a country", func(t *testing.T) {
+		seen := map[string]bool{}
+		for _, city := range Cities() {
+			assert.False(t, seen[city.String()], city.String())
+			seen[city.String()] = true
+		}
+	})
+}
+
+func TestFindCities(t *testing.T) {
+	names := func(cities []City) []string {
+		result := []string{}
+		for _, city := range cities {
+			result = append(result, city.Name)
+		}
+		return result
+	}
+
+	t.Run("finds a city by exact name ignoring case", func(t *testing.T) {
+		found := FindCities("berlin", 0)
+		assert.Equal(t, []string{"Berlin"}, names(found))
+		assert.Equal(t, "DE", found[0].CountryCode)
+	})
+
+	t.Run("finds every city starting with the query", func(t *testing.T) {
+		assert.Equal(t, []string{"Berlin", "Bern"}, names(FindCities("Ber", 0)))
+		assert.Equal(t, []string{"Bern"}, names(FindCities("Bern", 0)))
+	})
+
+	t.Run("ignores accents", func(t *testing.T) {
+		assert.Equal(t, []string{"São Paulo"}, names(FindCities("sao p", 0)))
+		assert.Equal(t, []string{"Zürich"}, names(FindCities("Zurich", 0)))
+	})
+
+	t.Run("filters by country code", func(t *testing.T) {
+		assert.Equal(t, []string{"Santiago"}, names(FindCities("santiago, cl", 0)))
+		assert.Empty(t, FindCities("santiago, es", 0))
+	})
+
+	t.Run("limits the number of results", func(t *testing.T) {
+		assert.Len(t, FindCities("B", 3), 3)
+	})
+
+	t.Run("returns nothing for an empty query", func(t *testing.T) {
+		assert.Empty(t, FindCities("  ", 0))
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// earthRadiusKm is the mean radius of the Earth, used for great-circle distances.
+const earthRadiusKm = 6371.0
+
+// GeoPoint is a position on Earth in decimal degrees.
+type GeoPoint struct {
+	Lat  float64 `json:"lat"`
+	Long float64 `json:"long"`
+}
+
+// IsValid reports whether the latitude and longitude are within range.
+func (p GeoPoint) IsValid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Long >= -180 && p.Long <= 180
+}
+
+// DistanceKm returns the great-circle distance between p and q in kilometers (haversine formula).
+func (p GeoPoint) DistanceKm(q GeoPoint) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRadians(q.Lat - p.Lat)
+	dLong := toRadians(q.Long - p.Long)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(p.Lat))*math.Cos(toRadians(q.Lat))*math.Sin(dLong/2)*math.Sin(dLong/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// String formats the point as "lat, long", the format ParseGeoPoint accepts.
+func (p GeoPoint) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + ", " + strconv.FormatFloat(p.Long, 'f', -1, 64)
+}
+
+// ErrInvalidGeoPoint is returned by ParseGeoPoint for text that isn't a valid "lat, long" pair.
+var ErrInvalidGeoPoint = errors.New("invalid coordinates")
+
+// ParseGeoPoint parses coordinates written as "lat, long" in decimal degrees,
+// e.g. "52.52, 13.405". The comma may be replaced by spaces.
+func ParseGeoPoint(s string) (GeoPoint, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t'
+	})
+	if len(parts) != 2 {
+		return GeoPoint{}, ErrInvalidGeoPoint
+	}
+	lat, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return GeoPoint{}, ErrInvalidGeoPoint
+	}
+	long, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return GeoPoint{}, ErrInvalidGeoPoint
+	}
+	p := GeoPoint{Lat: lat, Long: long}
+	if !p.IsValid() || math.IsNaN(lat) || math.IsNaN(long) {
+		return GeoPoint{}, fmt.Errorf("%w: out of range", ErrInvalidGeoPoint)
+	}
+	return p, nil
+}
+
+// Location returns the station's coordinates, if RadioBrowser knows them.
+func (s Station) Location() (GeoPoint, bool) {
+	if s.GeoLat == nil || s.GeoLong == nil {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: *s.GeoLat, Long: *s.GeoLong}, true
+}
+
+// SortByDistance sorts stations nearest-first from origin.
+// Stations without coordinates are moved to the end, in their original order.
+func SortByDistance(stations []Station, origin GeoPoint) {
+	distance := func(s Station) float64 {
+		if location, ok := s.Location(); ok {
+			return origin.DistanceKm(location)
+		}
+		return math.Inf(1)
+	}
+	sort.SliceStable(stations, func(i, j int) bool {
+		return distance(stations[i]) < distance(stations[j])
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoPoint_DistanceKm(t *testing.T) {
+	berlin := GeoPoint{Lat: 52.5200, Long: 13.4050}
+	paris := GeoPoint{Lat: 48.8566, Long: 2.3522}
+	sydney := GeoPoint{Lat: -33.8688, Long: 151.2093}
+
+	t.Run("is zero for the same point", func(t *testing.T) {
+		assert.Equal(t, 0.0, berlin.DistanceKm(berlin))
+	})
+
+	t.Run("matches known distances", func(t *testing.T) {
+		assert.InDelta(t, 878, berlin.DistanceKm(paris), 5)
+		assert.InDelta(t, 16000, berlin.DistanceKm(sydney), 100)
+	})
+
+	t.Run("is symmetric", func(t *testing.T) {
+		assert.InDelta(t, berlin.DistanceKm(paris), paris.DistanceKm(berlin), 1e-9)
+	})
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	t.Run("parses comma and space separated coordinates", func(t *testing.T) {
+		for _, input := range []string{"52.52, 13.405", "52.52,13.405", " 52.52 13.405 "} {
+			p, err := ParseGeoPoint(input)
+			assert.NoError(t, err, input)
+			assert.Equal(t, GeoPoint{Lat: 52.52, Long: 13.405}, p, input)
+		}
+	})
+
+	t.Run("round-trips through String", func(t *testing.T) {
+		p := GeoPoint{Lat: -33.8688, Long: 151.2093}
+		parsed, err := ParseGeoPoint(p.String())
+		assert.NoError(t, err)
+		assert.Equal(t, p, parsed)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		for _, input := range []string{"", "Berlin", "52.52", "52.52, 13.4, 1", "91, 0", "0, 181", "north, east"} {
+			_, err := ParseGeoPoint(input)
+			assert.ErrorIs(t, err, ErrInvalidGeoPoint, input)
+		}
+	})
+}
+
+func TestSortByDistance(t *testing.T) {
+	at := func(name string, lat, long float64) Station {
+		return Station{StationUuid: uuid.New(), Name: name, GeoLat: &lat, GeoLong: &long}
+	}
+	unknown := Station{StationUuid: uuid.New(), Name: "Unknown"}
+	stations := []Station{
+		unknown,
+		at("Paris", 48.8566, 2.3522),
+		at("Potsdam", 52.3906, 13.0645),
+		at("Hamburg", 53.5511, 9.9937),
+	}
+
+	SortByDistance(stations, GeoPoint{Lat: 52.5200, Long: 13.4050})
+
+	names := []string{}
+	for _, s := range stations {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"Potsdam", "Hamburg", "Paris", "Unknown"}, names)
+}
+
+func TestStation_Location(t *testing.T) {
+	lat, long := 45.4642, 9.19
+
+	location, ok := Station{GeoLat: &lat, GeoLong: &long}.Location()
+	assert.True(t, ok)
+	assert.Equal(t, GeoPoint{Lat: lat, Long: long}, location)
+
+	_, ok = Station{GeoLat: &lat}.Location()
+	assert.False(t, ok)
+}
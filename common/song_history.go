@@ -0,0 +1,95 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SongHistoryEntry is a song heard on a station, as announced by its stream.
+type SongHistoryEntry struct {
+	StationUuid uuid.UUID
+	StationName string
+	Title       string
+	StartedAt   time.Time
+	// EndedAt is zero while the song plays, or if the app quit before it ended.
+	EndedAt time.Time
+}
+
+// songHistoryRecord is the exported form of a SongHistoryEntry.
+type songHistoryRecord struct {
+	StationUuid string  `json:"stationuuid"`
+	StationName string  `json:"station_name"`
+	Title       string  `json:"title"`
+	StartedAt   string  `json:"started_at"`
+	EndedAt     *string `json:"ended_at"`
+}
+
+func newSongHistoryRecord(entry SongHistoryEntry) songHistoryRecord {
+	record := songHistoryRecord{
+		StationUuid: entry.StationUuid.String(),
+		StationName: entry.StationName,
+		Title:       entry.Title,
+		StartedAt:   entry.StartedAt.Format(time.RFC3339),
+	}
+	if !entry.EndedAt.IsZero() {
+		endedAt := entry.EndedAt.Format(time.RFC3339)
+		record.EndedAt = &endedAt
+	}
+	return record
+}
+
+// WriteSongHistoryCSV writes entries as CSV, with a header row.
+// Songs that haven't ended have an empty end time.
+func WriteSongHistoryCSV(w io.Writer, entries []SongHistoryEntry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"started_at", "ended_at", "station_name", "title", "stationuuid"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		record := newSongHistoryRecord(entry)
+		endedAt := ""
+		if record.EndedAt != nil {
+			endedAt = *record.EndedAt
+		}
+		if err := writer.Write([]string{record.StartedAt, endedAt, record.StationName, record.Title, record.StationUuid}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteSongHistoryJSON writes entries as an indented JSON array.
+// Songs that haven't ended have a null end time.
+func WriteSongHistoryJSON(w io.Writer, entries []SongHistoryEntry) error {
+	records := make([]songHistoryRecord, len(entries))
+	for i, entry := range entries {
+		records[i] = newSongHistoryRecord(entry)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSongHistoryExport(t *testing.T) {
+
+	stationUUID := uuid.MustParse("96062a7b-0601-11e8-ae97-52543be04c81")
+	startedAt := time.Date(2026, 3, 1, 15, 2, 0, 0, time.UTC)
+	entries := []SongHistoryEntry{
+		{
+			StationUuid: stationUUID,
+			StationName: "Team Radio",
+			Title:       "Daft Punk - One More Time",
+			StartedAt:   startedAt,
+			EndedAt:     startedAt.Add(5 * time.Minute),
+		},
+		{
+			StationUuid: stationUUID,
+			StationName: "Team Radio",
+			Title:       `Song, with "quotes"`,
+			StartedAt:   startedAt.Add(5 * time.Minute),
+		},
+	}
+
+	t.Run("writes CSV with a header row", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		assert.NoError(t, WriteSongHistoryCSV(&buf, entries))
+
+		assert.Equal(t, "started_at,ended_at,station_name,title,stationuuid\n"+
+			"2026-03-01T15:02:00Z,2026-03-01T15:07:00Z,Team Radio,Daft Punk - One More Time,96062a7b-0601-11e8-ae97-52543be04c81\n"+
+			"2026-03-01T15:07:00Z,,Team Radio,\"Song, with \"\"quotes\"\"\",96062a7b-0601-11e8-ae97-52543be04c81\n",
+			buf.String())
+	})
+
+	t.Run("writes JSON with null end times for unfinished songs", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		assert.NoError(t, WriteSongHistoryJSON(&buf, entries))
+
+		assert.JSONEq(t, `[
+			{"stationuuid": "96062a7b-0601-11e8-ae97-52543be04c81", "station_name": "Team Radio",
+			 "title": "Daft Punk - One More Time", "started_at": "2026-03-01T15:02:00Z", "ended_at": "2026-03-01T15:07:00Z"},
+			{"stationuuid": "96062a7b-0601-11e8-ae97-52543be04c81", "station_name": "Team Radio",
+			 "title": "Song, with \"quotes\"", "started_at": "2026-03-01T15:07:00Z", "ended_at": null}
+		]`, buf.String())
+	})
+
+	t.Run("writes an empty list", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		assert.NoError(t, WriteSongHistoryJSON(&buf, nil))
+
+		assert.JSONEq(t, `[]`, buf.String())
+	})
+}
@@ -93,6 +93,19 @@ type Station struct {
 	// Is true, if the stream owner does provide extended information as HTTP headers
 	// which override the information in the database.
 	HasExtendedInfo *bool `json:"has_extended_info,omitempty"`
+
+	// Where the station was found (not part of the RadioBrowser API).
+	// Empty for RadioBrowser stations.
+	Source StationSource `json:"source,omitempty"`
+}
+
+// StreamURL returns the URL to stream the station from: the resolved URL when
+// known, otherwise the URL provided by the user.
+func (s Station) StreamURL() string {
+	if resolved := s.UrlResolved.URL.String(); resolved != "" {
+		return resolved
+	}
+	return s.Url.URL.String()
 }
 
 func (bi *BoolFromlInt) UnmarshalJSON(data []byte) error {
@@ -0,0 +1,68 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import "github.com/zi0p4tch0/radiogogo/i18n"
+
+// StationList identifies one of RadioBrowser's ready-made station lists
+// (/json/stations/topclick, /json/stations/topvote...).
+type StationList string
+
+// The following constants represent the station lists exposed by the RadioBrowser API.
+const (
+	StationListTopClick   StationList = "topclick"   // Most clicked stations (trending).
+	StationListTopVote    StationList = "topvote"    // Most voted stations.
+	StationListLastClick  StationList = "lastclick"  // Most recently clicked stations.
+	StationListLastChange StationList = "lastchange" // Most recently added or changed stations.
+)
+
+// AllStationLists returns every station list, in display order.
+func AllStationLists() []StationList {
+	return []StationList{
+		StationListTopClick,
+		StationListTopVote,
+		StationListLastClick,
+		StationListLastChange,
+	}
+}
+
+// IsValid reports whether l is one of the lists supported by the API.
+func (l StationList) IsValid() bool {
+	for _, list := range AllStationLists() {
+		if l == list {
+			return true
+		}
+	}
+	return false
+}
+
+func (l StationList) Render() string {
+	switch l {
+	case StationListTopClick:
+		return i18n.T("discover_topclick")
+	case StationListTopVote:
+		return i18n.T("discover_topvote")
+	case StationListLastClick:
+		return i18n.T("discover_lastclick")
+	case StationListLastChange:
+		return i18n.T("discover_lastchange")
+	}
+	return string(l)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStationList_IsValid(t *testing.T) {
+	for _, list := range AllStationLists() {
+		assert.True(t, list.IsValid(), string(list))
+	}
+	assert.False(t, StationList("").IsValid())
+	assert.False(t, StationList("topsecret").IsValid())
+}
+
+func TestStationList_Render(t *testing.T) {
+
+	tests := []struct {
+		list     StationList
+		expected string
+	}{
+		{StationListTopClick, "Trending"},
+		{StationListTopVote, "Most Voted"},
+		{StationListLastClick, "Recently Played"},
+		{StationListLastChange, "Recently Changed"},
+		{StationList("topsecret"), "topsecret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.list.Render())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import "github.com/zi0p4tch0/radiogogo/i18n"
+
+// StationOrder represents a field RadioBrowser can sort station results by.
+type StationOrder string
+
+// The following constants represent the sort fields supported by the RadioBrowser API.
+const (
+	StationOrderName           StationOrder = "name"           // Sorts by station name.
+	StationOrderVotes          StationOrder = "votes"          // Sorts by number of votes.
+	StationOrderClickCount     StationOrder = "clickcount"     // Sorts by clicks within the last 24 hours.
+	StationOrderClickTrend     StationOrder = "clicktrend"     // Sorts by click trend over the last 2 days.
+	StationOrderBitrate        StationOrder = "bitrate"        // Sorts by stream bitrate.
+	StationOrderCodec          StationOrder = "codec"          // Sorts by stream codec.
+	StationOrderCountry        StationOrder = "country"        // Sorts by country name.
+	StationOrderLastChangeTime StationOrder = "lastchangetime" // Sorts by last change of the station information.
+	StationOrderRandom         StationOrder = "random"         // Returns stations in random order.
+)
+
+// AllStationOrders returns every supported sort field, in display order.
+func AllStationOrders() []StationOrder {
+	return []StationOrder{
+		StationOrderVotes,
+		StationOrderClickCount,
+		StationOrderClickTrend,
+		StationOrderName,
+		StationOrderBitrate,
+		StationOrderCodec,
+		StationOrderCountry,
+		StationOrderLastChangeTime,
+		StationOrderRandom,
+	}
+}
+
+// IsValid reports whether o is one of the sort fields supported by the API.
+func (o StationOrder) IsValid() bool {
+	for _, order := range AllStationOrders() {
+		if o == order {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the sort field following o in display order, wrapping around.
+// Unknown values start over from the first field.
+func (o StationOrder) Next() StationOrder {
+	orders := AllStationOrders()
+	for i, order := range orders {
+		if o == order {
+			return orders[(i+1)%len(orders)]
+		}
+	}
+	return orders[0]
+}
+
+// DefaultReverse returns whether results are best shown in descending order for this field.
+// Numeric and time-based fields put the highest/newest first; text fields sort A-Z.
+func (o StationOrder) DefaultReverse() bool {
+	switch o {
+	case StationOrderVotes, StationOrderClickCount, StationOrderClickTrend,
+		StationOrderBitrate, StationOrderLastChangeTime:
+		return true
+	}
+	return false
+}
+
+func (o StationOrder) Render() string {
+	switch o {
+	case StationOrderName:
+		return i18n.T("order_name")
+	case StationOrderVotes:
+		return i18n.T("order_votes")
+	case StationOrderClickCount:
+		return i18n.T("order_clickcount")
+	case StationOrderClickTrend:
+		return i18n.T("order_clicktrend")
+	case StationOrderBitrate:
+		return i18n.T("order_bitrate")
+	case StationOrderCodec:
+		return i18n.T("order_codec")
+	case StationOrderCountry:
+		return i18n.T("order_country")
+	case StationOrderLastChangeTime:
+		return i18n.T("order_lastchangetime")
+	case StationOrderRandom:
+		return i18n.T("order_random")
+	}
+	return string(o)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStationOrder_IsValid(t *testing.T) {
+	for _, order := range AllStationOrders() {
+		assert.True(t, order.IsValid(), string(order))
+	}
+	assert.False(t, StationOrder("").IsValid())
+	assert.False(t, StationOrder("loudness").IsValid())
+}
+
+func TestStationOrder_Next(t *testing.T) {
+	t.Run("visits every order before wrapping around", func(t *testing.T) {
+		orders := AllStationOrders()
+		order := orders[0]
+		for i := 1; i < len(orders); i++ {
+			order = order.Next()
+			assert.Equal(t, orders[i], order)
+		}
+		assert.Equal(t, orders[0], order.Next())
+	})
+
+	t.Run("unknown order starts from the first one", func(t *testing.T) {
+		assert.Equal(t, AllStationOrders()[0], StationOrder("loudness").Next())
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package common
+
+// StationSearchParams holds the criteria for a multi-field station search
+// (RadioBrowser's /json/stations/search endpoint).
+// Zero values mean "no filter" for that criterion; all set criteria must match.
+type StationSearchParams struct {
+	// Name matches stations whose name contains this text.
+	Name string
+	// TagList matches stations that have all of these tags.
+	TagList []string
+	// CountryCode matches stations in this country (ISO 3166-1 alpha-2).
+	CountryCode string
+	// State matches stations whose state contains this text.
+	State string
+	// Language matches stations whose language contains this text.
+	Language string
+	// Codec matches stations using this codec.
+	Codec string
+	// BitrateMin matches stations with at least this bitrate (kbps).
+	BitrateMin uint64
+	// BitrateMax matches stations with at most this bitrate (kbps). Zero means no upper bound.
+	BitrateMax uint64
+	// HasGeoInfo, if set, matches stations with (true) or without (false) coordinates.
+	HasGeoInfo *bool
+	// IsHttps, if set, matches stations with (true) or without (false) an HTTPS stream URL.
+	IsHttps *bool
+	// Near, if set, matches stations located within GeoDistance of this point.
+	Near *GeoPoint
+	// GeoDistance is the search radius around Near, in meters.
+	GeoDistance uint64
+	// Order is the field to sort results by. Empty uses the API default (name).
+	Order StationOrder
+	// Reverse sorts results in descending order.
+	Reverse bool
+	// Offset is the number of results to skip.
+	Offset uint64
+	// Limit is the maximum number of results to return.
+	Limit uint64
+	// HideBroken excludes stations that failed the last RadioBrowser check.
+	HideBroken bool
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package common
+
+import "github.com/zi0p4tch0/radiogogo/i18n"
+
+// StationSource identifies where a station was found.
+type StationSource string
+
+// The following constants represent the places stations can come from.
+const (
+	StationSourceRadioBrowser StationSource = ""          // Found on RadioBrowser.
+	StationSourceCustom       StationSource = "custom"    // Added by the user.
+	StationSourcePlaylists    StationSource = "playlists" // Listed in a local playlist file.
+	StationSourceIcecast      StationSource = "icecast"   // Listed in an Icecast directory.
+)
+
+func (s StationSource) Render() string {
+	switch s {
+	case StationSourceRadioBrowser:
+		return i18n.T("source_radiobrowser")
+	case StationSourceCustom:
+		return i18n.T("source_custom")
+	case StationSourcePlaylists:
+		return i18n.T("source_playlists")
+	case StationSourceIcecast:
+		return i18n.T("source_icecast")
+	}
+	return string(s)
+}
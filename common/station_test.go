@@ -327,3 +327,21 @@ func TestStation_JSONUnmarshal(t *testing.T) {
 		assert.Equal(t, "http://example.com/stream?param=value&other=123", station.Url.URL.String())
 	})
 }
+
+func TestStation_StreamURL(t *testing.T) {
+	t.Run("prefers the resolved URL", func(t *testing.T) {
+		var station Station
+		err := json.Unmarshal([]byte(`{"url": "http://example.com/list.pls", "url_resolved": "http://example.com/stream"}`), &station)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com/stream", station.StreamURL())
+	})
+
+	t.Run("falls back to the URL", func(t *testing.T) {
+		var station Station
+		err := json.Unmarshal([]byte(`{"url": "http://example.com/stream", "url_resolved": ""}`), &station)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://example.com/stream", station.StreamURL())
+	})
+}
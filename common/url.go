@@ -48,3 +48,8 @@ func (m *RadioGoGoURL) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// MarshalJSON encodes the URL as a JSON string, the same shape UnmarshalJSON reads.
+func (m RadioGoGoURL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.URL.String())
+}
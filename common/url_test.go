@@ -20,6 +20,7 @@
 package common
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,3 +67,27 @@ func TestRadioGoGoURL_UnmarshalJSON(t *testing.T) {
 		assert.Equal(t, "high", u.URL.Query().Get("quality"))
 	})
 }
+
+func TestRadioGoGoURL_MarshalJSON(t *testing.T) {
+
+	t.Run("marshals as a string", func(t *testing.T) {
+		var u RadioGoGoURL
+		assert.NoError(t, u.UnmarshalJSON([]byte(`"https://example.com/stream?format=mp3"`)))
+
+		data, err := u.MarshalJSON()
+		assert.NoError(t, err)
+		assert.Equal(t, `"https://example.com/stream?format=mp3"`, string(data))
+	})
+
+	t.Run("round-trips a station", func(t *testing.T) {
+		var station Station
+		assert.NoError(t, json.Unmarshal([]byte(`{"name":"Jazz FM","url":"http://jazz.example.com/live","url_resolved":"","lastcheckok":1}`), &station))
+
+		data, err := json.Marshal(station)
+		assert.NoError(t, err)
+
+		var decoded Station
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, station, decoded)
+	})
+}
@@ -22,7 +22,10 @@ package config
 import (
 	"errors"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/zi0p4tch0/radiogogo/common"
 	"gopkg.in/yaml.v3"
 )
 
@@ -31,6 +34,13 @@ type Config struct {
 	Theme             Theme             `yaml:"theme"`
 	Keybindings       Keybindings       `yaml:"keybindings"`
 	PlayerPreferences PlayerPreferences `yaml:"playerPreferences"`
+	API               APIPreferences    `yaml:"api"`
+	Search            SearchPreferences `yaml:"search"`
+	Cache             CachePreferences  `yaml:"cache"`
+
+	Providers ProviderPreferences  `yaml:"providers"`
+	Health    HealthPreferences    `yaml:"health"`
+	Recording RecordingPreferences `yaml:"recording"`
 }
 
 // PlayerPreferences holds user preferences for the audio player.
@@ -38,6 +48,90 @@ type PlayerPreferences struct {
 	// DefaultVolume is the initial volume level (0-100) when starting the application.
 	// If not set or out of range, defaults to 80.
 	DefaultVolume int `yaml:"defaultVolume"`
+	// Backend is the program used to play streams: one of PlayerBackends.
+	// "auto" uses the first installed player, in the order of PlayerBackends.
+	// If not set or unknown, defaults to "auto".
+	Backend string `yaml:"backend"`
+	// ReconnectAttempts is how many times playback is restarted, waiting longer each
+	// time, when the player stops by itself (e.g. the stream drops).
+	// If not set, defaults to 5; a negative value disables reconnecting.
+	ReconnectAttempts int `yaml:"reconnectAttempts"`
+	// TimeshiftBufferMB is how much of the playing station, in megabytes, is kept
+	// while playback is paused, so it can resume where it was paused.
+	// Timeshifting is opt-in: if not set (or negative), streams are played directly.
+	TimeshiftBufferMB int `yaml:"timeshiftBufferMB"`
+}
+
+// PlayerBackends lists the accepted values of PlayerPreferences.Backend.
+// After "auto", players are listed in autodetection priority order.
+var PlayerBackends = []string{"auto", "ffplay", "mpv", "cvlc", "mplayer"}
+
+// APIPreferences holds settings for the RadioBrowser API client.
+type APIPreferences struct {
+	// RequestTimeoutSeconds is how long a single API mirror may take to answer
+	// before the request fails over to the next mirror.
+	// If not set or out of range, defaults to 15.
+	RequestTimeoutSeconds int `yaml:"requestTimeoutSeconds"`
+}
+
+// SearchPreferences holds settings for station searches.
+type SearchPreferences struct {
+	// PageSize is how many stations are fetched at a time. More results are
+	// loaded as the cursor nears the end of the list.
+	// If not set or out of range, defaults to 100.
+	PageSize int `yaml:"pageSize"`
+	// Order is the sort field last chosen for search results.
+	// If not set or unknown, defaults to votes (descending).
+	Order common.StationOrder `yaml:"order"`
+	// Reverse sorts results in descending order.
+	Reverse bool `yaml:"reverse"`
+}
+
+// CachePreferences holds how long RadioBrowser responses are reused before the API is asked again.
+// Expired responses are still used when the API can't be reached.
+type CachePreferences struct {
+	// SearchTTLMinutes applies to search results. If not set or out of range, defaults to 10.
+	SearchTTLMinutes int `yaml:"searchTTLMinutes"`
+	// StationTTLMinutes applies to stations looked up by UUID (bookmarks, hidden stations).
+	// If not set or out of range, defaults to 60.
+	StationTTLMinutes int `yaml:"stationTTLMinutes"`
+	// FacetTTLMinutes applies to the browse lists (countries, tags...).
+	// If not set or out of range, defaults to 1440 (a day).
+	FacetTTLMinutes int `yaml:"facetTTLMinutes"`
+}
+
+// ProviderPreferences holds the station sources that can be searched besides RadioBrowser.
+// Each is disabled when left empty. Paths may start with ~ and contain environment variables.
+type ProviderPreferences struct {
+	// PlaylistDirectory is a directory of M3U, PLS and XSPF playlists whose streams can be searched.
+	PlaylistDirectory string `yaml:"playlistDirectory"`
+	// IcecastDirectory is the path or http(s) URL of an Icecast YP directory listing
+	// (e.g. https://dir.xiph.org/yp.xml).
+	IcecastDirectory string `yaml:"icecastDirectory"`
+}
+
+// HealthPreferences holds settings for the local stream health checks.
+type HealthPreferences struct {
+	// Workers is how many streams are checked at once. If not set or out of range, defaults to 8.
+	Workers int `yaml:"workers"`
+	// TimeoutSeconds is how long a stream may take to send its first bytes.
+	// If not set or out of range, defaults to 10.
+	TimeoutSeconds int `yaml:"timeoutSeconds"`
+	// BookmarkIntervalMinutes is how often bookmarked stations are checked in the background.
+	// If not set, defaults to 60; a negative value disables background checks.
+	BookmarkIntervalMinutes int `yaml:"bookmarkIntervalMinutes"`
+}
+
+// RecordingPreferences holds where recordings are saved, and how they're named.
+type RecordingPreferences struct {
+	// Directory holds the recordings, and is created if missing. It may start with ~
+	// and contain environment variables. If not set, recordings go in the current directory.
+	Directory string `yaml:"directory"`
+	// FilenameTemplate is the name of a recording, without its extension. It may
+	// contain subdirectories of Directory and placeholders: {station}, {country},
+	// {codec}, {title}, {date}, {time}, {year}, {month}, {day}, {hour}, {minute}, {second}.
+	// If not set, defaults to "{station}-{date}-{time}".
+	FilenameTemplate string `yaml:"filenameTemplate"`
 }
 
 // Theme holds the color configuration for the UI.
@@ -62,16 +156,29 @@ func NewDefaultConfig() Config {
 		},
 		Keybindings:       NewDefaultKeybindings(),
 		PlayerPreferences: NewDefaultPlayerPreferences(),
+		API:               NewDefaultAPIPreferences(),
+		Search:            NewDefaultSearchPreferences(),
+		Cache:             NewDefaultCachePreferences(),
+		Health:            NewDefaultHealthPreferences(),
+		Recording:         NewDefaultRecordingPreferences(),
 	}
 }
 
 // NewDefaultPlayerPreferences returns PlayerPreferences with sensible defaults.
 func NewDefaultPlayerPreferences() PlayerPreferences {
 	return PlayerPreferences{
-		DefaultVolume: 80,
+		DefaultVolume:     80,
+		Backend:           "auto",
+		ReconnectAttempts: defaultReconnectAttempts,
 	}
 }
 
+const (
+	defaultReconnectAttempts = 5
+	maxReconnectAttempts     = 20
+	maxTimeshiftBufferMB     = 512
+)
+
 // ValidateAndNormalize ensures PlayerPreferences values are within valid ranges.
 // Returns the normalized preferences.
 func (p PlayerPreferences) ValidateAndNormalize() PlayerPreferences {
@@ -82,6 +189,243 @@ func (p PlayerPreferences) ValidateAndNormalize() PlayerPreferences {
 	} else if normalized.DefaultVolume > 100 {
 		normalized.DefaultVolume = 100
 	}
+	normalized.Backend = strings.ToLower(strings.TrimSpace(normalized.Backend))
+	if !isPlayerBackend(normalized.Backend) {
+		normalized.Backend = "auto"
+	}
+	switch {
+	case normalized.ReconnectAttempts < 0:
+		normalized.ReconnectAttempts = -1
+	case normalized.ReconnectAttempts == 0:
+		normalized.ReconnectAttempts = defaultReconnectAttempts
+	case normalized.ReconnectAttempts > maxReconnectAttempts:
+		normalized.ReconnectAttempts = maxReconnectAttempts
+	}
+	if normalized.TimeshiftBufferMB < 0 {
+		normalized.TimeshiftBufferMB = 0
+	} else if normalized.TimeshiftBufferMB > maxTimeshiftBufferMB {
+		normalized.TimeshiftBufferMB = maxTimeshiftBufferMB
+	}
+	return normalized
+}
+
+// MaxReconnectAttempts returns how many times playback may be restarted, or 0 if reconnecting is disabled.
+func (p PlayerPreferences) MaxReconnectAttempts() int {
+	if p.ReconnectAttempts < 0 {
+		return 0
+	}
+	return p.ReconnectAttempts
+}
+
+// TimeshiftBufferSize returns the size of the timeshift buffer in bytes, or 0 if timeshifting is disabled.
+func (p PlayerPreferences) TimeshiftBufferSize() int {
+	if p.TimeshiftBufferMB <= 0 {
+		return 0
+	}
+	return p.TimeshiftBufferMB * 1024 * 1024
+}
+
+func isPlayerBackend(backend string) bool {
+	for _, b := range PlayerBackends {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	defaultRequestTimeoutSeconds = 15
+	maxRequestTimeoutSeconds     = 300
+)
+
+// NewDefaultAPIPreferences returns APIPreferences with sensible defaults.
+func NewDefaultAPIPreferences() APIPreferences {
+	return APIPreferences{
+		RequestTimeoutSeconds: defaultRequestTimeoutSeconds,
+	}
+}
+
+// ValidateAndNormalize ensures APIPreferences values are within valid ranges.
+// Returns the normalized preferences.
+func (p APIPreferences) ValidateAndNormalize() APIPreferences {
+	normalized := p
+	// Non-positive timeouts would make every request fail immediately
+	if normalized.RequestTimeoutSeconds <= 0 {
+		normalized.RequestTimeoutSeconds = defaultRequestTimeoutSeconds
+	} else if normalized.RequestTimeoutSeconds > maxRequestTimeoutSeconds {
+		normalized.RequestTimeoutSeconds = maxRequestTimeoutSeconds
+	}
+	return normalized
+}
+
+// RequestTimeout returns the request timeout as a time.Duration.
+func (p APIPreferences) RequestTimeout() time.Duration {
+	return time.Duration(p.RequestTimeoutSeconds) * time.Second
+}
+
+const (
+	defaultPageSize = 100
+	minPageSize     = 10
+	maxPageSize     = 1000
+)
+
+// NewDefaultSearchPreferences returns SearchPreferences with sensible defaults.
+func NewDefaultSearchPreferences() SearchPreferences {
+	return SearchPreferences{
+		PageSize: defaultPageSize,
+		Order:    common.StationOrderVotes,
+		Reverse:  true,
+	}
+}
+
+// ValidateAndNormalize ensures SearchPreferences values are within valid ranges.
+// Returns the normalized preferences.
+func (p SearchPreferences) ValidateAndNormalize() SearchPreferences {
+	normalized := p
+	if normalized.PageSize <= 0 {
+		normalized.PageSize = defaultPageSize
+	} else if normalized.PageSize < minPageSize {
+		normalized.PageSize = minPageSize
+	} else if normalized.PageSize > maxPageSize {
+		normalized.PageSize = maxPageSize
+	}
+	if !normalized.Order.IsValid() {
+		normalized.Order = common.StationOrderVotes
+		normalized.Reverse = true
+	}
+	return normalized
+}
+
+const (
+	defaultSearchTTLMinutes  = 10
+	defaultStationTTLMinutes = 60
+	defaultFacetTTLMinutes   = 24 * 60
+	maxCacheTTLMinutes       = 7 * 24 * 60
+)
+
+// NewDefaultCachePreferences returns CachePreferences with sensible defaults.
+func NewDefaultCachePreferences() CachePreferences {
+	return CachePreferences{
+		SearchTTLMinutes:  defaultSearchTTLMinutes,
+		StationTTLMinutes: defaultStationTTLMinutes,
+		FacetTTLMinutes:   defaultFacetTTLMinutes,
+	}
+}
+
+// ValidateAndNormalize ensures CachePreferences values are within valid ranges.
+// Returns the normalized preferences.
+func (p CachePreferences) ValidateAndNormalize() CachePreferences {
+	normalized := p
+	normalized.SearchTTLMinutes = normalizeTTLMinutes(normalized.SearchTTLMinutes, defaultSearchTTLMinutes)
+	normalized.StationTTLMinutes = normalizeTTLMinutes(normalized.StationTTLMinutes, defaultStationTTLMinutes)
+	normalized.FacetTTLMinutes = normalizeTTLMinutes(normalized.FacetTTLMinutes, defaultFacetTTLMinutes)
+	return normalized
+}
+
+// normalizeTTLMinutes replaces non-positive TTLs with def and caps them at a week.
+func normalizeTTLMinutes(minutes int, def int) int {
+	if minutes <= 0 {
+		return def
+	}
+	if minutes > maxCacheTTLMinutes {
+		return maxCacheTTLMinutes
+	}
+	return minutes
+}
+
+// SearchTTL returns the search results TTL as a time.Duration.
+func (p CachePreferences) SearchTTL() time.Duration {
+	return time.Duration(p.SearchTTLMinutes) * time.Minute
+}
+
+// StationTTL returns the station lookup TTL as a time.Duration.
+func (p CachePreferences) StationTTL() time.Duration {
+	return time.Duration(p.StationTTLMinutes) * time.Minute
+}
+
+// FacetTTL returns the browse lists TTL as a time.Duration.
+func (p CachePreferences) FacetTTL() time.Duration {
+	return time.Duration(p.FacetTTLMinutes) * time.Minute
+}
+
+const (
+	defaultHealthWorkers                 = 8
+	maxHealthWorkers                     = 32
+	defaultHealthTimeoutSeconds          = 10
+	maxHealthTimeoutSeconds              = 60
+	defaultHealthBookmarkIntervalMinutes = 60
+	minHealthBookmarkIntervalMinutes     = 5
+	maxHealthBookmarkIntervalMinutes     = 24 * 60
+)
+
+// NewDefaultHealthPreferences returns HealthPreferences with sensible defaults.
+func NewDefaultHealthPreferences() HealthPreferences {
+	return HealthPreferences{
+		Workers:                 defaultHealthWorkers,
+		TimeoutSeconds:          defaultHealthTimeoutSeconds,
+		BookmarkIntervalMinutes: defaultHealthBookmarkIntervalMinutes,
+	}
+}
+
+// ValidateAndNormalize ensures HealthPreferences values are within valid ranges.
+// Returns the normalized preferences.
+func (p HealthPreferences) ValidateAndNormalize() HealthPreferences {
+	normalized := p
+	if normalized.Workers <= 0 {
+		normalized.Workers = defaultHealthWorkers
+	} else if normalized.Workers > maxHealthWorkers {
+		normalized.Workers = maxHealthWorkers
+	}
+	if normalized.TimeoutSeconds <= 0 {
+		normalized.TimeoutSeconds = defaultHealthTimeoutSeconds
+	} else if normalized.TimeoutSeconds > maxHealthTimeoutSeconds {
+		normalized.TimeoutSeconds = maxHealthTimeoutSeconds
+	}
+	switch {
+	case normalized.BookmarkIntervalMinutes < 0:
+		normalized.BookmarkIntervalMinutes = -1
+	case normalized.BookmarkIntervalMinutes == 0:
+		normalized.BookmarkIntervalMinutes = defaultHealthBookmarkIntervalMinutes
+	case normalized.BookmarkIntervalMinutes < minHealthBookmarkIntervalMinutes:
+		normalized.BookmarkIntervalMinutes = minHealthBookmarkIntervalMinutes
+	case normalized.BookmarkIntervalMinutes > maxHealthBookmarkIntervalMinutes:
+		normalized.BookmarkIntervalMinutes = maxHealthBookmarkIntervalMinutes
+	}
+	return normalized
+}
+
+// Timeout returns the stream check timeout as a time.Duration.
+func (p HealthPreferences) Timeout() time.Duration {
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+// BookmarkInterval returns how often bookmarks are checked, or 0 if background checks are disabled.
+func (p HealthPreferences) BookmarkInterval() time.Duration {
+	if p.BookmarkIntervalMinutes < 0 {
+		return 0
+	}
+	return time.Duration(p.BookmarkIntervalMinutes) * time.Minute
+}
+
+const defaultRecordingFilenameTemplate = "{station}-{date}-{time}"
+
+// NewDefaultRecordingPreferences returns RecordingPreferences with sensible defaults.
+func NewDefaultRecordingPreferences() RecordingPreferences {
+	return RecordingPreferences{
+		FilenameTemplate: defaultRecordingFilenameTemplate,
+	}
+}
+
+// ValidateAndNormalize trims the recording settings and fills in the default template.
+// Returns the normalized preferences.
+func (p RecordingPreferences) ValidateAndNormalize() RecordingPreferences {
+	normalized := p
+	normalized.Directory = strings.TrimSpace(normalized.Directory)
+	normalized.FilenameTemplate = strings.TrimSpace(normalized.FilenameTemplate)
+	if normalized.FilenameTemplate == "" {
+		normalized.FilenameTemplate = defaultRecordingFilenameTemplate
+	}
 	return normalized
 }
 
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/common"
 	"gopkg.in/yaml.v3"
 )
 
@@ -149,6 +151,27 @@ func TestConfigDir(t *testing.T) {
 	})
 }
 
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("RADIO_DIR", "/srv/radio")
+
+	t.Run("expands a leading tilde", func(t *testing.T) {
+		assert.Equal(t, home, ExpandPath("~"))
+		assert.Equal(t, filepath.Join(home, "playlists"), ExpandPath("~/playlists"))
+	})
+
+	t.Run("expands environment variables", func(t *testing.T) {
+		assert.Equal(t, "/srv/radio/playlists", ExpandPath("$RADIO_DIR/playlists"))
+		assert.Equal(t, "/srv/radio/playlists", ExpandPath("${RADIO_DIR}/playlists"))
+	})
+
+	t.Run("leaves other paths alone", func(t *testing.T) {
+		assert.Equal(t, "/music/~user", ExpandPath("/music/~user"))
+		assert.Equal(t, "https://dir.xiph.org/yp.xml", ExpandPath("https://dir.xiph.org/yp.xml"))
+	})
+}
+
 func TestConfigFile(t *testing.T) {
 	t.Run("returns path ending with config.yaml", func(t *testing.T) {
 		file := ConfigFile()
@@ -522,6 +545,72 @@ language: en
 		prefs := NewDefaultPlayerPreferences()
 
 		assert.Equal(t, 80, prefs.DefaultVolume)
+		assert.Equal(t, "auto", prefs.Backend)
+	})
+
+	t.Run("parses the backend from YAML", func(t *testing.T) {
+		input := `
+playerPreferences:
+  backend: mpv
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "mpv", cfg.PlayerPreferences.Backend)
+	})
+
+	t.Run("ValidateAndNormalize accepts the known backends", func(t *testing.T) {
+		for _, backend := range PlayerBackends {
+			normalized := PlayerPreferences{Backend: backend}.ValidateAndNormalize()
+
+			assert.Equal(t, backend, normalized.Backend)
+		}
+		assert.Equal(t, "cvlc", PlayerPreferences{Backend: " CVLC "}.ValidateAndNormalize().Backend)
+	})
+
+	t.Run("ValidateAndNormalize falls back to auto", func(t *testing.T) {
+		assert.Equal(t, "auto", PlayerPreferences{}.ValidateAndNormalize().Backend)
+		assert.Equal(t, "auto", PlayerPreferences{Backend: "winamp"}.ValidateAndNormalize().Backend)
+	})
+
+	t.Run("parses reconnect attempts from YAML", func(t *testing.T) {
+		input := `
+playerPreferences:
+  reconnectAttempts: 3
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cfg.PlayerPreferences.ReconnectAttempts)
+	})
+
+	t.Run("ValidateAndNormalize normalizes reconnect attempts", func(t *testing.T) {
+		assert.Equal(t, 5, NewDefaultPlayerPreferences().ReconnectAttempts)
+		assert.Equal(t, 5, PlayerPreferences{}.ValidateAndNormalize().ReconnectAttempts)
+		assert.Equal(t, 3, PlayerPreferences{ReconnectAttempts: 3}.ValidateAndNormalize().ReconnectAttempts)
+		assert.Equal(t, 20, PlayerPreferences{ReconnectAttempts: 100}.ValidateAndNormalize().ReconnectAttempts)
+		assert.Equal(t, -1, PlayerPreferences{ReconnectAttempts: -5}.ValidateAndNormalize().ReconnectAttempts)
+	})
+
+	t.Run("MaxReconnectAttempts is 0 when reconnecting is disabled", func(t *testing.T) {
+		assert.Equal(t, 0, PlayerPreferences{ReconnectAttempts: -1}.MaxReconnectAttempts())
+		assert.Equal(t, 3, PlayerPreferences{ReconnectAttempts: 3}.MaxReconnectAttempts())
+	})
+
+	t.Run("ValidateAndNormalize leaves timeshifting off unless a buffer size is set", func(t *testing.T) {
+		assert.Equal(t, 0, NewDefaultPlayerPreferences().TimeshiftBufferMB)
+		assert.Equal(t, 0, PlayerPreferences{}.ValidateAndNormalize().TimeshiftBufferMB)
+		assert.Equal(t, 64, PlayerPreferences{TimeshiftBufferMB: 64}.ValidateAndNormalize().TimeshiftBufferMB)
+		assert.Equal(t, 512, PlayerPreferences{TimeshiftBufferMB: 4096}.ValidateAndNormalize().TimeshiftBufferMB)
+		assert.Equal(t, 0, PlayerPreferences{TimeshiftBufferMB: -8}.ValidateAndNormalize().TimeshiftBufferMB)
+	})
+
+	t.Run("TimeshiftBufferSize is in bytes, 0 when timeshifting is disabled", func(t *testing.T) {
+		assert.Equal(t, 16*1024*1024, PlayerPreferences{TimeshiftBufferMB: 16}.TimeshiftBufferSize())
+		assert.Equal(t, 0, PlayerPreferences{}.TimeshiftBufferSize())
+		assert.Equal(t, 0, PlayerPreferences{TimeshiftBufferMB: -1}.TimeshiftBufferSize())
 	})
 
 	t.Run("ValidateAndNormalize clamps volume below 0", func(t *testing.T) {
@@ -622,3 +711,264 @@ theme:
 		assert.Equal(t, 0, cfg.PlayerPreferences.DefaultVolume)
 	})
 }
+
+func TestAPIPreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+api:
+  requestTimeoutSeconds: 30
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 30, cfg.API.RequestTimeoutSeconds)
+	})
+
+	t.Run("NewDefaultConfig includes API preferences", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, 15, cfg.API.RequestTimeoutSeconds)
+		assert.Equal(t, 15*time.Second, cfg.API.RequestTimeout())
+	})
+
+	t.Run("keeps the default when the section is missing from the file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		cfgPath := filepath.Join(tmpDir, "config.yaml")
+
+		err := os.WriteFile(cfgPath, []byte("language: en\n"), 0644)
+		assert.NoError(t, err)
+
+		cfg := NewDefaultConfig()
+		err = cfg.Load(cfgPath)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 15, cfg.API.RequestTimeoutSeconds)
+	})
+
+	t.Run("ValidateAndNormalize replaces non-positive timeouts with the default", func(t *testing.T) {
+		for _, timeout := range []int{0, -5} {
+			normalized := APIPreferences{RequestTimeoutSeconds: timeout}.ValidateAndNormalize()
+
+			assert.Equal(t, 15, normalized.RequestTimeoutSeconds)
+		}
+	})
+
+	t.Run("ValidateAndNormalize clamps timeouts above the maximum", func(t *testing.T) {
+		normalized := APIPreferences{RequestTimeoutSeconds: 3600}.ValidateAndNormalize()
+
+		assert.Equal(t, 300, normalized.RequestTimeoutSeconds)
+	})
+}
+
+func TestSearchPreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+search:
+  pageSize: 250
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 250, cfg.Search.PageSize)
+	})
+
+	t.Run("NewDefaultConfig includes search preferences", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, 100, cfg.Search.PageSize)
+	})
+
+	t.Run("ValidateAndNormalize replaces non-positive page sizes with the default", func(t *testing.T) {
+		for _, size := range []int{0, -1} {
+			normalized := SearchPreferences{PageSize: size}.ValidateAndNormalize()
+
+			assert.Equal(t, 100, normalized.PageSize)
+		}
+	})
+
+	t.Run("ValidateAndNormalize clamps page sizes to the allowed range", func(t *testing.T) {
+		assert.Equal(t, 10, SearchPreferences{PageSize: 3}.ValidateAndNormalize().PageSize)
+		assert.Equal(t, 1000, SearchPreferences{PageSize: 5000}.ValidateAndNormalize().PageSize)
+		assert.Equal(t, 50, SearchPreferences{PageSize: 50}.ValidateAndNormalize().PageSize)
+	})
+
+	t.Run("defaults to votes in descending order", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, common.StationOrderVotes, cfg.Search.Order)
+		assert.True(t, cfg.Search.Reverse)
+	})
+
+	t.Run("parses the remembered order from YAML", func(t *testing.T) {
+		input := `
+search:
+  order: name
+  reverse: false
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		normalized := cfg.Search.ValidateAndNormalize()
+		assert.Equal(t, common.StationOrderName, normalized.Order)
+		assert.False(t, normalized.Reverse)
+	})
+
+	t.Run("ValidateAndNormalize replaces unknown orders with votes", func(t *testing.T) {
+		for _, order := range []common.StationOrder{"", "loudness"} {
+			normalized := SearchPreferences{Order: order}.ValidateAndNormalize()
+
+			assert.Equal(t, common.StationOrderVotes, normalized.Order)
+			assert.True(t, normalized.Reverse)
+		}
+	})
+}
+
+func TestCachePreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+cache:
+  searchTTLMinutes: 5
+  stationTTLMinutes: 30
+  facetTTLMinutes: 120
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, cfg.Cache.SearchTTL())
+		assert.Equal(t, 30*time.Minute, cfg.Cache.StationTTL())
+		assert.Equal(t, 2*time.Hour, cfg.Cache.FacetTTL())
+	})
+
+	t.Run("NewDefaultConfig includes cache preferences", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, 10, cfg.Cache.SearchTTLMinutes)
+		assert.Equal(t, 60, cfg.Cache.StationTTLMinutes)
+		assert.Equal(t, 1440, cfg.Cache.FacetTTLMinutes)
+	})
+
+	t.Run("ValidateAndNormalize replaces non-positive TTLs with the defaults", func(t *testing.T) {
+		normalized := CachePreferences{SearchTTLMinutes: 0, StationTTLMinutes: -5}.ValidateAndNormalize()
+
+		assert.Equal(t, NewDefaultCachePreferences(), normalized)
+	})
+
+	t.Run("ValidateAndNormalize caps TTLs at a week", func(t *testing.T) {
+		normalized := CachePreferences{SearchTTLMinutes: 100000, StationTTLMinutes: 15, FacetTTLMinutes: 20000}.ValidateAndNormalize()
+
+		assert.Equal(t, 10080, normalized.SearchTTLMinutes)
+		assert.Equal(t, 15, normalized.StationTTLMinutes)
+		assert.Equal(t, 10080, normalized.FacetTTLMinutes)
+	})
+}
+
+func TestProviderPreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+providers:
+  playlistDirectory: ~/playlists
+  icecastDirectory: https://dir.xiph.org/yp.xml
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "~/playlists", cfg.Providers.PlaylistDirectory)
+		assert.Equal(t, "https://dir.xiph.org/yp.xml", cfg.Providers.IcecastDirectory)
+	})
+
+	t.Run("NewDefaultConfig only searches RadioBrowser", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, ProviderPreferences{}, cfg.Providers)
+	})
+}
+
+func TestHealthPreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+health:
+  workers: 4
+  timeoutSeconds: 5
+  bookmarkIntervalMinutes: 30
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, cfg.Health.Workers)
+		assert.Equal(t, 5*time.Second, cfg.Health.Timeout())
+		assert.Equal(t, 30*time.Minute, cfg.Health.BookmarkInterval())
+	})
+
+	t.Run("NewDefaultConfig includes health preferences", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, 8, cfg.Health.Workers)
+		assert.Equal(t, 10, cfg.Health.TimeoutSeconds)
+		assert.Equal(t, time.Hour, cfg.Health.BookmarkInterval())
+	})
+
+	t.Run("ValidateAndNormalize replaces unset values with the defaults", func(t *testing.T) {
+		normalized := HealthPreferences{}.ValidateAndNormalize()
+
+		assert.Equal(t, NewDefaultHealthPreferences(), normalized)
+	})
+
+	t.Run("ValidateAndNormalize clamps values to their ranges", func(t *testing.T) {
+		normalized := HealthPreferences{Workers: 100, TimeoutSeconds: 600, BookmarkIntervalMinutes: 1}.ValidateAndNormalize()
+
+		assert.Equal(t, 32, normalized.Workers)
+		assert.Equal(t, 60, normalized.TimeoutSeconds)
+		assert.Equal(t, 5, normalized.BookmarkIntervalMinutes)
+
+		normalized = HealthPreferences{BookmarkIntervalMinutes: 5000}.ValidateAndNormalize()
+		assert.Equal(t, 1440, normalized.BookmarkIntervalMinutes)
+	})
+
+	t.Run("a negative interval disables background checks", func(t *testing.T) {
+		normalized := HealthPreferences{BookmarkIntervalMinutes: -10}.ValidateAndNormalize()
+
+		assert.Equal(t, time.Duration(0), normalized.BookmarkInterval())
+	})
+}
+
+func TestRecordingPreferences(t *testing.T) {
+	t.Run("parses from YAML", func(t *testing.T) {
+		input := `
+recording:
+  directory: ~/Music/radio
+  filenameTemplate: "{station}/{date}-{title}"
+`
+		var cfg Config
+		err := yaml.Unmarshal([]byte(input), &cfg)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "~/Music/radio", cfg.Recording.Directory)
+		assert.Equal(t, "{station}/{date}-{title}", cfg.Recording.FilenameTemplate)
+	})
+
+	t.Run("NewDefaultConfig records in the current directory", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+
+		assert.Equal(t, "", cfg.Recording.Directory)
+		assert.Equal(t, "{station}-{date}-{time}", cfg.Recording.FilenameTemplate)
+	})
+
+	t.Run("ValidateAndNormalize replaces unset values with the defaults", func(t *testing.T) {
+		normalized := RecordingPreferences{}.ValidateAndNormalize()
+
+		assert.Equal(t, NewDefaultRecordingPreferences(), normalized)
+	})
+
+	t.Run("ValidateAndNormalize trims values", func(t *testing.T) {
+		normalized := RecordingPreferences{Directory: "  /srv/radio ", FilenameTemplate: " {station} \n"}.ValidateAndNormalize()
+
+		assert.Equal(t, "/srv/radio", normalized.Directory)
+		assert.Equal(t, "{station}", normalized.FilenameTemplate)
+	})
+}
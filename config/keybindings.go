@@ -27,20 +27,43 @@ import (
 // Keybindings holds all customizable key bindings for the application.
 // Keys are stored as strings matching BubbleTea's msg.String() format.
 type Keybindings struct {
-	Quit           string `yaml:"quit"`
-	Search         string `yaml:"search"`
-	Record         string `yaml:"record"`
-	BookmarkToggle string `yaml:"bookmarkToggle"`
-	BookmarksView  string `yaml:"bookmarksView"`
-	HideStation    string `yaml:"hideStation"`
-	ManageHidden   string `yaml:"manageHidden"`
-	ChangeLanguage string `yaml:"changeLanguage"`
-	VolumeDown     string `yaml:"volumeDown"`
-	VolumeUp       string `yaml:"volumeUp"`
-	NavigateDown   string `yaml:"navigateDown"`
-	NavigateUp     string `yaml:"navigateUp"`
-	StopPlayback   string `yaml:"stopPlayback"`
-	Vote           string `yaml:"vote"`
+	Quit            string `yaml:"quit"`
+	Search          string `yaml:"search"`
+	Record          string `yaml:"record"`
+	BookmarkToggle  string `yaml:"bookmarkToggle"`
+	BookmarksView   string `yaml:"bookmarksView"`
+	HideStation     string `yaml:"hideStation"`
+	ManageHidden    string `yaml:"manageHidden"`
+	ChangeLanguage  string `yaml:"changeLanguage"`
+	VolumeDown      string `yaml:"volumeDown"`
+	VolumeUp        string `yaml:"volumeUp"`
+	NavigateDown    string `yaml:"navigateDown"`
+	NavigateUp      string `yaml:"navigateUp"`
+	StopPlayback    string `yaml:"stopPlayback"`
+	Vote            string `yaml:"vote"`
+	AdvancedSearch  string `yaml:"advancedSearch"`
+	Retry           string `yaml:"retry"`
+	Browse          string `yaml:"browse"`
+	SortOrder       string `yaml:"sortOrder"`
+	SortDirection   string `yaml:"sortDirection"`
+	Refresh         string `yaml:"refresh"`
+	Discover        string `yaml:"discover"`
+	NearbySearch    string `yaml:"nearbySearch"`
+	CustomStations  string `yaml:"customStations"`
+	AddStation      string `yaml:"addStation"`
+	EditStation     string `yaml:"editStation"`
+	DeleteStation   string `yaml:"deleteStation"`
+	Source          string `yaml:"source"`
+	CheckHealth     string `yaml:"checkHealth"`
+	Pause           string `yaml:"pause"`
+	History         string `yaml:"history"`
+	ExportCSV       string `yaml:"exportCSV"`
+	ExportJSON      string `yaml:"exportJSON"`
+	JumpToLive      string `yaml:"jumpToLive"`
+	SleepTimer      string `yaml:"sleepTimer"`
+	SleepTimerInput string `yaml:"sleepTimerInput"`
+	Alarms          string `yaml:"alarms"`
+	Recordings      string `yaml:"recordings"`
 }
 
 // reservedKeys contains keys that cannot be remapped because they would break
@@ -61,6 +84,8 @@ var reservedKeys = map[string]bool{
 	// TextInput editing shortcuts
 	"ctrl+a": true, "ctrl+e": true, "ctrl+u": true,
 	"ctrl+w": true, "ctrl+d": true, "ctrl+h": true,
+	// TextInput cursor movement and suggestions, handled by the focused input
+	"ctrl+f": true, "ctrl+b": true, "ctrl+n": true, "ctrl+p": true,
 }
 
 // IsReserved returns true if the key is reserved and cannot be used as a custom keybinding.
@@ -71,20 +96,43 @@ func IsReserved(key string) bool {
 // NewDefaultKeybindings returns the default keybindings for RadioGoGo.
 func NewDefaultKeybindings() Keybindings {
 	return Keybindings{
-		Quit:           "q",
-		Search:         "s",
-		Record:         "r",
-		BookmarkToggle: "b",
-		BookmarksView:  "B",
-		HideStation:    "h",
-		ManageHidden:   "H",
-		ChangeLanguage: "L",
-		VolumeDown:     "9",
-		VolumeUp:       "0",
-		NavigateDown:   "j",
-		NavigateUp:     "k",
-		StopPlayback:   "ctrl+k",
-		Vote:           "v",
+		Quit:            "q",
+		Search:          "s",
+		Record:          "r",
+		BookmarkToggle:  "b",
+		BookmarksView:   "B",
+		HideStation:     "h",
+		ManageHidden:    "H",
+		ChangeLanguage:  "L",
+		VolumeDown:      "9",
+		VolumeUp:        "0",
+		NavigateDown:    "j",
+		NavigateUp:      "k",
+		StopPlayback:    "ctrl+k",
+		Vote:            "v",
+		AdvancedSearch:  "ctrl+t",
+		Retry:           "R",
+		Browse:          "ctrl+g",
+		SortOrder:       "o",
+		SortDirection:   "O",
+		Refresh:         "ctrl+r",
+		Discover:        "ctrl+o",
+		NearbySearch:    "alt+n",
+		CustomStations:  "C",
+		AddStation:      "a",
+		EditStation:     "e",
+		DeleteStation:   "D",
+		Source:          "alt+s",
+		CheckHealth:     "c",
+		Pause:           "p",
+		History:         "ctrl+y",
+		ExportCSV:       "ctrl+x",
+		ExportJSON:      "ctrl+j",
+		JumpToLive:      "l",
+		SleepTimer:      "z",
+		SleepTimerInput: "Z",
+		Alarms:          "alt+a",
+		Recordings:      "alt+r",
 	}
 }
 
@@ -131,6 +179,29 @@ func (k Keybindings) Validate() (Keybindings, []ValidationWarning) {
 		{"navigateUp", &result.NavigateUp, defaults.NavigateUp},
 		{"stopPlayback", &result.StopPlayback, defaults.StopPlayback},
 		{"vote", &result.Vote, defaults.Vote},
+		{"advancedSearch", &result.AdvancedSearch, defaults.AdvancedSearch},
+		{"retry", &result.Retry, defaults.Retry},
+		{"browse", &result.Browse, defaults.Browse},
+		{"sortOrder", &result.SortOrder, defaults.SortOrder},
+		{"sortDirection", &result.SortDirection, defaults.SortDirection},
+		{"refresh", &result.Refresh, defaults.Refresh},
+		{"discover", &result.Discover, defaults.Discover},
+		{"nearbySearch", &result.NearbySearch, defaults.NearbySearch},
+		{"customStations", &result.CustomStations, defaults.CustomStations},
+		{"addStation", &result.AddStation, defaults.AddStation},
+		{"editStation", &result.EditStation, defaults.EditStation},
+		{"deleteStation", &result.DeleteStation, defaults.DeleteStation},
+		{"source", &result.Source, defaults.Source},
+		{"checkHealth", &result.CheckHealth, defaults.CheckHealth},
+		{"pause", &result.Pause, defaults.Pause},
+		{"history", &result.History, defaults.History},
+		{"exportCSV", &result.ExportCSV, defaults.ExportCSV},
+		{"exportJSON", &result.ExportJSON, defaults.ExportJSON},
+		{"jumpToLive", &result.JumpToLive, defaults.JumpToLive},
+		{"sleepTimer", &result.SleepTimer, defaults.SleepTimer},
+		{"sleepTimerInput", &result.SleepTimerInput, defaults.SleepTimerInput},
+		{"alarms", &result.Alarms, defaults.Alarms},
+		{"recordings", &result.Recordings, defaults.Recordings},
 	}
 
 	// Check for reserved keys
@@ -24,6 +24,29 @@ func TestNewDefaultKeybindings(t *testing.T) {
 		assert.Equal(t, "j", kb.NavigateDown)
 		assert.Equal(t, "k", kb.NavigateUp)
 		assert.Equal(t, "ctrl+k", kb.StopPlayback)
+		assert.Equal(t, "ctrl+t", kb.AdvancedSearch)
+		assert.Equal(t, "R", kb.Retry)
+		assert.Equal(t, "ctrl+g", kb.Browse)
+		assert.Equal(t, "o", kb.SortOrder)
+		assert.Equal(t, "O", kb.SortDirection)
+		assert.Equal(t, "ctrl+r", kb.Refresh)
+		assert.Equal(t, "ctrl+o", kb.Discover)
+		assert.Equal(t, "alt+n", kb.NearbySearch)
+		assert.Equal(t, "C", kb.CustomStations)
+		assert.Equal(t, "a", kb.AddStation)
+		assert.Equal(t, "e", kb.EditStation)
+		assert.Equal(t, "D", kb.DeleteStation)
+		assert.Equal(t, "alt+s", kb.Source)
+		assert.Equal(t, "c", kb.CheckHealth)
+		assert.Equal(t, "p", kb.Pause)
+		assert.Equal(t, "ctrl+y", kb.History)
+		assert.Equal(t, "ctrl+x", kb.ExportCSV)
+		assert.Equal(t, "ctrl+j", kb.ExportJSON)
+		assert.Equal(t, "l", kb.JumpToLive)
+		assert.Equal(t, "z", kb.SleepTimer)
+		assert.Equal(t, "Z", kb.SleepTimerInput)
+		assert.Equal(t, "alt+a", kb.Alarms)
+		assert.Equal(t, "alt+r", kb.Recordings)
 	})
 }
 
@@ -38,6 +61,7 @@ func TestIsReserved(t *testing.T) {
 			"ctrl+s", "ctrl+q", "ctrl+l",
 			"ctrl+a", "ctrl+e", "ctrl+u",
 			"ctrl+w", "ctrl+d", "ctrl+h",
+			"ctrl+f", "ctrl+b", "ctrl+n", "ctrl+p",
 		}
 
 		for _, key := range reservedKeys {
@@ -99,6 +123,41 @@ func TestKeybindings_Validate(t *testing.T) {
 		assert.Equal(t, "s", validated.Search)
 	})
 
+	t.Run("rejects keys the search input handles itself", func(t *testing.T) {
+		kb := NewDefaultKeybindings()
+		kb.Alarms = "ctrl+f"
+
+		validated, warnings := kb.Validate()
+
+		assert.Len(t, warnings, 1)
+		assert.Equal(t, "reserved key", warnings[0].Reason)
+		assert.Equal(t, "alt+a", validated.Alarms)
+
+		kb = NewDefaultKeybindings()
+		kb.Recordings = "ctrl+b"
+
+		validated, warnings = kb.Validate()
+
+		assert.Len(t, warnings, 1)
+		assert.Equal(t, "alt+r", validated.Recordings)
+
+		kb = NewDefaultKeybindings()
+		kb.NearbySearch = "ctrl+n"
+
+		validated, warnings = kb.Validate()
+
+		assert.Len(t, warnings, 1)
+		assert.Equal(t, "alt+n", validated.NearbySearch)
+
+		kb = NewDefaultKeybindings()
+		kb.Source = "ctrl+p"
+
+		validated, warnings = kb.Validate()
+
+		assert.Len(t, warnings, 1)
+		assert.Equal(t, "alt+s", validated.Source)
+	})
+
 	t.Run("fills empty keys with defaults", func(t *testing.T) {
 		kb := Keybindings{} // all empty
 
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // ConfigDir returns the path to the directory where the application's configuration files are stored.
@@ -25,3 +26,15 @@ func ConfigDir() string {
 func ConfigFile() string {
 	return filepath.Join(ConfigDir(), "config.yaml")
 }
+
+// ExpandPath expands environment variables ($VAR or ${VAR}) in path, and a leading ~
+// to the user's home directory.
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
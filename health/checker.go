@@ -0,0 +1,218 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/data"
+)
+
+const (
+	// DefaultWorkers is how many streams are probed at once by default.
+	DefaultWorkers = 8
+	// DefaultTimeout is how long a probe waits for the first bytes of a stream by default.
+	DefaultTimeout = 10 * time.Second
+)
+
+// firstBytesSize is how much of the stream a probe reads before disconnecting.
+const firstBytesSize = 512
+
+// HTTPClient makes the requests of a Checker. *http.Client satisfies it.
+// (api.HTTPClientService isn't used so that mocks can depend on this package.)
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Checker probes station streams.
+type Checker struct {
+	client  HTTPClient
+	workers int
+	timeout time.Duration
+	// Returns the current time (replaceable in tests).
+	now func() time.Time
+}
+
+// NewChecker returns a Checker probing up to workers streams at once, each for at most timeout.
+// Non-positive values are replaced with DefaultWorkers and DefaultTimeout.
+func NewChecker(workers int, timeout time.Duration) *Checker {
+	// No client timeout: streams never end, so each probe is bounded by its context instead
+	return NewCheckerWithClient(&http.Client{}, workers, timeout)
+}
+
+// NewCheckerWithClient returns a Checker making its requests with client.
+func NewCheckerWithClient(client HTTPClient, workers int, timeout time.Duration) *Checker {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{
+		client:  client,
+		workers: workers,
+		timeout: timeout,
+		now:     time.Now,
+	}
+}
+
+// CheckAll probes the given stations, at most c.workers at once, and returns
+// their results in the same order. Stations left unprobed because ctx was
+// cancelled are not included.
+func (c *Checker) CheckAll(ctx context.Context, stations []common.Station) []Result {
+	results := make([]*Result, len(stations))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.workers && w < len(stations); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := c.Check(ctx, stations[i])
+				results[i] = &result
+			}
+		}()
+	}
+
+feed:
+	for i := range stations {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	checked := make([]Result, 0, len(stations))
+	for _, result := range results {
+		if result != nil {
+			checked = append(checked, *result)
+		}
+	}
+	return checked
+}
+
+// Check probes a single station's stream: it connects, reads the headers and
+// waits for the first bytes of audio, giving up after c.timeout.
+// The resolved URL is probed if the station has one.
+func (c *Checker) Check(ctx context.Context, station common.Station) Result {
+	result := c.probe(ctx, station)
+	result.StationUuid = station.StationUuid
+	result.CheckedAt = c.now()
+	return result
+}
+
+// probe connects to the station's stream and fills in everything but the station and time of the result.
+func (c *Checker) probe(ctx context.Context, station common.Station) Result {
+	var result Result
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, station.StreamURL(), nil)
+	if err != nil {
+		result.Status = StatusUnreachable
+		result.Error = err.Error()
+		return result
+	}
+	req.Header.Set("User-Agent", data.UserAgent)
+	req.Header.Set("Icy-MetaData", "1")
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Shoutcast v1 servers answer "ICY 200 OK", which isn't valid HTTP: they're up and streaming
+		if strings.Contains(err.Error(), `malformed HTTP version "ICY"`) {
+			result.Status = StatusOK
+			result.Latency = time.Since(start)
+			return result
+		}
+		result.Status = StatusUnreachable
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.IcyName = resp.Header.Get("icy-name")
+	result.IcyBitrate = resp.Header.Get("icy-br")
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.Status = StatusHTTPError
+		result.Error = fmt.Sprintf("server answered %s", resp.Status)
+		return result
+	}
+	if !isStreamContentType(result.ContentType) {
+		result.Status = StatusNotAudio
+		result.Error = fmt.Sprintf("unexpected content type %s", result.ContentType)
+		return result
+	}
+
+	buf := make([]byte, firstBytesSize)
+	if _, err := io.ReadAtLeast(resp.Body, buf, 1); err != nil {
+		result.Status = StatusNoData
+		if errors.Is(err, io.EOF) {
+			result.Error = "stream ended without data"
+		} else {
+			result.Error = err.Error()
+		}
+		return result
+	}
+
+	result.Status = StatusOK
+	result.Latency = time.Since(start)
+	return result
+}
+
+// isStreamContentType returns true if a response with the given content type can be an audio stream.
+// Servers that don't send one are given the benefit of the doubt.
+func isStreamContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return true
+	}
+	switch mediaType {
+	case "application/ogg", "application/octet-stream",
+		"application/vnd.apple.mpegurl", "application/x-mpegurl",
+		"application/pls+xml", "application/xspf+xml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+func stationAt(t *testing.T, rawURL string) common.Station {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+	return common.Station{
+		StationUuid: uuid.New(),
+		Url:         common.RadioGoGoURL{URL: *u},
+	}
+}
+
+// streamHandler serves a never-ending stream of the given content type.
+func streamHandler(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("icy-name", "Test FM")
+		w.Header().Set("icy-br", "128")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("audio"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+
+	checkedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newChecker := func(timeout time.Duration) *Checker {
+		c := NewChecker(2, timeout)
+		c.now = func() time.Time { return checkedAt }
+		return c
+	}
+
+	t.Run("reports a stream sending audio as ok, with its ICY headers", func(t *testing.T) {
+		var icyMetaData string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			icyMetaData = r.Header.Get("Icy-MetaData")
+			streamHandler("audio/mpeg")(w, r)
+		}))
+		defer server.Close()
+		station := stationAt(t, server.URL)
+
+		result := newChecker(time.Second).Check(context.Background(), station)
+
+		assert.Equal(t, StatusOK, result.Status)
+		assert.True(t, result.OK())
+		assert.Equal(t, station.StationUuid, result.StationUuid)
+		assert.Equal(t, http.StatusOK, result.StatusCode)
+		assert.Equal(t, "audio/mpeg", result.ContentType)
+		assert.Equal(t, "Test FM", result.IcyName)
+		assert.Equal(t, "128", result.IcyBitrate)
+		assert.Equal(t, checkedAt, result.CheckedAt)
+		assert.Empty(t, result.Error)
+		assert.Equal(t, "1", icyMetaData)
+	})
+
+	t.Run("probes the resolved URL when there is one", func(t *testing.T) {
+		server := httptest.NewServer(streamHandler("audio/aac"))
+		defer server.Close()
+		station := stationAt(t, "http://127.0.0.1:1/unreachable")
+		resolved, _ := url.Parse(server.URL)
+		station.UrlResolved = common.RadioGoGoURL{URL: *resolved}
+
+		result := newChecker(time.Second).Check(context.Background(), station)
+
+		assert.Equal(t, StatusOK, result.Status)
+	})
+
+	t.Run("reports HTTP errors", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		result := newChecker(time.Second).Check(context.Background(), stationAt(t, server.URL))
+
+		assert.Equal(t, StatusHTTPError, result.Status)
+		assert.Equal(t, http.StatusNotFound, result.StatusCode)
+		assert.Contains(t, result.Error, "404")
+	})
+
+	t.Run("reports web pages as not audio", func(t *testing.T) {
+		server := httptest.NewServer(streamHandler("text/html; charset=utf-8"))
+		defer server.Close()
+
+		result := newChecker(time.Second).Check(context.Background(), stationAt(t, server.URL))
+
+		assert.Equal(t, StatusNotAudio, result.Status)
+		assert.Equal(t, "text/html; charset=utf-8", result.ContentType)
+	})
+
+	t.Run("reports streams that send nothing in time", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "audio/mpeg")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		result := newChecker(100*time.Millisecond).Check(context.Background(), stationAt(t, server.URL))
+
+		assert.Equal(t, StatusNoData, result.Status)
+		assert.NotEmpty(t, result.Error)
+	})
+
+	t.Run("reports servers that can't be reached", func(t *testing.T) {
+		server := httptest.NewServer(streamHandler("audio/mpeg"))
+		server.Close()
+
+		result := newChecker(time.Second).Check(context.Background(), stationAt(t, server.URL))
+
+		assert.Equal(t, StatusUnreachable, result.Status)
+		assert.Equal(t, 0, result.StatusCode)
+		assert.NotEmpty(t, result.Error)
+	})
+
+	t.Run("accepts Shoutcast v1 servers answering ICY 200 OK", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			buf := make([]byte, 1024)
+			_, _ = conn.Read(buf)
+			_, _ = conn.Write([]byte("ICY 200 OK\r\nicy-name: Old FM\r\ncontent-type: audio/mpeg\r\n\r\naudio"))
+		}()
+
+		result := newChecker(time.Second).Check(context.Background(), stationAt(t, "http://"+listener.Addr().String()))
+
+		assert.Equal(t, StatusOK, result.Status)
+	})
+}
+
+func TestChecker_CheckAll(t *testing.T) {
+
+	t.Run("probes every station with a bounded number of workers, keeping their order", func(t *testing.T) {
+		var running, maxRunning int32
+		var mu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			mu.Lock()
+			if now > maxRunning {
+				maxRunning = now
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			if r.URL.Path == "/down" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = w.Write([]byte("audio"))
+		}))
+		defer server.Close()
+
+		stations := make([]common.Station, 10)
+		for i := range stations {
+			stations[i] = stationAt(t, server.URL+"/up")
+		}
+		stations[3] = stationAt(t, server.URL+"/down")
+
+		results := NewChecker(3, time.Second).CheckAll(context.Background(), stations)
+
+		assert.Len(t, results, 10)
+		for i, result := range results {
+			assert.Equal(t, stations[i].StationUuid, result.StationUuid)
+			if i == 3 {
+				assert.Equal(t, StatusHTTPError, result.Status)
+			} else {
+				assert.Equal(t, StatusOK, result.Status)
+			}
+		}
+		assert.LessOrEqual(t, maxRunning, int32(3))
+	})
+
+	t.Run("stops probing when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var probed int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&probed, 1) == 1 {
+				cancel()
+			}
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = w.Write([]byte("audio"))
+		}))
+		defer server.Close()
+
+		stations := make([]common.Station, 20)
+		for i := range stations {
+			stations[i] = stationAt(t, server.URL)
+		}
+
+		results := NewChecker(1, time.Second).CheckAll(ctx, stations)
+
+		assert.Less(t, len(results), len(stations))
+	})
+
+	t.Run("returns nothing for no stations", func(t *testing.T) {
+		assert.Empty(t, NewChecker(4, time.Second).CheckAll(context.Background(), nil))
+	})
+}
+
+func TestIsStreamContentType(t *testing.T) {
+	for contentType, expected := range map[string]bool{
+		"":                              true,
+		"audio/mpeg":                    true,
+		"audio/aacp":                    true,
+		"application/ogg":               true,
+		"video/mp2t":                    true,
+		"application/vnd.apple.mpegurl": true,
+		"text/html; charset=utf-8":      false,
+		"application/json":              false,
+	} {
+		t.Run(contentType, func(t *testing.T) {
+			assert.Equal(t, expected, isStreamContentType(contentType))
+		})
+	}
+}
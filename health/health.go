@@ -0,0 +1,69 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package health checks from this machine whether station streams can be played,
+// as RadioBrowser's own check (common.Station.LastCheckOk) runs elsewhere and can be stale.
+//
+// A probe connects to the stream, reads its HTTP and ICY headers and waits for the
+// first bytes of audio. Checker probes many stations at once with a bounded number of workers.
+package health
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the outcome of probing a stream.
+type Status string
+
+const (
+	// StatusOK means audio data was received.
+	StatusOK Status = "ok"
+	// StatusUnreachable means the server couldn't be connected to, or didn't answer in time.
+	StatusUnreachable Status = "unreachable"
+	// StatusHTTPError means the server answered with an error status code.
+	StatusHTTPError Status = "http_error"
+	// StatusNotAudio means the server answered with something other than a stream, e.g. a web page.
+	StatusNotAudio Status = "not_audio"
+	// StatusNoData means the server answered but sent no data in time.
+	StatusNoData Status = "no_data"
+)
+
+// Result is the outcome of probing a station's stream.
+type Result struct {
+	StationUuid uuid.UUID
+	Status      Status
+	// StatusCode is the HTTP status code, or 0 if the server didn't answer.
+	StatusCode  int
+	ContentType string
+	// IcyName and IcyBitrate come from the icy-name and icy-br headers of Shoutcast/Icecast servers.
+	IcyName    string
+	IcyBitrate string
+	// Latency is how long it took to receive the first bytes of audio.
+	Latency time.Duration
+	// Error describes why the probe failed (empty if it succeeded).
+	Error     string
+	CheckedAt time.Time
+}
+
+// OK returns true if the stream sent audio data.
+func (r Result) OK() bool {
+	return r.Status == StatusOK
+}
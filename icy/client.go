@@ -0,0 +1,145 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package icy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/data"
+)
+
+// ErrNoMetadata is returned by Watch when a stream doesn't carry ICY metadata.
+var ErrNoMetadata = errors.New("stream has no ICY metadata")
+
+// HTTPClient makes the requests of a Client. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client reads ICY metadata from station streams.
+type Client struct {
+	client HTTPClient
+}
+
+// NewClient returns a Client that also understands Shoutcast v1 servers,
+// which answer with an "ICY 200 OK" status line instead of HTTP.
+func NewClient() *Client {
+	return NewClientWithHTTPClient(NewHTTPClient())
+}
+
+// NewHTTPClient returns an HTTP client for station streams. Unlike the standard
+// client, it also understands Shoutcast v1 servers, which answer with an
+// "ICY 200 OK" status line instead of HTTP.
+func NewHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &icyConn{Conn: conn}, nil
+	}
+	// No client timeout: streams never end, so requests are bounded by their context instead
+	return &http.Client{Transport: transport}
+}
+
+// NewClientWithHTTPClient returns a Client making its requests with client.
+func NewClientWithHTTPClient(client HTTPClient) *Client {
+	return &Client{client: client}
+}
+
+// Watch connects to the stream and calls onTitle with the stream title every
+// time it changes, until ctx is cancelled or the stream ends.
+// Only the metadata is used; the audio is read and discarded.
+func (c *Client) Watch(ctx context.Context, streamURL string, onTitle func(title string)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", data.UserAgent)
+	req.Header.Set("Icy-MetaData", "1")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("stream request failed with status %d", resp.StatusCode)
+	}
+	metaint, err := strconv.Atoi(resp.Header.Get("icy-metaint"))
+	if err != nil || metaint <= 0 {
+		return ErrNoMetadata
+	}
+
+	reader := NewReader(resp.Body, metaint)
+	var title string
+	for {
+		metadata, err := reader.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if metadata.StreamTitle != title {
+			title = metadata.StreamTitle
+			onTitle(title)
+		}
+	}
+}
+
+// icyConn rewrites the "ICY" status line of Shoutcast v1 servers to HTTP/1.0,
+// so that net/http can read their responses.
+type icyConn struct {
+	net.Conn
+	started bool
+	pending []byte
+}
+
+func (c *icyConn) Read(p []byte) (int, error) {
+	if !c.started {
+		c.started = true
+		head := make([]byte, 4)
+		n, err := io.ReadFull(c.Conn, head)
+		if n == len(head) && string(head) == "ICY " {
+			c.pending = []byte("HTTP/1.0 ")
+		} else {
+			c.pending = head[:n]
+		}
+		if err != nil && len(c.pending) == 0 {
+			return 0, err
+		}
+	}
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
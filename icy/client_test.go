@@ -0,0 +1,171 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package icy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/data"
+)
+
+var testAudio = bytes.Repeat([]byte{0xff}, 16)
+
+// newICYServer serves a stream announcing each title in turn, then ends.
+func newICYServer(t *testing.T, titles ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Icy-MetaData") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, data.UserAgent, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("icy-metaint", fmt.Sprint(len(testAudio)))
+		for _, title := range titles {
+			_, _ = w.Write(icyChunk(testAudio, title))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Watch(t *testing.T) {
+	t.Run("reports each title change", func(t *testing.T) {
+		server := newICYServer(t, "Artist - First", "Artist - First", "", "Artist - Second")
+		var titles []string
+
+		err := NewClient().Watch(context.Background(), server.URL, func(title string) {
+			titles = append(titles, title)
+		})
+
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, []string{"Artist - First", "Artist - Second"}, titles)
+	})
+
+	t.Run("fails when the stream has no metadata", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = w.Write(testAudio)
+		}))
+		defer server.Close()
+
+		err := NewClient().Watch(context.Background(), server.URL, func(string) {})
+
+		assert.ErrorIs(t, err, ErrNoMetadata)
+	})
+
+	t.Run("fails on HTTP errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		err := NewClient().Watch(context.Background(), server.URL, func(string) {})
+
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("icy-metaint", fmt.Sprint(len(testAudio)))
+			_, _ = w.Write(icyChunk(testAudio, "Endless"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := NewClient().Watch(ctx, server.URL, func(string) { cancel() })
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("understands Shoutcast ICY status lines", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			// Read the request headers before answering
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == "\r\n" {
+					break
+				}
+			}
+			fmt.Fprintf(conn, "ICY 200 OK\r\nicy-name: Test FM\r\nicy-metaint: %d\r\n\r\n", len(testAudio))
+			_, _ = conn.Write(icyChunk(testAudio, "Old School - Song"))
+		}()
+		var titles []string
+
+		_ = NewClient().Watch(context.Background(), "http://"+listener.Addr().String(), func(title string) {
+			titles = append(titles, title)
+		})
+
+		assert.Equal(t, []string{"Old School - Song"}, titles)
+	})
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("sends titles and closes when the stream ends", func(t *testing.T) {
+		server := newICYServer(t, "One", "Two")
+		watcher := NewWatcher(NewClient())
+
+		var titles []string
+		for title := range watcher.Start(server.URL) {
+			titles = append(titles, title)
+		}
+
+		assert.Equal(t, []string{"One", "Two"}, titles)
+	})
+
+	t.Run("closes the channel when stopped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("icy-metaint", fmt.Sprint(len(testAudio)))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+		watcher := NewWatcher(NewClient())
+
+		titles := watcher.Start(server.URL)
+		watcher.Stop()
+
+		select {
+		case _, open := <-titles:
+			assert.False(t, open)
+		case <-time.After(5 * time.Second):
+			t.Fatal("titles channel wasn't closed")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package icy reads the song titles that Shoutcast and Icecast servers
+// interleave with the audio of a stream (ICY metadata).
+package icy
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Metadata is the content of one ICY metadata block.
+type Metadata struct {
+	// StreamTitle is what's playing, usually "Artist - Title".
+	StreamTitle string
+	// StreamURL is an optional link sent by the station.
+	StreamURL string
+}
+
+// ParseMetadata parses an ICY metadata block such as
+// "StreamTitle='Artist - Title';StreamUrl='http://example.com';".
+// Titles that aren't valid UTF-8 are decoded as Latin-1, which older servers use.
+func ParseMetadata(block string) Metadata {
+	block = strings.TrimRight(block, "\x00")
+
+	var metadata Metadata
+	for block != "" {
+		keyEnd := strings.Index(block, "='")
+		if keyEnd < 0 {
+			break
+		}
+		key := strings.TrimSpace(block[:keyEnd])
+		block = block[keyEnd+2:]
+
+		// Values may contain quotes ("Guns N' Roses"), so only a quote followed
+		// by a semicolon ends them
+		var value string
+		if valueEnd := strings.Index(block, "';"); valueEnd >= 0 {
+			value, block = block[:valueEnd], block[valueEnd+2:]
+		} else {
+			value, block = strings.TrimSuffix(block, "'"), ""
+		}
+
+		switch key {
+		case "StreamTitle":
+			metadata.StreamTitle = decodeText(value)
+		case "StreamUrl":
+			metadata.StreamURL = decodeText(value)
+		}
+	}
+	return metadata
+}
+
+// decodeText returns s as UTF-8, treating invalid UTF-8 as Latin-1.
+func decodeText(s string) string {
+	s = strings.TrimSpace(s)
+	if utf8.ValidString(s) {
+		return s
+	}
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+// Reader extracts the metadata blocks from an ICY stream. Every metaint bytes
+// of audio are followed by a length byte (in units of 16 bytes) and a block of
+// that length, which is empty when the metadata hasn't changed.
+type Reader struct {
+	r       *bufio.Reader
+	metaint int64
+}
+
+// NewReader returns a Reader for a stream whose icy-metaint header is metaint.
+func NewReader(r io.Reader, metaint int) *Reader {
+	return &Reader{r: bufio.NewReader(r), metaint: int64(metaint)}
+}
+
+// Next skips the audio and returns the next non-empty metadata block.
+func (r *Reader) Next() (Metadata, error) {
+	for {
+		if _, err := io.CopyN(io.Discard, r.r, r.metaint); err != nil {
+			return Metadata{}, err
+		}
+		length, err := r.r.ReadByte()
+		if err != nil {
+			return Metadata{}, err
+		}
+		if length == 0 {
+			continue
+		}
+		block := make([]byte, int(length)*16)
+		if _, err := io.ReadFull(r.r, block); err != nil {
+			return Metadata{}, err
+		}
+		return ParseMetadata(string(bytes.TrimRight(block, "\x00"))), nil
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package icy
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// icyChunk returns audio bytes followed by an ICY metadata block for title.
+// An empty title produces an empty ("unchanged") block.
+func icyChunk(audio []byte, title string) []byte {
+	var buf bytes.Buffer
+	buf.Write(audio)
+	if title == "" {
+		buf.WriteByte(0)
+		return buf.Bytes()
+	}
+	block := []byte("StreamTitle='" + title + "';")
+	length := (len(block) + 15) / 16
+	buf.WriteByte(byte(length))
+	buf.Write(block)
+	buf.Write(make([]byte, length*16-len(block)))
+	return buf.Bytes()
+}
+
+func TestParseMetadata(t *testing.T) {
+	t.Run("parses title and url", func(t *testing.T) {
+		metadata := ParseMetadata("StreamTitle='Artist - Song';StreamUrl='https://example.com';")
+
+		assert.Equal(t, "Artist - Song", metadata.StreamTitle)
+		assert.Equal(t, "https://example.com", metadata.StreamURL)
+	})
+
+	t.Run("keeps quotes and semicolons inside values", func(t *testing.T) {
+		metadata := ParseMetadata("StreamTitle='Guns N' Roses - Don't Cry; Live';StreamUrl='';")
+
+		assert.Equal(t, "Guns N' Roses - Don't Cry; Live", metadata.StreamTitle)
+	})
+
+	t.Run("handles a missing final semicolon and padding", func(t *testing.T) {
+		metadata := ParseMetadata("StreamTitle='Artist - Song'\x00\x00\x00")
+
+		assert.Equal(t, "Artist - Song", metadata.StreamTitle)
+	})
+
+	t.Run("decodes Latin-1 titles", func(t *testing.T) {
+		metadata := ParseMetadata("StreamTitle='Beyonc\xe9 - Halo';")
+
+		assert.Equal(t, "Beyoncé - Halo", metadata.StreamTitle)
+	})
+
+	t.Run("ignores malformed blocks", func(t *testing.T) {
+		assert.Equal(t, Metadata{}, ParseMetadata("garbage"))
+		assert.Equal(t, Metadata{}, ParseMetadata(""))
+	})
+}
+
+func TestReader(t *testing.T) {
+	audio := bytes.Repeat([]byte{0xff}, 8)
+
+	t.Run("returns blocks and skips empty ones", func(t *testing.T) {
+		var stream bytes.Buffer
+		stream.Write(icyChunk(audio, "First"))
+		stream.Write(icyChunk(audio, ""))
+		stream.Write(icyChunk(audio, "Second"))
+		reader := NewReader(&stream, len(audio))
+
+		first, err := reader.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, "First", first.StreamTitle)
+
+		second, err := reader.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, "Second", second.StreamTitle)
+
+		_, err = reader.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("reports a truncated block", func(t *testing.T) {
+		chunk := icyChunk(audio, "Cut short")
+		reader := NewReader(bytes.NewReader(chunk[:len(chunk)-5]), len(audio))
+
+		_, err := reader.Next()
+
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+	})
+}
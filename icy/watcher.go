@@ -0,0 +1,73 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package icy
+
+import (
+	"context"
+	"sync"
+)
+
+// Watcher follows the song titles of one stream at a time: starting a new
+// stream stops the previous one.
+type Watcher struct {
+	client *Client
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// NewWatcher returns a Watcher reading metadata with client.
+func NewWatcher(client *Client) *Watcher {
+	return &Watcher{client: client}
+}
+
+// Start stops the current stream, if any, and starts following streamURL.
+// Titles are sent on the returned channel, which is closed when the stream
+// ends, doesn't carry metadata, or is stopped.
+func (w *Watcher) Start(streamURL string) <-chan string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	titles := make(chan string)
+	go func() {
+		defer close(titles)
+		// Titles are best effort: errors just end the watch
+		_ = w.client.Watch(ctx, streamURL, func(title string) {
+			select {
+			case titles <- title:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return titles
+}
+
+// Stop stops following the current stream.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+	}
+}
@@ -20,12 +20,15 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/zi0p4tch0/radiogogo/common"
 )
 
 type MockRadioBrowserService struct {
 	GetStationsFunc func(
+		ctx context.Context,
 		stationQuery common.StationQuery,
 		searchTerm string,
 		order string,
@@ -35,14 +38,23 @@ type MockRadioBrowserService struct {
 		hideBroken bool,
 	) ([]common.Station, error)
 
-	ClickStationFunc func(station common.Station) (common.ClickStationResponse, error)
+	SearchStationsFunc func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error)
+
+	GetFacetsFunc func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error)
+
+	GetStationListFunc func(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error)
+
+	ClickStationFunc func(ctx context.Context, station common.Station) (common.ClickStationResponse, error)
+
+	GetStationsByUUIDsFunc func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error)
 
-	GetStationsByUUIDsFunc func(uuids []uuid.UUID) ([]common.Station, error)
+	VoteStationFunc func(ctx context.Context, station common.Station) (common.VoteStationResponse, error)
 
-	VoteStationFunc func(station common.Station) (common.VoteStationResponse, error)
+	CurrentMirrorFunc func(ctx context.Context) string
 }
 
 func (m *MockRadioBrowserService) GetStations(
+	ctx context.Context,
 	stationQuery common.StationQuery,
 	searchTerm string,
 	order string,
@@ -51,23 +63,51 @@ func (m *MockRadioBrowserService) GetStations(
 	limit uint64,
 	hideBroken bool,
 ) ([]common.Station, error) {
-	return m.GetStationsFunc(stationQuery, searchTerm, order, reverse, offset, limit, hideBroken)
+	return m.GetStationsFunc(ctx, stationQuery, searchTerm, order, reverse, offset, limit, hideBroken)
 }
 
-func (m *MockRadioBrowserService) ClickStation(station common.Station) (common.ClickStationResponse, error) {
-	return m.ClickStationFunc(station)
+func (m *MockRadioBrowserService) SearchStations(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+	if m.SearchStationsFunc != nil {
+		return m.SearchStationsFunc(ctx, params)
+	}
+	return []common.Station{}, nil
+}
+
+func (m *MockRadioBrowserService) GetFacets(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+	if m.GetFacetsFunc != nil {
+		return m.GetFacetsFunc(ctx, kind, filter, limit)
+	}
+	return []common.Facet{}, nil
 }
 
-func (m *MockRadioBrowserService) GetStationsByUUIDs(uuids []uuid.UUID) ([]common.Station, error) {
+func (m *MockRadioBrowserService) GetStationList(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+	if m.GetStationListFunc != nil {
+		return m.GetStationListFunc(ctx, list, offset, limit)
+	}
+	return []common.Station{}, nil
+}
+
+func (m *MockRadioBrowserService) ClickStation(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+	return m.ClickStationFunc(ctx, station)
+}
+
+func (m *MockRadioBrowserService) GetStationsByUUIDs(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
 	if m.GetStationsByUUIDsFunc != nil {
-		return m.GetStationsByUUIDsFunc(uuids)
+		return m.GetStationsByUUIDsFunc(ctx, uuids)
 	}
 	return []common.Station{}, nil
 }
 
-func (m *MockRadioBrowserService) VoteStation(station common.Station) (common.VoteStationResponse, error) {
+func (m *MockRadioBrowserService) VoteStation(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
 	if m.VoteStationFunc != nil {
-		return m.VoteStationFunc(station)
+		return m.VoteStationFunc(ctx, station)
 	}
 	return common.VoteStationResponse{Ok: true}, nil
 }
+
+func (m *MockRadioBrowserService) CurrentMirror(ctx context.Context) string {
+	if m.CurrentMirrorFunc != nil {
+		return m.CurrentMirrorFunc(ctx)
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mocks
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// errNoSuchHost is returned by MockDNSResolverService lookups without a configured func.
+var errNoSuchHost = errors.New("no such host")
+
+type MockDNSResolverService struct {
+	LookupSRVFunc  func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHostFunc func(ctx context.Context, host string) ([]string, error)
+	LookupAddrFunc func(ctx context.Context, addr string) ([]string, error)
+}
+
+func (m *MockDNSResolverService) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	if m.LookupSRVFunc != nil {
+		return m.LookupSRVFunc(ctx, service, proto, name)
+	}
+	return "", nil, errNoSuchHost
+}
+
+func (m *MockDNSResolverService) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if m.LookupHostFunc != nil {
+		return m.LookupHostFunc(ctx, host)
+	}
+	return nil, errNoSuchHost
+}
+
+func (m *MockDNSResolverService) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	if m.LookupAddrFunc != nil {
+		return m.LookupAddrFunc(ctx, addr)
+	}
+	return nil, errNoSuchHost
+}
@@ -19,7 +19,10 @@
 
 package mocks
 
-import "github.com/zi0p4tch0/radiogogo/common"
+import (
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/playback"
+)
 
 type MockPlaybackManagerService struct {
 	NameResult                          string
@@ -120,3 +123,102 @@ func (m *MockPlaybackManagerService) StopRecording() (string, error) {
 func (m *MockPlaybackManagerService) CurrentRecordingPath() string {
 	return m.CurrentRecordingPathResult
 }
+
+// MockLivePlaybackManagerService is a MockPlaybackManagerService whose player
+// also supports live volume changes and pausing, like mpv, reports the URL it
+// resolved the station to, watches its player and has a timeshift buffer.
+type MockLivePlaybackManagerService struct {
+	MockPlaybackManagerService
+	SetVolumeFunc      func(volume int) error
+	PauseFunc          func() error
+	ResumeFunc         func() error
+	IsPausedResult     bool
+	StreamURLResult    string
+	PlayerExitedResult <-chan playback.PlayerExit
+
+	TimeshiftBufferSize  int
+	TimeshiftingResult   bool
+	TimeshiftStateResult playback.TimeshiftState
+	JumpToLiveFunc       func() error
+}
+
+func (m *MockLivePlaybackManagerService) SetVolume(volume int) error {
+	if m.SetVolumeFunc != nil {
+		return m.SetVolumeFunc(volume)
+	}
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) Pause() error {
+	if m.PauseFunc != nil {
+		return m.PauseFunc()
+	}
+	m.IsPausedResult = true
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) Resume() error {
+	if m.ResumeFunc != nil {
+		return m.ResumeFunc()
+	}
+	m.IsPausedResult = false
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) IsPaused() bool {
+	return m.IsPausedResult
+}
+
+func (m *MockLivePlaybackManagerService) StreamURL() string {
+	return m.StreamURLResult
+}
+
+func (m *MockLivePlaybackManagerService) PlayerExited() <-chan playback.PlayerExit {
+	return m.PlayerExitedResult
+}
+
+func (m *MockLivePlaybackManagerService) EnableTimeshift(bufferSize int) {
+	m.TimeshiftBufferSize = bufferSize
+}
+
+func (m *MockLivePlaybackManagerService) IsTimeshifting() bool {
+	return m.TimeshiftingResult
+}
+
+func (m *MockLivePlaybackManagerService) PauseLive() error {
+	m.TimeshiftStateResult.Paused = true
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) ResumeLive() error {
+	m.TimeshiftStateResult.Paused = false
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) JumpToLive() error {
+	if m.JumpToLiveFunc != nil {
+		return m.JumpToLiveFunc()
+	}
+	m.TimeshiftStateResult.Paused = false
+	m.TimeshiftStateResult.Behind = 0
+	return nil
+}
+
+func (m *MockLivePlaybackManagerService) TimeshiftState() playback.TimeshiftState {
+	return m.TimeshiftStateResult
+}
+
+// MockRestartingPlaybackManagerService is a MockPlaybackManagerService whose
+// player is restarted on its own to change volume, like ffplay, keeping the
+// recording and timeshift buffer of the playing station.
+type MockRestartingPlaybackManagerService struct {
+	MockPlaybackManagerService
+	RestartPlayerFunc func(volume int) error
+}
+
+func (m *MockRestartingPlaybackManagerService) RestartPlayer(volume int) error {
+	if m.RestartPlayerFunc != nil {
+		return m.RestartPlayerFunc(volume)
+	}
+	return nil
+}
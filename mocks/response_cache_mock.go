@@ -0,0 +1,41 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mocks
+
+import "time"
+
+type MockResponseCache struct {
+	GetCachedResponseFunc func(key string) ([]byte, time.Time, bool)
+	PutCachedResponseFunc func(key string, data []byte, storedAt time.Time) error
+}
+
+func (m *MockResponseCache) GetCachedResponse(key string) ([]byte, time.Time, bool) {
+	if m.GetCachedResponseFunc != nil {
+		return m.GetCachedResponseFunc(key)
+	}
+	return nil, time.Time{}, false
+}
+
+func (m *MockResponseCache) PutCachedResponse(key string, data []byte, storedAt time.Time) error {
+	if m.PutCachedResponseFunc != nil {
+		return m.PutCachedResponseFunc(key, data, storedAt)
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/health"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 )
 
 type MockStationStorageService struct {
@@ -37,6 +40,31 @@ type MockStationStorageService struct {
 
 	GetLastVoteTimestampFunc func() (time.Time, bool)
 	SetLastVoteTimestampFunc func(timestamp time.Time) error
+
+	GetBookmarkedStationsFunc func() ([]common.Station, error)
+	SaveBookmarkSnapshotsFunc func(stations []common.Station) error
+
+	GetCustomStationsFunc   func() ([]common.Station, error)
+	SaveCustomStationFunc   func(station common.Station) error
+	DeleteCustomStationFunc func(stationUUID uuid.UUID) error
+	IsCustomStationFunc     func(stationUUID uuid.UUID) bool
+
+	GetHealthResultFunc   func(stationUUID uuid.UUID) (health.Result, bool)
+	SaveHealthResultsFunc func(results []health.Result) error
+
+	StartSongFunc      func(entry common.SongHistoryEntry) error
+	EndSongFunc        func(endedAt time.Time) error
+	GetSongHistoryFunc func(search string) ([]common.SongHistoryEntry, error)
+
+	GetAlarmsFunc   func() ([]schedule.Alarm, error)
+	SaveAlarmFunc   func(alarm schedule.Alarm) (schedule.Alarm, error)
+	DeleteAlarmFunc func(id int64) error
+
+	GetScheduledRecordingsFunc   func() ([]schedule.Recording, error)
+	SaveScheduledRecordingFunc   func(recording schedule.Recording) (schedule.Recording, error)
+	DeleteScheduledRecordingFunc func(id int64) error
+	GetRecordingRunsFunc         func(recordingID int64) ([]schedule.RecordingRun, error)
+	SaveRecordingRunFunc         func(run schedule.RecordingRun) (schedule.RecordingRun, error)
 }
 
 func (m *MockStationStorageService) GetBookmarks() ([]uuid.UUID, error) {
@@ -67,6 +95,139 @@ func (m *MockStationStorageService) IsBookmarked(stationUUID uuid.UUID) bool {
 	return false
 }
 
+func (m *MockStationStorageService) GetBookmarkedStations() ([]common.Station, error) {
+	if m.GetBookmarkedStationsFunc != nil {
+		return m.GetBookmarkedStationsFunc()
+	}
+	return []common.Station{}, nil
+}
+
+func (m *MockStationStorageService) SaveBookmarkSnapshots(stations []common.Station) error {
+	if m.SaveBookmarkSnapshotsFunc != nil {
+		return m.SaveBookmarkSnapshotsFunc(stations)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) GetCustomStations() ([]common.Station, error) {
+	if m.GetCustomStationsFunc != nil {
+		return m.GetCustomStationsFunc()
+	}
+	return []common.Station{}, nil
+}
+
+func (m *MockStationStorageService) SaveCustomStation(station common.Station) error {
+	if m.SaveCustomStationFunc != nil {
+		return m.SaveCustomStationFunc(station)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) DeleteCustomStation(stationUUID uuid.UUID) error {
+	if m.DeleteCustomStationFunc != nil {
+		return m.DeleteCustomStationFunc(stationUUID)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) IsCustomStation(stationUUID uuid.UUID) bool {
+	if m.IsCustomStationFunc != nil {
+		return m.IsCustomStationFunc(stationUUID)
+	}
+	return false
+}
+
+func (m *MockStationStorageService) GetHealthResult(stationUUID uuid.UUID) (health.Result, bool) {
+	if m.GetHealthResultFunc != nil {
+		return m.GetHealthResultFunc(stationUUID)
+	}
+	return health.Result{}, false
+}
+
+func (m *MockStationStorageService) SaveHealthResults(results []health.Result) error {
+	if m.SaveHealthResultsFunc != nil {
+		return m.SaveHealthResultsFunc(results)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) StartSong(entry common.SongHistoryEntry) error {
+	if m.StartSongFunc != nil {
+		return m.StartSongFunc(entry)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) EndSong(endedAt time.Time) error {
+	if m.EndSongFunc != nil {
+		return m.EndSongFunc(endedAt)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) GetSongHistory(search string) ([]common.SongHistoryEntry, error) {
+	if m.GetSongHistoryFunc != nil {
+		return m.GetSongHistoryFunc(search)
+	}
+	return []common.SongHistoryEntry{}, nil
+}
+
+func (m *MockStationStorageService) GetAlarms() ([]schedule.Alarm, error) {
+	if m.GetAlarmsFunc != nil {
+		return m.GetAlarmsFunc()
+	}
+	return []schedule.Alarm{}, nil
+}
+
+func (m *MockStationStorageService) SaveAlarm(alarm schedule.Alarm) (schedule.Alarm, error) {
+	if m.SaveAlarmFunc != nil {
+		return m.SaveAlarmFunc(alarm)
+	}
+	return alarm, nil
+}
+
+func (m *MockStationStorageService) DeleteAlarm(id int64) error {
+	if m.DeleteAlarmFunc != nil {
+		return m.DeleteAlarmFunc(id)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) GetScheduledRecordings() ([]schedule.Recording, error) {
+	if m.GetScheduledRecordingsFunc != nil {
+		return m.GetScheduledRecordingsFunc()
+	}
+	return []schedule.Recording{}, nil
+}
+
+func (m *MockStationStorageService) SaveScheduledRecording(recording schedule.Recording) (schedule.Recording, error) {
+	if m.SaveScheduledRecordingFunc != nil {
+		return m.SaveScheduledRecordingFunc(recording)
+	}
+	return recording, nil
+}
+
+func (m *MockStationStorageService) DeleteScheduledRecording(id int64) error {
+	if m.DeleteScheduledRecordingFunc != nil {
+		return m.DeleteScheduledRecordingFunc(id)
+	}
+	return nil
+}
+
+func (m *MockStationStorageService) GetRecordingRuns(recordingID int64) ([]schedule.RecordingRun, error) {
+	if m.GetRecordingRunsFunc != nil {
+		return m.GetRecordingRunsFunc(recordingID)
+	}
+	return []schedule.RecordingRun{}, nil
+}
+
+func (m *MockStationStorageService) SaveRecordingRun(run schedule.RecordingRun) (schedule.RecordingRun, error) {
+	if m.SaveRecordingRunFunc != nil {
+		return m.SaveRecordingRunFunc(run)
+	}
+	return run, nil
+}
+
 func (m *MockStationStorageService) GetHidden() ([]uuid.UUID, error) {
 	if m.GetHiddenFunc != nil {
 		return m.GetHiddenFunc()
@@ -0,0 +1,71 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mocks
+
+import (
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/playback"
+)
+
+type MockStreamRecorderService struct {
+	IsAvailableResult bool
+	StartFunc         func(id int64, station common.Station, outputPath string, duration time.Duration) error
+	StopFunc          func(id int64) (string, error)
+	StopAllFunc       func()
+	IsRecordingFunc   func(id int64) bool
+	ExitsResult       chan playback.RecordingExit
+}
+
+func (m *MockStreamRecorderService) IsAvailable() bool {
+	return m.IsAvailableResult
+}
+
+func (m *MockStreamRecorderService) Start(id int64, station common.Station, outputPath string, duration time.Duration) error {
+	if m.StartFunc != nil {
+		return m.StartFunc(id, station, outputPath, duration)
+	}
+	return nil
+}
+
+func (m *MockStreamRecorderService) Stop(id int64) (string, error) {
+	if m.StopFunc != nil {
+		return m.StopFunc(id)
+	}
+	return "", nil
+}
+
+func (m *MockStreamRecorderService) StopAll() {
+	if m.StopAllFunc != nil {
+		m.StopAllFunc()
+	}
+}
+
+func (m *MockStreamRecorderService) IsRecording(id int64) bool {
+	if m.IsRecordingFunc != nil {
+		return m.IsRecordingFunc(id)
+	}
+	return false
+}
+
+func (m *MockStreamRecorderService) Exits() <-chan playback.RecordingExit {
+	return m.ExitsResult
+}
@@ -0,0 +1,337 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultAlarmRampUp is the ramp-up offered for new alarms.
+const defaultAlarmRampUp = 5 * time.Minute
+
+// maxAlarmRampUp is the longest ramp-up an alarm can have.
+const maxAlarmRampUp = time.Hour
+
+// alarmWeek is the order the days of the week are shown in.
+var alarmWeek = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+// weekdayLabel returns the localized short name of a day of the week.
+func weekdayLabel(day time.Weekday) string {
+	return i18n.T("weekday_" + strings.ToLower(day.String()[:3]))
+}
+
+// weekdayPicker picks the days of the week something repeats on.
+type weekdayPicker struct {
+	days schedule.Weekdays
+	// day is the position in alarmWeek of the day the cursor is on
+	day int
+}
+
+// update moves the cursor with left and right, and picks the day under it with space or x.
+func (p weekdayPicker) update(key string) weekdayPicker {
+	switch key {
+	case "left":
+		p.day = (p.day + len(alarmWeek) - 1) % len(alarmWeek)
+	case "right":
+		p.day = (p.day + 1) % len(alarmWeek)
+	case " ", "x":
+		p.days = p.days.Toggle(alarmWeek[p.day])
+	}
+	return p
+}
+
+// view renders the days of the week, the picked ones highlighted, and the cursor when focused.
+func (p weekdayPicker) view(theme Theme, focused bool) string {
+	days := make([]string, len(alarmWeek))
+	for i, day := range alarmWeek {
+		label := weekdayLabel(day)
+		style := theme.TertiaryText
+		if p.days.Has(day) {
+			style = theme.PrimaryText.Bold(true)
+		}
+		if focused && i == p.day {
+			days[i] = theme.Text.Render("[") + style.Render(label) + theme.Text.Render("]")
+		} else {
+			days[i] = " " + style.Render(label) + " "
+		}
+	}
+	v := strings.Join(days, "")
+	if p.days == 0 {
+		v += "  " + theme.TertiaryText.Render(i18n.T("alarm_once"))
+	}
+	return v
+}
+
+// alarmField identifies a field of the alarm form.
+type alarmField int
+
+const (
+	alarmFieldStation alarmField = iota
+	alarmFieldTime
+	alarmFieldDays
+	alarmFieldVolume
+	alarmFieldRampUp
+	alarmFieldCount
+)
+
+// label returns the localized label for the field.
+func (f alarmField) label() string {
+	switch f {
+	case alarmFieldStation:
+		return i18n.T("header_station")
+	case alarmFieldTime:
+		return i18n.T("header_time")
+	case alarmFieldDays:
+		return i18n.T("header_repeat")
+	case alarmFieldVolume:
+		return i18n.T("header_volume")
+	case alarmFieldRampUp:
+		return i18n.T("alarm_field_ramp_up")
+	}
+	return ""
+}
+
+// AlarmForm adds or edits an alarm playing one of the bookmarked stations.
+type AlarmForm struct {
+	theme Theme
+
+	stations []common.Station
+	station  int
+	days     weekdayPicker
+	// inputs holds the text inputs, by field (unused for the station and days)
+	inputs []textinput.Model
+	focus  alarmField
+	err    string
+
+	volumeMin int
+	volumeMax int
+	// alarmID is the alarm being edited, or 0 when adding one
+	alarmID int64
+}
+
+// NewAlarmForm returns a form to add an alarm playing one of stations at volume,
+// which must be between volumeMin and volumeMax.
+func NewAlarmForm(theme Theme, stations []common.Station, volume, volumeMin, volumeMax int) AlarmForm {
+	inputs := make([]textinput.Model, alarmFieldCount)
+	for i := range inputs {
+		input := textinput.New()
+		input.Width = 10
+		input.Prompt = ""
+		input.TextStyle = theme.Text
+		input.PlaceholderStyle = theme.TertiaryText
+		inputs[i] = input
+	}
+	inputs[alarmFieldTime].Placeholder = "07:00"
+	inputs[alarmFieldTime].CharLimit = 5
+	inputs[alarmFieldVolume].CharLimit = 4
+	inputs[alarmFieldRampUp].CharLimit = 2
+
+	form := AlarmForm{
+		theme:     theme,
+		stations:  stations,
+		inputs:    inputs,
+		volumeMin: volumeMin,
+		volumeMax: volumeMax,
+	}
+	form.inputs[alarmFieldVolume].SetValue(strconv.Itoa(volume))
+	form.inputs[alarmFieldRampUp].SetValue(strconv.Itoa(int(defaultAlarmRampUp / time.Minute)))
+	form.setFocus(alarmFieldStation)
+	return form
+}
+
+// NewAlarmFormFor returns a form pre-filled to edit the given alarm. Its station is
+// offered even if it's no longer among stations.
+func NewAlarmFormFor(theme Theme, stations []common.Station, alarm schedule.Alarm, volumeMin, volumeMax int) AlarmForm {
+	station := -1
+	for i, s := range stations {
+		if s.StationUuid == alarm.Station.StationUuid {
+			station = i
+			break
+		}
+	}
+	if station < 0 {
+		stations = append([]common.Station{alarm.Station}, stations...)
+		station = 0
+	}
+
+	form := NewAlarmForm(theme, stations, alarm.Volume, volumeMin, volumeMax)
+	form.alarmID = alarm.ID
+	form.station = station
+	form.days.days = alarm.Days
+	form.inputs[alarmFieldTime].SetValue(alarm.TimeOfDay())
+	form.inputs[alarmFieldRampUp].SetValue(strconv.Itoa(int(alarm.RampUp / time.Minute)))
+	return form
+}
+
+// IsEditing returns true if the form edits an existing alarm rather than adding one.
+func (m AlarmForm) IsEditing() bool {
+	return m.alarmID != 0
+}
+
+// setFocus moves focus to the given field.
+func (m *AlarmForm) setFocus(field alarmField) {
+	m.focus = field
+	for i := range m.inputs {
+		if alarmField(i) == field {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+// Alarm builds the alarm described by the form. Saving an alarm turns it on.
+// Returns an error if the time, volume or ramp-up aren't valid.
+func (m AlarmForm) Alarm() (schedule.Alarm, error) {
+	value := func(field alarmField) string {
+		return strings.TrimSpace(m.inputs[field].Value())
+	}
+
+	rawTime := value(alarmFieldTime)
+	hour, minute, err := schedule.ParseTimeOfDay(rawTime)
+	if err != nil {
+		return schedule.Alarm{}, fmt.Errorf("%s", i18n.Tf("error_alarm_time_invalid", map[string]interface{}{"Value": rawTime}))
+	}
+
+	volume, err := strconv.Atoi(value(alarmFieldVolume))
+	if err != nil || volume < m.volumeMin || volume > m.volumeMax {
+		return schedule.Alarm{}, fmt.Errorf("%s", i18n.Tf("error_alarm_volume_invalid", map[string]interface{}{"Min": m.volumeMin, "Max": m.volumeMax}))
+	}
+
+	rampUp := 0
+	if v := value(alarmFieldRampUp); v != "" {
+		rampUp, err = strconv.Atoi(v)
+		if err != nil || rampUp < 0 || time.Duration(rampUp)*time.Minute > maxAlarmRampUp {
+			return schedule.Alarm{}, fmt.Errorf("%s", i18n.Tf("error_alarm_ramp_up_invalid", map[string]interface{}{"Max": int(maxAlarmRampUp / time.Minute)}))
+		}
+	}
+
+	return schedule.Alarm{
+		ID:      m.alarmID,
+		Station: m.stations[m.station],
+		Hour:    hour,
+		Minute:  minute,
+		Days:    m.days.days,
+		Volume:  volume,
+		RampUp:  time.Duration(rampUp) * time.Minute,
+		Enabled: true,
+	}, nil
+}
+
+// SetError sets the validation error shown below the form.
+func (m *AlarmForm) SetError(err string) {
+	m.err = err
+}
+
+// Bubbletea
+
+func (m AlarmForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m AlarmForm) Update(msg tea.Msg) (AlarmForm, tea.Cmd) {
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			m.setFocus((m.focus + 1) % alarmFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focus + alarmFieldCount - 1) % alarmFieldCount)
+			return m, nil
+		}
+		m.err = ""
+
+		switch m.focus {
+		case alarmFieldStation:
+			switch msg.String() {
+			case "left":
+				m.station = (m.station + len(m.stations) - 1) % len(m.stations)
+			case "right":
+				m.station = (m.station + 1) % len(m.stations)
+			}
+			return m, nil
+		case alarmFieldDays:
+			m.days = m.days.update(msg.String())
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m AlarmForm) View() string {
+
+	title := i18n.T("alarm_add_title")
+	if m.IsEditing() {
+		title = i18n.T("alarm_edit_title")
+	}
+	v := m.theme.SecondaryText.Bold(true).Render(title) + "\n\n"
+
+	labels := make([]string, alarmFieldCount)
+	labelWidth := 0
+	for f := alarmField(0); f < alarmFieldCount; f++ {
+		labels[f] = f.label()
+		if w := len([]rune(labels[f])); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	for f := alarmField(0); f < alarmFieldCount; f++ {
+		cursor := "  "
+		if f == m.focus {
+			cursor = "> "
+		}
+		label := labels[f] + strings.Repeat(" ", labelWidth-len([]rune(labels[f])))
+		var field string
+		switch f {
+		case alarmFieldStation:
+			field = m.theme.Text.Render("◀ " + m.stations[m.station].Name + " ▶")
+		case alarmFieldDays:
+			field = m.days.view(m.theme, m.focus == alarmFieldDays)
+		case alarmFieldRampUp:
+			field = m.inputs[f].View() + " " + m.theme.TertiaryText.Render(i18n.T("alarm_minutes"))
+		default:
+			field = m.inputs[f].View()
+		}
+		v += m.theme.Text.Render(cursor+label+"  ") + field + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+
+	v += "\n" + m.theme.TertiaryText.Render(i18n.T("alarm_form_help"))
+
+	return v
+}
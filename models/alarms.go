@@ -0,0 +1,433 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+	"github.com/zi0p4tch0/radiogogo/storage"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// alarmsChromeHeight is the number of lines the alarms view uses around the table
+// (title, counter, status line and spacing).
+const alarmsChromeHeight = 7
+
+// alarmCheckInterval is how often the alarms are checked for one that's due.
+const alarmCheckInterval = 5 * time.Second
+
+// alarmMaxDelay is how late an alarm still rings, e.g. after the computer slept
+// through it. Older alarms are skipped.
+const alarmMaxDelay = 5 * time.Minute
+
+// alarmTickMsg checks whether an alarm is due.
+type alarmTickMsg struct{}
+
+// scheduleAlarmCheckCmd sends the next alarmTickMsg after interval.
+func scheduleAlarmCheckCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return alarmTickMsg{}
+	})
+}
+
+// AlarmsModel lists the alarms, which start playing a bookmarked station at a time
+// of day, and adds, edits, deletes and turns them on and off.
+type AlarmsModel struct {
+	theme           Theme
+	storage         storage.StationStorageService
+	playbackManager playback.PlaybackManagerService
+	keybindings     config.Keybindings
+	clock           schedule.Clock
+
+	alarmsTable table.Model
+	alarms      []schedule.Alarm
+	// stations are the bookmarked stations an alarm can play
+	stations []common.Station
+
+	showForm bool
+	form     AlarmForm
+	// deleteCandidate is the alarm waiting for delete confirmation (0 if none)
+	deleteCandidate int64
+
+	loading    bool
+	err        string
+	successMsg string
+
+	width  int
+	height int
+}
+
+func NewAlarmsModel(
+	theme Theme,
+	storage storage.StationStorageService,
+	playbackManager playback.PlaybackManagerService,
+	clock schedule.Clock,
+	keybindings config.Keybindings,
+) AlarmsModel {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: "", Width: 1},
+			{Title: i18n.T("header_time"), Width: 6},
+			{Title: i18n.T("header_repeat"), Width: 20},
+			{Title: i18n.T("header_station"), Width: 30},
+			{Title: i18n.T("header_volume"), Width: 7},
+			{Title: i18n.T("alarm_field_ramp_up"), Width: 9},
+			{Title: i18n.T("header_next_ring"), Width: 20},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(theme.StationsTableStyle)
+
+	return AlarmsModel{
+		theme:           theme,
+		storage:         storage,
+		playbackManager: playbackManager,
+		keybindings:     keybindings,
+		clock:           clock,
+		alarmsTable:     t,
+		loading:         true,
+	}
+}
+
+// Messages
+
+type alarmsLoadedMsg struct {
+	alarms   []schedule.Alarm
+	stations []common.Station
+	err      error
+}
+
+type alarmSavedMsg struct {
+	alarm schedule.Alarm
+	err   error
+}
+
+type alarmDeletedMsg struct {
+	err error
+}
+
+// Commands
+
+// loadAlarmsCmd loads the alarms, and the bookmarked stations they can play.
+func loadAlarmsCmd(storage storage.StationStorageService) tea.Cmd {
+	return func() tea.Msg {
+		alarms, err := storage.GetAlarms()
+		if err != nil {
+			return alarmsLoadedMsg{err: err}
+		}
+		stations, err := savedBookmarkedStations(storage)
+		return alarmsLoadedMsg{alarms: alarms, stations: stations, err: err}
+	}
+}
+
+func saveAlarmCmd(storage storage.StationStorageService, alarm schedule.Alarm) tea.Cmd {
+	return func() tea.Msg {
+		saved, err := storage.SaveAlarm(alarm)
+		return alarmSavedMsg{alarm: saved, err: err}
+	}
+}
+
+func deleteAlarmCmd(storage storage.StationStorageService, id int64) tea.Cmd {
+	return func() tea.Msg {
+		return alarmDeletedMsg{err: storage.DeleteAlarm(id)}
+	}
+}
+
+func updateAlarmsCommandsCmd(kb config.Keybindings) tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": "esc"}),
+				i18n.T("cmd_move"),
+				i18n.T("cmd_alarm_toggle"),
+				i18n.Tf("cmd_alarm_add", map[string]interface{}{"Key": kb.AddStation}),
+				i18n.Tf("cmd_alarm_edit", map[string]interface{}{"Key": kb.EditStation}),
+				i18n.Tf("cmd_alarm_delete", map[string]interface{}{"Key": kb.DeleteStation}),
+				i18n.T("current_language"),
+			},
+		}
+	}
+}
+
+// Bubbletea
+
+func (m AlarmsModel) Init() tea.Cmd {
+	return tea.Batch(
+		updateAlarmsCommandsCmd(m.keybindings),
+		loadAlarmsCmd(m.storage),
+	)
+}
+
+func (m AlarmsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case alarmsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = i18n.Tf("error_load_alarms", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.stations = msg.stations
+		m.setAlarms(msg.alarms)
+		return m, nil
+
+	case alarmSavedMsg:
+		if msg.err != nil {
+			m.successMsg = ""
+			m.err = i18n.Tf("error_save_alarm", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		m.successMsg = ""
+		if msg.alarm.Enabled {
+			m.successMsg = i18n.Tf("alarm_saved", map[string]interface{}{
+				"Station": msg.alarm.Station.Name,
+				"Next":    m.formatRing(msg.alarm.NextRing(m.clock.Now())),
+			})
+		}
+		return m, loadAlarmsCmd(m.storage)
+
+	case alarmDeletedMsg:
+		if msg.err != nil {
+			m.err = i18n.Tf("error_delete_alarm", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		return m, loadAlarmsCmd(m.storage)
+
+	case tea.KeyMsg:
+		if m.showForm {
+			return m.updateForm(msg)
+		}
+
+		key := msg.String()
+		// Any key other than delete cancels a pending delete
+		if m.deleteCandidate != 0 && key != m.keybindings.DeleteStation {
+			m.deleteCandidate = 0
+		}
+
+		switch key {
+		case "esc":
+			return m, func() tea.Msg {
+				return switchToSearchModelMsg{}
+			}
+		case "up", "down", "pgup", "pgdown", m.keybindings.NavigateUp, m.keybindings.NavigateDown:
+			newTable, cmd := m.alarmsTable.Update(msg)
+			m.alarmsTable = newTable
+			return m, cmd
+		case "enter":
+			alarm, ok := m.selectedAlarm()
+			if !ok {
+				return m, nil
+			}
+			alarm.Enabled = !alarm.Enabled
+			return m, saveAlarmCmd(m.storage, alarm)
+		case m.keybindings.AddStation:
+			if len(m.stations) == 0 {
+				m.err = i18n.T("alarm_no_bookmarks")
+				return m, nil
+			}
+			m.err = ""
+			m.form = NewAlarmForm(m.theme, m.stations, m.playbackManager.VolumeDefault(), m.playbackManager.VolumeMin(), m.playbackManager.VolumeMax())
+			m.showForm = true
+			return m, m.form.Init()
+		case m.keybindings.EditStation:
+			alarm, ok := m.selectedAlarm()
+			if !ok {
+				return m, nil
+			}
+			m.form = NewAlarmFormFor(m.theme, m.stations, alarm, m.playbackManager.VolumeMin(), m.playbackManager.VolumeMax())
+			m.showForm = true
+			return m, m.form.Init()
+		case m.keybindings.DeleteStation:
+			alarm, ok := m.selectedAlarm()
+			if !ok {
+				return m, nil
+			}
+			if m.deleteCandidate != alarm.ID {
+				m.deleteCandidate = alarm.ID
+				return m, nil
+			}
+			m.deleteCandidate = 0
+			return m, deleteAlarmCmd(m.storage, alarm.ID)
+		}
+	}
+
+	if m.showForm {
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateForm handles key presses while the alarm form is open.
+func (m AlarmsModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showForm = false
+		return m, nil
+	case "enter":
+		alarm, err := m.form.Alarm()
+		if err != nil {
+			m.form.SetError(err.Error())
+			return m, nil
+		}
+		m.showForm = false
+		return m, saveAlarmCmd(m.storage, alarm)
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+	return m, cmd
+}
+
+// selectedAlarm returns the alarm under the cursor, if there's any.
+func (m AlarmsModel) selectedAlarm() (schedule.Alarm, bool) {
+	if len(m.alarms) == 0 {
+		return schedule.Alarm{}, false
+	}
+	return m.alarms[m.alarmsTable.Cursor()], true
+}
+
+// setAlarms replaces the alarms shown in the table, keeping the cursor where it was.
+func (m *AlarmsModel) setAlarms(alarms []schedule.Alarm) {
+	now := m.clock.Now()
+	rows := make([]table.Row, len(alarms))
+	for i, alarm := range alarms {
+		enabled, next := "○", "—"
+		if alarm.Enabled {
+			enabled = "●"
+			next = m.formatRing(alarm.NextRing(now))
+		}
+		rampUp := "—"
+		if alarm.RampUp > 0 {
+			rampUp = i18n.Tf("alarm_ramp_up_minutes", map[string]interface{}{"Minutes": int(alarm.RampUp / time.Minute)})
+		}
+		rows[i] = table.Row{
+			enabled,
+			alarm.TimeOfDay(),
+			formatAlarmDays(alarm.Days),
+			alarm.Station.Name,
+			fmt.Sprintf("%d", alarm.Volume),
+			rampUp,
+			next,
+		}
+	}
+	m.alarms = alarms
+	m.alarmsTable.SetRows(rows)
+	if cursor := m.alarmsTable.Cursor(); cursor >= len(alarms) {
+		m.alarmsTable.SetCursor(max(len(alarms)-1, 0))
+	}
+}
+
+// formatRing formats when an alarm rings next: its weekday and date, and its time.
+func (m AlarmsModel) formatRing(ring time.Time) string {
+	return weekdayLabel(ring.Weekday()) + " " + ring.Format("2006-01-02 15:04")
+}
+
+// formatAlarmDays describes the days an alarm rings on.
+func formatAlarmDays(days schedule.Weekdays) string {
+	workWeek := schedule.EveryDay.Toggle(time.Saturday).Toggle(time.Sunday)
+	switch days {
+	case 0:
+		return i18n.T("alarm_once")
+	case schedule.EveryDay:
+		return i18n.T("alarm_every_day")
+	case workWeek:
+		return i18n.T("alarm_work_week")
+	case schedule.EveryDay ^ workWeek:
+		return i18n.T("alarm_weekend")
+	}
+	labels := []string{}
+	for _, day := range alarmWeek {
+		if days.Has(day) {
+			labels = append(labels, weekdayLabel(day))
+		}
+	}
+	return strings.Join(labels, " ")
+}
+
+func (m AlarmsModel) View() string {
+	if m.showForm {
+		modal := m.theme.ModalStyle.Render(m.form.View())
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+	}
+
+	v := fmt.Sprintf("\n%s\n\n", m.theme.SecondaryText.Bold(true).Render(i18n.T("alarms_title")))
+
+	switch {
+	case m.loading:
+		v += m.theme.TertiaryText.Render(i18n.T("loading")) + "\n"
+	case len(m.alarms) == 0:
+		v += m.theme.TertiaryText.Render(i18n.Tf("alarms_empty", map[string]interface{}{"Key": m.keybindings.AddStation})) + "\n"
+	default:
+		v += m.alarmsTable.View() + "\n\n" +
+			m.theme.TertiaryText.Render(i18n.Tfn("alarms_count", len(m.alarms), map[string]interface{}{"Count": len(m.alarms)})) + "\n"
+	}
+
+	switch {
+	case m.deleteCandidate != 0:
+		alarm, _ := m.selectedAlarm()
+		v += "\n" + m.theme.ErrorText.Render(i18n.Tf("alarm_delete_confirm", map[string]interface{}{
+			"Time": alarm.TimeOfDay(),
+			"Key":  m.keybindings.DeleteStation,
+		})) + "\n"
+	case m.err != "":
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	case m.successMsg != "":
+		v += "\n" + m.theme.SecondaryText.Render(m.successMsg) + "\n"
+	}
+
+	return v
+}
+
+func (m *AlarmsModel) SetWidthAndHeight(width int, height int) {
+	m.width = width
+	m.height = height
+
+	tableHeight := height - alarmsChromeHeight
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.alarmsTable.SetHeight(tableHeight)
+
+	// The station column takes whatever width is left
+	columns := m.alarmsTable.Columns()
+	stationWidth := width - 8
+	for i, column := range columns {
+		if i != 3 {
+			stationWidth -= column.Width + 2
+		}
+	}
+	if stationWidth < 20 {
+		stationWidth = 20
+	}
+	columns[3].Width = stationWidth
+	m.alarmsTable.SetColumns(columns)
+}
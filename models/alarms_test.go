@@ -0,0 +1,326 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// testAlarmsNow is a Sunday morning.
+var testAlarmsNow = time.Date(2026, 3, 1, 6, 0, 0, 0, time.Local)
+
+func testAlarms() []schedule.Alarm {
+	return []schedule.Alarm{
+		{ID: 1, Station: createTestStation("Morning Radio"), Hour: 7, Minute: 30, Days: schedule.EveryDay, Volume: 60, RampUp: 5 * time.Minute, Enabled: true},
+		{ID: 2, Station: createTestStation("Jazz FM"), Hour: 9, Minute: 0, Volume: 40},
+	}
+}
+
+func newTestAlarmsModel(storage *mocks.MockStationStorageService) AlarmsModel {
+	pm := &mocks.MockPlaybackManagerService{VolumeMinResult: 0, VolumeDefaultResult: 80, VolumeMaxResult: 100}
+	return NewAlarmsModel(Theme{}, storage, pm, &mocks.MockClock{NowResult: testAlarmsNow}, defaultStationsKeybindings)
+}
+
+func TestAlarmsModel(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	press := func(model AlarmsModel, key string) (AlarmsModel, tea.Cmd) {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(AlarmsModel), cmd
+	}
+
+	t.Run("loads the alarms and the bookmarks they can play", func(t *testing.T) {
+		bookmarked := createTestStation("Morning Radio")
+		storage := &mocks.MockStationStorageService{
+			GetAlarmsFunc:             func() ([]schedule.Alarm, error) { return testAlarms(), nil },
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) { return []common.Station{bookmarked}, nil },
+		}
+		model := NewAlarmsModel(Theme{}, storage, &mocks.MockPlaybackManagerService{}, &mocks.MockClock{NowResult: testAlarmsNow}, defaultStationsKeybindings)
+		model.SetWidthAndHeight(120, 30)
+
+		msg := findMsgInCmd(model.Init(), func(msg tea.Msg) bool {
+			_, ok := msg.(alarmsLoadedMsg)
+			return ok
+		})
+		newModel, _ := model.Update(msg)
+		model = newModel.(AlarmsModel)
+
+		assert.False(t, model.loading)
+		assert.Equal(t, []common.Station{bookmarked}, model.stations)
+		rows := model.alarmsTable.Rows()
+		assert.Len(t, rows, 2)
+		assert.Equal(t, []string{"●", "07:30", "Every day", "Morning Radio", "60", "5 min", "Sun 2026-03-01 07:30"}, []string(rows[0]))
+		assert.Equal(t, []string{"○", "09:00", "Once", "Jazz FM", "40", "—", "—"}, []string(rows[1]))
+		assert.Contains(t, model.View(), "2 alarms")
+	})
+
+	t.Run("reports load errors", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{})
+
+		newModel, _ := model.Update(alarmsLoadedMsg{err: errors.New("disk full")})
+
+		assert.Contains(t, newModel.(AlarmsModel).View(), "Failed to load alarms: disk full")
+	})
+
+	t.Run("shows an empty list", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{})
+
+		assert.Contains(t, model.View(), "No alarms yet. Press a")
+	})
+
+	t.Run("enter turns the selected alarm on and off", func(t *testing.T) {
+		var saved schedule.Alarm
+		storage := &mocks.MockStationStorageService{
+			SaveAlarmFunc: func(alarm schedule.Alarm) (schedule.Alarm, error) {
+				saved = alarm
+				return alarm, nil
+			},
+		}
+		model := loadedModel(newTestAlarmsModel(storage), alarmsLoadedMsg{alarms: testAlarms()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg := cmd()
+		assert.Equal(t, int64(1), saved.ID)
+		assert.False(t, saved.Enabled)
+
+		newModel, cmd := model.Update(msg)
+		assert.Empty(t, newModel.(AlarmsModel).successMsg)
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("needs a bookmark to add an alarm", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{})
+
+		model, _ = press(model, "a")
+
+		assert.False(t, model.showForm)
+		assert.Contains(t, model.View(), "Bookmark a station first")
+	})
+
+	t.Run("adds an alarm with the form", func(t *testing.T) {
+		var saved schedule.Alarm
+		storage := &mocks.MockStationStorageService{
+			SaveAlarmFunc: func(alarm schedule.Alarm) (schedule.Alarm, error) {
+				saved = alarm
+				alarm.ID = 3
+				return alarm, nil
+			},
+		}
+		stations := []common.Station{createTestStation("Jazz FM"), createTestStation("Morning Radio")}
+		model := loadedModel(newTestAlarmsModel(storage), alarmsLoadedMsg{stations: stations})
+
+		model, _ = press(model, "a")
+		assert.True(t, model.showForm)
+		assert.Contains(t, model.View(), "New alarm")
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRight})
+		model = newModel.(AlarmsModel)
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(AlarmsModel)
+		model, _ = press(model, "6:45")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(AlarmsModel)
+		assert.False(t, model.showForm)
+
+		newModel, _ = model.Update(cmd())
+		assert.Equal(t, schedule.Alarm{
+			Station: stations[1],
+			Hour:    6,
+			Minute:  45,
+			Volume:  80,
+			RampUp:  defaultAlarmRampUp,
+			Enabled: true,
+		}, saved)
+		assert.Equal(t, "Alarm saved: Morning Radio will play Sun 2026-03-01 06:45", newModel.(AlarmsModel).successMsg)
+	})
+
+	t.Run("keeps the form open on invalid input", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{stations: []common.Station{createTestStation("Jazz FM")}})
+
+		model, _ = press(model, "a")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(AlarmsModel)
+		model, _ = press(model, "25:00")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(AlarmsModel)
+
+		assert.Nil(t, cmd)
+		assert.True(t, model.showForm)
+		assert.Contains(t, model.View(), `"25:00" isn't a time`)
+	})
+
+	t.Run("edits the selected alarm", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{alarms: testAlarms()})
+
+		model, _ = press(model, "e")
+
+		assert.True(t, model.showForm)
+		assert.True(t, model.form.IsEditing())
+		assert.Contains(t, model.View(), "Edit alarm")
+		alarm, err := model.form.Alarm()
+		assert.NoError(t, err)
+		assert.Equal(t, model.alarms[0], alarm)
+	})
+
+	t.Run("esc closes the form", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{alarms: testAlarms()})
+
+		model, _ = press(model, "e")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Nil(t, cmd)
+		assert.False(t, newModel.(AlarmsModel).showForm)
+	})
+
+	t.Run("deletes the selected alarm once confirmed", func(t *testing.T) {
+		var deleted int64
+		storage := &mocks.MockStationStorageService{
+			DeleteAlarmFunc: func(id int64) error {
+				deleted = id
+				return nil
+			},
+		}
+		model := loadedModel(newTestAlarmsModel(storage), alarmsLoadedMsg{alarms: testAlarms()})
+		model.alarmsTable.MoveDown(1)
+
+		model, cmd := press(model, "D")
+		assert.Nil(t, cmd)
+		assert.Contains(t, model.View(), "Delete the 09:00 alarm? Press D again to confirm")
+
+		model, cmd = press(model, "D")
+		cmd()
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, int64(0), model.deleteCandidate)
+	})
+
+	t.Run("other keys cancel a delete", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{alarms: testAlarms()})
+
+		model, _ = press(model, "D")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+		assert.Equal(t, int64(0), newModel.(AlarmsModel).deleteCandidate)
+	})
+
+	t.Run("esc goes back to search", func(t *testing.T) {
+		model := loadedModel(newTestAlarmsModel(&mocks.MockStationStorageService{}), alarmsLoadedMsg{alarms: testAlarms()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Equal(t, switchToSearchModelMsg{}, cmd())
+	})
+}
+
+func TestAlarmForm(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	stations := []common.Station{createTestStation("Jazz FM"), createTestStation("Morning Radio")}
+	valid := func(form AlarmForm) AlarmForm {
+		form.inputs[alarmFieldTime].SetValue("07:00")
+		return form
+	}
+
+	t.Run("picks the station with left and right", func(t *testing.T) {
+		form := valid(NewAlarmForm(Theme{}, stations, 50, 0, 100))
+
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		alarm, err := form.Alarm()
+
+		assert.NoError(t, err)
+		assert.Equal(t, stations[1], alarm.Station)
+	})
+
+	t.Run("toggles days of the week", func(t *testing.T) {
+		form := valid(NewAlarmForm(Theme{}, stations, 50, 0, 100))
+		form.setFocus(alarmFieldDays)
+
+		// Monday, then Wednesday, then Monday off again
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyRight})
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyRight})
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		alarm, _ := form.Alarm()
+		assert.Equal(t, schedule.Weekdays(0).Toggle(time.Monday).Toggle(time.Wednesday), alarm.Days)
+
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeyLeft})
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+		alarm, _ = form.Alarm()
+		assert.Equal(t, schedule.Weekdays(0).Toggle(time.Wednesday), alarm.Days)
+	})
+
+	t.Run("validates the volume against the player's range", func(t *testing.T) {
+		form := valid(NewAlarmForm(Theme{}, stations, 50, 0, 100))
+		form.inputs[alarmFieldVolume].SetValue("150")
+
+		_, err := form.Alarm()
+
+		assert.EqualError(t, err, "The volume must be between 0 and 100")
+	})
+
+	t.Run("validates the ramp-up", func(t *testing.T) {
+		form := valid(NewAlarmForm(Theme{}, stations, 50, 0, 100))
+
+		form.inputs[alarmFieldRampUp].SetValue("90")
+		_, err := form.Alarm()
+		assert.EqualError(t, err, "The ramp-up must be between 0 and 60 minutes")
+
+		form.inputs[alarmFieldRampUp].SetValue("")
+		alarm, err := form.Alarm()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), alarm.RampUp)
+	})
+
+	t.Run("offers the station of an edited alarm that's no longer bookmarked", func(t *testing.T) {
+		alarm := testAlarms()[0]
+
+		form := NewAlarmFormFor(Theme{}, stations, alarm, 0, 100)
+		edited, err := form.Alarm()
+
+		assert.NoError(t, err)
+		assert.Equal(t, alarm, edited)
+		assert.Len(t, form.stations, 3)
+	})
+}
+
+func TestFormatAlarmDays(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	weekend := schedule.Weekdays(0).Toggle(time.Saturday).Toggle(time.Sunday)
+	assert.Equal(t, "Once", formatAlarmDays(0))
+	assert.Equal(t, "Every day", formatAlarmDays(schedule.EveryDay))
+	assert.Equal(t, "Mon–Fri", formatAlarmDays(schedule.EveryDay^weekend))
+	assert.Equal(t, "Weekend", formatAlarmDays(weekend))
+	// Listed from Monday
+	assert.Equal(t, "Tue Sun", formatAlarmDays(schedule.Weekdays(0).Toggle(time.Sunday).Toggle(time.Tuesday)))
+}
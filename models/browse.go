@@ -0,0 +1,329 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browseFacetLimit caps how many values are fetched per facet listing.
+// Listings are sorted by station count, so only rarely used values (mostly tags) are cut.
+const browseFacetLimit = 2000
+
+// browseChromeHeight is the number of lines the browse view uses around the table
+// (title, facet tabs, filter input, counter and spacing).
+const browseChromeHeight = 9
+
+// BrowseModel lets the user pick a country, state, language, tag or codec from
+// RadioBrowser's facet listings and search for the stations matching it exactly.
+type BrowseModel struct {
+	theme       Theme
+	browser     api.RadioBrowserService
+	keybindings config.Keybindings
+
+	kinds       []common.FacetKind
+	kindIndex   int
+	filterInput textinput.Model
+	facetsTable table.Model
+
+	// facets caches the fetched listings so switching tabs doesn't refetch them
+	facets map[common.FacetKind][]common.Facet
+	// visible holds the facets currently shown in the table (after filtering)
+	visible []common.Facet
+	loading bool
+	err     string
+
+	width  int
+	height int
+
+	// Cancels in-flight listing requests when the user leaves the browse screen
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewBrowseModel(theme Theme, browser api.RadioBrowserService, keybindings config.Keybindings) BrowseModel {
+	i := textinput.New()
+	i.Placeholder = i18n.T("browse_filter_placeholder")
+	i.Width = 30
+	i.TextStyle = theme.Text
+	i.PlaceholderStyle = theme.TertiaryText
+	i.Focus()
+
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: i18n.T("header_name"), Width: 50},
+			{Title: i18n.T("header_stations"), Width: 10},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(theme.StationsTableStyle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return BrowseModel{
+		theme:       theme,
+		browser:     browser,
+		keybindings: keybindings,
+		kinds:       common.AllFacetKinds(),
+		filterInput: i,
+		facetsTable: t,
+		facets:      make(map[common.FacetKind][]common.Facet),
+		loading:     true,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Kind returns the facet listing currently shown.
+func (m BrowseModel) Kind() common.FacetKind {
+	return m.kinds[m.kindIndex]
+}
+
+// Messages
+
+type facetsFetchedMsg struct {
+	kind   common.FacetKind
+	facets []common.Facet
+}
+
+type facetsFetchFailedMsg struct {
+	kind common.FacetKind
+	err  error
+}
+
+// Commands
+
+// fetchFacetsCmd loads a facet listing.
+// If ctx is cancelled (the user left the browse screen) the result is discarded.
+func fetchFacetsCmd(ctx context.Context, browser api.RadioBrowserService, kind common.FacetKind) tea.Cmd {
+	return func() tea.Msg {
+		facets, err := browser.GetFacets(ctx, kind, "", browseFacetLimit)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return facetsFetchFailedMsg{kind: kind, err: err}
+		}
+		return facetsFetchedMsg{kind: kind, facets: facets}
+	}
+}
+
+func updateBrowseCommandsCmd() tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": "esc"}),
+				i18n.T("cmd_change_list"),
+				i18n.T("cmd_move"),
+				i18n.T("cmd_enter_search"),
+				i18n.T("current_language"),
+			},
+		}
+	}
+}
+
+// Bubbletea
+
+func (m BrowseModel) Init() tea.Cmd {
+	return tea.Batch(
+		textinput.Blink,
+		updateBrowseCommandsCmd(),
+		fetchFacetsCmd(m.ctx, m.browser, m.Kind()),
+	)
+}
+
+func (m BrowseModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case facetsFetchedMsg:
+		facets := append([]common.Facet(nil), msg.facets...)
+		sort.SliceStable(facets, func(i, j int) bool {
+			return facets[i].StationCount > facets[j].StationCount
+		})
+		m.facets[msg.kind] = facets
+		if msg.kind == m.Kind() {
+			m.loading = false
+			m.err = ""
+			m.applyFilter()
+		}
+		return m, nil
+
+	case facetsFetchFailedMsg:
+		if msg.kind == m.Kind() {
+			m.loading = false
+			m.err = i18n.Tf("error_load_facets", map[string]interface{}{"Error": msg.err.Error()})
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.cancel()
+			return m, func() tea.Msg {
+				return switchToSearchModelMsg{}
+			}
+		case "tab":
+			return m.selectKind((m.kindIndex + 1) % len(m.kinds))
+		case "shift+tab":
+			return m.selectKind((m.kindIndex + len(m.kinds) - 1) % len(m.kinds))
+		case "up", "down", "pgup", "pgdown":
+			newTable, cmd := m.facetsTable.Update(msg)
+			m.facetsTable = newTable
+			return m, cmd
+		case "enter":
+			if len(m.visible) == 0 {
+				return m, nil
+			}
+			m.cancel()
+			query, queryText := m.visible[m.facetsTable.Cursor()].ExactQuery(m.Kind())
+			return m, func() tea.Msg {
+				return switchToLoadingModelMsg{query: query, queryText: queryText}
+			}
+		}
+	}
+
+	// Any other input edits the filter
+	previousFilter := m.filterInput.Value()
+	newInputModel, cmd := m.filterInput.Update(msg)
+	m.filterInput = newInputModel
+	if m.filterInput.Value() != previousFilter {
+		m.applyFilter()
+	}
+	return m, cmd
+}
+
+// selectKind switches to another facet listing, fetching it if it isn't cached yet.
+func (m BrowseModel) selectKind(index int) (tea.Model, tea.Cmd) {
+	m.kindIndex = index
+	m.err = ""
+	m.filterInput.SetValue("")
+	if _, ok := m.facets[m.Kind()]; ok {
+		m.loading = false
+		m.applyFilter()
+		return m, nil
+	}
+	m.loading = true
+	m.visible = nil
+	m.facetsTable.SetRows(nil)
+	return m, fetchFacetsCmd(m.ctx, m.browser, m.Kind())
+}
+
+// applyFilter rebuilds the table from the current listing, keeping the
+// values whose name (or code) contains the filter text.
+func (m *BrowseModel) applyFilter() {
+	filter := strings.ToLower(strings.TrimSpace(m.filterInput.Value()))
+	kind := m.Kind()
+
+	visible := []common.Facet{}
+	rows := []table.Row{}
+	for _, facet := range m.facets[kind] {
+		if filter != "" && !facetMatches(facet, filter) {
+			continue
+		}
+		visible = append(visible, facet)
+		rows = append(rows, table.Row{facetLabel(kind, facet), formatNumber(facet.StationCount)})
+	}
+
+	m.visible = visible
+	m.facetsTable.SetRows(rows)
+	m.facetsTable.SetCursor(0)
+}
+
+// facetMatches reports whether a facet matches a lowercase filter text.
+func facetMatches(facet common.Facet, filter string) bool {
+	return strings.Contains(strings.ToLower(facet.Name), filter) ||
+		strings.ToLower(facet.Iso3166_1) == filter ||
+		strings.ToLower(facet.Iso639) == filter
+}
+
+// facetLabel renders a facet value with the extra detail its listing provides.
+func facetLabel(kind common.FacetKind, facet common.Facet) string {
+	switch kind {
+	case common.FacetCountries:
+		if facet.Iso3166_1 != "" {
+			return fmt.Sprintf("%s (%s)", facet.Name, facet.Iso3166_1)
+		}
+	case common.FacetStates:
+		if facet.Country != "" {
+			return fmt.Sprintf("%s (%s)", facet.Name, facet.Country)
+		}
+	case common.FacetLanguages:
+		if facet.Iso639 != "" {
+			return fmt.Sprintf("%s (%s)", facet.Name, facet.Iso639)
+		}
+	}
+	return facet.Name
+}
+
+func (m BrowseModel) View() string {
+	tabs := make([]string, len(m.kinds))
+	for i, kind := range m.kinds {
+		if i == m.kindIndex {
+			tabs[i] = m.theme.SecondaryBlock.Render(kind.Render())
+		} else {
+			tabs[i] = m.theme.TertiaryText.Render(kind.Render())
+		}
+	}
+
+	v := fmt.Sprintf("\n%s\n\n%s\n\n%s\n\n",
+		m.theme.SecondaryText.Bold(true).Render(i18n.T("browse_title")),
+		strings.Join(tabs, "  "),
+		m.filterInput.View(),
+	)
+
+	switch {
+	case m.loading:
+		v += m.theme.TertiaryText.Render(i18n.T("browse_loading")) + "\n"
+	case m.err != "":
+		v += m.theme.ErrorText.Render(m.err) + "\n"
+	case len(m.visible) == 0:
+		v += m.theme.TertiaryText.Render(i18n.T("browse_empty")) + "\n"
+	default:
+		v += m.facetsTable.View() + "\n\n" +
+			m.theme.TertiaryText.Render(i18n.Tf("browse_count", map[string]interface{}{
+				"Shown": len(m.visible),
+				"Total": len(m.facets[m.Kind()]),
+			})) + "\n"
+	}
+
+	return v
+}
+
+func (m *BrowseModel) SetWidthAndHeight(width int, height int) {
+	m.width = width
+	m.height = height
+
+	tableHeight := height - browseChromeHeight
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.facetsTable.SetHeight(tableHeight)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFacets() []common.Facet {
+	return []common.Facet{
+		{Name: "Italy", Iso3166_1: "IT", StationCount: 900},
+		{Name: "Germany", Iso3166_1: "DE", StationCount: 3500},
+		{Name: "Greece", Iso3166_1: "GR", StationCount: 400},
+	}
+}
+
+func TestBrowseModel_Init(t *testing.T) {
+
+	t.Run("fetches the first facet listing", func(t *testing.T) {
+		var requestedKind common.FacetKind
+		mockBrowser := mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				requestedKind = kind
+				return testFacets(), nil
+			},
+		}
+
+		model := NewBrowseModel(Theme{}, &mockBrowser, testSearchKeybindings)
+
+		cmd := model.Init()
+		assert.NotNil(t, cmd)
+
+		batchMsg := cmd().(tea.BatchMsg)
+
+		var fetched *facetsFetchedMsg
+		for _, msg := range batchMsg {
+			if m, ok := msg().(facetsFetchedMsg); ok {
+				fetched = &m
+			}
+		}
+
+		assert.NotNil(t, fetched)
+		assert.Equal(t, common.FacetCountries, requestedKind)
+		assert.Equal(t, common.FacetCountries, fetched.kind)
+		assert.Len(t, fetched.facets, 3)
+	})
+
+	t.Run("reports fetch errors", func(t *testing.T) {
+		mockBrowser := mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				return nil, io.EOF
+			},
+		}
+
+		model := NewBrowseModel(Theme{}, &mockBrowser, testSearchKeybindings)
+
+		msg := fetchFacetsCmd(context.Background(), &mockBrowser, common.FacetTags)()
+		failed, ok := msg.(facetsFetchFailedMsg)
+		assert.True(t, ok)
+		assert.Equal(t, io.EOF, failed.err)
+
+		newModel, _ := model.Update(facetsFetchFailedMsg{kind: common.FacetCountries, err: io.EOF})
+		model = newModel.(BrowseModel)
+		assert.False(t, model.loading)
+		assert.Contains(t, model.View(), "EOF")
+	})
+
+	t.Run("discards the result when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		mockBrowser := mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				return nil, ctx.Err()
+			},
+		}
+
+		assert.Nil(t, fetchFacetsCmd(ctx, &mockBrowser, common.FacetTags)())
+	})
+}
+
+func TestBrowseModel_Update(t *testing.T) {
+
+	t.Run("sorts values by station count", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries, facets: testFacets()})
+
+		assert.False(t, model.loading)
+		assert.Equal(t, []string{"Germany", "Italy", "Greece"}, []string{model.visible[0].Name, model.visible[1].Name, model.visible[2].Name})
+	})
+
+	t.Run("filters values as the user types", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries, facets: testFacets()})
+
+		for _, r := range "gr" {
+			newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			model = newModel.(BrowseModel)
+		}
+
+		assert.Len(t, model.visible, 1)
+		assert.Equal(t, "Greece", model.visible[0].Name)
+	})
+
+	t.Run("matches country codes", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries, facets: testFacets()})
+
+		for _, r := range "it" {
+			newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			model = newModel.(BrowseModel)
+		}
+
+		assert.Len(t, model.visible, 1)
+		assert.Equal(t, "Italy", model.visible[0].Name)
+	})
+
+	t.Run("launches the exact query for the selected value on enter", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries, facets: testFacets()})
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model = newModel.(BrowseModel)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.NotNil(t, cmd)
+		assert.Equal(t, switchToLoadingModelMsg{query: common.StationQueryByCountryCodeExact, queryText: "IT"}, cmd())
+	})
+
+	t.Run("ignores enter when nothing matches", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("switches facet listing on tab and fetches it once", func(t *testing.T) {
+		fetches := 0
+		mockBrowser := mocks.MockRadioBrowserService{
+			GetFacetsFunc: func(ctx context.Context, kind common.FacetKind, filter string, limit uint64) ([]common.Facet, error) {
+				fetches++
+				return []common.Facet{{Name: "jazz", StationCount: 10}}, nil
+			},
+		}
+		model := NewBrowseModel(Theme{}, &mockBrowser, testSearchKeybindings)
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(BrowseModel)
+		assert.Equal(t, common.FacetCountryCodes, model.Kind())
+		assert.True(t, model.loading)
+
+		newModel, _ = model.Update(cmd())
+		model = newModel.(BrowseModel)
+		assert.False(t, model.loading)
+		assert.Len(t, model.visible, 1)
+
+		// Going back and forth again uses the cached listing
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+		model = newModel.(BrowseModel)
+		newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(BrowseModel)
+		assert.Nil(t, cmd)
+		assert.Equal(t, 1, fetches)
+		assert.Len(t, model.visible, 1)
+	})
+
+	t.Run("goes back to search on esc", func(t *testing.T) {
+		model := loadedModel(NewBrowseModel(Theme{}, &mocks.MockRadioBrowserService{}, testSearchKeybindings), facetsFetchedMsg{kind: common.FacetCountries, facets: testFacets()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.NotNil(t, cmd)
+		assert.Equal(t, switchToSearchModelMsg{}, cmd())
+		assert.Error(t, model.ctx.Err())
+	})
+}
+
+func TestFacetLabel(t *testing.T) {
+	assert.Equal(t, "Germany (DE)", facetLabel(common.FacetCountries, common.Facet{Name: "Germany", Iso3166_1: "DE"}))
+	assert.Equal(t, "Bavaria (Germany)", facetLabel(common.FacetStates, common.Facet{Name: "Bavaria", Country: "Germany"}))
+	assert.Equal(t, "italian (it)", facetLabel(common.FacetLanguages, common.Facet{Name: "italian", Iso639: "it"}))
+	assert.Equal(t, "jazz", facetLabel(common.FacetTags, common.Facet{Name: "jazz"}))
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// customField identifies a field of the custom station form.
+type customField int
+
+const (
+	customFieldName customField = iota
+	customFieldURL
+	customFieldCodec
+	customFieldBitrate
+	customFieldTags
+	customFieldCountryCode
+	customFieldCount
+)
+
+// label returns the localized label for the field.
+func (f customField) label() string {
+	switch f {
+	case customFieldName:
+		return i18n.T("custom_field_name")
+	case customFieldURL:
+		return i18n.T("custom_field_url")
+	case customFieldCodec:
+		return i18n.T("advanced_field_codec")
+	case customFieldBitrate:
+		return i18n.T("custom_field_bitrate")
+	case customFieldTags:
+		return i18n.T("advanced_field_tags")
+	case customFieldCountryCode:
+		return i18n.T("advanced_field_country_code")
+	}
+	return ""
+}
+
+// CustomStationForm adds or edits a station that isn't listed on RadioBrowser.
+type CustomStationForm struct {
+	theme Theme
+
+	inputs []textinput.Model
+	focus  customField
+	err    string
+	// stationUuid is the station being edited, or uuid.Nil when adding one
+	stationUuid uuid.UUID
+}
+
+func NewCustomStationForm(theme Theme) CustomStationForm {
+	inputs := make([]textinput.Model, customFieldCount)
+	for i := range inputs {
+		input := textinput.New()
+		input.Width = 40
+		input.Prompt = ""
+		input.TextStyle = theme.Text
+		input.PlaceholderStyle = theme.TertiaryText
+		inputs[i] = input
+	}
+	inputs[customFieldURL].Placeholder = "https://stream.example.com/live.mp3"
+	inputs[customFieldCodec].Placeholder = "MP3"
+	inputs[customFieldBitrate].Placeholder = "128"
+	inputs[customFieldBitrate].CharLimit = 4
+	inputs[customFieldTags].Placeholder = "jazz, smooth"
+	inputs[customFieldCountryCode].Placeholder = "DE"
+	inputs[customFieldCountryCode].CharLimit = 2
+
+	form := CustomStationForm{
+		theme:  theme,
+		inputs: inputs,
+	}
+	form.setFocus(customFieldName)
+	return form
+}
+
+// NewCustomStationFormFor returns a form pre-filled to edit the given custom station.
+func NewCustomStationFormFor(theme Theme, station common.Station) CustomStationForm {
+	form := NewCustomStationForm(theme)
+	form.stationUuid = station.StationUuid
+	form.inputs[customFieldName].SetValue(station.Name)
+	form.inputs[customFieldURL].SetValue(station.Url.URL.String())
+	form.inputs[customFieldCodec].SetValue(station.Codec)
+	if station.Bitrate > 0 {
+		form.inputs[customFieldBitrate].SetValue(strconv.FormatUint(station.Bitrate, 10))
+	}
+	form.inputs[customFieldTags].SetValue(station.Tags)
+	form.inputs[customFieldCountryCode].SetValue(station.CountryCode)
+	return form
+}
+
+// IsEditing returns true if the form edits an existing station rather than adding one.
+func (m CustomStationForm) IsEditing() bool {
+	return m.stationUuid != uuid.Nil
+}
+
+// setFocus moves focus to the given field.
+func (m *CustomStationForm) setFocus(field customField) {
+	m.focus = field
+	for i := range m.inputs {
+		if customField(i) == field {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+// Station builds the station described by the form. A new station gets a fresh UUID.
+// Returns an error if the name is missing, the URL isn't a stream URL or the bitrate isn't a number.
+func (m CustomStationForm) Station() (common.Station, error) {
+	value := func(field customField) string {
+		return strings.TrimSpace(m.inputs[field].Value())
+	}
+
+	name := value(customFieldName)
+	if name == "" {
+		return common.Station{}, fmt.Errorf("%s", i18n.T("error_custom_name_missing"))
+	}
+
+	rawURL := value(customFieldURL)
+	streamURL, err := url.Parse(rawURL)
+	if err != nil || streamURL.Scheme == "" || streamURL.Host == "" {
+		return common.Station{}, fmt.Errorf("%s", i18n.Tf("error_custom_url_invalid", map[string]interface{}{"Value": rawURL}))
+	}
+
+	var bitrate uint64
+	if v := value(customFieldBitrate); v != "" {
+		bitrate, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return common.Station{}, fmt.Errorf("%s", i18n.Tf("error_invalid_bitrate", map[string]interface{}{"Value": v}))
+		}
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(value(customFieldTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	stationUuid := m.stationUuid
+	if stationUuid == uuid.Nil {
+		stationUuid = uuid.New()
+	}
+
+	return common.Station{
+		StationUuid: stationUuid,
+		Name:        name,
+		Url:         common.RadioGoGoURL{URL: *streamURL},
+		UrlResolved: common.RadioGoGoURL{URL: *streamURL},
+		Codec:       strings.ToUpper(value(customFieldCodec)),
+		Bitrate:     bitrate,
+		Tags:        strings.Join(tags, ","),
+		CountryCode: strings.ToUpper(value(customFieldCountryCode)),
+		Source:      common.StationSourceCustom,
+	}, nil
+}
+
+// SetError sets the validation error shown below the form.
+func (m *CustomStationForm) SetError(err string) {
+	m.err = err
+}
+
+// Bubbletea
+
+func (m CustomStationForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m CustomStationForm) Update(msg tea.Msg) (CustomStationForm, tea.Cmd) {
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			m.setFocus((m.focus + 1) % customFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focus + customFieldCount - 1) % customFieldCount)
+			return m, nil
+		}
+		m.err = ""
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m CustomStationForm) View() string {
+
+	title := i18n.T("custom_add_title")
+	if m.IsEditing() {
+		title = i18n.T("custom_edit_title")
+	}
+	v := m.theme.SecondaryText.Bold(true).Render(title) + "\n\n"
+
+	labels := make([]string, customFieldCount)
+	labelWidth := 0
+	for f := customField(0); f < customFieldCount; f++ {
+		labels[f] = f.label()
+		if w := len([]rune(labels[f])); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	for f := customField(0); f < customFieldCount; f++ {
+		cursor := "  "
+		if f == m.focus {
+			cursor = "> "
+		}
+		label := labels[f] + strings.Repeat(" ", labelWidth-len([]rune(labels[f])))
+		v += m.theme.Text.Render(cursor+label+"  ") + m.inputs[f].View() + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+
+	v += "\n" + m.theme.TertiaryText.Render(i18n.T("custom_form_help"))
+
+	return v
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/storage"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// discoverTabsHeight is the number of lines the list tabs take above the stations view.
+const discoverTabsHeight = 2
+
+// DiscoverModel shows RadioBrowser's ready-made station lists (trending, most voted,
+// recently played, recently changed) as tabs over the stations table.
+// Playback, bookmarks and votes work as they do for search results.
+type DiscoverModel struct {
+	theme           Theme
+	browser         api.RadioBrowserService
+	playbackManager playback.PlaybackManagerService
+	storage         storage.StationStorageService
+	keybindings     config.Keybindings
+
+	lists     []common.StationList
+	listIndex int
+	spinner   spinner.Model
+	loading   bool
+	err       string
+
+	// stationsModel shows the current list once it has been fetched.
+	// While another list loads it keeps receiving messages, so playback carries on.
+	stationsModel StationsModel
+	hasStations   bool
+
+	// pageSize is the number of stations fetched per page of a list.
+	pageSize int
+	width    int
+	height   int
+
+	// songTitleWatcher is handed to each stations view, so song titles keep coming across lists.
+	songTitleWatcher *icy.Watcher
+	// reconnectAttempts is how many times each stations view restarts a player that stopped by itself.
+	reconnectAttempts int
+	// recordingLocation is where each stations view saves recordings.
+	recordingLocation playback.RecordingLocation
+
+	// Cancels in-flight list requests when the user leaves the discover screen
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewDiscoverModel(
+	theme Theme,
+	browser api.RadioBrowserService,
+	playbackManager playback.PlaybackManagerService,
+	storage storage.StationStorageService,
+	keybindings config.Keybindings,
+) DiscoverModel {
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = theme.SecondaryText
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return DiscoverModel{
+		theme:           theme,
+		browser:         browser,
+		playbackManager: playbackManager,
+		storage:         storage,
+		keybindings:     keybindings,
+		lists:           common.AllStationLists(),
+		spinner:         s,
+		loading:         true,
+		pageSize:        config.NewDefaultSearchPreferences().PageSize,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// List returns the station list currently shown.
+func (m DiscoverModel) List() common.StationList {
+	return m.lists[m.listIndex]
+}
+
+// IsModalShowing returns true if the stations view has a modal dialog open.
+func (m DiscoverModel) IsModalShowing() bool {
+	return m.showsStations() && m.stationsModel.IsModalShowing()
+}
+
+// showsStations reports whether the stations view is shown (rather than a spinner or an error).
+func (m DiscoverModel) showsStations() bool {
+	return m.hasStations && !m.loading && m.err == ""
+}
+
+// Messages
+
+type stationListFetchedMsg struct {
+	list     common.StationList
+	stations []common.Station
+}
+
+type stationListFetchFailedMsg struct {
+	list common.StationList
+	err  error
+}
+
+// Commands
+
+// fetchStationListCmd loads the first page of a station list.
+// If ctx is cancelled (the user left the discover screen) the result is discarded.
+func fetchStationListCmd(ctx context.Context, browser api.RadioBrowserService, list common.StationList, limit int) tea.Cmd {
+	return func() tea.Msg {
+		stations, err := browser.GetStationList(ctx, list, 0, uint64(limit))
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return stationListFetchFailedMsg{list: list, err: err}
+		}
+		return stationListFetchedMsg{list: list, stations: stations}
+	}
+}
+
+func updateDiscoverCommandsCmd(kb config.Keybindings) tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.Tf("cmd_quit", map[string]interface{}{"Key": kb.Quit}),
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": "esc"}),
+				i18n.T("cmd_change_list"),
+				i18n.T("current_language"),
+			},
+		}
+	}
+}
+
+// Bubbletea
+
+func (m DiscoverModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		updateDiscoverCommandsCmd(m.keybindings),
+		fetchStationListCmd(m.ctx, m.browser, m.List(), m.pageSize),
+	)
+}
+
+func (m DiscoverModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stationListFetchedMsg:
+		if msg.list != m.List() {
+			return m, nil
+		}
+		return m.showStations(msg.stations)
+
+	case stationListFetchFailedMsg:
+		if msg.list != m.List() {
+			return m, nil
+		}
+		m.loading = false
+		m.err = i18n.Tf("error_load_facets", map[string]interface{}{"Error": msg.err.Error()})
+		return m, nil
+
+	case tea.KeyMsg:
+		if handled, cmd := m.handleKey(msg); handled {
+			return m, cmd
+		}
+		if !m.showsStations() {
+			if msg.String() == m.keybindings.Quit {
+				return m, tea.Sequence(stopStationCmd(m.playbackManager), m.stationsModel.endSongOnQuitCmd(), quitCmd)
+			}
+			return m, nil
+		}
+	}
+
+	var cmds []tea.Cmd
+	if m.loading {
+		newSpinner, cmd := m.spinner.Update(msg)
+		m.spinner = newSpinner
+		cmds = append(cmds, cmd)
+	}
+	// Playback, volume, bookmark and vote messages keep going to the stations view,
+	// even while another list loads
+	if m.hasStations {
+		newStationsModel, cmd := m.stationsModel.Update(msg)
+		m.stationsModel = newStationsModel.(StationsModel)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleKey handles the keys that belong to the discover screen itself: switching
+// lists and going back to search. They are left to the stations view while it shows
+// bookmarks or a modal.
+func (m *DiscoverModel) handleKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if m.showsStations() && (m.stationsModel.IsModalShowing() || m.stationsModel.viewMode != viewModeSearchResults) {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "tab":
+		return true, m.selectList((m.listIndex + 1) % len(m.lists))
+	case "shift+tab":
+		return true, m.selectList((m.listIndex + len(m.lists) - 1) % len(m.lists))
+	case "esc", m.keybindings.Search:
+		m.cancel()
+		return true, func() tea.Msg {
+			return switchToSearchModelMsg{}
+		}
+	}
+	return false, nil
+}
+
+// selectList switches to another station list and fetches it.
+func (m *DiscoverModel) selectList(index int) tea.Cmd {
+	m.listIndex = index
+	m.loading = true
+	m.err = ""
+	return tea.Batch(
+		m.spinner.Tick,
+		updateDiscoverCommandsCmd(m.keybindings),
+		fetchStationListCmd(m.ctx, m.browser, m.List(), m.pageSize),
+	)
+}
+
+// showStations replaces the stations view with a freshly fetched list.
+// The playing station and the volume carry over from the previous list.
+func (m DiscoverModel) showStations(stations []common.Station) (tea.Model, tea.Cmd) {
+	previous := m.stationsModel
+	hadStations := m.hasStations
+
+	m.stationsModel = NewStationsModel(m.theme, m.browser, m.playbackManager, m.storage, filterHiddenStations(stations, m.storage), viewModeSearchResults, "", "", m.keybindings)
+	m.stationsModel.lastList = m.List()
+	m.stationsModel.EnablePaging(m.pageSize, len(stations))
+	m.stationsModel.SetSongTitleWatcher(m.songTitleWatcher)
+	m.stationsModel.SetReconnectAttempts(m.reconnectAttempts)
+	m.stationsModel.SetRecordingLocation(m.recordingLocation)
+	if hadStations {
+		m.stationsModel.currentStation = previous.currentStation
+		m.stationsModel.currentStationSpinner = previous.currentStationSpinner
+		m.stationsModel.volume = previous.volume
+		m.stationsModel.songTitle = previous.songTitle
+		m.stationsModel.streamURL = previous.streamURL
+		m.stationsModel.reconnectAttempt = previous.reconnectAttempt
+		m.stationsModel.reconnecting = previous.reconnecting
+		m.stationsModel.resumeRecording = previous.resumeRecording
+		m.stationsModel.playingSince = previous.playingSince
+		m.stationsModel.timeshift = previous.timeshift
+		m.stationsModel.timeshiftRefresh = previous.timeshiftRefresh
+		m.stationsModel.sleepAt = previous.sleepAt
+		m.stationsModel.sleepDuration = previous.sleepDuration
+		m.stationsModel.sleepTimer = previous.sleepTimer
+		m.stationsModel.sleepFading = previous.sleepFading
+		m.stationsModel.sleepFadeVolume = previous.sleepFadeVolume
+	}
+	m.stationsModel.SetWidthAndHeight(m.width, m.height-discoverTabsHeight)
+	m.stationsModel.rebuildTablePreservingCursor(0)
+	m.hasStations = true
+	m.loading = false
+	m.err = ""
+
+	sm := m.stationsModel
+	cmds := []tea.Cmd{
+		sm.updateCommandsCmd(),
+		sm.cursorMovedCmd(),
+	}
+	if m.storage != nil {
+		// Bookmarked stations in the list get their snapshots refreshed
+		cmds = append(cmds, saveBookmarkSnapshotsCmd(m.storage, stations))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m DiscoverModel) View() string {
+	tabs := make([]string, len(m.lists))
+	for i, list := range m.lists {
+		if i == m.listIndex {
+			tabs[i] = m.theme.SecondaryBlock.Render(list.Render())
+		} else {
+			tabs[i] = m.theme.TertiaryText.Render(list.Render())
+		}
+	}
+
+	v := "\n" + m.theme.SecondaryText.Bold(true).Render(i18n.T("discover_title")) + "  " +
+		strings.Join(tabs, "  ") + "  " +
+		m.theme.TertiaryText.Render(i18n.T("cmd_change_list"))
+
+	switch {
+	case m.loading:
+		return v + "\n\n" + m.spinner.View() + " " + i18n.T("loading") + "\n"
+	case m.err != "":
+		return v + "\n\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+	return v + "\n" + m.stationsModel.View()
+}
+
+func (m *DiscoverModel) SetWidthAndHeight(width int, height int) {
+	m.width = width
+	m.height = height
+	if m.hasStations {
+		m.stationsModel.SetWidthAndHeight(width, height-discoverTabsHeight)
+	}
+}
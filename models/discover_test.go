@@ -0,0 +1,247 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDiscoverModel(browser *mocks.MockRadioBrowserService) DiscoverModel {
+	playbackManager := &mocks.MockPlaybackManagerService{
+		VolumeDefaultResult: 50,
+		VolumeMaxResult:     100,
+	}
+	model := NewDiscoverModel(Theme{}, browser, playbackManager, &mocks.MockStationStorageService{}, defaultStationsKeybindings)
+	model.pageSize = 20
+	model.SetWidthAndHeight(120, 30)
+	return model
+}
+
+func TestDiscoverModel_Init(t *testing.T) {
+
+	t.Run("fetches the first page of the trending list", func(t *testing.T) {
+		var requested common.StationList
+		var requestedLimit uint64
+		browser := &mocks.MockRadioBrowserService{
+			GetStationListFunc: func(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+				requested, requestedLimit = list, limit
+				return createTestStations(3), nil
+			},
+		}
+		model := newTestDiscoverModel(browser)
+
+		msg := findMsgInCmd(model.Init(), func(msg tea.Msg) bool {
+			_, ok := msg.(stationListFetchedMsg)
+			return ok
+		})
+
+		assert.Equal(t, common.StationListTopClick, requested)
+		assert.Equal(t, uint64(20), requestedLimit)
+		assert.Len(t, msg.(stationListFetchedMsg).stations, 3)
+	})
+
+	t.Run("discards the list if the screen was left", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		browser := &mocks.MockRadioBrowserService{}
+
+		assert.Nil(t, fetchStationListCmd(ctx, browser, common.StationListTopVote, 20)())
+	})
+}
+
+func TestDiscoverModel_Update(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	t.Run("shows the fetched list in the stations table", func(t *testing.T) {
+		model := loadedModel(newTestDiscoverModel(&mocks.MockRadioBrowserService{}), stationListFetchedMsg{list: common.StationListTopClick, stations: createTestStations(20)})
+
+		assert.False(t, model.loading)
+		assert.True(t, model.showsStations())
+		assert.Len(t, model.stationsModel.stations, 20)
+		assert.Equal(t, common.StationListTopClick, model.stationsModel.lastList)
+		assert.True(t, model.stationsModel.hasMorePages)
+		assert.Contains(t, model.View(), "Trending")
+	})
+
+	t.Run("switches lists with tab and shift+tab", func(t *testing.T) {
+		var requested []common.StationList
+		browser := &mocks.MockRadioBrowserService{
+			GetStationListFunc: func(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+				requested = append(requested, list)
+				return nil, nil
+			},
+		}
+		model := newTestDiscoverModel(browser)
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(DiscoverModel)
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+		assert.Equal(t, common.StationListTopVote, model.List())
+		assert.True(t, model.loading)
+
+		newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+		model = newModel.(DiscoverModel)
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+		model = newModel.(DiscoverModel)
+		assert.Equal(t, common.StationListLastChange, model.List())
+
+		assert.Equal(t, []common.StationList{common.StationListTopVote, common.StationListTopClick}, requested)
+	})
+
+	t.Run("ignores lists that were switched away from", func(t *testing.T) {
+		model := newTestDiscoverModel(&mocks.MockRadioBrowserService{})
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+		newModel, _ = newModel.Update(stationListFetchedMsg{list: common.StationListTopClick, stations: createTestStations(3)})
+		model = newModel.(DiscoverModel)
+
+		assert.True(t, model.loading)
+		assert.False(t, model.hasStations)
+	})
+
+	t.Run("shows an error when the list can't be fetched", func(t *testing.T) {
+		model := newTestDiscoverModel(&mocks.MockRadioBrowserService{})
+
+		newModel, _ := model.Update(stationListFetchFailedMsg{list: common.StationListTopClick, err: errors.New("boom")})
+		model = newModel.(DiscoverModel)
+
+		assert.False(t, model.loading)
+		assert.Contains(t, model.View(), "boom")
+	})
+
+	t.Run("goes back to search with esc", func(t *testing.T) {
+		model := loadedModel(newTestDiscoverModel(&mocks.MockRadioBrowserService{}), stationListFetchedMsg{list: common.StationListTopClick, stations: createTestStations(3)})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Equal(t, switchToSearchModelMsg{}, cmd())
+		assert.Error(t, model.ctx.Err())
+	})
+
+	t.Run("keeps the playing station and volume when switching lists", func(t *testing.T) {
+		stations := createTestStations(3)
+		model := loadedModel(newTestDiscoverModel(&mocks.MockRadioBrowserService{}), stationListFetchedMsg{list: common.StationListTopClick, stations: stations})
+		model.stationsModel.currentStation = stations[1]
+		model.stationsModel.volume = 80
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		newModel, _ = newModel.Update(stationListFetchedMsg{list: common.StationListTopVote, stations: stations})
+		model = newModel.(DiscoverModel)
+
+		assert.Equal(t, stations[1], model.stationsModel.currentStation)
+		assert.Equal(t, 80, model.stationsModel.volume)
+		assert.Contains(t, model.stationsModel.stationsTable.Rows()[1][0], "▶ ")
+	})
+
+	t.Run("forwards other keys to the stations table", func(t *testing.T) {
+		model := loadedModel(newTestDiscoverModel(&mocks.MockRadioBrowserService{}), stationListFetchedMsg{list: common.StationListTopClick, stations: createTestStations(3)})
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		model = newModel.(DiscoverModel)
+
+		assert.Equal(t, 1, model.stationsModel.stationsTable.Cursor())
+	})
+
+	t.Run("leaves tab to the stations view while bookmarks are shown", func(t *testing.T) {
+		model := loadedModel(newTestDiscoverModel(&mocks.MockRadioBrowserService{}), stationListFetchedMsg{list: common.StationListTopClick, stations: createTestStations(3)})
+		newModel, _ := model.Update(bookmarksFetchedMsg{stations: createTestStations(1)})
+
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(DiscoverModel)
+
+		assert.Equal(t, common.StationListTopClick, model.List())
+		assert.Equal(t, viewModeBookmarks, model.stationsModel.viewMode)
+	})
+}
+
+func TestStationsModel_StationList(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	newListModel := func(browser *mocks.MockRadioBrowserService, list common.StationList) StationsModel {
+		model := createTestStationsModel(createTestStations(20), defaultStationsKeybindings)
+		model.browser = browser
+		model.lastList = list
+		model.EnablePaging(20, 20)
+		model.SetWidthAndHeight(120, 40)
+		return model
+	}
+
+	t.Run("fetches further pages of the list", func(t *testing.T) {
+		var requested common.StationList
+		var requestedOffset uint64
+		browser := &mocks.MockRadioBrowserService{
+			GetStationListFunc: func(ctx context.Context, list common.StationList, offset uint64, limit uint64) ([]common.Station, error) {
+				requested, requestedOffset = list, offset
+				return createTestStations(5), nil
+			},
+		}
+		model := newListModel(browser, common.StationListLastClick)
+		model.stationsTable.SetCursor(18)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		page := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(stationsPageFetchedMsg)
+			return ok
+		})
+
+		assert.NotNil(t, page)
+		assert.Equal(t, common.StationListLastClick, requested)
+		assert.Equal(t, uint64(20), requestedOffset)
+	})
+
+	t.Run("can't be re-sorted", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(3), defaultStationsKeybindings)
+		model.lastList = common.StationListTopVote
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+
+		assert.Nil(t, cmd)
+		assert.Equal(t, common.StationOrderVotes, newModel.(StationsModel).sortOrder)
+		assert.Empty(t, newModel.(StationsModel).sortLabel())
+
+		msg := newModel.(StationsModel).updateCommandsCmd()()
+		for _, command := range msg.(bottomBarUpdateMsg).secondaryCommands {
+			assert.NotContains(t, command, "sort")
+		}
+	})
+
+	t.Run("drops pages of a list that was replaced", func(t *testing.T) {
+		model := newListModel(&mocks.MockRadioBrowserService{}, common.StationListTopVote)
+		model.loadingMore = true
+		src := model.source()
+		src.list = common.StationListTopClick
+
+		newModel, _ := model.Update(stationsPageFetchedMsg{source: src, offset: 20, stations: createTestStations(20)})
+
+		assert.Len(t, newModel.(StationsModel).stations, 20)
+	})
+}
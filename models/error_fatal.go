@@ -20,8 +20,10 @@
 package models
 
 import (
+	"errors"
 	"time"
 
+	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/i18n"
 
@@ -45,6 +47,10 @@ type ErrorModel struct {
 
 	message     string
 	recoverable bool
+	// The original error, used to explain API failures
+	cause error
+	// Message sent when the retry key is pressed (nil if retrying is not possible)
+	retry tea.Msg
 
 	tickCount int
 	width     int
@@ -76,6 +82,9 @@ func (m ErrorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key == m.keybindings.Quit:
 			return m, quitCmd
+		case key == m.keybindings.Retry && m.retry != nil:
+			retry := m.retry
+			return m, func() tea.Msg { return retry }
 		case key == "enter" || key == "esc":
 			if m.recoverable {
 				return m, func() tea.Msg { return switchToSearchModelMsg{} }
@@ -99,16 +108,51 @@ func (m ErrorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 }
 
+// describeAPIError returns a localized explanation of an API failure and a hint on what to do.
+func describeAPIError(err *api.APIError) (string, string) {
+	switch err.Kind {
+	case api.ErrorKindNetworkUnreachable:
+		return i18n.T("api_error_network_unreachable"), i18n.T("api_hint_network_unreachable")
+	case api.ErrorKindDNS:
+		return i18n.T("api_error_dns"), i18n.T("api_hint_dns")
+	case api.ErrorKindTimeout:
+		return i18n.T("api_error_timeout"), i18n.T("api_hint_timeout")
+	case api.ErrorKindRateLimited:
+		if err.RetryAfter > 0 {
+			seconds := int((err.RetryAfter + time.Second - 1) / time.Second)
+			return i18n.Tf("api_error_rate_limited_after", map[string]interface{}{"Seconds": seconds}), i18n.T("api_hint_rate_limited")
+		}
+		return i18n.T("api_error_rate_limited"), i18n.T("api_hint_rate_limited")
+	case api.ErrorKindServer:
+		return i18n.Tf("api_error_server", map[string]interface{}{"Status": err.StatusCode}), i18n.T("api_hint_server")
+	case api.ErrorKindMalformedResponse:
+		return i18n.T("api_error_malformed"), i18n.T("api_hint_malformed")
+	}
+	return i18n.Tf("api_error_unexpected_status", map[string]interface{}{"Status": err.StatusCode}), i18n.T("api_hint_unexpected_status")
+}
+
 func (m ErrorModel) View() string {
 
-	var message string
-	if m.recoverable {
-		message = m.message + "\n\n" + i18n.Tf("error_recoverable", map[string]interface{}{"QuitKey": m.keybindings.Quit})
-	} else {
-		message = m.message + "\n\n" + i18n.Tf("error_quitting", map[string]interface{}{"Seconds": quitTicks - m.tickCount, "QuitKey": m.keybindings.Quit})
+	var footer string
+	switch {
+	case m.recoverable && m.retry != nil:
+		footer = i18n.Tf("error_recoverable_retry", map[string]interface{}{"RetryKey": m.keybindings.Retry, "QuitKey": m.keybindings.Quit})
+	case m.recoverable:
+		footer = i18n.Tf("error_recoverable", map[string]interface{}{"QuitKey": m.keybindings.Quit})
+	default:
+		footer = i18n.Tf("error_quitting", map[string]interface{}{"Seconds": quitTicks - m.tickCount, "QuitKey": m.keybindings.Quit})
+	}
+
+	var apiErr *api.APIError
+	if errors.As(m.cause, &apiErr) {
+		explanation, hint := describeAPIError(apiErr)
+		return "\n" + m.theme.ErrorText.Render(explanation) + "\n\n" +
+			m.theme.Text.Render(hint) + "\n\n" +
+			m.theme.TertiaryText.Render(i18n.Tf("error_details", map[string]interface{}{"Error": m.message})) + "\n\n" +
+			m.theme.ErrorText.Render(footer) + "\n\n"
 	}
 
-	return "\n" + m.theme.ErrorText.Render(message) + "\n\n"
+	return "\n" + m.theme.ErrorText.Render(m.message+"\n\n"+footer) + "\n\n"
 
 }
 
@@ -21,8 +21,12 @@ package models
 
 import (
 	"testing"
+	"time"
 
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +46,7 @@ var testKeybindings = config.Keybindings{
 	NavigateDown:   "j",
 	NavigateUp:     "k",
 	StopPlayback:   "ctrl+k",
+	Retry:          "R",
 }
 
 func TestErrorModel_Init(t *testing.T) {
@@ -137,3 +142,92 @@ func TestErrorModel_Update(t *testing.T) {
 	})
 
 }
+
+func TestErrorModel_Retry(t *testing.T) {
+
+	retryKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")}
+
+	t.Run("sends the retry message when the retry key is pressed", func(t *testing.T) {
+
+		model := NewErrorModel(Theme{}, "timeout", true, testKeybindings)
+		model.retry = switchToLoadingModelMsg{query: common.StationQueryByTag, queryText: "jazz"}
+
+		_, cmd := model.Update(retryKey)
+		assert.NotNil(t, cmd)
+
+		assert.Equal(t, switchToLoadingModelMsg{query: common.StationQueryByTag, queryText: "jazz"}, cmd())
+
+	})
+
+	t.Run("ignores the retry key when there is nothing to retry", func(t *testing.T) {
+
+		model := NewErrorModel(Theme{}, "storage error", true, testKeybindings)
+
+		_, cmd := model.Update(retryKey)
+		assert.Nil(t, cmd)
+
+	})
+
+	t.Run("mentions the retry key only when retrying is possible", func(t *testing.T) {
+
+		_ = i18n.Init("en")
+
+		model := NewErrorModel(Theme{}, "timeout", true, testKeybindings)
+		assert.NotContains(t, model.View(), "to retry")
+
+		model.retry = switchToLoadingModelMsg{}
+		assert.Contains(t, model.View(), "Press \"R\" to retry")
+
+	})
+
+}
+
+func TestErrorModel_APIErrors(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	testCases := []struct {
+		name        string
+		err         *api.APIError
+		explanation string
+		hint        string
+	}{
+		{"network unreachable", &api.APIError{Kind: api.ErrorKindNetworkUnreachable}, "Could not connect to RadioBrowser.", "Check your internet connection"},
+		{"DNS failure", &api.APIError{Kind: api.ErrorKindDNS}, "Could not resolve the RadioBrowser server name.", "Check your DNS settings"},
+		{"timeout", &api.APIError{Kind: api.ErrorKindTimeout}, "RadioBrowser took too long to answer.", "api.requestTimeoutSeconds"},
+		{"rate limited", &api.APIError{Kind: api.ErrorKindRateLimited, StatusCode: 429}, "RadioBrowser is limiting requests from your address.", "Wait a moment"},
+		{"rate limited with Retry-After", &api.APIError{Kind: api.ErrorKindRateLimited, StatusCode: 429, RetryAfter: 1500 * time.Millisecond}, "(try again in 2s)", "Wait a moment"},
+		{"server error", &api.APIError{Kind: api.ErrorKindServer, StatusCode: 503}, "RadioBrowser had a server error (HTTP 503).", "usually temporary"},
+		{"malformed response", &api.APIError{Kind: api.ErrorKindMalformedResponse}, "could not be read", "Retry to use another one"},
+		{"unexpected status", &api.APIError{Kind: api.ErrorKindUnexpectedStatus, StatusCode: 404}, "rejected the request (HTTP 404)", "Check the search text"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			explanation, hint := describeAPIError(tc.err)
+			assert.Contains(t, explanation, tc.explanation)
+			assert.Contains(t, hint, tc.hint)
+
+			model := NewErrorModel(Theme{}, tc.err.Error(), true, testKeybindings)
+			model.cause = tc.err
+
+			view := model.View()
+			assert.Contains(t, view, explanation)
+			assert.Contains(t, view, hint)
+			assert.Contains(t, view, "Details: "+tc.err.Error())
+
+		})
+	}
+
+	t.Run("shows non-API errors verbatim", func(t *testing.T) {
+
+		model := NewErrorModel(Theme{}, "database is locked", true, testKeybindings)
+		model.cause = assert.AnError
+
+		assert.Contains(t, model.View(), "database is locked")
+		assert.NotContains(t, model.View(), "Details:")
+
+	})
+
+}
@@ -21,6 +21,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -36,6 +37,9 @@ const (
 	PlaybackIdle PlaybackStatus = iota
 	PlaybackPlaying
 	PlaybackRestarting
+	PlaybackPaused
+	// PlaybackReconnecting means the player stopped by itself and playback will be restarted.
+	PlaybackReconnecting
 )
 
 // playbackStatusMsg is sent to update the header's playback status indicator
@@ -58,6 +62,8 @@ type HeaderModel struct {
 	playbackStatus PlaybackStatus
 	playerName     string
 	isRecording    bool
+	loadingMore    bool
+	sleepRemaining time.Duration // Time left on the sleep timer, 0 when it's off
 }
 
 func NewHeaderModel(theme Theme, playbackManager playback.PlaybackManagerService) HeaderModel {
@@ -76,23 +82,29 @@ func (m HeaderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case stationCursorMovedMsg:
 		m.stationOffset = msg.offset
 		m.totalStations = msg.totalStations
+		m.loadingMore = msg.loadingMore
 	case playbackStatusMsg:
 		m.playbackStatus = msg.status
 	case recordingStatusMsg:
 		m.isRecording = msg.isRecording
+	case sleepTimerStatusMsg:
+		m.sleepRemaining = msg.remaining
 	}
 	return m, nil
 }
 
 // View renders the header bar with app name, version, and status indicators.
-// Layout: [radiogogo][v0.x.x][(●) ffplay][(●) rec] ... [1/100]
+// Layout: [radiogogo][v0.x.x][(●) ffplay][(●) rec][☾ 14:59] ... [1/100]
+// While the next page of results is loading, the counter reads [1/100 loading more…].
+// The sleep timer countdown is only shown while the timer runs.
 //
 // The header adapts based on context:
 //   - In search/loading views: Shows only app name and version
 //   - In stations view: Shows full header with playback/recording indicators
 //
 // Status indicator colors:
-//   - Playback dot: white (idle), green (playing), yellow (restarting)
+//   - Playback dot: white (idle), green (playing), yellow (restarting), blue (paused),
+//     orange (reconnecting, also labeled as such)
 //   - Recording dot: white (not recording), red (recording)
 func (m HeaderModel) View() string {
 
@@ -114,8 +126,9 @@ func (m HeaderModel) View() string {
 	baseStyle := m.theme.PrimaryBlock.Copy().PaddingLeft(0).PaddingRight(0)
 
 	// Playback status indicator: (●) ffplay
-	// Color indicates: idle (white), playing (green), restarting (yellow)
+	// Color indicates: idle (white), playing (green), restarting (yellow), paused (blue), reconnecting (orange)
 	var playbackDotColor lipgloss.Color
+	playbackLabel := i18n.T("header_play")
 	switch m.playbackStatus {
 	case PlaybackIdle:
 		playbackDotColor = lipgloss.Color("252") // white/gray
@@ -123,13 +136,18 @@ func (m HeaderModel) View() string {
 		playbackDotColor = lipgloss.Color("42") // green
 	case PlaybackRestarting:
 		playbackDotColor = lipgloss.Color("226") // yellow
+	case PlaybackPaused:
+		playbackDotColor = lipgloss.Color("39") // blue
+	case PlaybackReconnecting:
+		playbackDotColor = lipgloss.Color("208") // orange
+		playbackLabel = i18n.T("header_reconnecting")
 	}
 
 	playbackDotStyle := baseStyle.Copy().Foreground(playbackDotColor)
 	// Build indicator piece by piece to maintain consistent background
 	playbackIndicator := baseStyle.Copy().PaddingLeft(2).Render("(") +
 		playbackDotStyle.Render("●") +
-		baseStyle.Copy().PaddingRight(2).Render(") "+i18n.T("header_play"))
+		baseStyle.Copy().PaddingRight(2).Render(") "+playbackLabel)
 
 	// Recording status indicator: (●) rec
 	// Color indicates: not recording (white), recording (red)
@@ -145,9 +163,23 @@ func (m HeaderModel) View() string {
 		recDotStyle.Render("●") +
 		baseStyle.Copy().PaddingRight(2).Render(") "+i18n.T("header_recording"))
 
+	// Sleep timer countdown: ☾ 14:59
+	var sleepIndicator string
+	if m.sleepRemaining > 0 {
+		// Rounded up, so the countdown ends on 0:01 rather than 0:00
+		remaining := (m.sleepRemaining + time.Second - 1).Truncate(time.Second)
+		sleepIndicator = baseStyle.Copy().PaddingRight(2).Render("☾ " + i18n.Tf("header_sleep_timer", map[string]interface{}{
+			"Remaining": formatCountdown(remaining),
+		}))
+	}
+
 	// Compose left and right sections
-	leftHeader := header + version + playbackIndicator + recIndicator
-	rightHeader := m.theme.PrimaryBlock.Render(fmt.Sprintf("%d/%d", m.stationOffset+1, m.totalStations))
+	leftHeader := header + version + playbackIndicator + recIndicator + sleepIndicator
+	counter := fmt.Sprintf("%d/%d", m.stationOffset+1, m.totalStations)
+	if m.loadingMore {
+		counter += " " + i18n.T("header_loading_more")
+	}
+	rightHeader := m.theme.PrimaryBlock.Render(counter)
 
 	// Fill remaining space to push station counter to the right edge
 	fillerWidth := m.width - lipgloss.Width(leftHeader) - lipgloss.Width(rightHeader)
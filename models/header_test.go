@@ -22,9 +22,11 @@ package models
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
 	"github.com/zi0p4tch0/radiogogo/mocks"
 )
 
@@ -127,6 +129,30 @@ func TestHeaderModelView(t *testing.T) {
 		assert.True(t, strings.HasSuffix(view, "\n"))
 	})
 
+	t.Run("labels the playback indicator while reconnecting", func(t *testing.T) {
+		header := NewHeaderModel(theme, mockPM)
+		header.showOffset = true
+		header.width = 120
+
+		model, _ := header.Update(playbackStatusMsg{status: PlaybackReconnecting})
+		view := model.(HeaderModel).View()
+
+		assert.Contains(t, view, "reconnecting")
+	})
+
+	t.Run("shows the sleep timer countdown", func(t *testing.T) {
+		_ = i18n.Init("en")
+		header := NewHeaderModel(theme, mockPM)
+		header.showOffset = true
+		header.width = 120
+
+		model, _ := header.Update(sleepTimerStatusMsg{remaining: 14*time.Minute + 58*time.Second + 300*time.Millisecond})
+		assert.Contains(t, model.(HeaderModel).View(), "sleep 14:59")
+
+		model, _ = model.Update(sleepTimerStatusMsg{remaining: 0})
+		assert.NotContains(t, model.(HeaderModel).View(), "sleep")
+	})
+
 	t.Run("shows recording indicator", func(t *testing.T) {
 		header := NewHeaderModel(theme, mockPM)
 		header.showOffset = true
@@ -137,4 +163,19 @@ func TestHeaderModelView(t *testing.T) {
 
 		assert.Contains(t, view, "rec")
 	})
+
+	t.Run("shows loading more next to the counter", func(t *testing.T) {
+		_ = i18n.Init("en")
+
+		header := NewHeaderModel(theme, mockPM)
+		header.showOffset = true
+		header.width = 120
+		header.stationOffset = 95
+		header.totalStations = 100
+		header.loadingMore = true
+
+		view := header.View()
+
+		assert.Contains(t, view, "96/100 loading more…")
+	})
 }
@@ -0,0 +1,350 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/storage"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyChromeHeight is the number of lines the history view uses around the table
+// (title, search input, counter, status line and spacing).
+const historyChromeHeight = 9
+
+// historyTimeFormat is how the time a song started is shown in the history.
+const historyTimeFormat = "2006-01-02 15:04"
+
+// historyExportFormat is a format the song history can be exported to.
+type historyExportFormat string
+
+const (
+	historyExportCSV  historyExportFormat = "csv"
+	historyExportJSON historyExportFormat = "json"
+)
+
+// HistoryModel lists the songs heard on every station, newest first, with a search
+// over titles and station names. Titles can be copied to the clipboard, and the
+// listed songs exported as CSV or JSON.
+type HistoryModel struct {
+	theme       Theme
+	storage     storage.StationStorageService
+	keybindings config.Keybindings
+
+	filterInput  textinput.Model
+	historyTable table.Model
+
+	// entries holds the songs shown in the table (matching the search)
+	entries    []common.SongHistoryEntry
+	loading    bool
+	err        string
+	successMsg string
+
+	// copyToClipboard writes text to the system clipboard (replaced in tests)
+	copyToClipboard func(string) error
+	// exportDir is where exported files are written, the recording directory
+	// ("" for the working directory)
+	exportDir string
+
+	width  int
+	height int
+}
+
+func NewHistoryModel(theme Theme, storage storage.StationStorageService, keybindings config.Keybindings) HistoryModel {
+	i := textinput.New()
+	i.Placeholder = i18n.T("history_filter_placeholder")
+	i.Width = 30
+	i.TextStyle = theme.Text
+	i.PlaceholderStyle = theme.TertiaryText
+	i.Focus()
+
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: i18n.T("header_time"), Width: len(historyTimeFormat)},
+			{Title: i18n.T("header_station"), Width: 25},
+			{Title: i18n.T("header_song"), Width: 50},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(theme.StationsTableStyle)
+
+	return HistoryModel{
+		theme:           theme,
+		storage:         storage,
+		keybindings:     keybindings,
+		filterInput:     i,
+		historyTable:    t,
+		loading:         true,
+		copyToClipboard: clipboard.WriteAll,
+	}
+}
+
+// Messages
+
+type songHistoryLoadedMsg struct {
+	search  string
+	entries []common.SongHistoryEntry
+	err     error
+}
+
+type songTitleCopiedMsg struct {
+	title string
+	err   error
+}
+
+type songHistoryExportedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// Commands
+
+// loadSongHistoryCmd loads the songs matching a search text.
+func loadSongHistoryCmd(storage storage.StationStorageService, search string) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := storage.GetSongHistory(search)
+		return songHistoryLoadedMsg{search: search, entries: entries, err: err}
+	}
+}
+
+// copySongTitleCmd copies a song title to the clipboard.
+func copySongTitleCmd(copyToClipboard func(string) error, title string) tea.Cmd {
+	return func() tea.Msg {
+		return songTitleCopiedMsg{title: title, err: copyToClipboard(title)}
+	}
+}
+
+// exportSongHistoryCmd writes entries to a new file in dir, named after the current time.
+// The directory is created if missing. The exported message has the file's absolute path.
+// Example: radiogogo-history-20260301-150405.csv
+func exportSongHistoryCmd(entries []common.SongHistoryEntry, dir string, format historyExportFormat) tea.Cmd {
+	return func() tea.Msg {
+		if dir == "" {
+			dir = "."
+		}
+		name := fmt.Sprintf("radiogogo-history-%s.%s", time.Now().Format("20060102-150405"), format)
+		path := filepath.Join(dir, name)
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return songHistoryExportedMsg{path: path, count: len(entries), err: err}
+		}
+		err := writeSongHistoryFile(path, entries, format)
+		return songHistoryExportedMsg{path: path, count: len(entries), err: err}
+	}
+}
+
+func writeSongHistoryFile(path string, entries []common.SongHistoryEntry, format historyExportFormat) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	write := common.WriteSongHistoryCSV
+	if format == historyExportJSON {
+		write = common.WriteSongHistoryJSON
+	}
+	if err := write(file, entries); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func updateHistoryCommandsCmd(kb config.Keybindings) tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": "esc"}),
+				i18n.T("cmd_move"),
+				i18n.T("cmd_copy_title"),
+				i18n.Tf("cmd_export_csv", map[string]interface{}{"Key": kb.ExportCSV}),
+				i18n.Tf("cmd_export_json", map[string]interface{}{"Key": kb.ExportJSON}),
+				i18n.T("current_language"),
+			},
+		}
+	}
+}
+
+// Bubbletea
+
+func (m HistoryModel) Init() tea.Cmd {
+	return tea.Batch(
+		textinput.Blink,
+		updateHistoryCommandsCmd(m.keybindings),
+		loadSongHistoryCmd(m.storage, ""),
+	)
+}
+
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case songHistoryLoadedMsg:
+		// Results of an older search are dropped
+		if msg.search != m.filterInput.Value() {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.err = i18n.Tf("error_load_history", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		m.setEntries(msg.entries)
+		return m, nil
+
+	case songTitleCopiedMsg:
+		if msg.err != nil {
+			m.successMsg = ""
+			m.err = i18n.Tf("error_clipboard", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		m.successMsg = i18n.Tf("history_copied", map[string]interface{}{"Title": msg.title})
+		return m, nil
+
+	case songHistoryExportedMsg:
+		if msg.err != nil {
+			m.successMsg = ""
+			m.err = i18n.Tf("error_export_history", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		m.successMsg = i18n.Tfn("history_exported", msg.count, map[string]interface{}{"Count": msg.count, "Path": msg.path})
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg {
+				return switchToSearchModelMsg{}
+			}
+		case "up", "down", "pgup", "pgdown":
+			newTable, cmd := m.historyTable.Update(msg)
+			m.historyTable = newTable
+			return m, cmd
+		case "enter":
+			if len(m.entries) == 0 {
+				return m, nil
+			}
+			return m, copySongTitleCmd(m.copyToClipboard, m.entries[m.historyTable.Cursor()].Title)
+		case m.keybindings.ExportCSV:
+			return m, m.export(historyExportCSV)
+		case m.keybindings.ExportJSON:
+			return m, m.export(historyExportJSON)
+		}
+	}
+
+	// Any other input edits the search
+	previousSearch := m.filterInput.Value()
+	newInputModel, cmd := m.filterInput.Update(msg)
+	m.filterInput = newInputModel
+	if m.filterInput.Value() != previousSearch {
+		return m, tea.Batch(cmd, loadSongHistoryCmd(m.storage, m.filterInput.Value()))
+	}
+	return m, cmd
+}
+
+// SetExportDirectory sets where exported files are written ("" for the working directory).
+func (m *HistoryModel) SetExportDirectory(dir string) {
+	m.exportDir = dir
+}
+
+// export writes the songs shown to a file. Nothing happens if there are none.
+func (m HistoryModel) export(format historyExportFormat) tea.Cmd {
+	if len(m.entries) == 0 {
+		return nil
+	}
+	return exportSongHistoryCmd(m.entries, m.exportDir, format)
+}
+
+// setEntries replaces the songs shown in the table.
+func (m *HistoryModel) setEntries(entries []common.SongHistoryEntry) {
+	rows := make([]table.Row, len(entries))
+	for i, entry := range entries {
+		rows[i] = table.Row{
+			entry.StartedAt.Local().Format(historyTimeFormat),
+			entry.StationName,
+			formatSongTitle(entry.Title),
+		}
+	}
+	m.entries = entries
+	m.historyTable.SetRows(rows)
+	m.historyTable.SetCursor(0)
+}
+
+func (m HistoryModel) View() string {
+	v := fmt.Sprintf("\n%s\n\n%s\n\n",
+		m.theme.SecondaryText.Bold(true).Render(i18n.T("history_title")),
+		m.filterInput.View(),
+	)
+
+	switch {
+	case m.loading:
+		v += m.theme.TertiaryText.Render(i18n.T("loading")) + "\n"
+	case len(m.entries) == 0 && strings.TrimSpace(m.filterInput.Value()) == "":
+		v += m.theme.TertiaryText.Render(i18n.T("history_empty")) + "\n"
+	case len(m.entries) == 0:
+		v += m.theme.TertiaryText.Render(i18n.T("browse_empty")) + "\n"
+	default:
+		v += m.historyTable.View() + "\n\n" +
+			m.theme.TertiaryText.Render(i18n.Tfn("history_count", len(m.entries), map[string]interface{}{"Count": len(m.entries)})) + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	} else if m.successMsg != "" {
+		v += "\n" + m.theme.SecondaryText.Render(m.successMsg) + "\n"
+	}
+
+	return v
+}
+
+func (m *HistoryModel) SetWidthAndHeight(width int, height int) {
+	m.width = width
+	m.height = height
+
+	tableHeight := height - historyChromeHeight
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.historyTable.SetHeight(tableHeight)
+
+	// The song column takes whatever width is left
+	columns := m.historyTable.Columns()
+	songWidth := width - columns[0].Width - columns[1].Width - 8
+	if songWidth < 20 {
+		songWidth = 20
+	}
+	columns[2].Width = songWidth
+	m.historyTable.SetColumns(columns)
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSongHistory() []common.SongHistoryEntry {
+	startedAt := time.Date(2026, 3, 1, 15, 0, 0, 0, time.Local)
+	station := uuid.New()
+	return []common.SongHistoryEntry{
+		{StationUuid: station, StationName: "Team Radio", Title: "Air - Playground Love", StartedAt: startedAt.Add(5 * time.Minute)},
+		{StationUuid: station, StationName: "Team Radio", Title: "Daft Punk - One More Time", StartedAt: startedAt, EndedAt: startedAt.Add(5 * time.Minute)},
+	}
+}
+
+func TestHistoryModel(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	t.Run("loads the whole history", func(t *testing.T) {
+		var searched *string
+		storage := &mocks.MockStationStorageService{
+			GetSongHistoryFunc: func(search string) ([]common.SongHistoryEntry, error) {
+				searched = &search
+				return testSongHistory(), nil
+			},
+		}
+		model := NewHistoryModel(Theme{}, storage, testSearchKeybindings)
+
+		msg := findMsgInCmd(model.Init(), func(msg tea.Msg) bool {
+			_, ok := msg.(songHistoryLoadedMsg)
+			return ok
+		})
+		assert.Equal(t, "", *searched)
+
+		newModel, _ := model.Update(msg)
+		model = newModel.(HistoryModel)
+		assert.False(t, model.loading)
+		rows := model.historyTable.Rows()
+		assert.Len(t, rows, 2)
+		assert.Equal(t, []string{"2026-03-01 15:05", "Team Radio", "Air – Playground Love"}, []string(rows[0]))
+		assert.Contains(t, model.View(), "2 songs")
+	})
+
+	t.Run("searches as the user types, dropping outdated results", func(t *testing.T) {
+		var searched string
+		storage := &mocks.MockStationStorageService{
+			GetSongHistoryFunc: func(search string) ([]common.SongHistoryEntry, error) {
+				searched = search
+				return testSongHistory()[1:], nil
+			},
+		}
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		model.storage = storage
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		model = newModel.(HistoryModel)
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(songHistoryLoadedMsg)
+			return ok
+		})
+		assert.Equal(t, "d", searched)
+
+		// Results for the empty search arrive late
+		newModel, _ = model.Update(songHistoryLoadedMsg{search: "", entries: testSongHistory()})
+		model = newModel.(HistoryModel)
+		assert.Len(t, model.historyTable.Rows(), 2)
+
+		newModel, _ = model.Update(msg)
+		model = newModel.(HistoryModel)
+		assert.Len(t, model.historyTable.Rows(), 1)
+		assert.Contains(t, model.View(), "1 song")
+	})
+
+	t.Run("shows an empty history", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{})
+
+		assert.Contains(t, model.View(), "No songs yet")
+	})
+
+	t.Run("copies the selected title to the clipboard", func(t *testing.T) {
+		var copied string
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		model.copyToClipboard = func(text string) error {
+			copied = text
+			return nil
+		}
+		model.historyTable.MoveDown(1)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg := cmd()
+		assert.Equal(t, "Daft Punk - One More Time", copied)
+
+		newModel, _ := model.Update(msg)
+		assert.Equal(t, "Copied to clipboard: Daft Punk - One More Time", newModel.(HistoryModel).successMsg)
+	})
+
+	t.Run("reports clipboard errors", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		model.copyToClipboard = func(string) error { return errors.New("no clipboard utilities available") }
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		newModel, _ := model.Update(cmd())
+
+		assert.Equal(t, "Failed to copy to clipboard: no clipboard utilities available", newModel.(HistoryModel).err)
+	})
+
+	t.Run("exports the listed songs as CSV and JSON", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		model.exportDir = t.TempDir()
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+		msg := cmd().(songHistoryExportedMsg)
+		assert.NoError(t, msg.err)
+		assert.Equal(t, 2, msg.count)
+		assert.Equal(t, ".csv", filepath.Ext(msg.path))
+		data, err := os.ReadFile(msg.path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "Daft Punk - One More Time")
+
+		newModel, _ := model.Update(msg)
+		assert.Equal(t, "Exported 2 songs to "+msg.path, newModel.(HistoryModel).successMsg)
+
+		_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+		msg = cmd().(songHistoryExportedMsg)
+		assert.NoError(t, msg.err)
+		assert.Equal(t, ".json", filepath.Ext(msg.path))
+	})
+
+	t.Run("reports export errors", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		file := filepath.Join(t.TempDir(), "file")
+		assert.NoError(t, os.WriteFile(file, nil, 0o644))
+		model.exportDir = filepath.Join(file, "missing")
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+		newModel, _ := model.Update(cmd())
+
+		assert.Contains(t, newModel.(HistoryModel).err, "Failed to export song history")
+	})
+
+	t.Run("creates a missing export directory", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+		model.exportDir = filepath.Join(t.TempDir(), "recordings")
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+		msg := cmd().(songHistoryExportedMsg)
+
+		assert.NoError(t, msg.err)
+		assert.Equal(t, model.exportDir, filepath.Dir(msg.path))
+	})
+
+	t.Run("shows the full path of files exported to the working directory", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Chdir(dir)
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+		msg := cmd().(songHistoryExportedMsg)
+
+		assert.NoError(t, msg.err)
+		assert.True(t, filepath.IsAbs(msg.path))
+		assert.FileExists(t, filepath.Join(dir, filepath.Base(msg.path)))
+	})
+
+	t.Run("doesn't export an empty history", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("esc goes back to search", func(t *testing.T) {
+		model := loadedModel(NewHistoryModel(Theme{}, &mocks.MockStationStorageService{}, testSearchKeybindings), songHistoryLoadedMsg{entries: testSongHistory()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.IsType(t, switchToSearchModelMsg{}, cmd())
+	})
+}
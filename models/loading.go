@@ -20,9 +20,13 @@
 package models
 
 import (
+	"context"
+
 	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/providers"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -34,10 +38,23 @@ type LoadingModel struct {
 	spinnerModel spinner.Model
 	query        common.StationQuery
 	queryText    string
-	width        int
-	height       int
+	// advancedParams, when set, runs a multi-criteria search instead of query/queryText.
+	advancedParams *common.StationSearchParams
+	// order and reverse sort the results of query/queryText.
+	order   common.StationOrder
+	reverse bool
+	// pageSize is the number of stations fetched for the first page of results.
+	pageSize int
+	width    int
+	height   int
 
 	browser api.RadioBrowserService
+	// provider is where query/queryText are searched (RadioBrowser by default)
+	provider providers.StationProvider
+
+	// Cancels the in-flight search when the user presses Esc
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func NewLoadingModel(
@@ -51,39 +68,107 @@ func NewLoadingModel(
 	s.Spinner = spinner.Dot
 	s.Style = theme.SecondaryText
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return LoadingModel{
 		theme:        theme,
 		spinnerModel: s,
 		query:        query,
 		queryText:    queryText,
+		order:        common.StationOrderVotes,
+		reverse:      true,
+		pageSize:     config.NewDefaultSearchPreferences().PageSize,
 		browser:      browser,
+		provider:     providers.NewRadioBrowserProvider(browser),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
 
 }
 
 func (m LoadingModel) Init() tea.Cmd {
-	return tea.Batch(m.spinnerModel.Tick, searchStations(m.browser, m.query, m.queryText))
+	if m.advancedParams != nil {
+		return tea.Batch(m.spinnerModel.Tick, advancedSearchStations(m.ctx, m.browser, *m.advancedParams, m.pageSize))
+	}
+	return tea.Batch(m.spinnerModel.Tick, searchStations(m.ctx, m.provider, m.query, m.queryText, m.order, m.reverse, m.pageSize))
 }
 
 func (m LoadingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+		// Abort the request and go back to the search screen with the query intact
+		m.cancel()
+		return m, func() tea.Msg {
+			return switchToSearchModelMsg{
+				query:          m.query,
+				queryText:      m.queryText,
+				advancedParams: m.advancedParams,
+				order:          m.order,
+				reverse:        m.reverse,
+			}
+		}
+	}
+
 	newSpinnerModel, cmd := m.spinnerModel.Update(msg)
 	m.spinnerModel = newSpinnerModel
 	return m, cmd
 }
 
 func (m LoadingModel) View() string {
-	return "\n" + m.spinnerModel.View() + " " + i18n.T("loading")
+	return "\n" + m.spinnerModel.View() + " " + i18n.T("loading") + "\n\n" +
+		m.theme.TertiaryText.Render(i18n.T("loading_cancel_hint"))
 }
 
 // Commands
 
-func searchStations(browser api.RadioBrowserService, query common.StationQuery, queryText string) tea.Cmd {
+// searchStations runs a single-filter search with provider and fetches the first page of results.
+// If ctx is cancelled (the user left the loading screen) the result is discarded.
+func searchStations(
+	ctx context.Context,
+	provider providers.StationProvider,
+	query common.StationQuery,
+	queryText string,
+	order common.StationOrder,
+	reverse bool,
+	pageSize int,
+) tea.Cmd {
 	return func() tea.Msg {
-		stations, err := browser.GetStations(query, queryText, "votes", true, 0, 100, true)
+		stations, err := provider.Search(ctx, query, queryText, order, reverse, 0, uint64(pageSize))
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return switchToErrorModelMsg{
+				err:         err.Error(),
+				recoverable: true,
+				cause:       err,
+				retry:       switchToLoadingModelMsg{query: query, queryText: queryText, order: order, reverse: reverse, provider: provider},
+			}
+		}
+		return switchToStationsModelMsg{stations: stations, query: query, queryText: queryText, order: order, reverse: reverse, provider: provider}
+	}
+}
+
+// advancedSearchStations runs a multi-criteria search via the stations/search endpoint
+// and fetches the first page of results.
+// If ctx is cancelled (the user left the loading screen) the result is discarded.
+func advancedSearchStations(ctx context.Context, browser api.RadioBrowserService, params common.StationSearchParams, pageSize int) tea.Cmd {
+	return func() tea.Msg {
+		params.Offset = 0
+		params.Limit = uint64(pageSize)
+		src := stationSource{params: &params, order: params.Order, reverse: params.Reverse}
+		stations, err := fetchStations(ctx, browser, src, 0, params.Limit)
+		if ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
-			return switchToErrorModelMsg{err: err.Error(), recoverable: true}
+			return switchToErrorModelMsg{
+				err:         err.Error(),
+				recoverable: true,
+				cause:       err,
+				retry:       switchToLoadingModelMsg{advancedParams: &params},
+			}
 		}
-		return switchToStationsModelMsg{stations: stations, query: query, queryText: queryText}
+		return switchToStationsModelMsg{stations: stations, advancedParams: &params, order: params.Order, reverse: params.Reverse}
 	}
 }
 
@@ -20,12 +20,14 @@
 package models
 
 import (
+	"context"
 	"io"
 	"testing"
 
 	"github.com/zi0p4tch0/radiogogo/common"
 
 	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/providers"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -60,10 +62,10 @@ func TestLoadingModel_Init(t *testing.T) {
 	t.Run("searches for stations and broadcasts switchToStationsModelMsg on success", func(t *testing.T) {
 
 		mockBrowser := mocks.MockRadioBrowserService{
-			GetStationsFunc: func(stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
 				return []common.Station{}, nil
 			},
-			ClickStationFunc: func(station common.Station) (common.ClickStationResponse, error) {
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
 				return common.ClickStationResponse{}, nil
 			},
 		}
@@ -91,10 +93,10 @@ func TestLoadingModel_Init(t *testing.T) {
 	t.Run("searches for stations and broadcasts switchToErrorModelMsg on error", func(t *testing.T) {
 
 		mockBrowser := mocks.MockRadioBrowserService{
-			GetStationsFunc: func(stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
 				return nil, io.EOF
 			},
-			ClickStationFunc: func(station common.Station) (common.ClickStationResponse, error) {
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
 				return common.ClickStationResponse{}, io.EOF
 			},
 		}
@@ -106,16 +108,116 @@ func TestLoadingModel_Init(t *testing.T) {
 
 		var batchMsg tea.BatchMsg = cmd().(tea.BatchMsg)
 
-		found := false
+		var errorMsg *switchToErrorModelMsg
 		for _, msg := range batchMsg {
-			currentMsg := msg()
-			if _, ok := currentMsg.(switchToErrorModelMsg); ok {
-				found = true
+			if m, ok := msg().(switchToErrorModelMsg); ok {
+				errorMsg = &m
 				break
 			}
 		}
 
-		assert.True(t, found)
+		assert.NotNil(t, errorMsg)
+		assert.Equal(t, io.EOF, errorMsg.cause)
+		assert.Equal(t, switchToLoadingModelMsg{
+			query:     common.StationQueryAll,
+			queryText: "text",
+			order:     common.StationOrderVotes,
+			reverse:   true,
+			provider:  providers.NewRadioBrowserProvider(&mockBrowser),
+		}, errorMsg.retry)
+
+	})
+
+	t.Run("runs an advanced search when advanced params are set", func(t *testing.T) {
+
+		var received common.StationSearchParams
+		mockBrowser := mocks.MockRadioBrowserService{
+			SearchStationsFunc: func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+				received = params
+				return []common.Station{{Name: "Jazz FM"}}, nil
+			},
+		}
+
+		params := common.StationSearchParams{TagList: []string{"jazz"}, CountryCode: "DE", Limit: 100}
+
+		model := NewLoadingModel(Theme{}, &mockBrowser, common.StationQueryAll, "")
+		model.advancedParams = &params
+
+		cmd := model.Init()
+		assert.NotNil(t, cmd)
+
+		var batchMsg tea.BatchMsg = cmd().(tea.BatchMsg)
+
+		var stationsMsg *switchToStationsModelMsg
+		for _, msg := range batchMsg {
+			if m, ok := msg().(switchToStationsModelMsg); ok {
+				stationsMsg = &m
+				break
+			}
+		}
+
+		assert.NotNil(t, stationsMsg)
+		assert.Equal(t, params, received)
+		assert.Equal(t, &params, stationsMsg.advancedParams)
+		assert.Len(t, stationsMsg.stations, 1)
+
+	})
+
+}
+
+func TestLoadingModel_Cancel(t *testing.T) {
+
+	t.Run("esc cancels the search and returns to search with the query preserved", func(t *testing.T) {
+
+		mockBrowser := mocks.MockRadioBrowserService{}
+		model := NewLoadingModel(Theme{}, &mockBrowser, common.StationQueryByTag, "jazz")
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.NotNil(t, cmd)
+
+		assert.Equal(t, switchToSearchModelMsg{
+			query:     common.StationQueryByTag,
+			queryText: "jazz",
+			order:     common.StationOrderVotes,
+			reverse:   true,
+		}, cmd())
+		assert.ErrorIs(t, newModel.(LoadingModel).ctx.Err(), context.Canceled)
+
+	})
+
+	t.Run("esc preserves advanced search params", func(t *testing.T) {
+
+		params := common.StationSearchParams{Codec: "AAC"}
+
+		mockBrowser := mocks.MockRadioBrowserService{}
+		model := NewLoadingModel(Theme{}, &mockBrowser, common.StationQueryAll, "")
+		model.advancedParams = &params
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.NotNil(t, cmd)
+
+		msg := cmd().(switchToSearchModelMsg)
+		assert.Equal(t, &params, msg.advancedParams)
+
+	})
+
+	t.Run("passes the context to the API and discards the result once cancelled", func(t *testing.T) {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var received context.Context
+		mockBrowser := mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				received = ctx
+				cancel()
+				return nil, ctx.Err()
+			},
+		}
+
+		msg := searchStations(ctx, providers.NewRadioBrowserProvider(&mockBrowser), common.StationQueryByName, "test", common.StationOrderVotes, true, 100)()
+
+		assert.Nil(t, msg)
+		assert.Equal(t, ctx, received)
 
 	})
 
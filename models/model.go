@@ -21,7 +21,12 @@
 // It uses BubbleTea's Elm-inspired architecture with the following states:
 //   - bootState: Initialization, checks if playback (FFplay) is available
 //   - searchState: User enters search criteria (name, country, codec, etc.)
-//   - loadingState: Fetches stations from RadioBrowser API
+//   - browseState: User picks a country, language, tag... from RadioBrowser's listings
+//   - discoverState: Shows RadioBrowser's trending, most voted, recently played and changed stations
+//   - historyState: Lists the songs heard on every station, read from stream metadata
+//   - alarmsState: Lists the alarms that start playing a bookmarked station at a time of day
+//   - recordingsState: Lists the scheduled recordings of stations and the files they recorded
+//   - loadingState: Fetches stations from RadioBrowser API (or another station provider)
 //   - stationsState: Displays results in a table, allows selection and playback
 //   - errorState: Shows error messages
 //   - terminalTooSmallState: Displays when terminal is below minimum size
@@ -31,11 +36,17 @@
 package models
 
 import (
+	"time"
+
 	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/health"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
 	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/providers"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 	"github.com/zi0p4tch0/radiogogo/storage"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -56,6 +67,11 @@ const (
 	loadingState
 	stationsState
 	terminalTooSmallState
+	browseState
+	discoverState
+	historyState
+	alarmsState
+	recordingsState
 )
 
 // State switching messages
@@ -63,20 +79,52 @@ const (
 type switchToErrorModelMsg struct {
 	err         string
 	recoverable bool
+	// cause is the original error, used to explain API failures (optional)
+	cause error
+	// retry is sent when the user presses the retry key (optional)
+	retry tea.Msg
 }
 type switchToSearchModelMsg struct {
+	// Optional query to restore on the search screen (e.g. after cancelling a search)
+	query          common.StationQuery
+	queryText      string
+	advancedParams *common.StationSearchParams
+	// Optional sort order to restore (empty means the one remembered in config)
+	order   common.StationOrder
+	reverse bool
 }
+type switchToBrowseModelMsg struct{}
+type switchToDiscoverModelMsg struct{}
+type switchToHistoryModelMsg struct{}
+type switchToAlarmsModelMsg struct{}
+type switchToRecordingsModelMsg struct{}
 type switchToLoadingModelMsg struct {
 	query     common.StationQuery
 	queryText string
+	// advancedParams, when set, replaces query/queryText with a multi-criteria search.
+	advancedParams *common.StationSearchParams
+	// Sort order for query/queryText (empty means the one remembered in config)
+	order   common.StationOrder
+	reverse bool
+	// Where query/queryText are searched (nil means RadioBrowser)
+	provider providers.StationProvider
 }
 type switchToStationsModelMsg struct {
-	stations  []common.Station
-	query     common.StationQuery
-	queryText string
+	stations       []common.Station
+	query          common.StationQuery
+	queryText      string
+	advancedParams *common.StationSearchParams
+	order          common.StationOrder
+	reverse        bool
+	provider       providers.StationProvider
 }
 type switchToBookmarksMsg struct {
 	stations []common.Station
+	// refresh fetches the bookmarks from the API once shown, they're listed from their snapshots
+	refresh bool
+}
+type switchToCustomStationsMsg struct {
+	stations []common.Station
 }
 
 // UI messages
@@ -91,6 +139,15 @@ type languageChangedMsg struct {
 	lang string
 }
 
+// bookmarkHealthTickMsg starts the periodic health check of bookmarked stations
+type bookmarkHealthTickMsg struct{}
+
+// sortOrderChangedMsg asks to remember the sort order chosen by the user in config
+type sortOrderChangedMsg struct {
+	order   common.StationOrder
+	reverse bool
+}
+
 // Quit message
 
 type quitMsg struct{}
@@ -127,6 +184,11 @@ type Model struct {
 	// Models
 	headerModel                HeaderModel
 	searchModel                SearchModel
+	browseModel                BrowseModel
+	historyModel               HistoryModel
+	alarmsModel                AlarmsModel
+	recordingsModel            RecordingsModel
+	discoverModel              DiscoverModel
 	errorModel                 ErrorModel
 	loadingModel               LoadingModel
 	stationsModel              StationsModel
@@ -141,27 +203,73 @@ type Model struct {
 	browser         api.RadioBrowserService
 	playbackManager playback.PlaybackManagerService
 	storage         storage.StationStorageService
+
+	// Station sources offered on the search screen, and the one last searched
+	stationProviders []providers.StationProvider
+	selectedSource   common.StationSource
+
+	// Local stream health checks, and how often bookmarks are checked (0 disables it)
+	healthChecker          *health.Checker
+	bookmarkHealthInterval time.Duration
+
+	// Follows the song titles of the playing station, whichever view it was started from
+	songTitleWatcher *icy.Watcher
+
+	// How many times a player that stopped by itself is restarted (0 disables it)
+	reconnectAttempts int
+
+	// Set once the background checks (bookmark health, alarms, scheduled recordings)
+	// are ticking, so that they're started exactly once, whatever state the app booted into
+	tickersStarted bool
+
+	// Alarms ring when their time passed since the last check (zero before the first one)
+	clock          schedule.Clock
+	lastAlarmCheck time.Time
+	// pendingAlarm rang while an alarm or recording form was open, or the terminal was
+	// too small: it plays once the user is done (ID 0 when none is waiting)
+	pendingAlarm schedule.Alarm
+
+	// Records the scheduled recordings in the background, whatever is playing.
+	// Its exits are listened to once a recording started.
+	streamRecorder             playback.StreamRecorderService
+	listeningForRecordingExits bool
+
+	// Where recordings, manual and scheduled, are saved
+	recordingLocation playback.RecordingLocation
 }
 
-// NewDefaultModel creates a new Model with production dependencies (real API client,
-// FFplay playback manager, and SQLite-based storage). Returns an error if any
-// dependency initialization fails.
+// NewDefaultModel creates a new Model with production dependencies (real API client
+// cached in SQLite, the configured player backend, and SQLite-based storage). Returns an
+// error if any dependency initialization fails.
 func NewDefaultModel(cfg config.Config) (Model, error) {
 
-	browser, err := api.NewRadioBrowser()
+	storageService, err := storage.NewSQLiteStorage()
 	if err != nil {
 		return Model{}, err
 	}
 
-	// Normalize player preferences and create playback manager with configured volume
-	playerPrefs := cfg.PlayerPreferences.ValidateAndNormalize()
-	playbackManager := playback.NewFFPlaybackManager(playerPrefs.DefaultVolume)
-
-	storageService, err := storage.NewSQLiteStorage()
+	apiPrefs := cfg.API.ValidateAndNormalize()
+	radioBrowser, err := api.NewRadioBrowser(apiPrefs.RequestTimeout())
 	if err != nil {
 		return Model{}, err
 	}
 
+	// Reuse API responses stored in the database (and serve them when offline)
+	cachePrefs := cfg.Cache.ValidateAndNormalize()
+	browser := api.NewCachedRadioBrowser(radioBrowser, storageService, api.CacheTTLs{
+		Search:  cachePrefs.SearchTTL(),
+		Station: cachePrefs.StationTTL(),
+		Facet:   cachePrefs.FacetTTL(),
+	})
+
+	// Normalize player preferences and create the configured (or first installed) player
+	playerPrefs := cfg.PlayerPreferences.ValidateAndNormalize()
+	playbackManager := playback.NewDefaultBackendRegistry().Select(playerPrefs.Backend, playerPrefs.DefaultVolume)
+	// Pausing keeps downloading the station, so playback resumes where it was paused
+	if timeshifter, ok := playbackManager.(playback.Timeshifter); ok {
+		timeshifter.EnableTimeshift(playerPrefs.TimeshiftBufferSize())
+	}
+
 	return NewModel(cfg, browser, playbackManager, storageService), nil
 
 }
@@ -176,20 +284,60 @@ func NewModel(
 ) Model {
 
 	theme := NewTheme(cfg)
+	healthPrefs := cfg.Health.ValidateAndNormalize()
+	playerPrefs := cfg.PlayerPreferences.ValidateAndNormalize()
+	recordingPrefs := cfg.Recording.ValidateAndNormalize()
 
 	return Model{
-		config:          cfg,
-		theme:           theme,
-		headerModel:     NewHeaderModel(theme, playbackManager),
-		state:           bootState,
-		browser:         browser,
-		playbackManager: playbackManager,
-		storage:         storage,
+		config:           cfg,
+		theme:            theme,
+		headerModel:      NewHeaderModel(theme, playbackManager),
+		state:            bootState,
+		browser:          browser,
+		playbackManager:  playbackManager,
+		storage:          storage,
+		stationProviders: newStationProviders(cfg.Providers, browser),
+
+		healthChecker:          health.NewChecker(healthPrefs.Workers, healthPrefs.Timeout()),
+		bookmarkHealthInterval: healthPrefs.BookmarkInterval(),
+
+		songTitleWatcher: icy.NewWatcher(icy.NewClient()),
+
+		reconnectAttempts: playerPrefs.MaxReconnectAttempts(),
+
+		clock: schedule.SystemClock{},
+
+		streamRecorder: playback.NewStreamRecorder(),
+
+		recordingLocation: playback.RecordingLocation{
+			Directory:        config.ExpandPath(recordingPrefs.Directory),
+			FilenameTemplate: recordingPrefs.FilenameTemplate,
+		},
 	}
 }
 
+// newStationProviders returns the station sources offered on the search screen:
+// RadioBrowser, followed by the local sources enabled in config.
+func newStationProviders(prefs config.ProviderPreferences, browser api.RadioBrowserService) []providers.StationProvider {
+	result := []providers.StationProvider{providers.NewRadioBrowserProvider(browser)}
+	if prefs.PlaylistDirectory != "" {
+		result = append(result, providers.NewPlaylistDirProvider(config.ExpandPath(prefs.PlaylistDirectory)))
+	}
+	if prefs.IcecastDirectory != "" {
+		result = append(result, providers.NewIcecastDirectoryProvider(config.ExpandPath(prefs.IcecastDirectory)))
+	}
+	return result
+}
+
+// scheduleBookmarkHealthCheckCmd sends the next bookmarkHealthTickMsg after interval.
+func scheduleBookmarkHealthCheckCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return bookmarkHealthTickMsg{}
+	})
+}
+
 // Init initializes the model by checking if playback is available.
-// If FFplay is not found, transitions to error state; otherwise transitions to search state.
+// If no usable player is found, transitions to error state; otherwise transitions to search state.
 func (m Model) Init() tea.Cmd {
 	return checkIfPlaybackIsPossibleCmd(m.playbackManager)
 }
@@ -240,6 +388,16 @@ func (m Model) View() string {
 		currentView = "\n" + i18n.T("initializing")
 	case searchState:
 		currentView = m.searchModel.View()
+	case browseState:
+		currentView = m.browseModel.View()
+	case discoverState:
+		currentView = m.discoverModel.View()
+	case historyState:
+		currentView = m.historyModel.View()
+	case alarmsState:
+		currentView = m.alarmsModel.View()
+	case recordingsState:
+		currentView = m.recordingsModel.View()
 	case loadingState:
 		currentView = m.loadingModel.View()
 	case stationsState:
@@ -252,18 +410,21 @@ func (m Model) View() string {
 	view += currentView
 
 	// Push the bottom bar at the bottom of the terminal (skip for stations - it handles its own height)
-	if m.state != stationsState {
+	if m.state != stationsState && !(m.state == discoverState && m.discoverModel.showsStations()) {
 		// Measure the actual height of header + content combined
 		// This accounts for trailing newline merge effects
 		headerContentHeight := lipgloss.Height(view)
 		bottomBarHeight := 1
+		if len(m.bottomBarSecondaryCommands) > 0 {
+			bottomBarHeight = 2
+		}
 		fillerHeight := CalculateFillerHeight(m.height, headerContentHeight, bottomBarHeight)
 		view += RenderFiller(fillerHeight)
 	}
 
-	// Render bottom bar (one or two rows) - skip when hidden modal is showing
-	if m.state == stationsState && m.stationsModel.showHiddenModal {
-		// Don't render bottom bar when hidden modal is open
+	// Render bottom bar (one or two rows) - skip when a modal is showing
+	if (m.state == stationsState && m.stationsModel.IsModalShowing()) || (m.state == discoverState && m.discoverModel.IsModalShowing()) {
+		// Don't render bottom bar when a modal is open
 	} else if len(m.bottomBarSecondaryCommands) > 0 {
 		view += m.theme.StyleTwoRowBottomBar(m.bottomBarCommands, m.bottomBarSecondaryCommands)
 	} else {
@@ -273,6 +434,17 @@ func (m Model) View() string {
 	return view
 }
 
+// pageSize returns the configured number of stations fetched per page of results.
+func (m Model) pageSize() int {
+	return m.config.Search.ValidateAndNormalize().PageSize
+}
+
+// sortOrder returns the sort order remembered in config.
+func (m Model) sortOrder() (common.StationOrder, bool) {
+	prefs := m.config.Search.ValidateAndNormalize()
+	return prefs.Order, prefs.Reverse
+}
+
 // filterHiddenStations removes hidden stations from the list
 func filterHiddenStations(stations []common.Station, storage storage.StationStorageService) []common.Station {
 	if storage == nil {
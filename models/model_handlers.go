@@ -20,8 +20,15 @@
 package models
 
 import (
+	"context"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+	"github.com/zi0p4tch0/radiogogo/storage"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -33,6 +40,7 @@ func (m Model) handleGlobalMessages(msg tea.Msg) (bool, Model, tea.Cmd) {
 	case stationCursorMovedMsg:
 		m.headerModel.totalStations = msg.totalStations
 		m.headerModel.stationOffset = msg.offset
+		m.headerModel.loadingMore = msg.loadingMore
 		return true, m, nil
 
 	case playbackStatusMsg:
@@ -45,10 +53,16 @@ func (m Model) handleGlobalMessages(msg tea.Msg) (bool, Model, tea.Cmd) {
 		m.headerModel = newHeaderModel.(HeaderModel)
 		return true, m, cmd
 
+	case sleepTimerStatusMsg:
+		newHeaderModel, cmd := m.headerModel.Update(msg)
+		m.headerModel = newHeaderModel.(HeaderModel)
+		return true, m, cmd
+
 	case tea.WindowSizeMsg:
 		return m.handleWindowResize(msg)
 
 	case quitMsg:
+		m.stopScheduledRecordings()
 		return true, m, tea.Quit
 
 	case bottomBarUpdateMsg:
@@ -58,10 +72,243 @@ func (m Model) handleGlobalMessages(msg tea.Msg) (bool, Model, tea.Cmd) {
 
 	case languageChangedMsg:
 		return m.handleLanguageChange(msg)
+
+	case sortOrderChangedMsg:
+		return m.handleSortOrderChange(msg)
+
+	case bookmarkHealthTickMsg:
+		if m.bookmarkHealthInterval <= 0 || m.storage == nil {
+			return true, m, nil
+		}
+		return true, m, tea.Batch(
+			checkBookmarksHealthCmd(m.healthChecker, m.storage, m.bookmarkHealthInterval),
+			scheduleBookmarkHealthCheckCmd(m.bookmarkHealthInterval),
+		)
+
+	case alarmTickMsg:
+		return m.handleAlarmTick()
+
+	case alarmFiredMsg:
+		// The alarm plays in the stations view, which the alarm tick switched to
+		if m.state != stationsState {
+			return true, m, nil
+		}
+		newStationsModel, cmd := m.stationsModel.Update(msg)
+		m.stationsModel = newStationsModel.(StationsModel)
+		return true, m, cmd
+
+	case recordingTickMsg:
+		return m.handleRecordingTick()
+
+	case scheduledRecordingStartedMsg:
+		var cmd tea.Cmd
+		if msg.err != nil {
+			endRecordingRun(m.storage, msg.run.RecordingID, m.clock.Now(), msg.err.Error())
+		} else if !m.listeningForRecordingExits {
+			m.listeningForRecordingExits = true
+			cmd = waitForRecordingExitCmd(m.streamRecorder)
+		}
+		return true, m, tea.Batch(cmd, m.reloadRecordingsCmd())
+
+	case scheduledRecordingExitMsg:
+		errText := ""
+		if msg.exit.Err != nil {
+			errText = msg.exit.Err.Error()
+		}
+		endRecordingRun(m.storage, msg.exit.ID, m.clock.Now(), errText)
+		return true, m, tea.Batch(waitForRecordingExitCmd(m.streamRecorder), m.reloadRecordingsCmd())
 	}
 	return false, m, nil
 }
 
+// handleRecordingTick starts the scheduled recordings that are due, each occurrence
+// once, and stops the ones running well past their end.
+func (m Model) handleRecordingTick() (bool, Model, tea.Cmd) {
+	next := scheduleRecordingCheckCmd(recordingCheckInterval)
+	if m.storage == nil || m.streamRecorder == nil {
+		return true, m, next
+	}
+	recordings, err := m.storage.GetScheduledRecordings()
+	if err != nil {
+		return true, m, next
+	}
+
+	now := m.clock.Now()
+	cmds := []tea.Cmd{next}
+	for _, recording := range recordings {
+		runs, err := m.storage.GetRecordingRuns(recording.ID)
+		if err != nil {
+			continue
+		}
+		if m.streamRecorder.IsRecording(recording.ID) {
+			if len(runs) > 0 && now.After(runs[0].Scheduled.Add(recording.Duration+recordingOverrun)) {
+				stopScheduledRecording(m.storage, m.streamRecorder, recording.ID, now)
+				cmds = append(cmds, m.reloadRecordingsCmd())
+			}
+			continue
+		}
+		start, due := recording.Current(now)
+		if !recording.Enabled || !due {
+			continue
+		}
+		// An occurrence that already ran, or failed, isn't started again
+		if len(runs) > 0 && runs[0].Scheduled.Equal(start) {
+			continue
+		}
+		// The run is saved right away, so that the next tick doesn't start it again
+		run := schedule.RecordingRun{
+			RecordingID: recording.ID,
+			Scheduled:   start,
+			Start:       now,
+		}
+		path, err := m.recordingLocation.Path(recording.Station, "", now)
+		if err != nil {
+			run.End = now
+			run.Err = err.Error()
+			_, _ = m.storage.SaveRecordingRun(run)
+			cmds = append(cmds, m.reloadRecordingsCmd())
+			continue
+		}
+		run.Path = path
+		run, err = m.storage.SaveRecordingRun(run)
+		if err != nil {
+			playback.DiscardRecordingPath(path)
+			continue
+		}
+		cmds = append(cmds, startScheduledRecordingCmd(m.streamRecorder, recording.Station, run, start.Add(recording.Duration).Sub(now)))
+	}
+	return true, m, tea.Batch(cmds...)
+}
+
+// reloadRecordingsCmd refreshes the recordings view, if it's showing.
+func (m Model) reloadRecordingsCmd() tea.Cmd {
+	if m.state != recordingsState || m.storage == nil {
+		return nil
+	}
+	return loadRecordingsCmd(m.storage)
+}
+
+// stopScheduledRecordings stops the scheduled recordings under way, e.g. before quitting,
+// ending their runs.
+func (m Model) stopScheduledRecordings() {
+	if m.streamRecorder == nil {
+		return
+	}
+	if m.storage != nil {
+		recordings, _ := m.storage.GetScheduledRecordings()
+		now := m.clock.Now()
+		for _, recording := range recordings {
+			stopScheduledRecording(m.storage, m.streamRecorder, recording.ID, now)
+		}
+	}
+	// Deleted recordings may still be running
+	m.streamRecorder.StopAll()
+}
+
+// handleAlarmTick rings the alarms due since the last check. The station of the first
+// one plays in the stations view, which shows the bookmarks if it wasn't showing.
+// An alarm due while the user fills in a form, or the terminal is too small, waits
+// for them to be done rather than throwing their input away.
+func (m Model) handleAlarmTick() (bool, Model, tea.Cmd) {
+	now := m.clock.Now()
+	from := m.lastAlarmCheck
+	m.lastAlarmCheck = now
+	next := scheduleAlarmCheckCmd(alarmCheckInterval)
+	if m.storage == nil || from.IsZero() {
+		return true, m, next
+	}
+	if now.Sub(from) > alarmMaxDelay {
+		from = now.Add(-alarmMaxDelay)
+	}
+
+	alarms, err := m.storage.GetAlarms()
+	if err != nil {
+		return true, m, next
+	}
+	due := schedule.DueAlarms(alarms, from, now)
+	// One-off alarms turn themselves off once they rang
+	for _, alarm := range due {
+		if alarm.Once() {
+			alarm.Enabled = false
+			_, _ = m.storage.SaveAlarm(alarm)
+		}
+	}
+
+	// An alarm already waiting goes first
+	alarm := m.pendingAlarm
+	if alarm.ID == 0 && len(due) > 0 {
+		alarm = due[0]
+	}
+	if alarm.ID == 0 {
+		return true, m, next
+	}
+	if m.isBusy() {
+		m.pendingAlarm = alarm
+		return true, m, next
+	}
+	m.pendingAlarm = schedule.Alarm{}
+	return true, m, tea.Batch(next, m.ringAlarm(alarm, now))
+}
+
+// isBusy reports whether an alarm would get in the way of the user: while they fill in
+// an alarm or recording form, or the terminal is too small to show the stations.
+func (m Model) isBusy() bool {
+	switch m.state {
+	case terminalTooSmallState:
+		return true
+	case alarmsState:
+		return m.alarmsModel.showForm
+	case recordingsState:
+		return m.recordingsModel.showForm
+	}
+	return false
+}
+
+// ringAlarm plays the station of alarm in the stations view, switching to the bookmarks
+// (and stopping whatever plays) if another screen is showing.
+func (m *Model) ringAlarm(alarm schedule.Alarm, now time.Time) tea.Cmd {
+	fired := func() tea.Msg { return alarmFiredMsg{alarm: alarm, at: now} }
+	if m.state == stationsState {
+		return fired
+	}
+	endSong := m.stopPlayback()
+	return tea.Batch(endSong, tea.Sequence(openBookmarksForAlarmCmd(m.storage, alarm.Station), fired))
+}
+
+// openBookmarksForAlarmCmd switches to the bookmarks saved in storage, so an alarm
+// doesn't wait on the network. The alarm's station is listed first if it's no longer bookmarked.
+func openBookmarksForAlarmCmd(storage storage.StationStorageService, station common.Station) tea.Cmd {
+	return func() tea.Msg {
+		stations, _ := savedBookmarks(storage)
+		for _, s := range stations {
+			if s.StationUuid == station.StationUuid {
+				return switchToBookmarksMsg{stations: stations}
+			}
+		}
+		return switchToBookmarksMsg{stations: append([]common.Station{station}, stations...)}
+	}
+}
+
+// stopPlayback stops the playing station, if any, before leaving the view it plays in.
+// Returns the command ending its song in the song history.
+func (m *Model) stopPlayback() tea.Cmd {
+	if m.playbackManager != nil {
+		m.playbackManager.StopStation()
+	}
+	if m.songTitleWatcher != nil {
+		m.songTitleWatcher.Stop()
+	}
+	var endSong tea.Cmd
+	if m.storage != nil {
+		endSong = endSongCmd(m.storage)
+	}
+	m.headerModel.playbackStatus = PlaybackIdle
+	m.headerModel.isRecording = false
+	// The sleep timer belongs to the stations view, which is left
+	m.headerModel.sleepRemaining = 0
+	return endSong
+}
+
 // handleWindowResize handles terminal resize events.
 func (m Model) handleWindowResize(msg tea.WindowSizeMsg) (bool, Model, tea.Cmd) {
 	m.width = msg.Width
@@ -85,6 +332,21 @@ func (m Model) handleWindowResize(msg tea.WindowSizeMsg) (bool, Model, tea.Cmd)
 	case searchState:
 		childHeight := m.height - 2 // 1 header + 1 bottom bar row
 		m.searchModel.SetWidthAndHeight(m.width, childHeight)
+	case browseState:
+		childHeight := m.height - 2 // 1 header + 1 bottom bar row
+		m.browseModel.SetWidthAndHeight(m.width, childHeight)
+	case discoverState:
+		childHeight := m.height - 3 // 1 header + 2 bottom bar rows
+		m.discoverModel.SetWidthAndHeight(m.width, childHeight)
+	case historyState:
+		childHeight := m.height - 2 // 1 header + 1 bottom bar row
+		m.historyModel.SetWidthAndHeight(m.width, childHeight)
+	case alarmsState:
+		childHeight := m.height - 2 // 1 header + 1 bottom bar row
+		m.alarmsModel.SetWidthAndHeight(m.width, childHeight)
+	case recordingsState:
+		childHeight := m.height - 2 // 1 header + 1 bottom bar row
+		m.recordingsModel.SetWidthAndHeight(m.width, childHeight)
 	case loadingState:
 		childHeight := m.height - 2 // 1 header + 1 bottom bar row
 		m.loadingModel.SetWidthAndHeight(m.width, childHeight)
@@ -106,31 +368,115 @@ func (m Model) handleLanguageChange(msg languageChangedMsg) (bool, Model, tea.Cm
 
 	// Recreate search model to refresh all strings
 	m.searchModel = NewSearchModel(m.theme, m.browser, m.storage, m.config.Keybindings)
+	m.searchModel.SetOrder(m.sortOrder())
+	m.searchModel.SetProviders(m.stationProviders, m.selectedSource)
 	m.searchModel.SetWidthAndHeight(m.width, m.height-2)
 	return true, m, m.searchModel.Init()
 }
 
+// handleSortOrderChange remembers the sort order chosen by the user.
+// The config file is only written when the order actually changed.
+func (m Model) handleSortOrderChange(msg sortOrderChangedMsg) (bool, Model, tea.Cmd) {
+	order, reverse := m.sortOrder()
+	if order == msg.order && reverse == msg.reverse {
+		return true, m, nil
+	}
+	m.config.Search.Order = msg.order
+	m.config.Search.Reverse = msg.reverse
+	_ = m.config.Save(config.ConfigFile())
+	return true, m, nil
+}
+
 // handleStateTransitions handles messages that trigger state changes.
 // Returns (handled, model, cmd) where handled indicates if the message was processed.
 func (m Model) handleStateTransitions(msg tea.Msg) (bool, Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case switchToSearchModelMsg:
-		if m.playbackManager != nil {
-			m.playbackManager.StopStation()
-		}
+		endSong := m.stopPlayback()
 		m.headerModel.showOffset = false
-		m.headerModel.playbackStatus = PlaybackIdle
-		m.headerModel.isRecording = false
 		m.bottomBarSecondaryCommands = nil
 		m.searchModel = NewSearchModel(m.theme, m.browser, m.storage, m.config.Keybindings)
+		m.searchModel.SetOrder(m.sortOrder())
+		m.searchModel.SetProviders(m.stationProviders, m.selectedSource)
+		if msg.order != "" {
+			m.searchModel.SetOrder(msg.order, msg.reverse)
+		}
+		m.searchModel.RestoreQuery(msg.query, msg.queryText, msg.advancedParams)
 		m.searchModel.SetWidthAndHeight(m.width, m.height-2)
 		m.state = searchState
-		return true, m, m.searchModel.Init()
+		// Bookmarks start being checked, alarms ringing and shows recording, once the app is up
+		if !m.tickersStarted {
+			m.tickersStarted = true
+			return true, m, tea.Batch(
+				m.searchModel.Init(),
+				func() tea.Msg { return bookmarkHealthTickMsg{} },
+				func() tea.Msg { return alarmTickMsg{} },
+				func() tea.Msg { return recordingTickMsg{} },
+			)
+		}
+		return true, m, tea.Batch(m.searchModel.Init(), endSong)
+
+	case switchToBrowseModelMsg:
+		m.headerModel.showOffset = false
+		m.bottomBarSecondaryCommands = nil
+		m.browseModel = NewBrowseModel(m.theme, m.browser, m.config.Keybindings)
+		m.browseModel.SetWidthAndHeight(m.width, m.height-2)
+		m.state = browseState
+		return true, m, m.browseModel.Init()
+
+	case switchToDiscoverModelMsg:
+		m.headerModel.showOffset = true
+		m.bottomBarSecondaryCommands = nil
+		m.discoverModel = NewDiscoverModel(m.theme, m.browser, m.playbackManager, m.storage, m.config.Keybindings)
+		m.discoverModel.pageSize = m.pageSize()
+		m.discoverModel.songTitleWatcher = m.songTitleWatcher
+		m.discoverModel.reconnectAttempts = m.reconnectAttempts
+		m.discoverModel.recordingLocation = m.recordingLocation
+		m.discoverModel.SetWidthAndHeight(m.width, m.height-3)
+		m.state = discoverState
+		return true, m, m.discoverModel.Init()
+
+	case switchToHistoryModelMsg:
+		m.headerModel.showOffset = false
+		m.bottomBarSecondaryCommands = nil
+		m.historyModel = NewHistoryModel(m.theme, m.storage, m.config.Keybindings)
+		m.historyModel.SetExportDirectory(m.recordingLocation.Directory)
+		m.historyModel.SetWidthAndHeight(m.width, m.height-2)
+		m.state = historyState
+		return true, m, m.historyModel.Init()
+
+	case switchToAlarmsModelMsg:
+		endSong := m.stopPlayback()
+		m.headerModel.showOffset = false
+		m.bottomBarSecondaryCommands = nil
+		m.alarmsModel = NewAlarmsModel(m.theme, m.storage, m.playbackManager, m.clock, m.config.Keybindings)
+		m.alarmsModel.SetWidthAndHeight(m.width, m.height-2)
+		m.state = alarmsState
+		return true, m, tea.Batch(m.alarmsModel.Init(), endSong)
+
+	case switchToRecordingsModelMsg:
+		endSong := m.stopPlayback()
+		m.headerModel.showOffset = false
+		m.bottomBarSecondaryCommands = nil
+		m.recordingsModel = NewRecordingsModel(m.theme, m.storage, m.streamRecorder, m.clock, m.config.Keybindings)
+		m.recordingsModel.SetWidthAndHeight(m.width, m.height-2)
+		m.state = recordingsState
+		return true, m, tea.Batch(m.recordingsModel.Init(), endSong)
 
 	case switchToLoadingModelMsg:
 		m.headerModel.showOffset = false
 		m.bottomBarSecondaryCommands = nil
 		m.loadingModel = NewLoadingModel(m.theme, m.browser, msg.query, msg.queryText)
+		m.loadingModel.advancedParams = msg.advancedParams
+		m.loadingModel.pageSize = m.pageSize()
+		m.loadingModel.order, m.loadingModel.reverse = msg.order, msg.reverse
+		if msg.order == "" {
+			m.loadingModel.order, m.loadingModel.reverse = m.sortOrder()
+		}
+		if msg.provider != nil {
+			m.loadingModel.provider = msg.provider
+			m.selectedSource = msg.provider.Source()
+		}
 		m.loadingModel.SetWidthAndHeight(m.width, m.height-2)
 		m.state = loadingState
 		return true, m, m.loadingModel.Init()
@@ -139,13 +485,48 @@ func (m Model) handleStateTransitions(msg tea.Msg) (bool, Model, tea.Cmd) {
 		m.headerModel.showOffset = true
 		filteredStations := filterHiddenStations(msg.stations, m.storage)
 		m.stationsModel = NewStationsModel(m.theme, m.browser, m.playbackManager, m.storage, filteredStations, viewModeSearchResults, msg.query, msg.queryText, m.config.Keybindings)
+		m.stationsModel.lastSearchParams = msg.advancedParams
+		m.stationsModel.EnablePaging(m.pageSize(), len(msg.stations))
+		m.stationsModel.SetSort(msg.order, msg.reverse)
+		if msg.provider != nil {
+			m.stationsModel.SetProvider(msg.provider)
+		}
+		if msg.advancedParams != nil && msg.advancedParams.Near != nil {
+			m.stationsModel.SetOrigin(*msg.advancedParams.Near)
+		}
+		m.stationsModel.SetHealthChecker(m.healthChecker)
+		m.stationsModel.SetSongTitleWatcher(m.songTitleWatcher)
+		m.stationsModel.SetReconnectAttempts(m.reconnectAttempts)
+		m.stationsModel.SetRecordingLocation(m.recordingLocation)
 		m.stationsModel.SetWidthAndHeight(m.width, m.height-3)
 		m.state = stationsState
-		return true, m, m.stationsModel.Init()
+		if m.storage == nil {
+			return true, m, m.stationsModel.Init()
+		}
+		// Bookmarked stations among the results get their snapshots refreshed
+		return true, m, tea.Batch(m.stationsModel.Init(), saveBookmarkSnapshotsCmd(m.storage, msg.stations))
 
 	case switchToBookmarksMsg:
 		m.headerModel.showOffset = true
 		m.stationsModel = NewStationsModel(m.theme, m.browser, m.playbackManager, m.storage, msg.stations, viewModeBookmarks, "", "", m.config.Keybindings)
+		m.stationsModel.SetHealthChecker(m.healthChecker)
+		m.stationsModel.SetSongTitleWatcher(m.songTitleWatcher)
+		m.stationsModel.SetReconnectAttempts(m.reconnectAttempts)
+		m.stationsModel.SetRecordingLocation(m.recordingLocation)
+		m.stationsModel.SetWidthAndHeight(m.width, m.height-3)
+		m.state = stationsState
+		if msg.refresh {
+			return true, m, tea.Batch(m.stationsModel.Init(), refreshBookmarksCmd(context.Background(), m.browser, m.storage))
+		}
+		return true, m, m.stationsModel.Init()
+
+	case switchToCustomStationsMsg:
+		m.headerModel.showOffset = true
+		m.stationsModel = NewStationsModel(m.theme, m.browser, m.playbackManager, m.storage, msg.stations, viewModeCustom, "", "", m.config.Keybindings)
+		m.stationsModel.SetHealthChecker(m.healthChecker)
+		m.stationsModel.SetSongTitleWatcher(m.songTitleWatcher)
+		m.stationsModel.SetReconnectAttempts(m.reconnectAttempts)
+		m.stationsModel.SetRecordingLocation(m.recordingLocation)
 		m.stationsModel.SetWidthAndHeight(m.width, m.height-3)
 		m.state = stationsState
 		return true, m, m.stationsModel.Init()
@@ -154,6 +535,8 @@ func (m Model) handleStateTransitions(msg tea.Msg) (bool, Model, tea.Cmd) {
 		m.headerModel.showOffset = false
 		m.bottomBarSecondaryCommands = nil
 		m.errorModel = NewErrorModel(m.theme, msg.err, msg.recoverable, m.config.Keybindings)
+		m.errorModel.cause = msg.cause
+		m.errorModel.retry = msg.retry
 		m.errorModel.SetWidthAndHeight(m.width, m.height-2)
 		m.state = errorState
 		return true, m, m.errorModel.Init()
@@ -169,6 +552,26 @@ func (m Model) delegateToCurrentState(msg tea.Msg) (Model, tea.Cmd) {
 		newSearchModel, cmd := m.searchModel.Update(msg)
 		m.searchModel = newSearchModel.(SearchModel)
 		return m, cmd
+	case browseState:
+		newBrowseModel, cmd := m.browseModel.Update(msg)
+		m.browseModel = newBrowseModel.(BrowseModel)
+		return m, cmd
+	case discoverState:
+		newDiscoverModel, cmd := m.discoverModel.Update(msg)
+		m.discoverModel = newDiscoverModel.(DiscoverModel)
+		return m, cmd
+	case historyState:
+		newHistoryModel, cmd := m.historyModel.Update(msg)
+		m.historyModel = newHistoryModel.(HistoryModel)
+		return m, cmd
+	case alarmsState:
+		newAlarmsModel, cmd := m.alarmsModel.Update(msg)
+		m.alarmsModel = newAlarmsModel.(AlarmsModel)
+		return m, cmd
+	case recordingsState:
+		newRecordingsModel, cmd := m.recordingsModel.Update(msg)
+		m.recordingsModel = newRecordingsModel.(RecordingsModel)
+		return m, cmd
 	case loadingState:
 		newLoadingModel, cmd := m.loadingModel.Update(msg)
 		m.loadingModel = newLoadingModel.(LoadingModel)
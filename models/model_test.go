@@ -20,10 +20,20 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
@@ -245,6 +255,55 @@ func TestModel_Update(t *testing.T) {
 
 	})
 
+	t.Run("restores the query carried by switchToSearchModelMsg", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		msg := switchToSearchModelMsg{query: common.StationQueryByTag, queryText: "jazz"}
+
+		newModel, _ := model.Update(tea.Msg(msg))
+
+		assert.Equal(t, searchState, newModel.(Model).state)
+		assert.Equal(t, "jazz", newModel.(Model).searchModel.inputModel.Value())
+		assert.Equal(t, common.StationQueryByTag, newModel.(Model).searchModel.querySelector.Selection())
+
+	})
+
+	t.Run("switches to browse model if switchToBrowseModelMsg is received", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, cmd := model.Update(tea.Msg(switchToBrowseModelMsg{}))
+
+		assert.Equal(t, browseState, newModel.(Model).state)
+		assert.Equal(t, common.FacetCountries, newModel.(Model).browseModel.Kind())
+		assert.NotNil(t, cmd)
+
+	})
+
+	t.Run("switches to discover model if switchToDiscoverModelMsg is received", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{Search: config.SearchPreferences{PageSize: 30}}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, cmd := model.Update(tea.Msg(switchToDiscoverModelMsg{}))
+
+		assert.Equal(t, discoverState, newModel.(Model).state)
+		assert.Equal(t, common.StationListTopClick, newModel.(Model).discoverModel.List())
+		assert.Equal(t, 30, newModel.(Model).discoverModel.pageSize)
+		assert.True(t, newModel.(Model).headerModel.showOffset)
+		assert.NotNil(t, cmd)
+
+	})
+
 	t.Run("recreates and switches to loading model if switchToLoadingModelMsg is received", func(t *testing.T) {
 
 		browser := mocks.MockRadioBrowserService{}
@@ -282,6 +341,223 @@ func TestModel_Update(t *testing.T) {
 
 	})
 
+	t.Run("switches to the custom stations view if switchToCustomStationsMsg is received", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		stations := []common.Station{{StationUuid: uuid.New(), Name: "Team Radio"}}
+		newModel, cmd := model.Update(tea.Msg(switchToCustomStationsMsg{stations: stations}))
+
+		assert.Equal(t, stationsState, newModel.(Model).state)
+		assert.Equal(t, viewModeCustom, newModel.(Model).stationsModel.viewMode)
+		assert.Equal(t, stations, newModel.(Model).stationsModel.stations)
+		assert.NotNil(t, cmd)
+
+	})
+
+	t.Run("passes the configured page size to the loading and stations models", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		cfg := config.Config{Search: config.SearchPreferences{PageSize: 10}}
+		model := NewModel(cfg, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, _ := model.Update(tea.Msg(switchToLoadingModelMsg{query: common.StationQueryByTag, queryText: "pop"}))
+		assert.Equal(t, 10, newModel.(Model).loadingModel.pageSize)
+
+		stations := make([]common.Station, 10)
+		for i := range stations {
+			stations[i] = common.Station{StationUuid: uuid.New()}
+		}
+		newModel, _ = newModel.Update(tea.Msg(switchToStationsModelMsg{stations: stations, query: common.StationQueryByTag, queryText: "pop"}))
+		assert.Equal(t, 10, newModel.(Model).stationsModel.pageSize)
+		assert.True(t, newModel.(Model).stationsModel.hasMorePages)
+
+	})
+
+	t.Run("searches in the configured sort order unless one is given", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		cfg := config.Config{Search: config.SearchPreferences{Order: common.StationOrderName, Reverse: false}}
+		model := NewModel(cfg, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, _ := model.Update(tea.Msg(switchToLoadingModelMsg{query: common.StationQueryByTag, queryText: "pop"}))
+		assert.Equal(t, common.StationOrderName, newModel.(Model).loadingModel.order)
+		assert.False(t, newModel.(Model).loadingModel.reverse)
+
+		newModel, _ = model.Update(tea.Msg(switchToLoadingModelMsg{query: common.StationQueryByTag, queryText: "pop", order: common.StationOrderBitrate, reverse: true}))
+		assert.Equal(t, common.StationOrderBitrate, newModel.(Model).loadingModel.order)
+		assert.True(t, newModel.(Model).loadingModel.reverse)
+
+		newModel, _ = newModel.Update(tea.Msg(switchToStationsModelMsg{query: common.StationQueryByTag, queryText: "pop", order: common.StationOrderBitrate, reverse: true}))
+		assert.Equal(t, common.StationOrderBitrate, newModel.(Model).stationsModel.sortOrder)
+		assert.True(t, newModel.(Model).stationsModel.sortReverse)
+
+	})
+
+	t.Run("offers the configured station sources and remembers the one searched", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		cfg := config.Config{Providers: config.ProviderPreferences{PlaylistDirectory: t.TempDir()}}
+		model := NewModel(cfg, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, _ := model.Update(tea.Msg(switchToSearchModelMsg{}))
+		sources := newModel.(Model).searchModel.stationProviders
+		assert.Len(t, sources, 2)
+		assert.Equal(t, common.StationSourceRadioBrowser, sources[0].Source())
+		assert.Equal(t, common.StationSourcePlaylists, sources[1].Source())
+
+		newModel, _ = newModel.Update(tea.Msg(switchToLoadingModelMsg{query: common.StationQueryByName, queryText: "jazz", provider: sources[1]}))
+		assert.Equal(t, sources[1], newModel.(Model).loadingModel.provider)
+
+		newModel, _ = newModel.Update(tea.Msg(switchToStationsModelMsg{query: common.StationQueryByName, queryText: "jazz", provider: sources[1]}))
+		assert.Equal(t, sources[1], newModel.(Model).stationsModel.provider)
+
+		newModel, _ = newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+		assert.Equal(t, sources[1], newModel.(Model).searchModel.selectedProvider())
+
+	})
+
+	t.Run("starts checking bookmarks in the background once booted", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+		assert.Equal(t, time.Hour, model.bookmarkHealthInterval)
+
+		newModel, cmd := model.Update(tea.Msg(switchToSearchModelMsg{}))
+		tick := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(bookmarkHealthTickMsg)
+			return ok
+		})
+		assert.NotNil(t, tick)
+
+		// Only once
+		_, cmd = newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+		assert.Nil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(bookmarkHealthTickMsg)
+			return ok
+		}))
+
+		_, cmd = newModel.Update(tick)
+		assert.NotNil(t, cmd)
+
+	})
+
+	t.Run("doesn't check bookmarks when disabled in config", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		cfg := config.Config{Health: config.HealthPreferences{BookmarkIntervalMinutes: -1}}
+		model := NewModel(cfg, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		_, cmd := model.Update(tea.Msg(bookmarkHealthTickMsg{}))
+		assert.Nil(t, cmd)
+
+	})
+
+	t.Run("shows distances for a search around a point", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		cfg := config.Config{Search: config.SearchPreferences{PageSize: 10}}
+		model := NewModel(cfg, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		stations := make([]common.Station, 10)
+		for i := range stations {
+			stations[i] = common.Station{StationUuid: uuid.New()}
+		}
+		near := common.GeoPoint{Lat: 48.8566, Long: 2.3522}
+		params := common.StationSearchParams{Near: &near, GeoDistance: 50000}
+
+		newModel, _ := model.Update(tea.Msg(switchToStationsModelMsg{stations: stations, advancedParams: &params}))
+		assert.Equal(t, &near, newModel.(Model).stationsModel.origin)
+		assert.False(t, newModel.(Model).stationsModel.hasMorePages)
+
+	})
+
+	t.Run("remembers a changed sort order in config", func(t *testing.T) {
+
+		t.Setenv("HOME", t.TempDir())
+		assert.NoError(t, os.MkdirAll(config.ConfigDir(), 0755))
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		newModel, _ := model.Update(tea.Msg(sortOrderChangedMsg{order: common.StationOrderClickTrend, reverse: true}))
+
+		assert.Equal(t, common.StationOrderClickTrend, newModel.(Model).config.Search.Order)
+		assert.True(t, newModel.(Model).config.Search.Reverse)
+
+		saved := config.Config{}
+		assert.NoError(t, saved.Load(config.ConfigFile()))
+		assert.Equal(t, common.StationOrderClickTrend, saved.Search.Order)
+
+	})
+
+	t.Run("refreshes bookmarks shown from their snapshots", func(t *testing.T) {
+
+		snapshot := common.Station{StationUuid: uuid.New(), Name: "Saved"}
+		browser := mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				return nil, errors.New("network unreachable")
+			},
+		}
+		playbackManager := mocks.MockPlaybackManagerService{}
+		storage := &mocks.MockStationStorageService{
+			GetBookmarksFunc:          func() ([]uuid.UUID, error) { return []uuid.UUID{snapshot.StationUuid}, nil },
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) { return []common.Station{snapshot}, nil },
+		}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, storage)
+
+		newModel, cmd := model.Update(tea.Msg(switchToBookmarksMsg{stations: []common.Station{snapshot}, refresh: true}))
+
+		assert.Equal(t, stationsState, newModel.(Model).state)
+		assert.Equal(t, viewModeBookmarks, newModel.(Model).stationsModel.viewMode)
+
+		refreshed := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(bookmarksRefreshedMsg)
+			return ok
+		})
+		assert.Equal(t, bookmarksRefreshedMsg{stations: []common.Station{snapshot}, offline: true}, refreshed)
+
+		newModel, _ = newModel.Update(refreshed)
+		assert.NotEmpty(t, newModel.(Model).stationsModel.err)
+
+	})
+
+	t.Run("passes the cause and retry message to the error model", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{}
+		playbackManager := mocks.MockPlaybackManagerService{}
+
+		model := NewModel(config.Config{}, &browser, &playbackManager, &mocks.MockStationStorageService{})
+
+		cause := &api.APIError{Kind: api.ErrorKindTimeout}
+		retry := switchToLoadingModelMsg{query: common.StationQueryByName, queryText: "rock"}
+
+		newModel, _ := model.Update(tea.Msg(switchToErrorModelMsg{err: cause.Error(), recoverable: true, cause: cause, retry: retry}))
+
+		assert.Equal(t, errorState, newModel.(Model).state)
+		assert.Equal(t, cause, newModel.(Model).errorModel.cause)
+		assert.Equal(t, retry, newModel.(Model).errorModel.retry)
+
+	})
+
 	t.Run("recreates and switches to error model if switchToErrorModelMsg is received", func(t *testing.T) {
 
 		browser := mocks.MockRadioBrowserService{}
@@ -541,4 +817,573 @@ func TestModel_StateTransitionWorkflows(t *testing.T) {
 		assert.False(t, errorMsg.recoverable)
 	})
 
+	t.Run("song history exports to the recording directory", func(t *testing.T) {
+		model := NewModel(config.Config{}, &mocks.MockRadioBrowserService{}, &mocks.MockPlaybackManagerService{}, &mocks.MockStationStorageService{})
+		model.recordingLocation = playback.RecordingLocation{Directory: "/music/radio"}
+
+		newModel, _ := model.Update(switchToHistoryModelMsg{})
+		model = newModel.(Model)
+
+		assert.Equal(t, historyState, model.state)
+		assert.Equal(t, "/music/radio", model.historyModel.exportDir)
+	})
+
+}
+
+func TestModel_Alarms(t *testing.T) {
+
+	morning := time.Date(2026, 3, 2, 7, 30, 0, 0, time.Local)
+	station := common.Station{StationUuid: uuid.New(), Name: "Morning Radio"}
+	newAlarmModel := func(storage *mocks.MockStationStorageService, pm *mocks.MockPlaybackManagerService, now time.Time) Model {
+		model := NewModel(config.Config{}, &mocks.MockRadioBrowserService{}, pm, storage)
+		model.clock = &mocks.MockClock{NowResult: now}
+		return model
+	}
+	alarmsStorage := func(saved *[]schedule.Alarm) *mocks.MockStationStorageService {
+		return &mocks.MockStationStorageService{
+			GetAlarmsFunc: func() ([]schedule.Alarm, error) {
+				return []schedule.Alarm{{ID: 1, Station: station, Hour: 7, Minute: 30, Volume: 60, Enabled: true}}, nil
+			},
+			SaveAlarmFunc: func(alarm schedule.Alarm) (schedule.Alarm, error) {
+				*saved = append(*saved, alarm)
+				return alarm, nil
+			},
+		}
+	}
+
+	t.Run("starts checking alarms once booted", func(t *testing.T) {
+
+		model := newAlarmModel(&mocks.MockStationStorageService{}, &mocks.MockPlaybackManagerService{}, morning)
+
+		_, cmd := model.Update(tea.Msg(switchToSearchModelMsg{}))
+
+		assert.NotNil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(alarmTickMsg)
+			return ok
+		}))
+
+	})
+
+	t.Run("starts checking alarms when booted in a terminal too small", func(t *testing.T) {
+
+		model := newAlarmModel(&mocks.MockStationStorageService{}, &mocks.MockPlaybackManagerService{}, morning)
+
+		newModel, _ := model.Update(tea.WindowSizeMsg{Width: 40, Height: 10})
+		assert.Equal(t, terminalTooSmallState, newModel.(Model).state)
+		newModel, cmd := newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+
+		assert.NotNil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(alarmTickMsg)
+			return ok
+		}))
+
+		// Going back to the search screen doesn't start another tick chain
+		_, cmd = newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+		assert.Nil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(alarmTickMsg)
+			return ok
+		}))
+
+	})
+
+	t.Run("the first check only remembers when it happened", func(t *testing.T) {
+
+		checked := false
+		storage := &mocks.MockStationStorageService{
+			GetAlarmsFunc: func() ([]schedule.Alarm, error) {
+				checked = true
+				return nil, nil
+			},
+		}
+		model := newAlarmModel(storage, &mocks.MockPlaybackManagerService{}, morning)
+
+		newModel, cmd := model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.False(t, checked)
+		assert.Equal(t, morning, newModel.(Model).lastAlarmCheck)
+
+	})
+
+	t.Run("rings a due alarm, leaving the current view", func(t *testing.T) {
+
+		var saved []schedule.Alarm
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		model := newAlarmModel(alarmsStorage(&saved), pm, morning.Add(2*time.Second))
+		model.state = searchState
+		model.lastAlarmCheck = morning.Add(-3 * time.Second)
+
+		newModel, cmd := model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.True(t, stopped)
+		assert.Equal(t, morning.Add(2*time.Second), newModel.(Model).lastAlarmCheck)
+		// A one-off alarm turns itself off
+		assert.Len(t, saved, 1)
+		assert.False(t, saved[0].Enabled)
+
+	})
+
+	t.Run("rings in the stations view without leaving it", func(t *testing.T) {
+
+		var saved []schedule.Alarm
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		model := newAlarmModel(alarmsStorage(&saved), pm, morning.Add(2*time.Second))
+		model.state = stationsState
+		model.lastAlarmCheck = morning.Add(-3 * time.Second)
+
+		_, cmd := model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.False(t, stopped)
+		assert.Len(t, saved, 1)
+
+	})
+
+	t.Run("waits for the alarm form to close before ringing", func(t *testing.T) {
+
+		var saved []schedule.Alarm
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		clock := &mocks.MockClock{NowResult: morning.Add(2 * time.Second)}
+		model := newAlarmModel(alarmsStorage(&saved), pm, morning)
+		model.clock = clock
+		model.state = alarmsState
+		model.alarmsModel.showForm = true
+		model.lastAlarmCheck = morning.Add(-3 * time.Second)
+
+		newModel, _ := model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.False(t, stopped)
+		assert.Equal(t, alarmsState, newModel.(Model).state)
+		assert.True(t, newModel.(Model).alarmsModel.showForm)
+		assert.Equal(t, int64(1), newModel.(Model).pendingAlarm.ID)
+		assert.Len(t, saved, 1)
+
+		// Still filling in the form
+		clock.NowResult = morning.Add(7 * time.Second)
+		newModel, _ = newModel.Update(tea.Msg(alarmTickMsg{}))
+		assert.False(t, stopped)
+
+		model = newModel.(Model)
+		model.alarmsModel.showForm = false
+		clock.NowResult = morning.Add(12 * time.Second)
+		newModel, _ = model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.True(t, stopped)
+		assert.Zero(t, newModel.(Model).pendingAlarm.ID)
+		assert.Len(t, saved, 1)
+
+	})
+
+	t.Run("waits for the terminal to grow before ringing", func(t *testing.T) {
+
+		var saved []schedule.Alarm
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		clock := &mocks.MockClock{NowResult: morning.Add(2 * time.Second)}
+		model := newAlarmModel(alarmsStorage(&saved), pm, morning)
+		model.clock = clock
+		model.state = searchState
+		model.lastAlarmCheck = morning.Add(-3 * time.Second)
+		newModel, _ := model.Update(tea.WindowSizeMsg{Width: 40, Height: 10})
+
+		newModel, _ = newModel.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.False(t, stopped)
+		assert.Equal(t, terminalTooSmallState, newModel.(Model).state)
+
+		newModel, _ = newModel.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+		clock.NowResult = morning.Add(7 * time.Second)
+		newModel, _ = newModel.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.True(t, stopped)
+		assert.Zero(t, newModel.(Model).pendingAlarm.ID)
+
+	})
+
+	t.Run("skips alarms missed long ago", func(t *testing.T) {
+
+		var saved []schedule.Alarm
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		model := newAlarmModel(alarmsStorage(&saved), pm, morning.Add(3*time.Hour))
+		model.state = searchState
+		model.lastAlarmCheck = morning.Add(-time.Hour)
+
+		_, _ = model.Update(tea.Msg(alarmTickMsg{}))
+
+		assert.False(t, stopped)
+		assert.Empty(t, saved)
+
+	})
+
+	t.Run("opens the bookmarks with the alarm's station", func(t *testing.T) {
+
+		bookmarked := common.Station{StationUuid: uuid.New(), Name: "Jazz FM"}
+		storage := &mocks.MockStationStorageService{
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) {
+				return []common.Station{bookmarked}, nil
+			},
+		}
+
+		msg := openBookmarksForAlarmCmd(storage, bookmarked)()
+		assert.Equal(t, switchToBookmarksMsg{stations: []common.Station{bookmarked}}, msg)
+
+		// No longer bookmarked
+		msg = openBookmarksForAlarmCmd(storage, station)()
+		assert.Equal(t, switchToBookmarksMsg{stations: []common.Station{station, bookmarked}}, msg)
+
+	})
+
+	t.Run("plays a fired alarm in the stations view", func(t *testing.T) {
+
+		model := newAlarmModel(&mocks.MockStationStorageService{}, &mocks.MockPlaybackManagerService{VolumeMaxResult: 100}, morning)
+		newModel, _ := model.Update(tea.Msg(switchToBookmarksMsg{stations: []common.Station{station}}))
+
+		newModel, cmd := newModel.Update(tea.Msg(alarmFiredMsg{alarm: schedule.Alarm{Station: station, Hour: 7, Minute: 30, Volume: 60}, at: morning}))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, 60, newModel.(Model).stationsModel.volume)
+
+	})
+
+	t.Run("switches to the alarms view", func(t *testing.T) {
+
+		model := newAlarmModel(&mocks.MockStationStorageService{}, &mocks.MockPlaybackManagerService{}, morning)
+
+		newModel, cmd := model.Update(tea.Msg(switchToAlarmsModelMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, alarmsState, newModel.(Model).state)
+
+	})
+
+	t.Run("stops the playing station when switching to the alarms view", func(t *testing.T) {
+
+		stopped := false
+		pm := &mocks.MockPlaybackManagerService{IsPlayingResult: true, StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		model := newAlarmModel(&mocks.MockStationStorageService{}, pm, morning)
+		model.state = stationsState
+
+		newModel, _ := model.Update(tea.Msg(switchToAlarmsModelMsg{}))
+
+		assert.True(t, stopped)
+		assert.Equal(t, alarmsState, newModel.(Model).state)
+
+	})
+
+}
+
+func TestModel_ScheduledRecordings(t *testing.T) {
+
+	evening := time.Date(2026, 3, 2, 20, 0, 0, 0, time.Local)
+	recording := schedule.Recording{ID: 1, Station: common.Station{StationUuid: uuid.New(), Name: "Jazz FM", Codec: "MP3"}, Start: evening, Duration: time.Hour, Enabled: true}
+	newRecordingModel := func(storage *mocks.MockStationStorageService, recorder *mocks.MockStreamRecorderService, now time.Time) Model {
+		model := NewModel(config.Config{}, &mocks.MockRadioBrowserService{}, &mocks.MockPlaybackManagerService{}, storage)
+		model.clock = &mocks.MockClock{NowResult: now}
+		model.streamRecorder = recorder
+		model.recordingLocation = playback.RecordingLocation{Directory: t.TempDir()}
+		return model
+	}
+	// recordingsStorage keeps the runs saved by the scheduler
+	recordingsStorage := func(recording schedule.Recording, runs *[]schedule.RecordingRun) *mocks.MockStationStorageService {
+		return &mocks.MockStationStorageService{
+			GetScheduledRecordingsFunc: func() ([]schedule.Recording, error) {
+				return []schedule.Recording{recording}, nil
+			},
+			GetRecordingRunsFunc: func(recordingID int64) ([]schedule.RecordingRun, error) {
+				result := []schedule.RecordingRun{}
+				for i := len(*runs) - 1; i >= 0; i-- {
+					result = append(result, (*runs)[i])
+				}
+				return result, nil
+			},
+			SaveRecordingRunFunc: func(run schedule.RecordingRun) (schedule.RecordingRun, error) {
+				if run.ID == 0 {
+					run.ID = int64(len(*runs) + 1)
+					*runs = append(*runs, run)
+				} else {
+					(*runs)[run.ID-1] = run
+				}
+				return run, nil
+			},
+		}
+	}
+	// startCmd returns the command starting a recording, among those of a tick
+	startCmd := func(cmd tea.Cmd) tea.Cmd {
+		batch := cmd().(tea.BatchMsg)
+		// The first one schedules the next tick
+		assert.Len(t, batch, 2)
+		return batch[1]
+	}
+
+	t.Run("starts checking recordings once booted", func(t *testing.T) {
+
+		model := newRecordingModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}, evening)
+
+		_, cmd := model.Update(tea.Msg(switchToSearchModelMsg{}))
+
+		assert.NotNil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(recordingTickMsg)
+			return ok
+		}))
+
+	})
+
+	t.Run("starts checking recordings when booted in a terminal too small", func(t *testing.T) {
+
+		model := newRecordingModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}, evening)
+
+		newModel, _ := model.Update(tea.WindowSizeMsg{Width: 40, Height: 10})
+		assert.Equal(t, terminalTooSmallState, newModel.(Model).state)
+		newModel, cmd := newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+
+		assert.NotNil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(recordingTickMsg)
+			return ok
+		}))
+		assert.True(t, newModel.(Model).tickersStarted)
+
+		// Going back to the search screen doesn't start another tick chain
+		_, cmd = newModel.Update(tea.Msg(switchToSearchModelMsg{}))
+		assert.Nil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(recordingTickMsg)
+			return ok
+		}))
+
+	})
+
+	t.Run("starts a due recording for the rest of its time, once", func(t *testing.T) {
+
+		var runs []schedule.RecordingRun
+		var started time.Duration
+		recorder := &mocks.MockStreamRecorderService{
+			StartFunc: func(id int64, station common.Station, outputPath string, duration time.Duration) error {
+				started = duration
+				return nil
+			},
+		}
+		now := evening.Add(10 * time.Minute)
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, now)
+
+		newModel, cmd := model.Update(tea.Msg(recordingTickMsg{}))
+
+		assert.Len(t, runs, 1)
+		assert.Equal(t, int64(1), runs[0].RecordingID)
+		assert.Equal(t, evening, runs[0].Scheduled)
+		assert.Equal(t, now, runs[0].Start)
+		assert.Contains(t, runs[0].Path, "jazz_fm")
+		msg := startCmd(cmd)()
+		assert.Equal(t, scheduledRecordingStartedMsg{run: runs[0]}, msg)
+		assert.Equal(t, 50*time.Minute, started)
+
+		// The occurrence already has its run
+		_, _ = newModel.Update(tea.Msg(recordingTickMsg{}))
+		assert.Len(t, runs, 1)
+
+	})
+
+	t.Run("records into the configured location", func(t *testing.T) {
+
+		var runs []schedule.RecordingRun
+		var outputPath string
+		recorder := &mocks.MockStreamRecorderService{
+			StartFunc: func(id int64, station common.Station, path string, duration time.Duration) error {
+				outputPath = path
+				return nil
+			},
+		}
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, evening)
+		dir := t.TempDir()
+		model.recordingLocation = playback.RecordingLocation{Directory: dir, FilenameTemplate: "{station}/{date}_{hour}{minute}"}
+
+		_, cmd := model.Update(tea.Msg(recordingTickMsg{}))
+		_ = startCmd(cmd)()
+
+		assert.Len(t, runs, 1)
+		assert.Equal(t, filepath.Join(dir, "jazz_fm", "2026-03-02_2000.mp3"), runs[0].Path)
+		assert.Equal(t, runs[0].Path, outputPath)
+
+	})
+
+	t.Run("fails the run when the filename template is invalid", func(t *testing.T) {
+
+		var runs []schedule.RecordingRun
+		started := false
+		recorder := &mocks.MockStreamRecorderService{
+			StartFunc: func(id int64, station common.Station, path string, duration time.Duration) error {
+				started = true
+				return nil
+			},
+		}
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, evening)
+		model.recordingLocation.FilenameTemplate = "{station}-{genre}"
+
+		_, _ = model.Update(tea.Msg(recordingTickMsg{}))
+
+		assert.Len(t, runs, 1)
+		assert.Equal(t, evening, runs[0].End)
+		assert.NotEmpty(t, runs[0].Err)
+		assert.Empty(t, runs[0].Path)
+		assert.False(t, started)
+
+	})
+
+	t.Run("doesn't start recordings that are off or not due", func(t *testing.T) {
+
+		var runs []schedule.RecordingRun
+		off := recording
+		off.Enabled = false
+
+		model := newRecordingModel(recordingsStorage(off, &runs), &mocks.MockStreamRecorderService{}, evening.Add(time.Minute))
+		_, _ = model.Update(tea.Msg(recordingTickMsg{}))
+		model = newRecordingModel(recordingsStorage(recording, &runs), &mocks.MockStreamRecorderService{}, evening.Add(-time.Minute))
+		_, _ = model.Update(tea.Msg(recordingTickMsg{}))
+
+		assert.Empty(t, runs)
+
+	})
+
+	t.Run("stops a recording running well past its end", func(t *testing.T) {
+
+		runs := []schedule.RecordingRun{{ID: 1, RecordingID: 1, Scheduled: evening, Start: evening, Path: "/tmp/jazz.mp3"}}
+		var stopped int64
+		recorder := &mocks.MockStreamRecorderService{
+			IsRecordingFunc: func(id int64) bool { return true },
+			StopFunc: func(id int64) (string, error) {
+				stopped = id
+				return "/tmp/jazz.mp3", nil
+			},
+		}
+
+		// ffmpeg is given a moment to stop by itself
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, evening.Add(time.Hour+10*time.Second))
+		_, _ = model.Update(tea.Msg(recordingTickMsg{}))
+		assert.Equal(t, int64(0), stopped)
+
+		end := evening.Add(time.Hour + 2*time.Minute)
+		model = newRecordingModel(recordingsStorage(recording, &runs), recorder, end)
+		_, _ = model.Update(tea.Msg(recordingTickMsg{}))
+		assert.Equal(t, int64(1), stopped)
+		assert.Equal(t, end, runs[0].End)
+		assert.Empty(t, runs[0].Err)
+
+	})
+
+	t.Run("ends the run of a recording that failed to start", func(t *testing.T) {
+
+		runs := []schedule.RecordingRun{{ID: 1, RecordingID: 1, Scheduled: evening, Start: evening, Path: "/tmp/jazz.mp3"}}
+		model := newRecordingModel(recordingsStorage(recording, &runs), &mocks.MockStreamRecorderService{}, evening)
+
+		newModel, _ := model.Update(tea.Msg(scheduledRecordingStartedMsg{run: runs[0], err: errors.New("ffmpeg not found")}))
+
+		assert.Equal(t, evening, runs[0].End)
+		assert.Equal(t, "ffmpeg not found", runs[0].Err)
+		assert.False(t, newModel.(Model).listeningForRecordingExits)
+
+	})
+
+	t.Run("listens for recordings ending by themselves once one started", func(t *testing.T) {
+
+		runs := []schedule.RecordingRun{{ID: 1, RecordingID: 1, Scheduled: evening, Start: evening, Path: "/tmp/jazz.mp3"}}
+		exits := make(chan playback.RecordingExit, 1)
+		recorder := &mocks.MockStreamRecorderService{ExitsResult: exits}
+		end := evening.Add(time.Hour)
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, end)
+
+		newModel, cmd := model.Update(tea.Msg(scheduledRecordingStartedMsg{run: runs[0]}))
+		assert.True(t, newModel.(Model).listeningForRecordingExits)
+
+		exits <- playback.RecordingExit{ID: 1, Path: "/tmp/jazz.mp3", Err: errors.New("exit status 1")}
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(scheduledRecordingExitMsg)
+			return ok
+		})
+		assert.NotNil(t, msg)
+
+		// Already listening
+		_, cmd = newModel.Update(tea.Msg(scheduledRecordingStartedMsg{run: runs[0]}))
+		assert.Nil(t, cmd)
+
+		_, cmd = newModel.Update(msg)
+		assert.NotNil(t, cmd)
+		assert.Equal(t, end, runs[0].End)
+		assert.Equal(t, "exit status 1", runs[0].Err)
+
+	})
+
+	t.Run("stops the recordings under way when quitting", func(t *testing.T) {
+
+		runs := []schedule.RecordingRun{{ID: 1, RecordingID: 1, Scheduled: evening, Start: evening, Path: "/tmp/jazz.mp3"}}
+		var stopped []int64
+		stoppedAll := false
+		recorder := &mocks.MockStreamRecorderService{
+			IsRecordingFunc: func(id int64) bool { return true },
+			StopFunc: func(id int64) (string, error) {
+				stopped = append(stopped, id)
+				return "/tmp/jazz.mp3", nil
+			},
+			StopAllFunc: func() { stoppedAll = true },
+		}
+		now := evening.Add(30 * time.Minute)
+		model := newRecordingModel(recordingsStorage(recording, &runs), recorder, now)
+
+		_, cmd := model.Update(tea.Msg(quitMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, []int64{1}, stopped)
+		assert.True(t, stoppedAll)
+		assert.Equal(t, now, runs[0].End)
+
+	})
+
+	t.Run("switches to the recordings view", func(t *testing.T) {
+
+		model := newRecordingModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}, evening)
+
+		newModel, cmd := model.Update(tea.Msg(switchToRecordingsModelMsg{}))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, recordingsState, newModel.(Model).state)
+
+	})
+
+	t.Run("stops the playing station when switching to the recordings view", func(t *testing.T) {
+
+		stopped := false
+		model := newRecordingModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}, evening)
+		model.playbackManager = &mocks.MockPlaybackManagerService{IsPlayingResult: true, StopStationFunc: func() error {
+			stopped = true
+			return nil
+		}}
+		model.state = stationsState
+
+		newModel, _ := model.Update(tea.Msg(switchToRecordingsModelMsg{}))
+
+		assert.True(t, stopped)
+		assert.Equal(t, recordingsState, newModel.(Model).state)
+
+	})
+
 }
@@ -0,0 +1,295 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultRecordingDuration is the duration offered for new recordings.
+const defaultRecordingDuration = time.Hour
+
+// recordingDateLayout is how the date of a recording is typed.
+const recordingDateLayout = "2006-01-02"
+
+// recordingField identifies a field of the recording form.
+type recordingField int
+
+const (
+	recordingFieldStation recordingField = iota
+	recordingFieldDate
+	recordingFieldTime
+	recordingFieldDuration
+	recordingFieldDays
+	recordingFieldCount
+)
+
+// label returns the localized label for the field.
+func (f recordingField) label() string {
+	switch f {
+	case recordingFieldStation:
+		return i18n.T("header_station")
+	case recordingFieldDate:
+		return i18n.T("header_date")
+	case recordingFieldTime:
+		return i18n.T("header_time")
+	case recordingFieldDuration:
+		return i18n.T("header_duration")
+	case recordingFieldDays:
+		return i18n.T("header_repeat")
+	}
+	return ""
+}
+
+// RecordingForm adds or edits a scheduled recording of one of the bookmarked stations.
+type RecordingForm struct {
+	theme Theme
+
+	stations []common.Station
+	station  int
+	days     weekdayPicker
+	// inputs holds the text inputs, by field (unused for the station and days)
+	inputs []textinput.Model
+	focus  recordingField
+	err    string
+
+	// now is when the form was opened, to tell recordings already over
+	now time.Time
+	// recordingID is the recording being edited, or 0 when adding one
+	recordingID int64
+	// enabled is whether the recording being edited is on
+	enabled bool
+	// overlapConfirmed is true once the user was told the recording overlaps
+	// another one, so that saving again saves it anyway
+	overlapConfirmed bool
+}
+
+// NewRecordingForm returns a form to schedule a recording of one of stations, starting today.
+func NewRecordingForm(theme Theme, stations []common.Station, now time.Time) RecordingForm {
+	inputs := make([]textinput.Model, recordingFieldCount)
+	for i := range inputs {
+		input := textinput.New()
+		input.Width = 10
+		input.Prompt = ""
+		input.TextStyle = theme.Text
+		input.PlaceholderStyle = theme.TertiaryText
+		inputs[i] = input
+	}
+	inputs[recordingFieldDate].Placeholder = recordingDateLayout
+	inputs[recordingFieldDate].CharLimit = 10
+	inputs[recordingFieldTime].Placeholder = "20:00"
+	inputs[recordingFieldTime].CharLimit = 5
+	inputs[recordingFieldDuration].CharLimit = 4
+
+	form := RecordingForm{
+		theme:    theme,
+		stations: stations,
+		inputs:   inputs,
+		now:      now,
+		enabled:  true,
+	}
+	form.inputs[recordingFieldDate].SetValue(now.Format(recordingDateLayout))
+	form.inputs[recordingFieldDuration].SetValue(strconv.Itoa(int(defaultRecordingDuration / time.Minute)))
+	form.setFocus(recordingFieldStation)
+	return form
+}
+
+// NewRecordingFormFor returns a form pre-filled to edit the given recording. Its station is
+// offered even if it's no longer among stations.
+func NewRecordingFormFor(theme Theme, stations []common.Station, recording schedule.Recording, now time.Time) RecordingForm {
+	station := -1
+	for i, s := range stations {
+		if s.StationUuid == recording.Station.StationUuid {
+			station = i
+			break
+		}
+	}
+	if station < 0 {
+		stations = append([]common.Station{recording.Station}, stations...)
+		station = 0
+	}
+
+	form := NewRecordingForm(theme, stations, now)
+	form.recordingID = recording.ID
+	form.enabled = recording.Enabled
+	form.station = station
+	form.days.days = recording.Days
+	form.inputs[recordingFieldDate].SetValue(recording.Start.Format(recordingDateLayout))
+	form.inputs[recordingFieldTime].SetValue(recording.Start.Format("15:04"))
+	form.inputs[recordingFieldDuration].SetValue(strconv.Itoa(int(recording.Duration / time.Minute)))
+	return form
+}
+
+// IsEditing returns true if the form edits an existing recording rather than adding one.
+func (m RecordingForm) IsEditing() bool {
+	return m.recordingID != 0
+}
+
+// setFocus moves focus to the given field.
+func (m *RecordingForm) setFocus(field recordingField) {
+	m.focus = field
+	for i := range m.inputs {
+		if recordingField(i) == field {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+// Recording builds the recording described by the form. A new recording is on, an
+// edited one stays on or off. Returns an error if the date, time or duration aren't
+// valid, or if a one-off recording would already be over.
+func (m RecordingForm) Recording() (schedule.Recording, error) {
+	value := func(field recordingField) string {
+		return strings.TrimSpace(m.inputs[field].Value())
+	}
+
+	rawDate := value(recordingFieldDate)
+	date, err := time.ParseInLocation(recordingDateLayout, rawDate, m.now.Location())
+	if err != nil {
+		return schedule.Recording{}, fmt.Errorf("%s", i18n.Tf("error_recording_date_invalid", map[string]interface{}{"Value": rawDate}))
+	}
+
+	rawTime := value(recordingFieldTime)
+	hour, minute, err := schedule.ParseTimeOfDay(rawTime)
+	if err != nil {
+		return schedule.Recording{}, fmt.Errorf("%s", i18n.Tf("error_alarm_time_invalid", map[string]interface{}{"Value": rawTime}))
+	}
+
+	minutes, err := strconv.Atoi(value(recordingFieldDuration))
+	if err != nil || minutes <= 0 || time.Duration(minutes)*time.Minute > schedule.MaxRecordingDuration {
+		return schedule.Recording{}, fmt.Errorf("%s", i18n.Tf("error_recording_duration_invalid", map[string]interface{}{"Max": int(schedule.MaxRecordingDuration / time.Minute)}))
+	}
+
+	recording := schedule.Recording{
+		ID:       m.recordingID,
+		Station:  m.stations[m.station],
+		Start:    time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, date.Location()),
+		Duration: time.Duration(minutes) * time.Minute,
+		Days:     m.days.days,
+		Enabled:  m.enabled,
+	}
+	if recording.Once() && !recording.Start.Add(recording.Duration).After(m.now) {
+		return schedule.Recording{}, fmt.Errorf("%s", i18n.T("error_recording_over"))
+	}
+	return recording, nil
+}
+
+// SetError sets the validation error shown below the form.
+func (m *RecordingForm) SetError(err string) {
+	m.err = err
+}
+
+// Bubbletea
+
+func (m RecordingForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m RecordingForm) Update(msg tea.Msg) (RecordingForm, tea.Cmd) {
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			m.setFocus((m.focus + 1) % recordingFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focus + recordingFieldCount - 1) % recordingFieldCount)
+			return m, nil
+		}
+		// A changed recording is checked for overlaps again
+		m.err = ""
+		m.overlapConfirmed = false
+
+		switch m.focus {
+		case recordingFieldStation:
+			switch msg.String() {
+			case "left":
+				m.station = (m.station + len(m.stations) - 1) % len(m.stations)
+			case "right":
+				m.station = (m.station + 1) % len(m.stations)
+			}
+			return m, nil
+		case recordingFieldDays:
+			m.days = m.days.update(msg.String())
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m RecordingForm) View() string {
+
+	title := i18n.T("recording_add_title")
+	if m.IsEditing() {
+		title = i18n.T("recording_edit_title")
+	}
+	v := m.theme.SecondaryText.Bold(true).Render(title) + "\n\n"
+
+	labels := make([]string, recordingFieldCount)
+	labelWidth := 0
+	for f := recordingField(0); f < recordingFieldCount; f++ {
+		labels[f] = f.label()
+		if w := len([]rune(labels[f])); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	for f := recordingField(0); f < recordingFieldCount; f++ {
+		cursor := "  "
+		if f == m.focus {
+			cursor = "> "
+		}
+		label := labels[f] + strings.Repeat(" ", labelWidth-len([]rune(labels[f])))
+		var field string
+		switch f {
+		case recordingFieldStation:
+			field = m.theme.Text.Render("◀ " + m.stations[m.station].Name + " ▶")
+		case recordingFieldDays:
+			field = m.days.view(m.theme, m.focus == recordingFieldDays)
+		case recordingFieldDuration:
+			field = m.inputs[f].View() + " " + m.theme.TertiaryText.Render(i18n.T("alarm_minutes"))
+		default:
+			field = m.inputs[f].View()
+		}
+		v += m.theme.Text.Render(cursor+label+"  ") + field + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+
+	v += "\n" + m.theme.TertiaryText.Render(i18n.T("alarm_form_help"))
+
+	return v
+}
@@ -0,0 +1,555 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+	"github.com/zi0p4tch0/radiogogo/storage"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// recordingsChromeHeight is the number of lines the recordings view uses around the table
+// (title, counter, runs of the selected recording, status line and spacing).
+const recordingsChromeHeight = 12
+
+// recordingRunsShown is how many runs of the selected recording are listed.
+const recordingRunsShown = 3
+
+// recordingCheckInterval is how often the scheduled recordings are checked for one to start.
+const recordingCheckInterval = 5 * time.Second
+
+// recordingOverrun is how long a recording may run past its end before it's stopped.
+// ffmpeg stops by itself, unless the stream stalled.
+const recordingOverrun = time.Minute
+
+// recordingTickMsg checks whether a scheduled recording has to start or stop.
+type recordingTickMsg struct{}
+
+// scheduleRecordingCheckCmd sends the next recordingTickMsg after interval.
+func scheduleRecordingCheckCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return recordingTickMsg{}
+	})
+}
+
+// RecordingsModel lists the scheduled recordings, upcoming, under way and finished, with
+// the files recorded by the selected one. It adds, edits, deletes and turns them on and off.
+type RecordingsModel struct {
+	theme       Theme
+	storage     storage.StationStorageService
+	recorder    playback.StreamRecorderService
+	keybindings config.Keybindings
+	clock       schedule.Clock
+
+	recordingsTable table.Model
+	recordings      []schedule.Recording
+	// runs are the runs of each recording, newest first
+	runs map[int64][]schedule.RecordingRun
+	// stations are the bookmarked stations that can be recorded
+	stations []common.Station
+
+	showForm bool
+	form     RecordingForm
+	// deleteCandidate is the recording waiting for delete confirmation (0 if none)
+	deleteCandidate int64
+
+	loading    bool
+	err        string
+	successMsg string
+
+	width  int
+	height int
+}
+
+func NewRecordingsModel(
+	theme Theme,
+	storage storage.StationStorageService,
+	recorder playback.StreamRecorderService,
+	clock schedule.Clock,
+	keybindings config.Keybindings,
+) RecordingsModel {
+	t := table.New(
+		table.WithColumns([]table.Column{
+			{Title: i18n.T("header_status"), Width: 14},
+			{Title: i18n.T("header_start"), Width: 20},
+			{Title: i18n.T("header_duration"), Width: 8},
+			{Title: i18n.T("header_repeat"), Width: 20},
+			{Title: i18n.T("header_station"), Width: 30},
+			{Title: "", Width: 1},
+		}),
+		table.WithFocused(true),
+	)
+	t.SetStyles(theme.StationsTableStyle)
+
+	return RecordingsModel{
+		theme:           theme,
+		storage:         storage,
+		recorder:        recorder,
+		keybindings:     keybindings,
+		clock:           clock,
+		recordingsTable: t,
+		runs:            map[int64][]schedule.RecordingRun{},
+		loading:         true,
+	}
+}
+
+// Messages
+
+type recordingsLoadedMsg struct {
+	recordings []schedule.Recording
+	runs       map[int64][]schedule.RecordingRun
+	stations   []common.Station
+	err        error
+}
+
+type recordingSavedMsg struct {
+	recording schedule.Recording
+	err       error
+}
+
+type recordingDeletedMsg struct {
+	err error
+}
+
+// scheduledRecordingStartedMsg tells that ffmpeg was started for a run, or why it couldn't be.
+type scheduledRecordingStartedMsg struct {
+	run schedule.RecordingRun
+	err error
+}
+
+// scheduledRecordingExitMsg tells that a scheduled recording ended by itself.
+type scheduledRecordingExitMsg struct {
+	exit playback.RecordingExit
+}
+
+// Commands
+
+// loadRecordingsCmd loads the scheduled recordings with their runs, and the bookmarked
+// stations that can be recorded.
+func loadRecordingsCmd(storage storage.StationStorageService) tea.Cmd {
+	return func() tea.Msg {
+		recordings, err := storage.GetScheduledRecordings()
+		if err != nil {
+			return recordingsLoadedMsg{err: err}
+		}
+		runs := make(map[int64][]schedule.RecordingRun, len(recordings))
+		for _, recording := range recordings {
+			if runs[recording.ID], err = storage.GetRecordingRuns(recording.ID); err != nil {
+				return recordingsLoadedMsg{err: err}
+			}
+		}
+		stations, err := savedBookmarkedStations(storage)
+		return recordingsLoadedMsg{recordings: recordings, runs: runs, stations: stations, err: err}
+	}
+}
+
+// saveRecordingCmd saves a recording, stopping it first if it's being turned off.
+func saveRecordingCmd(storage storage.StationStorageService, recorder playback.StreamRecorderService, recording schedule.Recording, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if !recording.Enabled {
+			stopScheduledRecording(storage, recorder, recording.ID, now)
+		}
+		saved, err := storage.SaveScheduledRecording(recording)
+		return recordingSavedMsg{recording: saved, err: err}
+	}
+}
+
+// deleteRecordingCmd deletes a recording, stopping it first if it's under way.
+func deleteRecordingCmd(storage storage.StationStorageService, recorder playback.StreamRecorderService, id int64, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		stopScheduledRecording(storage, recorder, id, now)
+		return recordingDeletedMsg{err: storage.DeleteScheduledRecording(id)}
+	}
+}
+
+// startScheduledRecordingCmd starts recording run, which must end after duration.
+func startScheduledRecordingCmd(recorder playback.StreamRecorderService, station common.Station, run schedule.RecordingRun, duration time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		err := recorder.Start(run.RecordingID, station, run.Path, duration)
+		if err != nil {
+			playback.DiscardRecordingPath(run.Path)
+		}
+		return scheduledRecordingStartedMsg{run: run, err: err}
+	}
+}
+
+// waitForRecordingExitCmd waits for the next scheduled recording that ends by itself.
+func waitForRecordingExitCmd(recorder playback.StreamRecorderService) tea.Cmd {
+	return func() tea.Msg {
+		return scheduledRecordingExitMsg{exit: <-recorder.Exits()}
+	}
+}
+
+// stopScheduledRecording stops a recording if it's under way, and ends its current run.
+func stopScheduledRecording(storage storage.StationStorageService, recorder playback.StreamRecorderService, id int64, now time.Time) {
+	if recorder == nil || !recorder.IsRecording(id) {
+		return
+	}
+	errText := ""
+	if _, err := recorder.Stop(id); err != nil {
+		errText = err.Error()
+	}
+	endRecordingRun(storage, id, now, errText)
+}
+
+// endRecordingRun records that the current run of a recording ended at end, failing
+// with errText unless it's empty.
+func endRecordingRun(storage storage.StationStorageService, recordingID int64, end time.Time, errText string) {
+	runs, err := storage.GetRecordingRuns(recordingID)
+	if err != nil || len(runs) == 0 || !runs[0].End.IsZero() {
+		return
+	}
+	run := runs[0]
+	run.End = end
+	run.Err = errText
+	_, _ = storage.SaveRecordingRun(run)
+}
+
+func updateRecordingsCommandsCmd(kb config.Keybindings) tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": "esc"}),
+				i18n.T("cmd_move"),
+				i18n.T("cmd_recording_toggle"),
+				i18n.Tf("cmd_recording_add", map[string]interface{}{"Key": kb.AddStation}),
+				i18n.Tf("cmd_recording_edit", map[string]interface{}{"Key": kb.EditStation}),
+				i18n.Tf("cmd_recording_delete", map[string]interface{}{"Key": kb.DeleteStation}),
+				i18n.T("current_language"),
+			},
+		}
+	}
+}
+
+// Bubbletea
+
+func (m RecordingsModel) Init() tea.Cmd {
+	return tea.Batch(
+		updateRecordingsCommandsCmd(m.keybindings),
+		loadRecordingsCmd(m.storage),
+	)
+}
+
+func (m RecordingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case recordingsLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = i18n.Tf("error_load_recordings", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.stations = msg.stations
+		m.runs = msg.runs
+		m.setRecordings(msg.recordings)
+		return m, nil
+
+	case recordingSavedMsg:
+		if msg.err != nil {
+			m.successMsg = ""
+			m.err = i18n.Tf("error_save_recording", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		m.successMsg = ""
+		if next, ok := msg.recording.NextStart(m.clock.Now()); ok && msg.recording.Enabled {
+			m.successMsg = i18n.Tf("recording_saved", map[string]interface{}{
+				"Station": msg.recording.Station.Name,
+				"Next":    m.formatStart(next),
+			})
+		}
+		return m, loadRecordingsCmd(m.storage)
+
+	case recordingDeletedMsg:
+		if msg.err != nil {
+			m.err = i18n.Tf("error_delete_recording", map[string]interface{}{"Error": msg.err.Error()})
+			return m, nil
+		}
+		m.err = ""
+		return m, loadRecordingsCmd(m.storage)
+
+	case tea.KeyMsg:
+		if m.showForm {
+			return m.updateForm(msg)
+		}
+
+		key := msg.String()
+		// Any key other than delete cancels a pending delete
+		if m.deleteCandidate != 0 && key != m.keybindings.DeleteStation {
+			m.deleteCandidate = 0
+		}
+
+		switch key {
+		case "esc":
+			return m, func() tea.Msg {
+				return switchToSearchModelMsg{}
+			}
+		case "up", "down", "pgup", "pgdown", m.keybindings.NavigateUp, m.keybindings.NavigateDown:
+			newTable, cmd := m.recordingsTable.Update(msg)
+			m.recordingsTable = newTable
+			return m, cmd
+		case "enter":
+			recording, ok := m.selectedRecording()
+			if !ok {
+				return m, nil
+			}
+			recording.Enabled = !recording.Enabled
+			return m, saveRecordingCmd(m.storage, m.recorder, recording, m.clock.Now())
+		case m.keybindings.AddStation:
+			if len(m.stations) == 0 {
+				m.err = i18n.T("recording_no_bookmarks")
+				return m, nil
+			}
+			m.err = ""
+			m.form = NewRecordingForm(m.theme, m.stations, m.clock.Now())
+			m.showForm = true
+			return m, m.form.Init()
+		case m.keybindings.EditStation:
+			recording, ok := m.selectedRecording()
+			if !ok {
+				return m, nil
+			}
+			m.form = NewRecordingFormFor(m.theme, m.stations, recording, m.clock.Now())
+			m.showForm = true
+			return m, m.form.Init()
+		case m.keybindings.DeleteStation:
+			recording, ok := m.selectedRecording()
+			if !ok {
+				return m, nil
+			}
+			if m.deleteCandidate != recording.ID {
+				m.deleteCandidate = recording.ID
+				return m, nil
+			}
+			m.deleteCandidate = 0
+			return m, deleteRecordingCmd(m.storage, m.recorder, recording.ID, m.clock.Now())
+		}
+	}
+
+	if m.showForm {
+		var cmd tea.Cmd
+		m.form, cmd = m.form.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// updateForm handles key presses while the recording form is open. A recording
+// overlapping another one is only saved once the user confirms it.
+func (m RecordingsModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.showForm = false
+		return m, nil
+	case "enter":
+		recording, err := m.form.Recording()
+		if err != nil {
+			m.form.SetError(err.Error())
+			return m, nil
+		}
+		now := m.clock.Now()
+		if conflicts := schedule.Conflicts(m.recordings, recording, now); recording.Enabled && len(conflicts) > 0 && !m.form.overlapConfirmed {
+			overlap, _ := schedule.Overlap(recording, conflicts[0], now)
+			m.form.SetError(i18n.Tf("recording_overlap_confirm", map[string]interface{}{
+				"Station": conflicts[0].Station.Name,
+				"Start":   m.formatStart(overlap),
+			}))
+			m.form.overlapConfirmed = true
+			return m, nil
+		}
+		m.showForm = false
+		return m, saveRecordingCmd(m.storage, m.recorder, recording, now)
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+	return m, cmd
+}
+
+// selectedRecording returns the recording under the cursor, if there's any.
+func (m RecordingsModel) selectedRecording() (schedule.Recording, bool) {
+	if len(m.recordings) == 0 {
+		return schedule.Recording{}, false
+	}
+	return m.recordings[m.recordingsTable.Cursor()], true
+}
+
+// recordingStatus describes whether a recording is under way, starts again, is over or is off.
+func (m RecordingsModel) recordingStatus(recording schedule.Recording, now time.Time) string {
+	switch {
+	case m.recorder != nil && m.recorder.IsRecording(recording.ID):
+		return "● " + i18n.T("recording_status_recording")
+	case !recording.Enabled:
+		return "○ " + i18n.T("recording_status_off")
+	}
+	if _, ok := recording.NextStart(now); ok {
+		return "◷ " + i18n.T("recording_status_upcoming")
+	}
+	if runs := m.runs[recording.ID]; len(runs) > 0 && runs[0].Err != "" {
+		return "✗ " + i18n.T("recording_status_failed")
+	}
+	return "✓ " + i18n.T("recording_status_finished")
+}
+
+// setRecordings replaces the recordings shown in the table, keeping the cursor where it was.
+func (m *RecordingsModel) setRecordings(recordings []schedule.Recording) {
+	now := m.clock.Now()
+	rows := make([]table.Row, len(recordings))
+	for i, recording := range recordings {
+		// The occurrence under way, else the next one, else the last one
+		start, ok := recording.Current(now)
+		if !ok {
+			start, ok = recording.NextStart(now)
+		}
+		if !ok {
+			start = recording.Start
+			if runs := m.runs[recording.ID]; len(runs) > 0 {
+				start = runs[0].Scheduled
+			}
+		}
+		conflict := ""
+		if recording.Enabled && len(schedule.Conflicts(recordings, recording, now)) > 0 {
+			conflict = "⚠"
+		}
+		rows[i] = table.Row{
+			m.recordingStatus(recording, now),
+			m.formatStart(start),
+			formatRecordingDuration(recording.Duration),
+			formatAlarmDays(recording.Days),
+			recording.Station.Name,
+			conflict,
+		}
+	}
+	m.recordings = recordings
+	m.recordingsTable.SetRows(rows)
+	if cursor := m.recordingsTable.Cursor(); cursor >= len(recordings) {
+		m.recordingsTable.SetCursor(max(len(recordings)-1, 0))
+	}
+}
+
+// formatStart formats when a recording starts: its weekday and date, and its time.
+func (m RecordingsModel) formatStart(start time.Time) string {
+	return weekdayLabel(start.Weekday()) + " " + start.Format("2006-01-02 15:04")
+}
+
+// formatRecordingDuration formats a duration in hours and minutes, e.g. 1:30.
+func formatRecordingDuration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// runsView lists the latest runs of the selected recording, with the files they recorded.
+func (m RecordingsModel) runsView() string {
+	recording, ok := m.selectedRecording()
+	if !ok {
+		return ""
+	}
+	v := m.theme.SecondaryText.Render(i18n.Tf("recording_runs_title", map[string]interface{}{"Station": recording.Station.Name})) + "\n"
+	runs := m.runs[recording.ID]
+	if len(runs) == 0 {
+		return v + m.theme.TertiaryText.Render(i18n.T("recording_runs_empty")) + "\n"
+	}
+	for i, run := range runs {
+		if i == recordingRunsShown {
+			break
+		}
+		line := m.formatStart(run.Scheduled) + "  " + run.Path
+		switch {
+		case run.Err != "":
+			v += m.theme.ErrorText.Render("✗ "+line+" — "+run.Err) + "\n"
+		case run.End.IsZero() && m.recorder != nil && m.recorder.IsRecording(recording.ID) && i == 0:
+			v += m.theme.PrimaryText.Render("● "+line) + "\n"
+		case run.End.IsZero():
+			// The app quit without ending the run
+			v += m.theme.ErrorText.Render("✗ "+line+" — "+i18n.T("recording_run_interrupted")) + "\n"
+		default:
+			v += m.theme.Text.Render("✓ "+line) + "\n"
+		}
+	}
+	return v
+}
+
+func (m RecordingsModel) View() string {
+	if m.showForm {
+		modal := m.theme.ModalStyle.Render(m.form.View())
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+	}
+
+	v := fmt.Sprintf("\n%s\n\n", m.theme.SecondaryText.Bold(true).Render(i18n.T("recordings_title")))
+
+	switch {
+	case m.loading:
+		v += m.theme.TertiaryText.Render(i18n.T("loading")) + "\n"
+	case len(m.recordings) == 0:
+		v += m.theme.TertiaryText.Render(i18n.Tf("recordings_empty", map[string]interface{}{"Key": m.keybindings.AddStation})) + "\n"
+	default:
+		v += m.recordingsTable.View() + "\n\n" +
+			m.theme.TertiaryText.Render(i18n.Tfn("recordings_count", len(m.recordings), map[string]interface{}{"Count": len(m.recordings)})) + "\n\n" +
+			m.runsView()
+	}
+
+	switch {
+	case m.deleteCandidate != 0:
+		recording, _ := m.selectedRecording()
+		v += "\n" + m.theme.ErrorText.Render(i18n.Tf("recording_delete_confirm", map[string]interface{}{
+			"Station": recording.Station.Name,
+			"Key":     m.keybindings.DeleteStation,
+		})) + "\n"
+	case m.err != "":
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	case m.successMsg != "":
+		v += "\n" + m.theme.SecondaryText.Render(m.successMsg) + "\n"
+	}
+
+	return v
+}
+
+func (m *RecordingsModel) SetWidthAndHeight(width int, height int) {
+	m.width = width
+	m.height = height
+
+	tableHeight := height - recordingsChromeHeight
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.recordingsTable.SetHeight(tableHeight)
+
+	// The station column takes whatever width is left
+	columns := m.recordingsTable.Columns()
+	stationWidth := width - 8
+	for i, column := range columns {
+		if i != 4 {
+			stationWidth -= column.Width + 2
+		}
+	}
+	if stationWidth < 20 {
+		stationWidth = 20
+	}
+	columns[4].Width = stationWidth
+	m.recordingsTable.SetColumns(columns)
+}
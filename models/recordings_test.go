@@ -0,0 +1,414 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRecordingsNow is a Sunday evening.
+var testRecordingsNow = time.Date(2026, 3, 1, 18, 0, 0, 0, time.Local)
+
+func testRecordings() []schedule.Recording {
+	return []schedule.Recording{
+		{ID: 1, Station: createTestStation("Jazz FM"), Start: time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local), Duration: 2 * time.Hour, Days: schedule.EveryDay, Enabled: true},
+		{ID: 2, Station: createTestStation("Morning Radio"), Start: time.Date(2026, 3, 1, 21, 0, 0, 0, time.Local), Duration: time.Hour, Enabled: true},
+		{ID: 3, Station: createTestStation("Talk Radio"), Start: time.Date(2026, 2, 28, 10, 0, 0, 0, time.Local), Duration: 30 * time.Minute, Enabled: true},
+		{ID: 4, Station: createTestStation("News 24"), Start: time.Date(2026, 3, 2, 7, 0, 0, 0, time.Local), Duration: 90 * time.Minute},
+	}
+}
+
+func testRecordingRuns() map[int64][]schedule.RecordingRun {
+	scheduled := time.Date(2026, 2, 28, 10, 0, 0, 0, time.Local)
+	return map[int64][]schedule.RecordingRun{
+		3: {{ID: 1, RecordingID: 3, Scheduled: scheduled, Start: scheduled, End: scheduled.Add(30 * time.Minute), Path: "/tmp/talk.mp3"}},
+	}
+}
+
+func newTestRecordingsModel(storage *mocks.MockStationStorageService, recorder *mocks.MockStreamRecorderService) RecordingsModel {
+	return NewRecordingsModel(Theme{}, storage, recorder, &mocks.MockClock{NowResult: testRecordingsNow}, defaultStationsKeybindings)
+}
+
+func TestRecordingsModel(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	press := func(model RecordingsModel, key string) (RecordingsModel, tea.Cmd) {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(RecordingsModel), cmd
+	}
+
+	t.Run("loads the recordings with their runs, and the bookmarks they can record", func(t *testing.T) {
+		bookmarked := createTestStation("Jazz FM")
+		runs := testRecordingRuns()
+		storage := &mocks.MockStationStorageService{
+			GetScheduledRecordingsFunc: func() ([]schedule.Recording, error) { return testRecordings(), nil },
+			GetRecordingRunsFunc: func(recordingID int64) ([]schedule.RecordingRun, error) {
+				return runs[recordingID], nil
+			},
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) { return []common.Station{bookmarked}, nil },
+		}
+		model := NewRecordingsModel(Theme{}, storage, &mocks.MockStreamRecorderService{}, &mocks.MockClock{NowResult: testRecordingsNow}, defaultStationsKeybindings)
+		model.SetWidthAndHeight(120, 30)
+
+		msg := findMsgInCmd(model.Init(), func(msg tea.Msg) bool {
+			_, ok := msg.(recordingsLoadedMsg)
+			return ok
+		})
+		newModel, _ := model.Update(msg)
+		model = newModel.(RecordingsModel)
+
+		assert.False(t, model.loading)
+		assert.Equal(t, []common.Station{bookmarked}, model.stations)
+		rows := model.recordingsTable.Rows()
+		assert.Len(t, rows, 4)
+		assert.Equal(t, []string{"◷ Upcoming", "Sun 2026-03-01 20:00", "2:00", "Every day", "Jazz FM", "⚠"}, []string(rows[0]))
+		assert.Equal(t, []string{"◷ Upcoming", "Sun 2026-03-01 21:00", "1:00", "Once", "Morning Radio", "⚠"}, []string(rows[1]))
+		assert.Equal(t, []string{"✓ Finished", "Sat 2026-02-28 10:00", "0:30", "Once", "Talk Radio", ""}, []string(rows[2]))
+		assert.Equal(t, []string{"○ Off", "Mon 2026-03-02 07:00", "1:30", "Once", "News 24", ""}, []string(rows[3]))
+		assert.Contains(t, model.View(), "4 scheduled recordings")
+	})
+
+	t.Run("reports load errors", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{})
+
+		newModel, _ := model.Update(recordingsLoadedMsg{err: errors.New("disk full")})
+
+		assert.Contains(t, newModel.(RecordingsModel).View(), "Failed to load scheduled recordings: disk full")
+	})
+
+	t.Run("shows an empty list", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{})
+
+		assert.Contains(t, model.View(), "No scheduled recordings yet. Press a")
+	})
+
+	t.Run("lists the files recorded by the selected recording", func(t *testing.T) {
+		runs := testRecordingRuns()
+		runs[3] = append([]schedule.RecordingRun{{ID: 2, RecordingID: 3, Scheduled: testRecordingsNow, Path: "/tmp/talk2.mp3", End: testRecordingsNow, Err: "exit status 1: 404 Not Found"}}, runs[3]...)
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings(), runs: runs})
+
+		assert.Contains(t, model.View(), "Nothing recorded yet")
+
+		model.recordingsTable.MoveDown(2)
+		view := model.View()
+		assert.Contains(t, view, "Recorded from Talk Radio:")
+		assert.Contains(t, view, "✗ Sun 2026-03-01 18:00  /tmp/talk2.mp3 — exit status 1: 404 Not Found")
+		assert.Contains(t, view, "✓ Sat 2026-02-28 10:00  /tmp/talk.mp3")
+	})
+
+	t.Run("shows the recordings under way", func(t *testing.T) {
+		recorder := &mocks.MockStreamRecorderService{IsRecordingFunc: func(id int64) bool { return id == 1 }}
+
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, recorder), recordingsLoadedMsg{recordings: testRecordings()})
+
+		assert.Equal(t, "● Recording", model.recordingsTable.Rows()[0][0])
+	})
+
+	t.Run("enter turns the selected recording on and off, stopping it", func(t *testing.T) {
+		var saved schedule.Recording
+		var savedRun schedule.RecordingRun
+		storage := &mocks.MockStationStorageService{
+			SaveScheduledRecordingFunc: func(recording schedule.Recording) (schedule.Recording, error) {
+				saved = recording
+				return recording, nil
+			},
+			GetRecordingRunsFunc: func(recordingID int64) ([]schedule.RecordingRun, error) {
+				return []schedule.RecordingRun{{ID: 5, RecordingID: recordingID, Scheduled: testRecordingsNow, Path: "/tmp/jazz.mp3"}}, nil
+			},
+			SaveRecordingRunFunc: func(run schedule.RecordingRun) (schedule.RecordingRun, error) {
+				savedRun = run
+				return run, nil
+			},
+		}
+		var stopped int64
+		recorder := &mocks.MockStreamRecorderService{
+			IsRecordingFunc: func(id int64) bool { return true },
+			StopFunc: func(id int64) (string, error) {
+				stopped = id
+				return "/tmp/jazz.mp3", nil
+			},
+		}
+		model := loadedModel(newTestRecordingsModel(storage, recorder), recordingsLoadedMsg{recordings: testRecordings()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg := cmd()
+
+		assert.Equal(t, int64(1), saved.ID)
+		assert.False(t, saved.Enabled)
+		assert.Equal(t, int64(1), stopped)
+		assert.Equal(t, int64(5), savedRun.ID)
+		assert.Equal(t, testRecordingsNow, savedRun.End)
+		newModel, cmd := model.Update(msg)
+		assert.Empty(t, newModel.(RecordingsModel).successMsg)
+		assert.NotNil(t, cmd)
+	})
+
+	t.Run("needs a bookmark to add a recording", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{})
+
+		model, _ = press(model, "a")
+
+		assert.False(t, model.showForm)
+		assert.Contains(t, model.View(), "Bookmark a station first")
+	})
+
+	t.Run("adds a recording with the form", func(t *testing.T) {
+		var saved schedule.Recording
+		storage := &mocks.MockStationStorageService{
+			SaveScheduledRecordingFunc: func(recording schedule.Recording) (schedule.Recording, error) {
+				saved = recording
+				recording.ID = 5
+				return recording, nil
+			},
+		}
+		stations := []common.Station{createTestStation("Jazz FM"), createTestStation("Morning Radio")}
+		model := loadedModel(newTestRecordingsModel(storage, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{stations: stations})
+
+		model, _ = press(model, "a")
+		assert.True(t, model.showForm)
+		assert.Contains(t, model.View(), "New scheduled recording")
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRight})
+		model = newModel.(RecordingsModel)
+		// The date defaults to today
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(RecordingsModel)
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = newModel.(RecordingsModel)
+		model, _ = press(model, "22:30")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(RecordingsModel)
+		assert.False(t, model.showForm)
+
+		newModel, _ = model.Update(cmd())
+		assert.Equal(t, schedule.Recording{
+			Station:  stations[1],
+			Start:    time.Date(2026, 3, 1, 22, 30, 0, 0, time.Local),
+			Duration: defaultRecordingDuration,
+			Enabled:  true,
+		}, saved)
+		assert.Equal(t, "Recording saved: Morning Radio will be recorded Sun 2026-03-01 22:30", newModel.(RecordingsModel).successMsg)
+	})
+
+	t.Run("asks before saving a recording that overlaps another one", func(t *testing.T) {
+		saves := 0
+		storage := &mocks.MockStationStorageService{
+			SaveScheduledRecordingFunc: func(recording schedule.Recording) (schedule.Recording, error) {
+				saves++
+				return recording, nil
+			},
+		}
+		stations := []common.Station{createTestStation("Jazz FM")}
+		model := loadedModel(newTestRecordingsModel(storage, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings(), stations: stations})
+
+		model, _ = press(model, "a")
+		model.form.inputs[recordingFieldTime].SetValue("21:30")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(RecordingsModel)
+
+		assert.Nil(t, cmd)
+		assert.True(t, model.showForm)
+		assert.Contains(t, model.View(), "Overlaps the recording of Jazz FM on Sun 2026-03-01 21:30. Press enter again to save anyway")
+
+		newModel, cmd = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.False(t, newModel.(RecordingsModel).showForm)
+		cmd()
+		assert.Equal(t, 1, saves)
+	})
+
+	t.Run("checks a changed recording for overlaps again", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings(), stations: []common.Station{createTestStation("Jazz FM")}})
+
+		model, _ = press(model, "a")
+		model.form.inputs[recordingFieldTime].SetValue("21:30")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(RecordingsModel)
+		model, _ = press(model, "x")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.Nil(t, cmd)
+		assert.True(t, newModel.(RecordingsModel).showForm)
+	})
+
+	t.Run("keeps the form open on invalid input", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{stations: []common.Station{createTestStation("Jazz FM")}})
+
+		model, _ = press(model, "a")
+		model.form.inputs[recordingFieldDate].SetValue("tomorrow")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(RecordingsModel)
+
+		assert.Nil(t, cmd)
+		assert.True(t, model.showForm)
+		assert.Contains(t, model.View(), `"tomorrow" isn't a date`)
+	})
+
+	t.Run("edits the selected recording", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings()})
+
+		model, _ = press(model, "e")
+
+		assert.True(t, model.showForm)
+		assert.True(t, model.form.IsEditing())
+		assert.Contains(t, model.View(), "Edit scheduled recording")
+		recording, err := model.form.Recording()
+		assert.NoError(t, err)
+		assert.Equal(t, model.recordings[0], recording)
+	})
+
+	t.Run("esc closes the form", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings()})
+
+		model, _ = press(model, "e")
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Nil(t, cmd)
+		assert.False(t, newModel.(RecordingsModel).showForm)
+	})
+
+	t.Run("deletes the selected recording once confirmed, stopping it", func(t *testing.T) {
+		var deleted, stopped int64
+		storage := &mocks.MockStationStorageService{
+			DeleteScheduledRecordingFunc: func(id int64) error {
+				deleted = id
+				return nil
+			},
+		}
+		recorder := &mocks.MockStreamRecorderService{
+			IsRecordingFunc: func(id int64) bool { return true },
+			StopFunc: func(id int64) (string, error) {
+				stopped = id
+				return "", nil
+			},
+		}
+		model := loadedModel(newTestRecordingsModel(storage, recorder), recordingsLoadedMsg{recordings: testRecordings()})
+		model.recordingsTable.MoveDown(1)
+
+		model, cmd := press(model, "D")
+		assert.Nil(t, cmd)
+		assert.Contains(t, model.View(), "Delete the recording of Morning Radio? Its files are kept. Press D again to confirm")
+
+		model, cmd = press(model, "D")
+		cmd()
+		assert.Equal(t, int64(2), deleted)
+		assert.Equal(t, int64(2), stopped)
+		assert.Equal(t, int64(0), model.deleteCandidate)
+	})
+
+	t.Run("other keys cancel a delete", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings()})
+
+		model, _ = press(model, "D")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+		assert.Equal(t, int64(0), newModel.(RecordingsModel).deleteCandidate)
+	})
+
+	t.Run("esc goes back to search", func(t *testing.T) {
+		model := loadedModel(newTestRecordingsModel(&mocks.MockStationStorageService{}, &mocks.MockStreamRecorderService{}), recordingsLoadedMsg{recordings: testRecordings()})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.Equal(t, switchToSearchModelMsg{}, cmd())
+	})
+}
+
+func TestRecordingForm(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	stations := []common.Station{createTestStation("Jazz FM"), createTestStation("Morning Radio")}
+	valid := func(form RecordingForm) RecordingForm {
+		form.inputs[recordingFieldTime].SetValue("20:00")
+		return form
+	}
+
+	t.Run("repeats on the chosen days", func(t *testing.T) {
+		form := valid(NewRecordingForm(Theme{}, stations, testRecordingsNow))
+		form.setFocus(recordingFieldDays)
+
+		form, _ = form.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+		recording, err := form.Recording()
+
+		assert.NoError(t, err)
+		assert.Equal(t, schedule.Weekdays(0).Toggle(time.Monday), recording.Days)
+	})
+
+	t.Run("validates the duration", func(t *testing.T) {
+		form := valid(NewRecordingForm(Theme{}, stations, testRecordingsNow))
+
+		for _, value := range []string{"0", "1441", "long"} {
+			form.inputs[recordingFieldDuration].SetValue(value)
+			_, err := form.Recording()
+			assert.EqualError(t, err, "The duration must be between 1 and 1440 minutes")
+		}
+
+		form.inputs[recordingFieldDuration].SetValue("1440")
+		recording, err := form.Recording()
+		assert.NoError(t, err)
+		assert.Equal(t, schedule.MaxRecordingDuration, recording.Duration)
+	})
+
+	t.Run("rejects a one-off recording that would be over", func(t *testing.T) {
+		form := NewRecordingForm(Theme{}, stations, testRecordingsNow)
+		form.inputs[recordingFieldTime].SetValue("16:00")
+
+		_, err := form.Recording()
+		assert.EqualError(t, err, "This recording would already be over: pick a later date or time")
+
+		// Still under way
+		form.inputs[recordingFieldTime].SetValue("17:30")
+		_, err = form.Recording()
+		assert.NoError(t, err)
+
+		// Repeats later on
+		form.days.days = schedule.EveryDay
+		form.inputs[recordingFieldTime].SetValue("16:00")
+		_, err = form.Recording()
+		assert.NoError(t, err)
+	})
+
+	t.Run("offers the station of an edited recording that's no longer bookmarked", func(t *testing.T) {
+		recording := testRecordings()[3]
+
+		form := NewRecordingFormFor(Theme{}, stations, recording, testRecordingsNow)
+		edited, err := form.Recording()
+
+		assert.NoError(t, err)
+		assert.Equal(t, recording, edited)
+		assert.Len(t, form.stations, 3)
+	})
+}
+
+func TestFormatRecordingDuration(t *testing.T) {
+	assert.Equal(t, "0:45", formatRecordingDuration(45*time.Minute))
+	assert.Equal(t, "1:30", formatRecordingDuration(90*time.Minute))
+	assert.Equal(t, "24:00", formatRecordingDuration(schedule.MaxRecordingDuration))
+}
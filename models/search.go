@@ -20,6 +20,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -27,10 +28,12 @@ import (
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/providers"
 	"github.com/zi0p4tch0/radiogogo/storage"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type SearchModel struct {
@@ -40,8 +43,37 @@ type SearchModel struct {
 	keybindings   config.Keybindings
 	inputModel    textinput.Model
 	querySelector SelectorModel[common.StationQuery]
+	orderSelector SelectorModel[common.StationOrder]
+	order         common.StationOrder // remembered sort order
+	reverse       bool                // direction of order, kept while it stays selected
+	advancedForm  AdvancedSearchForm
+	advanced      bool
+	geoForm       GeoSearchForm
+	geo           bool
+	mirror        string
 	width         int
 	height        int
+
+	// Where simple searches look for stations; only offered when there's more than one
+	stationProviders []providers.StationProvider
+	providerIndex    int
+}
+
+// searchQueries are the filters offered by the simple search selector, in display order.
+var searchQueries = []common.StationQuery{
+	common.StationQueryByName,
+	common.StationQueryByNameExact,
+	common.StationQueryByCodec,
+	common.StationQueryByCodecExact,
+	common.StationQueryByCountry,
+	common.StationQueryByCountryExact,
+	common.StationQueryByCountryCodeExact,
+	common.StationQueryByState,
+	common.StationQueryByStateExact,
+	common.StationQueryByLanguage,
+	common.StationQueryByLanguageExact,
+	common.StationQueryByTag,
+	common.StationQueryByTagExact,
 }
 
 func NewSearchModel(theme Theme, browser api.RadioBrowserService, storage storage.StationStorageService, keybindings config.Keybindings) SearchModel {
@@ -55,21 +87,14 @@ func NewSearchModel(theme Theme, browser api.RadioBrowserService, storage storag
 	selector := NewSelectorModel[common.StationQuery](
 		theme,
 		i18n.T("filter_label"),
-		[]common.StationQuery{
-			common.StationQueryByName,
-			common.StationQueryByNameExact,
-			common.StationQueryByCodec,
-			common.StationQueryByCodecExact,
-			common.StationQueryByCountry,
-			common.StationQueryByCountryExact,
-			common.StationQueryByCountryCodeExact,
-			common.StationQueryByState,
-			common.StationQueryByStateExact,
-			common.StationQueryByLanguage,
-			common.StationQueryByLanguageExact,
-			common.StationQueryByTag,
-			common.StationQueryByTagExact,
-		},
+		searchQueries,
+		0,
+	)
+
+	orderSelector := NewSelectorModel[common.StationOrder](
+		theme,
+		i18n.T("order_label"),
+		common.AllStationOrders(),
 		0,
 	)
 
@@ -80,21 +105,133 @@ func NewSearchModel(theme Theme, browser api.RadioBrowserService, storage storag
 		keybindings:   keybindings,
 		inputModel:    i,
 		querySelector: selector,
+		orderSelector: orderSelector,
+		order:         common.StationOrderVotes,
+		reverse:       true,
+		advancedForm:  NewAdvancedSearchForm(theme),
+		geoForm:       NewGeoSearchForm(theme),
+	}
+
+}
+
+// SetOrder selects the sort order offered by default, e.g. the one remembered in config.
+func (m *SearchModel) SetOrder(order common.StationOrder, reverse bool) {
+	for i, o := range common.AllStationOrders() {
+		if o == order {
+			m.orderSelector.SetSelection(i)
+			m.order = order
+			m.reverse = reverse
+			return
+		}
+	}
+}
+
+// selectedOrder returns the sort order to search with. A newly selected order
+// uses its natural direction; the remembered one keeps its direction.
+func (m SearchModel) selectedOrder() (common.StationOrder, bool) {
+	order := m.orderSelector.Selection()
+	if order == m.order {
+		return order, m.reverse
+	}
+	return order, order.DefaultReverse()
+}
+
+// SetProviders sets where simple searches can look for stations and selects the one with the given source.
+func (m *SearchModel) SetProviders(list []providers.StationProvider, selected common.StationSource) {
+	m.stationProviders = list
+	m.providerIndex = 0
+	for i, p := range list {
+		if p.Source() == selected {
+			m.providerIndex = i
+			break
+		}
 	}
+}
 
+// selectedProvider returns where the simple search looks for stations, or nil if no providers were set.
+func (m SearchModel) selectedProvider() providers.StationProvider {
+	if len(m.stationProviders) == 0 {
+		return nil
+	}
+	return m.stationProviders[m.providerIndex]
+}
+
+// RestoreQuery pre-fills the search screen with a previous query, e.g. after a cancelled search.
+// If advancedParams is set, the advanced form is shown and filled in instead,
+// or the nearby search form if the parameters are centred on a point.
+func (m *SearchModel) RestoreQuery(query common.StationQuery, queryText string, advancedParams *common.StationSearchParams) {
+	if advancedParams != nil && advancedParams.Near != nil {
+		m.geo = true
+		m.inputModel.Blur()
+		m.geoForm.SetParams(*advancedParams)
+		m.geoForm.Focus()
+		return
+	}
+	if advancedParams != nil {
+		m.advanced = true
+		m.inputModel.Blur()
+		m.advancedForm.SetParams(*advancedParams)
+		m.advancedForm.Focus()
+		return
+	}
+	for i, q := range searchQueries {
+		if q == query {
+			m.querySelector.SetSelection(i)
+			break
+		}
+	}
+	m.inputModel.SetValue(queryText)
+}
+
+// Messages
+
+// mirrorResolvedMsg carries the RadioBrowser mirror host in use, for display on the search screen.
+type mirrorResolvedMsg struct {
+	host string
 }
 
 // Commands
 
+// resolveMirrorCmd asks the browser which mirror it is using.
+// This may trigger DNS-based mirror discovery, so it runs off the UI thread.
+func resolveMirrorCmd(browser api.RadioBrowserService) tea.Cmd {
+	return func() tea.Msg {
+		return mirrorResolvedMsg{host: browser.CurrentMirror(context.Background())}
+	}
+}
+
+// searchFocus identifies the focused element of the simple search screen.
+type searchFocus int
+
+const (
+	searchFocusInput searchFocus = iota
+	searchFocusQuery
+	searchFocusOrder
+)
+
+// focus returns the focused element of the simple search screen.
+func (m SearchModel) focus() searchFocus {
+	switch {
+	case m.querySelector.Focused():
+		return searchFocusQuery
+	case m.orderSelector.Focused():
+		return searchFocusOrder
+	}
+	return searchFocusInput
+}
+
 // updateSearchCommandsCmd returns a command that updates the bottom bar based on focus state.
-// When textfieldFocused is true, shows search-specific commands; otherwise shows filter commands.
-func updateSearchCommandsCmd(kb config.Keybindings, textfieldFocused bool) tea.Cmd {
+// When the text field is focused, shows search-specific commands; otherwise shows selector commands.
+func updateSearchCommandsCmd(kb config.Keybindings, focus searchFocus) tea.Cmd {
 	return func() tea.Msg {
 		var contextCmd string
-		if textfieldFocused {
-			contextCmd = i18n.T("cmd_enter_search")
-		} else {
+		switch focus {
+		case searchFocusQuery:
 			contextCmd = i18n.T("cmd_change_filter")
+		case searchFocusOrder:
+			contextCmd = i18n.T("cmd_change_order")
+		default:
+			contextCmd = i18n.T("cmd_enter_search")
 		}
 
 		return bottomBarUpdateMsg{
@@ -106,6 +243,72 @@ func updateSearchCommandsCmd(kb config.Keybindings, textfieldFocused bool) tea.C
 				i18n.Tf("cmd_change_language", map[string]interface{}{"Key": kb.ChangeLanguage}),
 				i18n.T("current_language"),
 			},
+			secondaryCommands: []string{
+				i18n.Tf("cmd_advanced_search", map[string]interface{}{"Key": kb.AdvancedSearch}),
+				i18n.Tf("cmd_nearby_search", map[string]interface{}{"Key": kb.NearbySearch}),
+				i18n.Tf("cmd_browse", map[string]interface{}{"Key": kb.Browse}),
+				i18n.Tf("cmd_discover", map[string]interface{}{"Key": kb.Discover}),
+				i18n.Tf("cmd_history", map[string]interface{}{"Key": kb.History}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+				i18n.Tf("cmd_custom_stations", map[string]interface{}{"Key": kb.CustomStations}),
+			},
+		}
+	}
+}
+
+// updateAdvancedSearchCommandsCmd returns a command that updates the bottom bar for the advanced search form.
+// When textfieldFocused is true, single-key shortcuts are hidden since they would be typed into the field.
+func updateAdvancedSearchCommandsCmd(kb config.Keybindings, textfieldFocused bool) tea.Cmd {
+	return func() tea.Msg {
+		var commands []string
+		if !textfieldFocused {
+			commands = append(commands, i18n.Tf("cmd_quit", map[string]interface{}{"Key": kb.Quit}))
+		}
+		commands = append(commands, i18n.T("cmd_move_field"))
+		if textfieldFocused {
+			commands = append(commands, i18n.T("cmd_enter_search"))
+		} else {
+			commands = append(commands, i18n.T("cmd_change_option"), i18n.T("cmd_enter_search"))
+		}
+		commands = append(commands, i18n.T("current_language"))
+
+		return bottomBarUpdateMsg{
+			commands: commands,
+			secondaryCommands: []string{
+				i18n.Tf("cmd_simple_search", map[string]interface{}{"Key": kb.AdvancedSearch}),
+				i18n.Tf("cmd_nearby_search", map[string]interface{}{"Key": kb.NearbySearch}),
+				i18n.Tf("cmd_browse", map[string]interface{}{"Key": kb.Browse}),
+				i18n.Tf("cmd_discover", map[string]interface{}{"Key": kb.Discover}),
+				i18n.Tf("cmd_history", map[string]interface{}{"Key": kb.History}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+				i18n.Tf("cmd_custom_stations", map[string]interface{}{"Key": kb.CustomStations}),
+			},
+		}
+	}
+}
+
+// updateGeoSearchCommandsCmd returns a command that updates the bottom bar for the nearby search form.
+// Every field is a text input, so single-key shortcuts are never shown.
+func updateGeoSearchCommandsCmd(kb config.Keybindings) tea.Cmd {
+	return func() tea.Msg {
+		return bottomBarUpdateMsg{
+			commands: []string{
+				i18n.T("cmd_move_field"),
+				i18n.T("cmd_enter_search"),
+				i18n.T("current_language"),
+			},
+			secondaryCommands: []string{
+				i18n.Tf("cmd_simple_search", map[string]interface{}{"Key": kb.NearbySearch}),
+				i18n.Tf("cmd_advanced_search", map[string]interface{}{"Key": kb.AdvancedSearch}),
+				i18n.Tf("cmd_browse", map[string]interface{}{"Key": kb.Browse}),
+				i18n.Tf("cmd_discover", map[string]interface{}{"Key": kb.Discover}),
+				i18n.Tf("cmd_history", map[string]interface{}{"Key": kb.History}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+				i18n.Tf("cmd_custom_stations", map[string]interface{}{"Key": kb.CustomStations}),
+			},
 		}
 	}
 }
@@ -126,30 +329,95 @@ func getNextLanguage() string {
 // Bubbletea
 
 func (m SearchModel) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink, updateSearchCommandsCmd(m.keybindings, true))
+	var cmds []tea.Cmd
+	if m.advanced {
+		cmds = []tea.Cmd{textinput.Blink, updateAdvancedSearchCommandsCmd(m.keybindings, m.advancedForm.TextFieldFocused())}
+	} else if m.geo {
+		cmds = []tea.Cmd{textinput.Blink, updateGeoSearchCommandsCmd(m.keybindings)}
+	} else {
+		cmds = []tea.Cmd{textinput.Blink, updateSearchCommandsCmd(m.keybindings, m.focus())}
+	}
+	if m.browser != nil {
+		cmds = append(cmds, resolveMirrorCmd(m.browser))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
+	case mirrorResolvedMsg:
+		m.mirror = msg.host
+		return m, nil
 	case tea.KeyMsg:
+		if msg.String() == m.keybindings.AdvancedSearch {
+			return m.toggleAdvanced()
+		}
+		if msg.String() == m.keybindings.NearbySearch {
+			return m.toggleGeo()
+		}
+		if msg.String() == m.keybindings.Browse {
+			return m, func() tea.Msg {
+				return switchToBrowseModelMsg{}
+			}
+		}
+		if msg.String() == m.keybindings.Discover {
+			return m, func() tea.Msg {
+				return switchToDiscoverModelMsg{}
+			}
+		}
+		if msg.String() == m.keybindings.History && m.storage != nil {
+			return m, func() tea.Msg {
+				return switchToHistoryModelMsg{}
+			}
+		}
+		if msg.String() == m.keybindings.Alarms && m.storage != nil {
+			return m, func() tea.Msg {
+				return switchToAlarmsModelMsg{}
+			}
+		}
+		if msg.String() == m.keybindings.Recordings && m.storage != nil {
+			return m, func() tea.Msg {
+				return switchToRecordingsModelMsg{}
+			}
+		}
+		if m.advanced {
+			return m.updateAdvanced(msg)
+		}
+		if m.geo {
+			return m.updateGeo(msg)
+		}
 		switch msg.String() {
 		case "tab":
-			if m.inputModel.Focused() {
+			// Cycle focus: text field → filter → sort order
+			switch m.focus() {
+			case searchFocusInput:
 				m.inputModel.Blur()
 				m.querySelector.Focus()
-				return m, updateSearchCommandsCmd(m.keybindings, false)
-			} else {
-				m.inputModel.Focus()
+			case searchFocusQuery:
 				m.querySelector.Blur()
-				return m, updateSearchCommandsCmd(m.keybindings, true)
+				m.orderSelector.Focus()
+			default:
+				m.orderSelector.Blur()
+				m.inputModel.Focus()
+			}
+			return m, updateSearchCommandsCmd(m.keybindings, m.focus())
+		case m.keybindings.Source:
+			if len(m.stationProviders) > 1 {
+				m.providerIndex = (m.providerIndex + 1) % len(m.stationProviders)
+				return m, nil
 			}
 		case m.keybindings.Quit:
 			if !m.inputModel.Focused() {
 				return m, quitCmd
 			}
 		case m.keybindings.BookmarksView:
-			return m, fetchBookmarksForSearchCmd(m.browser, m.storage)
+			return m, fetchBookmarksForSearchCmd(m.storage)
+		case m.keybindings.CustomStations:
+			// Capital letters are common in station names
+			if !m.inputModel.Focused() {
+				return m, fetchCustomStationsForSearchCmd(m.storage)
+			}
 		case m.keybindings.ChangeLanguage:
 			nextLang := getNextLanguage()
 			return m, func() tea.Msg {
@@ -159,15 +427,36 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !m.inputModel.Focused() {
 				return m, nil
 			}
-			return m, func() tea.Msg {
-				return switchToLoadingModelMsg{
-					query:     m.querySelector.Selection(),
-					queryText: m.inputModel.Value(),
-				}
-			}
+			order, reverse := m.selectedOrder()
+			return m, tea.Batch(
+				func() tea.Msg {
+					return switchToLoadingModelMsg{
+						query:     m.querySelector.Selection(),
+						queryText: m.inputModel.Value(),
+						order:     order,
+						reverse:   reverse,
+						provider:  m.selectedProvider(),
+					}
+				},
+				func() tea.Msg {
+					return sortOrderChangedMsg{order: order, reverse: reverse}
+				},
+			)
 		}
 	}
 
+	if m.advanced {
+		var cmd tea.Cmd
+		m.advancedForm, cmd = m.advancedForm.Update(msg)
+		return m, cmd
+	}
+
+	if m.geo {
+		var cmd tea.Cmd
+		m.geoForm, cmd = m.geoForm.Update(msg)
+		return m, cmd
+	}
+
 	var cmds []tea.Cmd
 
 	newInputModel, inputCmd := m.inputModel.Update(msg)
@@ -184,20 +473,152 @@ func (m SearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, selectorCmd)
 	}
 
+	newOrderSelectorModel, orderSelectorCmd := m.orderSelector.Update(msg)
+	m.orderSelector = newOrderSelectorModel
+
+	if orderSelectorCmd != nil {
+		cmds = append(cmds, orderSelectorCmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
+// toggleAdvanced switches between the simple query and the advanced search form.
+func (m SearchModel) toggleAdvanced() (tea.Model, tea.Cmd) {
+	m.advanced = !m.advanced
+	if m.advanced {
+		m.geo = false
+		m.geoForm.Blur()
+		m.inputModel.Blur()
+		m.querySelector.Blur()
+		m.orderSelector.Blur()
+		m.advancedForm.Focus()
+		return m, tea.Batch(textinput.Blink, updateAdvancedSearchCommandsCmd(m.keybindings, m.advancedForm.TextFieldFocused()))
+	}
+	m.advancedForm.Blur()
+	m.inputModel.Focus()
+	return m, tea.Batch(textinput.Blink, updateSearchCommandsCmd(m.keybindings, searchFocusInput))
+}
+
+// toggleGeo switches between the simple query and the nearby search form.
+func (m SearchModel) toggleGeo() (tea.Model, tea.Cmd) {
+	m.geo = !m.geo
+	if m.geo {
+		m.advanced = false
+		m.advancedForm.Blur()
+		m.inputModel.Blur()
+		m.querySelector.Blur()
+		m.orderSelector.Blur()
+		m.geoForm.Focus()
+		return m, tea.Batch(textinput.Blink, updateGeoSearchCommandsCmd(m.keybindings))
+	}
+	m.geoForm.Blur()
+	m.inputModel.Focus()
+	return m, tea.Batch(textinput.Blink, updateSearchCommandsCmd(m.keybindings, searchFocusInput))
+}
+
+// updateGeo handles key presses while the nearby search form is shown.
+func (m SearchModel) updateGeo(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "enter" {
+		params, err := m.geoForm.Params()
+		if err != nil {
+			m.geoForm.SetError(err.Error())
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return switchToLoadingModelMsg{advancedParams: &params}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.geoForm, cmd = m.geoForm.Update(msg)
+	return m, cmd
+}
+
+// updateAdvanced handles key presses while the advanced search form is shown.
+// Global shortcuts only apply when an option field (not a text input) is focused.
+func (m SearchModel) updateAdvanced(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !m.advancedForm.TextFieldFocused() {
+		switch msg.String() {
+		case m.keybindings.Quit:
+			return m, quitCmd
+		case m.keybindings.BookmarksView:
+			return m, fetchBookmarksForSearchCmd(m.storage)
+		case m.keybindings.CustomStations:
+			return m, fetchCustomStationsForSearchCmd(m.storage)
+		case m.keybindings.ChangeLanguage:
+			nextLang := getNextLanguage()
+			return m, func() tea.Msg {
+				return languageChangedMsg{lang: nextLang}
+			}
+		}
+	}
+
+	if msg.String() == "enter" {
+		params, err := m.advancedForm.Params()
+		if err != nil {
+			m.advancedForm.SetError(err.Error())
+			return m, nil
+		}
+		return m, func() tea.Msg {
+			return switchToLoadingModelMsg{advancedParams: &params}
+		}
+	}
+
+	wasTextField := m.advancedForm.TextFieldFocused()
+
+	var cmd tea.Cmd
+	m.advancedForm, cmd = m.advancedForm.Update(msg)
+
+	if wasTextField != m.advancedForm.TextFieldFocused() {
+		cmd = tea.Batch(cmd, updateAdvancedSearchCommandsCmd(m.keybindings, m.advancedForm.TextFieldFocused()))
+	}
+
+	return m, cmd
+}
+
 func (m SearchModel) View() string {
+	if m.advanced || m.geo {
+		var v string
+		if m.advanced {
+			v = "\n" + m.advancedForm.View()
+		} else {
+			v = "\n" + m.geoForm.View()
+		}
+		if m.mirror != "" {
+			v += "\n" + m.theme.TertiaryText.Render(i18n.Tf("api_mirror", map[string]interface{}{"Host": m.mirror})) + "\n"
+		}
+		return v
+	}
+
 	searchType := m.querySelector.Selection().Render()
 	searchType = strings.ToLower(searchType)
 
+	selectors := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		m.querySelector.View(),
+		"      ",
+		m.orderSelector.View(),
+	)
+
 	v := fmt.Sprintf("\n%s\n\n%s\n\n%s\n%s\n",
 		m.theme.SecondaryText.Render(i18n.Tf("search_title", map[string]interface{}{"Type": searchType})),
 		m.inputModel.View(),
-		m.querySelector.View(),
+		selectors,
 		m.theme.TertiaryText.Render(m.querySelector.Selection().ExampleString()),
 	)
 
+	if len(m.stationProviders) > 1 {
+		v += "\n" + m.theme.SecondaryText.Render(i18n.Tf("search_source", map[string]interface{}{
+			"Name": m.selectedProvider().Source().Render(),
+			"Key":  m.keybindings.Source,
+		})) + "\n"
+	}
+
+	if m.mirror != "" {
+		v += "\n" + m.theme.TertiaryText.Render(i18n.Tf("api_mirror", map[string]interface{}{"Host": m.mirror})) + "\n"
+	}
+
 	return v
 }
 
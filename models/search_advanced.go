@@ -0,0 +1,390 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// advancedField identifies a field of the advanced search form.
+type advancedField int
+
+const (
+	advancedFieldName advancedField = iota
+	advancedFieldTags
+	advancedFieldCountryCode
+	advancedFieldState
+	advancedFieldLanguage
+	advancedFieldCodec
+	advancedFieldBitrateMin
+	advancedFieldBitrateMax
+	advancedFieldHasGeoInfo
+	advancedFieldIsHttps
+	advancedFieldOrder
+	advancedFieldCount
+)
+
+// advancedTextFieldCount is the number of free-text fields, which come first in the form.
+const advancedTextFieldCount = int(advancedFieldHasGeoInfo)
+
+// label returns the localized label for the field.
+func (f advancedField) label() string {
+	switch f {
+	case advancedFieldName:
+		return i18n.T("advanced_field_name")
+	case advancedFieldTags:
+		return i18n.T("advanced_field_tags")
+	case advancedFieldCountryCode:
+		return i18n.T("advanced_field_country_code")
+	case advancedFieldState:
+		return i18n.T("advanced_field_state")
+	case advancedFieldLanguage:
+		return i18n.T("advanced_field_language")
+	case advancedFieldCodec:
+		return i18n.T("advanced_field_codec")
+	case advancedFieldBitrateMin:
+		return i18n.T("advanced_field_bitrate_min")
+	case advancedFieldBitrateMax:
+		return i18n.T("advanced_field_bitrate_max")
+	case advancedFieldHasGeoInfo:
+		return i18n.T("advanced_field_has_geo_info")
+	case advancedFieldIsHttps:
+		return i18n.T("advanced_field_is_https")
+	case advancedFieldOrder:
+		return i18n.T("advanced_field_order")
+	}
+	return ""
+}
+
+// isText returns true if the field is edited with a text input.
+func (f advancedField) isText() bool {
+	return int(f) < advancedTextFieldCount
+}
+
+// triState is an any/yes/no choice for optional boolean filters.
+type triState int
+
+const (
+	triStateAny triState = iota
+	triStateYes
+	triStateNo
+)
+
+func (s triState) Render() string {
+	switch s {
+	case triStateYes:
+		return i18n.T("option_yes")
+	case triStateNo:
+		return i18n.T("option_no")
+	}
+	return i18n.T("option_any")
+}
+
+// value returns the filter value, or nil for "any".
+func (s triState) value() *bool {
+	switch s {
+	case triStateYes:
+		v := true
+		return &v
+	case triStateNo:
+		v := false
+		return &v
+	}
+	return nil
+}
+
+// AdvancedSearchForm is a multi-field form used to build a StationSearchParams.
+// Text fields are edited with text inputs; option fields cycle with left/right.
+type AdvancedSearchForm struct {
+	theme Theme
+
+	inputs     []textinput.Model
+	hasGeoInfo triState
+	isHttps    triState
+	orders     []common.StationOrder
+	orderIndex int
+	focus      advancedField
+	err        string
+}
+
+func NewAdvancedSearchForm(theme Theme) AdvancedSearchForm {
+	inputs := make([]textinput.Model, advancedTextFieldCount)
+	for i := range inputs {
+		input := textinput.New()
+		input.Width = 30
+		input.Prompt = ""
+		input.TextStyle = theme.Text
+		input.PlaceholderStyle = theme.TertiaryText
+		inputs[i] = input
+	}
+	inputs[advancedFieldTags].Placeholder = "jazz, smooth"
+	inputs[advancedFieldCountryCode].Placeholder = "DE"
+	inputs[advancedFieldCountryCode].CharLimit = 2
+	inputs[advancedFieldCodec].Placeholder = "AAC"
+	inputs[advancedFieldBitrateMin].Placeholder = "128"
+	inputs[advancedFieldBitrateMin].CharLimit = 4
+	inputs[advancedFieldBitrateMax].CharLimit = 4
+
+	form := AdvancedSearchForm{
+		theme:  theme,
+		inputs: inputs,
+		orders: common.AllStationOrders(),
+	}
+	form.setFocus(advancedFieldName)
+	return form
+}
+
+// setFocus moves focus to the given field, focusing its text input if it has one.
+func (m *AdvancedSearchForm) setFocus(field advancedField) {
+	m.focus = field
+	for i := range m.inputs {
+		if advancedField(i) == field {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+// Blur removes focus from every text input (used when leaving advanced mode).
+func (m *AdvancedSearchForm) Blur() {
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+}
+
+// Focus restores focus to the current field.
+func (m *AdvancedSearchForm) Focus() {
+	m.setFocus(m.focus)
+}
+
+// TextFieldFocused returns true if the focused field is a text input,
+// in which case single-letter shortcuts must not be intercepted.
+func (m AdvancedSearchForm) TextFieldFocused() bool {
+	return m.focus.isText()
+}
+
+// Params builds the search parameters from the form.
+// Returns an error if a numeric field contains an invalid value.
+func (m AdvancedSearchForm) Params() (common.StationSearchParams, error) {
+	value := func(field advancedField) string {
+		return strings.TrimSpace(m.inputs[field].Value())
+	}
+
+	bitrate := func(field advancedField) (uint64, error) {
+		v := value(field)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s", i18n.Tf("error_invalid_bitrate", map[string]interface{}{"Value": v}))
+		}
+		return n, nil
+	}
+
+	bitrateMin, err := bitrate(advancedFieldBitrateMin)
+	if err != nil {
+		return common.StationSearchParams{}, err
+	}
+	bitrateMax, err := bitrate(advancedFieldBitrateMax)
+	if err != nil {
+		return common.StationSearchParams{}, err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(value(advancedFieldTags), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	order := m.orders[m.orderIndex]
+
+	return common.StationSearchParams{
+		Name:        value(advancedFieldName),
+		TagList:     tags,
+		CountryCode: strings.ToUpper(value(advancedFieldCountryCode)),
+		State:       value(advancedFieldState),
+		Language:    strings.ToLower(value(advancedFieldLanguage)),
+		Codec:       value(advancedFieldCodec),
+		BitrateMin:  bitrateMin,
+		BitrateMax:  bitrateMax,
+		HasGeoInfo:  m.hasGeoInfo.value(),
+		IsHttps:     m.isHttps.value(),
+		Order:       order,
+		Reverse:     order.DefaultReverse(),
+		HideBroken:  true,
+	}, nil
+}
+
+// SetParams fills in the form from previously submitted search parameters.
+func (m *AdvancedSearchForm) SetParams(params common.StationSearchParams) {
+	bitrate := func(value uint64) string {
+		if value == 0 {
+			return ""
+		}
+		return strconv.FormatUint(value, 10)
+	}
+	triStateOf := func(value *bool) triState {
+		switch {
+		case value == nil:
+			return triStateAny
+		case *value:
+			return triStateYes
+		}
+		return triStateNo
+	}
+
+	m.inputs[advancedFieldName].SetValue(params.Name)
+	m.inputs[advancedFieldTags].SetValue(strings.Join(params.TagList, ", "))
+	m.inputs[advancedFieldCountryCode].SetValue(params.CountryCode)
+	m.inputs[advancedFieldState].SetValue(params.State)
+	m.inputs[advancedFieldLanguage].SetValue(params.Language)
+	m.inputs[advancedFieldCodec].SetValue(params.Codec)
+	m.inputs[advancedFieldBitrateMin].SetValue(bitrate(params.BitrateMin))
+	m.inputs[advancedFieldBitrateMax].SetValue(bitrate(params.BitrateMax))
+	m.hasGeoInfo = triStateOf(params.HasGeoInfo)
+	m.isHttps = triStateOf(params.IsHttps)
+	for i, order := range m.orders {
+		if order == params.Order {
+			m.orderIndex = i
+			break
+		}
+	}
+}
+
+// SetError sets the validation error shown below the form.
+func (m *AdvancedSearchForm) SetError(err string) {
+	m.err = err
+}
+
+// cycleOption moves the focused option field forward or backward.
+func (m *AdvancedSearchForm) cycleOption(direction int) {
+	cycle := func(current, count int) int {
+		return (current + direction + count) % count
+	}
+	switch m.focus {
+	case advancedFieldHasGeoInfo:
+		m.hasGeoInfo = triState(cycle(int(m.hasGeoInfo), 3))
+	case advancedFieldIsHttps:
+		m.isHttps = triState(cycle(int(m.isHttps), 3))
+	case advancedFieldOrder:
+		m.orderIndex = cycle(m.orderIndex, len(m.orders))
+	}
+}
+
+// Bubbletea
+
+func (m AdvancedSearchForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m AdvancedSearchForm) Update(msg tea.Msg) (AdvancedSearchForm, tea.Cmd) {
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			m.setFocus((m.focus + 1) % advancedFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focus + advancedFieldCount - 1) % advancedFieldCount)
+			return m, nil
+		case "left":
+			if !m.focus.isText() {
+				m.cycleOption(-1)
+				return m, nil
+			}
+		case "right":
+			if !m.focus.isText() {
+				m.cycleOption(1)
+				return m, nil
+			}
+		}
+		m.err = ""
+	}
+
+	if !m.focus.isText() {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m AdvancedSearchForm) View() string {
+
+	v := m.theme.SecondaryText.Bold(true).Render(i18n.T("advanced_search_title")) + "\n\n"
+
+	labels := make([]string, advancedFieldCount)
+	labelWidth := 0
+	for f := advancedField(0); f < advancedFieldCount; f++ {
+		labels[f] = f.label()
+		if w := len([]rune(labels[f])); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	for f := advancedField(0); f < advancedFieldCount; f++ {
+		cursor := "  "
+		if f == m.focus {
+			cursor = "> "
+		}
+
+		label := labels[f] + strings.Repeat(" ", labelWidth-len([]rune(labels[f])))
+
+		var field string
+		switch f {
+		case advancedFieldHasGeoInfo:
+			field = m.renderOption(f, m.hasGeoInfo.Render())
+		case advancedFieldIsHttps:
+			field = m.renderOption(f, m.isHttps.Render())
+		case advancedFieldOrder:
+			field = m.renderOption(f, m.orders[m.orderIndex].Render())
+		default:
+			field = m.inputs[f].View()
+		}
+
+		v += m.theme.Text.Render(cursor+label+"  ") + field + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+
+	return v
+}
+
+// renderOption renders an option field value, with arrows when focused.
+func (m AdvancedSearchForm) renderOption(field advancedField, value string) string {
+	if field == m.focus {
+		return m.theme.SecondaryText.Render("< " + value + " >")
+	}
+	return m.theme.Text.Render("  " + value)
+}
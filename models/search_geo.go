@@ -0,0 +1,247 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// geoField identifies a field of the nearby search form.
+type geoField int
+
+const (
+	geoFieldPlace geoField = iota
+	geoFieldRadius
+	geoFieldCount
+)
+
+// label returns the localized label for the field.
+func (f geoField) label() string {
+	switch f {
+	case geoFieldPlace:
+		return i18n.T("geo_field_place")
+	case geoFieldRadius:
+		return i18n.T("geo_field_radius")
+	}
+	return ""
+}
+
+const (
+	// defaultGeoRadiusKm is the search radius used when the radius field is left empty.
+	defaultGeoRadiusKm = 50
+	// maxGeoRadiusKm is about half the Earth's circumference: anything larger covers the whole planet.
+	maxGeoRadiusKm = 20000
+)
+
+// GeoSearchForm builds a search for the stations around a place, given either as
+// a city from the built-in gazetteer or as coordinates, plus a radius.
+type GeoSearchForm struct {
+	theme Theme
+
+	inputs []textinput.Model
+	focus  geoField
+	err    string
+}
+
+func NewGeoSearchForm(theme Theme) GeoSearchForm {
+	inputs := make([]textinput.Model, geoFieldCount)
+	for i := range inputs {
+		input := textinput.New()
+		input.Width = 30
+		input.Prompt = ""
+		input.TextStyle = theme.Text
+		input.PlaceholderStyle = theme.TertiaryText
+		inputs[i] = input
+	}
+	inputs[geoFieldPlace].Placeholder = "Berlin / 52.52, 13.405"
+	inputs[geoFieldRadius].Placeholder = strconv.Itoa(defaultGeoRadiusKm)
+	inputs[geoFieldRadius].CharLimit = 5
+
+	form := GeoSearchForm{
+		theme:  theme,
+		inputs: inputs,
+	}
+	form.setFocus(geoFieldPlace)
+	return form
+}
+
+// setFocus moves focus to the given field.
+func (m *GeoSearchForm) setFocus(field geoField) {
+	m.focus = field
+	for i := range m.inputs {
+		if geoField(i) == field {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+// Blur removes focus from every text input (used when leaving nearby mode).
+func (m *GeoSearchForm) Blur() {
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+}
+
+// Focus restores focus to the current field.
+func (m *GeoSearchForm) Focus() {
+	m.setFocus(m.focus)
+}
+
+// resolvePlace turns the place field into a point: coordinates are used as typed,
+// anything else is looked up in the gazetteer (the best match wins).
+// ok is false if the field is empty or names no known city.
+func (m GeoSearchForm) resolvePlace() (label string, point common.GeoPoint, ok bool) {
+	text := strings.TrimSpace(m.inputs[geoFieldPlace].Value())
+	if text == "" {
+		return "", common.GeoPoint{}, false
+	}
+	if point, err := common.ParseGeoPoint(text); err == nil {
+		return point.String(), point, true
+	}
+	if cities := common.FindCities(text, 1); len(cities) > 0 {
+		return fmt.Sprintf("%s (%s)", cities[0], cities[0].Location), cities[0].Location, true
+	}
+	return "", common.GeoPoint{}, false
+}
+
+// Params builds the search parameters from the form.
+// Returns an error if the place can't be found or the radius is invalid.
+func (m GeoSearchForm) Params() (common.StationSearchParams, error) {
+	_, point, ok := m.resolvePlace()
+	if !ok {
+		return common.StationSearchParams{}, fmt.Errorf("%s", i18n.Tf("error_geo_place_unknown", map[string]interface{}{
+			"Value": strings.TrimSpace(m.inputs[geoFieldPlace].Value()),
+		}))
+	}
+
+	radius := uint64(defaultGeoRadiusKm)
+	if v := strings.TrimSpace(m.inputs[geoFieldRadius].Value()); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil || n == 0 || n > maxGeoRadiusKm {
+			return common.StationSearchParams{}, fmt.Errorf("%s", i18n.Tf("error_invalid_radius", map[string]interface{}{
+				"Value": v,
+				"Max":   maxGeoRadiusKm,
+			}))
+		}
+		radius = n
+	}
+
+	return common.StationSearchParams{
+		Near:        &point,
+		GeoDistance: radius * 1000,
+		HideBroken:  true,
+	}, nil
+}
+
+// SetParams fills in the form from a previously submitted search around a point.
+// The place is shown as a city name if the point is one from the gazetteer.
+func (m *GeoSearchForm) SetParams(params common.StationSearchParams) {
+	if params.Near == nil {
+		return
+	}
+	place := params.Near.String()
+	for _, city := range common.Cities() {
+		if city.Location == *params.Near {
+			place = city.String()
+			break
+		}
+	}
+	m.inputs[geoFieldPlace].SetValue(place)
+	if params.GeoDistance > 0 {
+		m.inputs[geoFieldRadius].SetValue(strconv.FormatUint(params.GeoDistance/1000, 10))
+	}
+}
+
+// SetError sets the validation error shown below the form.
+func (m *GeoSearchForm) SetError(err string) {
+	m.err = err
+}
+
+// Bubbletea
+
+func (m GeoSearchForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m GeoSearchForm) Update(msg tea.Msg) (GeoSearchForm, tea.Cmd) {
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "tab", "down":
+			m.setFocus((m.focus + 1) % geoFieldCount)
+			return m, nil
+		case "shift+tab", "up":
+			m.setFocus((m.focus + geoFieldCount - 1) % geoFieldCount)
+			return m, nil
+		}
+		m.err = ""
+	}
+
+	var cmd tea.Cmd
+	m.inputs[m.focus], cmd = m.inputs[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m GeoSearchForm) View() string {
+
+	v := m.theme.SecondaryText.Bold(true).Render(i18n.T("geo_search_title")) + "\n\n"
+
+	labels := make([]string, geoFieldCount)
+	labelWidth := 0
+	for f := geoField(0); f < geoFieldCount; f++ {
+		labels[f] = f.label()
+		if w := len([]rune(labels[f])); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	for f := geoField(0); f < geoFieldCount; f++ {
+		cursor := "  "
+		if f == m.focus {
+			cursor = "> "
+		}
+		label := labels[f] + strings.Repeat(" ", labelWidth-len([]rune(labels[f])))
+		v += m.theme.Text.Render(cursor+label+"  ") + m.inputs[f].View() + "\n"
+	}
+
+	// Show where the search will be centred while the place is typed
+	v += "\n"
+	if label, _, ok := m.resolvePlace(); ok {
+		v += m.theme.SecondaryText.Render(i18n.Tf("geo_place_resolved", map[string]interface{}{"Place": label})) + "\n"
+	} else {
+		v += m.theme.TertiaryText.Render(i18n.T("geo_place_hint")) + "\n"
+	}
+
+	if m.err != "" {
+		v += "\n" + m.theme.ErrorText.Render(m.err) + "\n"
+	}
+
+	return v
+}
@@ -20,32 +20,52 @@
 package models
 
 import (
+	"context"
 	"testing"
 
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/providers"
 
 	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 var testSearchKeybindings = config.Keybindings{
-	Quit:           "q",
-	Search:         "s",
-	Record:         "r",
-	BookmarkToggle: "b",
-	BookmarksView:  "B",
-	HideStation:    "h",
-	ManageHidden:   "H",
-	ChangeLanguage: "L",
-	VolumeDown:     "9",
-	VolumeUp:       "0",
-	NavigateDown:   "j",
-	NavigateUp:     "k",
-	StopPlayback:   "ctrl+k",
+	Quit:            "q",
+	Search:          "s",
+	Record:          "r",
+	BookmarkToggle:  "b",
+	BookmarksView:   "B",
+	HideStation:     "h",
+	ManageHidden:    "H",
+	ChangeLanguage:  "L",
+	VolumeDown:      "9",
+	VolumeUp:        "0",
+	NavigateDown:    "j",
+	NavigateUp:      "k",
+	StopPlayback:    "ctrl+k",
+	AdvancedSearch:  "ctrl+t",
+	Browse:          "ctrl+g",
+	Discover:        "ctrl+o",
+	NearbySearch:    "alt+n",
+	CustomStations:  "C",
+	Source:          "alt+s",
+	CheckHealth:     "c",
+	Pause:           "p",
+	History:         "ctrl+y",
+	ExportCSV:       "ctrl+x",
+	ExportJSON:      "ctrl+j",
+	JumpToLive:      "l",
+	SleepTimer:      "z",
+	SleepTimerInput: "Z",
+	Alarms:          "alt+a",
+	Recordings:      "alt+r",
 }
 
 func TestSearchModel_Init(t *testing.T) {
@@ -140,6 +160,57 @@ func TestSearchModel_Update(t *testing.T) {
 
 	})
 
+	t.Run("opens the alarms with the alarms key", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, &mocks.MockStationStorageService{}, testSearchKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Alt: true})
+
+		assert.IsType(t, switchToAlarmsModelMsg{}, cmd())
+
+	})
+
+	t.Run("ctrl+f moves the cursor in the query rather than leaving the search", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, &mocks.MockStationStorageService{}, testSearchKeybindings)
+		model.inputModel.Focus()
+		model.inputModel.SetValue("jazz")
+		model.inputModel.CursorStart()
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+
+		assert.Equal(t, 1, newModel.(SearchModel).inputModel.Position())
+		if cmd != nil {
+			assert.NotEqual(t, switchToAlarmsModelMsg{}, cmd())
+		}
+
+	})
+
+	t.Run("opens the scheduled recordings with the recordings key", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, &mocks.MockStationStorageService{}, testSearchKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r"), Alt: true})
+
+		assert.IsType(t, switchToRecordingsModelMsg{}, cmd())
+
+	})
+
+	t.Run("ctrl+b moves the cursor in the query rather than leaving the search", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, &mocks.MockStationStorageService{}, testSearchKeybindings)
+		model.inputModel.Focus()
+		model.inputModel.SetValue("jazz")
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+
+		assert.Equal(t, 3, newModel.(SearchModel).inputModel.Position())
+		if cmd != nil {
+			assert.NotEqual(t, switchToRecordingsModelMsg{}, cmd())
+		}
+
+	})
+
 	t.Run("broadcasts a switchToLoadingModelMsg when 'enter' is pressed, propagating text area value", func(t *testing.T) {
 
 		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
@@ -150,15 +221,62 @@ func TestSearchModel_Update(t *testing.T) {
 		_, cmd := model.Update(input)
 		assert.NotNil(t, cmd)
 
-		msg := cmd()
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(switchToLoadingModelMsg)
+			return ok
+		})
 
 		assert.Equal(t, switchToLoadingModelMsg{
 			query:     common.StationQueryByName,
 			queryText: "fancy value",
+			order:     common.StationOrderVotes,
+			reverse:   true,
 		}, msg)
 
 	})
 
+	t.Run("searches in the selected sort order and remembers it", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.inputModel.SetValue("jazz")
+		model.orderSelector.SetSelection(3) // name
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(switchToLoadingModelMsg)
+			return ok
+		})
+		assert.Equal(t, common.StationOrderName, msg.(switchToLoadingModelMsg).order)
+		assert.False(t, msg.(switchToLoadingModelMsg).reverse)
+
+		saved := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(sortOrderChangedMsg)
+			return ok
+		})
+		assert.Equal(t, sortOrderChangedMsg{order: common.StationOrderName, reverse: false}, saved)
+
+	})
+
+	t.Run("keeps the remembered direction while its order stays selected", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.SetOrder(common.StationOrderBitrate, false)
+		model.inputModel.SetValue("jazz")
+
+		assert.Equal(t, common.StationOrderBitrate, model.orderSelector.Selection())
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(switchToLoadingModelMsg)
+			return ok
+		})
+		assert.Equal(t, common.StationOrderBitrate, msg.(switchToLoadingModelMsg).order)
+		assert.False(t, msg.(switchToLoadingModelMsg).reverse)
+
+	})
+
 	t.Run("ignores 'enter' when is not in focus", func(t *testing.T) {
 
 		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
@@ -189,12 +307,64 @@ func TestSearchModel_Update(t *testing.T) {
 
 		newModel, cmd = newModel.Update(input)
 
-		assert.Equal(t, newModel.(SearchModel).inputModel.Focused(), true)
 		assert.Equal(t, newModel.(SearchModel).querySelector.Focused(), false)
+		assert.Equal(t, newModel.(SearchModel).orderSelector.Focused(), true)
 
 		msg = cmd()
 		assert.IsType(t, bottomBarUpdateMsg{}, msg)
 
+		newModel, cmd = newModel.Update(input)
+
+		assert.Equal(t, newModel.(SearchModel).inputModel.Focused(), true)
+		assert.Equal(t, newModel.(SearchModel).orderSelector.Focused(), false)
+
+		msg = cmd()
+		assert.IsType(t, bottomBarUpdateMsg{}, msg)
+
+	})
+
+	t.Run("opens the browse screen when the browse key is pressed", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, switchToBrowseModelMsg{}, cmd())
+
+	})
+
+	t.Run("opens the discover screen when the discover key is pressed", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, switchToDiscoverModelMsg{}, cmd())
+
+	})
+
+	t.Run("opens the custom stations unless the text input is focused", func(t *testing.T) {
+
+		stations := []common.Station{{StationUuid: uuid.New(), Name: "Team Radio"}}
+		storage := &mocks.MockStationStorageService{
+			GetCustomStationsFunc: func() ([]common.Station, error) {
+				return stations, nil
+			},
+		}
+		model := NewSearchModel(Theme{}, nil, storage, testSearchKeybindings)
+		key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")}
+
+		newModel, _ := model.Update(key)
+		assert.Equal(t, "C", newModel.(SearchModel).inputModel.Value())
+
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+		_, cmd := newModel.Update(key)
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, switchToCustomStationsMsg{stations: stations}, cmd())
+
 	})
 
 }
@@ -202,7 +372,7 @@ func TestUpdateSearchCommandsCmd(t *testing.T) {
 	t.Run("textfield focused shows search command", func(t *testing.T) {
 		expectedCommands := []string{"q: quit", "tab: cycle focus", "enter: search", "B: bookmarks", "L: language", "EN"}
 
-		cmd := updateSearchCommandsCmd(testSearchKeybindings, true)
+		cmd := updateSearchCommandsCmd(testSearchKeybindings, searchFocusInput)
 		msg := cmd()
 
 		updateMsg, ok := msg.(bottomBarUpdateMsg)
@@ -214,7 +384,19 @@ func TestUpdateSearchCommandsCmd(t *testing.T) {
 	t.Run("selector focused shows filter command", func(t *testing.T) {
 		expectedCommands := []string{"q: quit", "tab: cycle focus", "↑/↓: change filter", "B: bookmarks", "L: language", "EN"}
 
-		cmd := updateSearchCommandsCmd(testSearchKeybindings, false)
+		cmd := updateSearchCommandsCmd(testSearchKeybindings, searchFocusQuery)
+		msg := cmd()
+
+		updateMsg, ok := msg.(bottomBarUpdateMsg)
+
+		assert.True(t, ok)
+		assert.Equal(t, expectedCommands, updateMsg.commands)
+	})
+
+	t.Run("order selector focused shows order command", func(t *testing.T) {
+		expectedCommands := []string{"q: quit", "tab: cycle focus", "↑/↓: change order", "B: bookmarks", "L: language", "EN"}
+
+		cmd := updateSearchCommandsCmd(testSearchKeybindings, searchFocusOrder)
 		msg := cmd()
 
 		updateMsg, ok := msg.(bottomBarUpdateMsg)
@@ -314,3 +496,422 @@ func TestSearchModel_LanguageChange(t *testing.T) {
 		}
 	})
 }
+
+func TestSearchModel_Mirror(t *testing.T) {
+
+	t.Run("resolves the API mirror on init", func(t *testing.T) {
+
+		browser := mocks.MockRadioBrowserService{
+			CurrentMirrorFunc: func(ctx context.Context) string { return "de1.api.radio-browser.info" },
+		}
+
+		model := NewSearchModel(Theme{}, &browser, nil, testSearchKeybindings)
+
+		cmd := model.Init()
+		assert.NotNil(t, cmd)
+
+		var batchMsg tea.BatchMsg = cmd().(tea.BatchMsg)
+
+		var resolved *mirrorResolvedMsg
+		for _, msg := range batchMsg {
+			if m, ok := msg().(mirrorResolvedMsg); ok {
+				resolved = &m
+				break
+			}
+		}
+
+		assert.NotNil(t, resolved)
+		assert.Equal(t, "de1.api.radio-browser.info", resolved.host)
+
+	})
+
+	t.Run("shows the resolved mirror in the view", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		assert.NotContains(t, model.View(), "Mirror:")
+
+		newModel, cmd := model.Update(mirrorResolvedMsg{host: "de1.api.radio-browser.info"})
+		assert.Nil(t, cmd)
+		assert.Contains(t, newModel.View(), "Mirror: de1.api.radio-browser.info")
+
+	})
+
+}
+
+func TestSearchModel_AdvancedSearch(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	toggle := tea.KeyMsg{Type: tea.KeyCtrlT}
+
+	typeText := func(model tea.Model, text string) tea.Model {
+		for _, r := range text {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return model
+	}
+
+	t.Run("toggles the advanced form and updates the bottom bar", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+
+		newModel, cmd := model.Update(toggle)
+		assert.True(t, newModel.(SearchModel).advanced)
+		assert.False(t, newModel.(SearchModel).inputModel.Focused())
+		assert.NotNil(t, cmd)
+		assert.Contains(t, newModel.View(), "Advanced search")
+
+		var found bool
+		for _, c := range cmd().(tea.BatchMsg) {
+			if msg, ok := c().(bottomBarUpdateMsg); ok {
+				found = true
+				assert.Equal(t, []string{"ctrl+t: simple search", "alt+n: near me", "ctrl+g: browse", "ctrl+o: discover", "ctrl+y: history", "alt+a: alarms", "alt+r: recordings", "C: my stations"}, msg.secondaryCommands)
+			}
+		}
+		assert.True(t, found)
+
+		newModel, _ = newModel.Update(toggle)
+		assert.False(t, newModel.(SearchModel).advanced)
+		assert.True(t, newModel.(SearchModel).inputModel.Focused())
+
+	})
+
+	t.Run("does not quit when typing into a text field", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+
+		newModel, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		if cmd != nil {
+			assert.NotEqual(t, quitMsg{}, cmd())
+		}
+		assert.Equal(t, "q", newModel.(SearchModel).advancedForm.inputs[advancedFieldName].Value())
+
+	})
+
+	t.Run("quits when an option field is focused", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+
+		// shift+tab wraps around to the last field (order), an option field
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+		assert.False(t, newModel.(SearchModel).advancedForm.TextFieldFocused())
+
+		_, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		assert.NotNil(t, cmd)
+		assert.IsType(t, quitMsg{}, cmd())
+
+	})
+
+	t.Run("submits the form as a switchToLoadingModelMsg with advanced params", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		var newModel tea.Model
+		newModel, _ = model.Update(toggle)
+
+		next := tea.KeyMsg{Type: tea.KeyTab}
+
+		newModel, _ = newModel.Update(next) // tags
+		newModel = typeText(newModel, "jazz, smooth")
+		newModel, _ = newModel.Update(next) // country code
+		newModel = typeText(newModel, "de")
+		newModel, _ = newModel.Update(next) // state
+		newModel, _ = newModel.Update(next) // language
+		newModel, _ = newModel.Update(next) // codec
+		newModel = typeText(newModel, "AAC")
+		newModel, _ = newModel.Update(next) // bitrate min
+		newModel = typeText(newModel, "128")
+		newModel, _ = newModel.Update(next) // bitrate max
+		newModel, _ = newModel.Update(next) // has geo info
+		newModel, _ = newModel.Update(next) // is https
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyRight})
+
+		_, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.NotNil(t, cmd)
+
+		msg, ok := cmd().(switchToLoadingModelMsg)
+		assert.True(t, ok)
+		assert.NotNil(t, msg.advancedParams)
+
+		isHttps := true
+		assert.Equal(t, common.StationSearchParams{
+			TagList:     []string{"jazz", "smooth"},
+			CountryCode: "DE",
+			Codec:       "AAC",
+			BitrateMin:  128,
+			IsHttps:     &isHttps,
+			Order:       common.StationOrderVotes,
+			Reverse:     true,
+			HideBroken:  true,
+		}, *msg.advancedParams)
+
+	})
+
+	t.Run("shows an error instead of searching when the bitrate is invalid", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		var newModel tea.Model
+		newModel, _ = model.Update(toggle)
+
+		for i := 0; i < int(advancedFieldBitrateMin); i++ {
+			newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+		}
+		newModel = typeText(newModel, "fast")
+
+		newModel, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Nil(t, cmd)
+		assert.Contains(t, newModel.View(), "Invalid bitrate \"fast\"")
+
+	})
+
+	t.Run("restores previously submitted advanced params", func(t *testing.T) {
+
+		yes := true
+		params := common.StationSearchParams{
+			Name:       "radio",
+			TagList:    []string{"jazz", "smooth"},
+			Codec:      "AAC",
+			BitrateMin: 128,
+			IsHttps:    &yes,
+			Order:      common.StationOrderBitrate,
+			Reverse:    true,
+			HideBroken: true,
+		}
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.RestoreQuery(common.StationQueryAll, "", &params)
+
+		assert.True(t, model.advanced)
+
+		restored, err := model.advancedForm.Params()
+		assert.NoError(t, err)
+		assert.Equal(t, params, restored)
+
+	})
+
+}
+
+func TestSearchModel_NearbySearch(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	toggle := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true}
+
+	typeText := func(model tea.Model, text string) tea.Model {
+		for _, r := range text {
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return model
+	}
+
+	submit := func(model tea.Model) (tea.Model, tea.Cmd) {
+		return model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	t.Run("toggles the nearby form and updates the bottom bar", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+
+		newModel, cmd := model.Update(toggle)
+		assert.True(t, newModel.(SearchModel).geo)
+		assert.False(t, newModel.(SearchModel).inputModel.Focused())
+		assert.NotNil(t, cmd)
+		assert.Contains(t, newModel.View(), "Stations near a place")
+
+		var found bool
+		for _, c := range cmd().(tea.BatchMsg) {
+			if msg, ok := c().(bottomBarUpdateMsg); ok {
+				found = true
+				assert.Equal(t, []string{"tab/↑/↓: move", "enter: search", "EN"}, msg.commands)
+				assert.Equal(t, []string{"alt+n: simple search", "ctrl+t: advanced search", "ctrl+g: browse", "ctrl+o: discover", "ctrl+y: history", "alt+a: alarms", "alt+r: recordings", "C: my stations"}, msg.secondaryCommands)
+			}
+		}
+		assert.True(t, found)
+
+		newModel, _ = newModel.Update(toggle)
+		assert.False(t, newModel.(SearchModel).geo)
+		assert.True(t, newModel.(SearchModel).inputModel.Focused())
+
+	})
+
+	t.Run("switches straight between the advanced and nearby forms", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		newModel, _ = newModel.Update(toggle)
+		assert.True(t, newModel.(SearchModel).geo)
+		assert.False(t, newModel.(SearchModel).advanced)
+
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		assert.True(t, newModel.(SearchModel).advanced)
+		assert.False(t, newModel.(SearchModel).geo)
+
+	})
+
+	t.Run("does not quit when typing a place", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+
+		newModel, cmd := newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		if cmd != nil {
+			assert.NotEqual(t, quitMsg{}, cmd())
+		}
+		assert.Equal(t, "q", newModel.(SearchModel).geoForm.inputs[geoFieldPlace].Value())
+
+	})
+
+	t.Run("searches around a city with the default radius", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+		newModel = typeText(newModel, "berl")
+
+		assert.Contains(t, newModel.View(), "Searching around Berlin, DE")
+
+		_, cmd := submit(newModel)
+		assert.NotNil(t, cmd)
+
+		msg, ok := cmd().(switchToLoadingModelMsg)
+		assert.True(t, ok)
+		assert.NotNil(t, msg.advancedParams)
+		assert.NotNil(t, msg.advancedParams.Near)
+		assert.InDelta(t, 52.52, msg.advancedParams.Near.Lat, 0.01)
+		assert.InDelta(t, 13.405, msg.advancedParams.Near.Long, 0.01)
+		assert.Equal(t, uint64(50000), msg.advancedParams.GeoDistance)
+		assert.True(t, msg.advancedParams.HideBroken)
+
+	})
+
+	t.Run("searches around typed coordinates with a custom radius", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+		newModel = typeText(newModel, "45.5, -73.6")
+		newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+		newModel = typeText(newModel, "120")
+
+		_, cmd := submit(newModel)
+		assert.NotNil(t, cmd)
+
+		msg, ok := cmd().(switchToLoadingModelMsg)
+		assert.True(t, ok)
+		assert.Equal(t, common.StationSearchParams{
+			Near:        &common.GeoPoint{Lat: 45.5, Long: -73.6},
+			GeoDistance: 120000,
+			HideBroken:  true,
+		}, *msg.advancedParams)
+
+	})
+
+	t.Run("shows an error instead of searching when the place is unknown", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		newModel, _ := model.Update(toggle)
+		newModel = typeText(newModel, "Atlantis")
+
+		newModel, cmd := submit(newModel)
+		assert.Nil(t, cmd)
+		assert.Contains(t, newModel.View(), "Unknown place \"Atlantis\"")
+
+	})
+
+	t.Run("shows an error instead of searching when the radius is invalid", func(t *testing.T) {
+
+		for _, radius := range []string{"0", "far", "99999"} {
+			model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+			newModel, _ := model.Update(toggle)
+			newModel = typeText(newModel, "Paris")
+			newModel, _ = newModel.Update(tea.KeyMsg{Type: tea.KeyTab})
+			newModel = typeText(newModel, radius)
+
+			newModel, cmd := submit(newModel)
+			assert.Nil(t, cmd, radius)
+			assert.Contains(t, newModel.View(), "Invalid radius \""+radius+"\"")
+		}
+
+	})
+
+	t.Run("restores a previous search around a city", func(t *testing.T) {
+
+		tokyo := common.FindCities("Tokyo", 1)[0]
+		params := common.StationSearchParams{
+			Near:        &tokyo.Location,
+			GeoDistance: 25000,
+			HideBroken:  true,
+		}
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.RestoreQuery(common.StationQueryAll, "", &params)
+
+		assert.True(t, model.geo)
+		assert.False(t, model.advanced)
+		assert.Equal(t, "Tokyo, JP", model.geoForm.inputs[geoFieldPlace].Value())
+		assert.Equal(t, "25", model.geoForm.inputs[geoFieldRadius].Value())
+
+		restored, err := model.geoForm.Params()
+		assert.NoError(t, err)
+		assert.Equal(t, params, restored)
+
+	})
+
+}
+
+func TestSearchModel_Sources(t *testing.T) {
+
+	radioBrowser := providers.NewRadioBrowserProvider(&mocks.MockRadioBrowserService{})
+	playlists := providers.NewPlaylistDirProvider(t.TempDir())
+	sources := []providers.StationProvider{radioBrowser, playlists}
+
+	t.Run("does not offer a source when only RadioBrowser is available", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.SetProviders(sources[:1], common.StationSourceRadioBrowser)
+
+		assert.NotContains(t, model.View(), "alt+s to change")
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+		assert.Equal(t, 0, newModel.(SearchModel).providerIndex)
+
+	})
+
+	t.Run("cycles the source and searches there", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.SetProviders(sources, common.StationSourceRadioBrowser)
+		model.inputModel.SetValue("jazz")
+
+		assert.Contains(t, model.View(), "Source: RadioBrowser (alt+s to change)")
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+		model = newModel.(SearchModel)
+		assert.Contains(t, model.View(), "Source: Playlists")
+		assert.Equal(t, "jazz", model.inputModel.Value())
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(switchToLoadingModelMsg)
+			return ok
+		})
+		assert.Equal(t, playlists, msg.(switchToLoadingModelMsg).provider)
+
+		newModel, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s"), Alt: true})
+		assert.Contains(t, newModel.View(), "Source: RadioBrowser")
+
+	})
+
+	t.Run("selects the previously used source", func(t *testing.T) {
+
+		model := NewSearchModel(Theme{}, nil, nil, testSearchKeybindings)
+		model.SetProviders(sources, common.StationSourcePlaylists)
+
+		assert.Equal(t, playlists, model.selectedProvider())
+
+		model.SetProviders(sources, common.StationSourceIcecast)
+		assert.Equal(t, radioBrowser, model.selectedProvider())
+
+	})
+
+}
@@ -53,6 +53,13 @@ func (m SelectorModel[T]) Selection() T {
 	return m.items[m.selection]
 }
 
+// SetSelection selects the item at the given index, ignoring out-of-range indexes.
+func (m *SelectorModel[T]) SetSelection(index int) {
+	if index >= 0 && index < len(m.items) {
+		m.selection = index
+	}
+}
+
 // Focus
 
 func (m SelectorModel[T]) Focused() bool {
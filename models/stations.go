@@ -23,17 +23,22 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/health"
 	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
 	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/providers"
 	"github.com/zi0p4tch0/radiogogo/storage"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -43,6 +48,15 @@ type stationsViewMode int
 const (
 	viewModeSearchResults stationsViewMode = iota
 	viewModeBookmarks
+	viewModeCustom
+)
+
+const (
+	// stationNameWidth is the width of the name column when the terminal is wide enough.
+	stationNameWidth = 35
+	// minStationNameWidth is how narrow the name column gets to fit the optional columns
+	// (local check, source, distance) in the smallest supported terminal.
+	minStationNameWidth = 16
 )
 
 // formatNumber formats large numbers with K (thousands) or M (millions) suffix.
@@ -58,6 +72,18 @@ func formatNumber(n uint64) string {
 	return strconv.FormatUint(n, 10)
 }
 
+// formatDistance formats a distance in kilometers for the distance column.
+// Examples: 0.4 → "<1 km", 7.26 → "7.3 km", 1234 → "1234 km"
+func formatDistance(km float64) string {
+	switch {
+	case km < 1:
+		return "<1 km"
+	case km < 10:
+		return fmt.Sprintf("%.1f km", km)
+	}
+	return fmt.Sprintf("%.0f km", km)
+}
+
 // min returns the smaller of two integers.
 func min(a, b int) int {
 	if a < b {
@@ -112,6 +138,30 @@ type StationsModel struct {
 	// Last search query for refetching
 	lastQuery     common.StationQuery
 	lastQueryText string
+	// Last advanced search, if the results came from one (takes precedence over lastQuery)
+	lastSearchParams *common.StationSearchParams
+	// Ready-made list the results came from, if any (takes precedence over the searches above)
+	lastList common.StationList
+	// Where lastQuery was searched (nil means RadioBrowser)
+	provider providers.StationProvider
+
+	// Pagination of search results
+	pageSize     int
+	fetchedCount uint64 // results fetched from the API so far, including hidden ones
+	hasMorePages bool
+	loadingMore  bool
+
+	// Sort order of search results
+	sortOrder   common.StationOrder
+	sortReverse bool
+
+	// Point the results were searched around, if any (adds a distance column)
+	origin *common.GeoPoint
+
+	// Custom station form (add/edit) and the station waiting for delete confirmation
+	showCustomForm  bool
+	customForm      CustomStationForm
+	deleteCandidate common.Station
 
 	browser         api.RadioBrowserService
 	playbackManager playback.PlaybackManagerService
@@ -120,6 +170,49 @@ type StationsModel struct {
 
 	// Temporary success message (shown in green)
 	successMsg string
+
+	// Local stream health checks (disabled without a checker)
+	healthChecker  *health.Checker
+	checkingHealth bool
+
+	// Song titles read from the playing stream (disabled without a watcher)
+	songTitleWatcher *icy.Watcher
+	songTitle        string
+
+	// The URL the playing station is actually streamed from, after following
+	// redirects and playlists (empty when the playback manager doesn't resolve URLs)
+	streamURL string
+
+	// Reconnection after the player stops by itself (0 attempts disables it)
+	maxReconnectAttempts int
+	reconnectAttempt     int
+	reconnecting         bool
+	playingSince         time.Time // When the player last started, zero while reconnecting
+	resumeRecording      bool      // Whether to record again once reconnected, playing again stops the recording
+
+	// Where recordings are saved (the current directory by default)
+	recordingLocation playback.RecordingLocation
+
+	// Timeshift buffer of the playing station, refreshed while playback is behind live
+	timeshift        playback.TimeshiftState
+	timeshiftRefresh int
+
+	// Sleep timer: playback fades out over its last minute and stops at sleepAt
+	sleepAt         time.Time     // Zero while the timer is off
+	sleepDuration   time.Duration // What the timer was last set to, for cycling through presets
+	sleepTimer      int64         // Identifies the running countdown, older ticks are dropped
+	sleepFading     bool
+	sleepFadeVolume int // Volume the fade last set, while sleepFading
+	showSleepInput  bool
+	sleepInput      textinput.Model
+
+	// Alarm ramp-up: the volume rises to volume over alarmRampUp from alarmRampStart
+	alarmRamp       int64 // Identifies the running ramp-up, older ticks are dropped
+	alarmRamping    bool
+	alarmRampStart  time.Time
+	alarmRampUp     time.Duration
+	alarmRampVolume int       // Volume the ramp-up last set, while alarmRamping
+	alarmStation    uuid.UUID // Station the ramp-up is for
 }
 
 // NewStationsModel creates a new StationsModel with the given dependencies and stations.
@@ -142,7 +235,7 @@ func NewStationsModel(
 		theme:           theme,
 		keybindings:     keybindings,
 		stations:        stations,
-		stationsTable:   newStationsTableModel(theme, stations, storage, currentStation),
+		stationsTable:   newStationsTableModel(theme, stations, storage, currentStation, nil),
 		volume:          playbackManager.VolumeDefault(),
 		viewMode:        viewMode,
 		storage:         storage,
@@ -150,10 +243,153 @@ func NewStationsModel(
 		playbackManager: playbackManager,
 		lastQuery:       lastQuery,
 		lastQueryText:   lastQueryText,
+		pageSize:        config.NewDefaultSearchPreferences().PageSize,
+		sortOrder:       common.StationOrderVotes,
+		sortReverse:     true,
+	}
+}
+
+// SetSort records the order the search results were fetched in, so that
+// refetches and further pages keep it. Unknown orders are ignored.
+func (m *StationsModel) SetSort(order common.StationOrder, reverse bool) {
+	if !order.IsValid() {
+		return
+	}
+	m.sortOrder = order
+	m.sortReverse = reverse
+}
+
+// SetProvider records where the search results were found, so that refetches,
+// further pages and new sort orders search there again.
+func (m *StationsModel) SetProvider(provider providers.StationProvider) {
+	m.provider = provider
+}
+
+// SetHealthChecker enables checking the listed streams from this machine.
+func (m *StationsModel) SetHealthChecker(checker *health.Checker) {
+	m.healthChecker = checker
+}
+
+// SetSongTitleWatcher enables showing the song titles sent by the playing station.
+func (m *StationsModel) SetSongTitleWatcher(watcher *icy.Watcher) {
+	m.songTitleWatcher = watcher
+}
+
+// SetReconnectAttempts sets how many times playback is restarted when the player
+// stops by itself. 0 disables reconnecting.
+func (m *StationsModel) SetReconnectAttempts(attempts int) {
+	m.maxReconnectAttempts = attempts
+}
+
+// SetRecordingLocation sets where recordings are saved, and how they're named.
+func (m *StationsModel) SetRecordingLocation(location playback.RecordingLocation) {
+	m.recordingLocation = location
+}
+
+// SetOrigin marks the results as searched around a point: a distance column is
+// shown, and since they're already sorted nearest-first they can't be re-sorted or paged.
+func (m *StationsModel) SetOrigin(origin common.GeoPoint) {
+	m.origin = &origin
+	m.hasMorePages = false
+	m.rebuildTablePreservingCursor(-1)
+}
+
+// canResort reports whether the results can be re-queried in another sort order.
+// Ready-made lists and searches around a point come in a fixed order.
+func (m StationsModel) canResort() bool {
+	return m.lastList == "" && m.origin == nil
+}
+
+// sortLabel describes the current sort order for the bottom bar, e.g. "Votes ↓".
+// Results in a fixed order have no label.
+func (m StationsModel) sortLabel() string {
+	if !m.canResort() {
+		return ""
+	}
+	if m.sortReverse {
+		return m.sortOrder.Render() + " ↓"
 	}
+	return m.sortOrder.Render() + " ↑"
 }
 
-func newStationsTableModel(theme Theme, stations []common.Station, storage storage.StationStorageService, currentStation common.Station) table.Model {
+// EnablePaging lets the model fetch further pages of search results as the cursor
+// nears the end of the list. fetched is the number of results in the first page,
+// before hidden stations were filtered out; a short first page means there is nothing more.
+func (m *StationsModel) EnablePaging(pageSize int, fetched int) {
+	m.pageSize = pageSize
+	m.fetchedCount = uint64(fetched)
+	m.hasMorePages = pageSize > 0 && fetched >= pageSize
+}
+
+// formatAge formats how long ago something happened for the local status column.
+// Examples: 30s → "now", 5m, 3h, 2d
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return i18n.T("age_now")
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// formatTimeshiftBehind formats how far behind live playback is: as a duration
+// when the station's bitrate (in kbps) is known, otherwise as an amount of data.
+func formatTimeshiftBehind(behind int64, bitrate uint64) string {
+	if bitrate == 0 {
+		if behind < 1024*1024 {
+			return fmt.Sprintf("%d KB", behind/1024)
+		}
+		return fmt.Sprintf("%.1f MB", float64(behind)/(1024*1024))
+	}
+	return formatCountdown(time.Duration(behind*8/int64(bitrate*1000)) * time.Second)
+}
+
+// formatSongTitle formats a stream title for display: the "Artist - Title"
+// separator used by most stations becomes an en dash.
+// Example: "Daft Punk - One More Time" → "Daft Punk – One More Time"
+func formatSongTitle(title string) string {
+	return strings.Replace(title, " - ", " – ", 1)
+}
+
+// windowTitle returns the terminal title while station plays song (if known).
+func windowTitle(station common.Station, song string) string {
+	if song == "" {
+		return "radiogogo"
+	}
+	return formatSongTitle(song) + " · " + station.Name
+}
+
+// formatLocalStatus formats the result of a local health check, e.g. "✓ 5m".
+func formatLocalStatus(result health.Result, now time.Time) string {
+	mark := "✗"
+	if result.OK() {
+		mark = "✓"
+	}
+	return mark + " " + formatAge(now.Sub(result.CheckedAt))
+}
+
+// newStationsTableModel builds the stations table. If origin is set, a column shows
+// how far each station is from it. Lists with stations found elsewhere than on
+// RadioBrowser get a column showing where each station comes from, and lists with
+// stations checked from this machine a column with the latest local check.
+func newStationsTableModel(theme Theme, stations []common.Station, storage storage.StationStorageService, currentStation common.Station, origin *common.GeoPoint) table.Model {
+
+	showSource := false
+	showLocal := false
+	for _, station := range stations {
+		if station.Source != common.StationSourceRadioBrowser {
+			showSource = true
+		}
+		if storage != nil {
+			if _, ok := storage.GetHealthResult(station.StationUuid); ok {
+				showLocal = true
+			}
+		}
+	}
+	now := time.Now()
 
 	rows := make([]table.Row, len(stations))
 	for i, station := range stations {
@@ -204,25 +440,62 @@ func newStationsTableModel(theme Theme, stations []common.Station, storage stora
 			status = "✓"
 		}
 
+		clicks := formatNumber(uint64(station.ClickCount))
+		votes := formatNumber(uint64(station.Votes))
+
+		// Custom stations and stations from other sources aren't on RadioBrowser: no clicks, votes or checks
+		if station.Source != common.StationSourceRadioBrowser || storage != nil && storage.IsCustomStation(station.StationUuid) {
+			clicks, votes, status = "—", "—", "—"
+		}
+
 		rows[i] = table.Row{
 			name,
 			station.CountryCode,
 			quality,
-			formatNumber(uint64(station.ClickCount)),
-			formatNumber(uint64(station.Votes)),
+			clicks,
+			votes,
 			status,
 		}
+
+		if showLocal {
+			local := "—"
+			if result, ok := storage.GetHealthResult(station.StationUuid); ok {
+				local = formatLocalStatus(result, now)
+			}
+			rows[i] = append(rows[i], local)
+		}
+		if showSource {
+			rows[i] = append(rows[i], station.Source.Render())
+		}
+		if origin != nil {
+			distance := "—"
+			if location, ok := station.Location(); ok {
+				distance = formatDistance(origin.DistanceKm(location))
+			}
+			rows[i] = append(rows[i], distance)
+		}
+	}
+
+	columns := []table.Column{
+		{Title: i18n.T("header_name"), Width: stationNameWidth},
+		{Title: i18n.T("header_country"), Width: 10},
+		{Title: i18n.T("header_quality"), Width: 12},
+		{Title: i18n.T("header_clicks"), Width: 10},
+		{Title: i18n.T("header_votes"), Width: 8},
+		{Title: i18n.T("header_status"), Width: 6},
+	}
+	if showLocal {
+		columns = append(columns, table.Column{Title: i18n.T("header_local"), Width: 8})
+	}
+	if showSource {
+		columns = append(columns, table.Column{Title: i18n.T("header_source"), Width: 14})
+	}
+	if origin != nil {
+		columns = append(columns, table.Column{Title: i18n.T("header_distance"), Width: 10})
 	}
 
 	t := table.New(
-		table.WithColumns([]table.Column{
-			{Title: i18n.T("header_name"), Width: 35},
-			{Title: i18n.T("header_country"), Width: 10},
-			{Title: i18n.T("header_quality"), Width: 12},
-			{Title: i18n.T("header_clicks"), Width: 10},
-			{Title: i18n.T("header_votes"), Width: 8},
-			{Title: i18n.T("header_status"), Width: 6},
-		}),
+		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
 	)
@@ -236,13 +509,8 @@ func newStationsTableModel(theme Theme, stations []common.Station, storage stora
 // Init initializes the StationsModel and returns the initial command.
 func (m StationsModel) Init() tea.Cmd {
 	return tea.Batch(
-		updateCommandsCmd(m.viewMode, false, m.volume, m.playbackManager.VolumeIsPercentage(), false, m.keybindings),
-		func() tea.Msg {
-			return stationCursorMovedMsg{
-				offset:        m.stationsTable.Cursor(),
-				totalStations: len(m.stations),
-			}
-		},
+		m.updateCommandsCmd(),
+		m.cursorMovedCmd(),
 	)
 }
 
@@ -276,10 +544,34 @@ func (m StationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return newM, cmd
 	}
 
+	if handled, newM, cmd := m.handleCustomStationMessages(msg); handled {
+		return newM, cmd
+	}
+
 	if handled, newM, cmd := m.handleVoteMessages(msg); handled {
 		return newM, cmd
 	}
 
+	if handled, newM, cmd := m.handlePaginationMessages(msg); handled {
+		return newM, cmd
+	}
+
+	if handled, newM, cmd := m.handleSortMessages(msg); handled {
+		return newM, cmd
+	}
+
+	if handled, newM, cmd := m.handleHealthMessages(msg); handled {
+		return newM, cmd
+	}
+
+	if handled, newM, cmd := m.handleSleepTimerMessages(msg); handled {
+		return newM, cmd
+	}
+
+	if handled, newM, cmd := m.handleAlarmMessages(msg); handled {
+		return newM, cmd
+	}
+
 	// Handle key messages
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		handled, newM, cmd := m.handleKeyMessage(keyMsg)
@@ -293,6 +585,18 @@ func (m StationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m = newM
 	}
 
+	// Keep the custom station form's cursor blinking
+	if _, ok := msg.(tea.KeyMsg); !ok && m.showCustomForm {
+		newForm, cmd := m.customForm.Update(msg)
+		m.customForm = newForm
+		cmds = append(cmds, cmd)
+	}
+	if _, ok := msg.(tea.KeyMsg); !ok && m.showSleepInput {
+		newInput, cmd := m.sleepInput.Update(msg)
+		m.sleepInput = newInput
+		cmds = append(cmds, cmd)
+	}
+
 	// Update spinner if playing
 	if m.playbackManager.IsPlaying() {
 		newSpinner, cmd := m.currentStationSpinner.Update(msg)
@@ -305,6 +609,11 @@ func (m StationsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.stationsTable = newStationsTable
 	cmds = append(cmds, cmd)
 
+	// Fetch the next page of results once the cursor nears the end of the list
+	if _, ok := msg.(tea.KeyMsg); ok && !m.IsModalShowing() {
+		cmds = append(cmds, m.loadMoreIfNeeded())
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -335,6 +644,29 @@ func (m StationsModel) renderNowPlayingBox() string {
 
 	line1 := strings.Join(line1Parts, " • ")
 
+	// Song line: ♪ Artist – Title (when the stream sends titles)
+	var songLine string
+	if m.songTitle != "" {
+		songLine = "♪ " + formatSongTitle(m.songTitle)
+	}
+
+	// Timeshift line: ⏸ how far behind live playback is, while paused or catching up
+	var timeshiftLine string
+	if m.timeshift.Paused || m.timeshift.Behind > 0 {
+		params := map[string]interface{}{
+			"Behind": formatTimeshiftBehind(m.timeshift.Behind, station.Bitrate),
+			"Key":    m.keybindings.JumpToLive,
+		}
+		switch {
+		case m.timeshift.Paused && m.timeshift.Capacity > 0 && m.timeshift.Behind >= m.timeshift.Capacity:
+			timeshiftLine = "⏸ " + i18n.Tf("timeshift_buffer_full", params)
+		case m.timeshift.Paused:
+			timeshiftLine = "⏸ " + i18n.Tf("timeshift_paused", params)
+		default:
+			timeshiftLine = "⏪ " + i18n.Tf("timeshift_behind", params)
+		}
+	}
+
 	// Line 2: 📍 Country • tag1, tag2, tag3 • ⭐ Bookmarked
 	line2Parts := []string{}
 
@@ -367,11 +699,27 @@ func (m StationsModel) renderNowPlayingBox() string {
 
 	line2 := strings.Join(line2Parts, " • ")
 
+	// Stream line: 🔗 the URL that actually worked, when it's not the station's own
+	var streamLine string
+	if m.streamURL != "" && m.streamURL != station.Url.URL.String() {
+		// Box border and padding take 4 columns, the icon 3
+		streamLine = "🔗 " + truncateText(m.streamURL, m.width-4-4-3)
+	}
+
 	// Build the box content
 	boxContent := m.theme.PrimaryText.Render(line1)
+	if songLine != "" {
+		boxContent += "\n" + m.theme.Text.Render(songLine)
+	}
+	if timeshiftLine != "" {
+		boxContent += "\n" + m.theme.Text.Render(timeshiftLine)
+	}
 	if line2 != "" {
 		boxContent += "\n" + m.theme.SecondaryText.Render(line2)
 	}
+	if streamLine != "" {
+		boxContent += "\n" + m.theme.TertiaryText.Render(streamLine)
+	}
 
 	// Create the box with rounded border
 	boxStyle := lipgloss.NewStyle().
@@ -383,9 +731,35 @@ func (m StationsModel) renderNowPlayingBox() string {
 	return boxStyle.Render(boxContent)
 }
 
+// playingStreamURL returns the URL the playing station is streamed from.
+func (m StationsModel) playingStreamURL() string {
+	if m.streamURL != "" {
+		return m.streamURL
+	}
+	return m.currentStation.StreamURL()
+}
+
+// truncateText shortens text to at most max characters, ending it with an ellipsis.
+func truncateText(text string, max int) string {
+	runes := []rune(text)
+	if max < 1 || len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
 // buildStatusBar returns the styled status bar string.
-// Priority: success message > error message > now playing > default.
+// Priority: delete confirmation > success message > error message > now playing > default.
 func (m StationsModel) buildStatusBar() string {
+	if m.showSleepInput {
+		return m.sleepInput.View()
+	}
+	if m.deleteCandidate.StationUuid != uuid.Nil {
+		return m.theme.ErrorText.Render(i18n.Tf("custom_delete_confirm", map[string]interface{}{
+			"Name": m.deleteCandidate.Name,
+			"Key":  m.keybindings.DeleteStation,
+		}))
+	}
 	if m.successMsg != "" {
 		return m.theme.SuccessText.Render(m.successMsg)
 	} else if m.err != "" {
@@ -405,6 +779,8 @@ func (m StationsModel) View() string {
 		var emptyMsg string
 		if m.viewMode == viewModeBookmarks {
 			emptyMsg = i18n.Tf("no_bookmarks", map[string]interface{}{"BookmarksKey": m.keybindings.BookmarksView})
+		} else if m.viewMode == viewModeCustom {
+			emptyMsg = i18n.Tf("no_custom_stations", map[string]interface{}{"AddKey": m.keybindings.AddStation})
 		} else {
 			emptyMsg = i18n.T("no_stations")
 		}
@@ -445,6 +821,9 @@ func (m StationsModel) View() string {
 	if m.showHiddenModal {
 		return m.renderWithModal(v)
 	}
+	if m.showCustomForm {
+		return m.renderWithCustomForm()
+	}
 
 	return v
 }
@@ -461,6 +840,16 @@ func (m *StationsModel) SetWidthAndHeight(width int, height int) {
 func (m *StationsModel) updateTableDimensions() {
 	m.stationsTable.SetWidth(m.width)
 
+	// The name column gives up width when the optional columns wouldn't fit otherwise
+	if columns := m.stationsTable.Columns(); m.width > 0 && len(columns) > 0 {
+		nameWidth := m.width - 2
+		for _, column := range columns[1:] {
+			nameWidth -= column.Width + 2
+		}
+		columns[0].Width = min(max(nameWidth, minStationNameWidth), stationNameWidth)
+		m.stationsTable.SetColumns(columns)
+	}
+
 	// Calculate actual status bar height (can wrap to multiple lines)
 	statusBar := m.buildStatusBar()
 	statusHeight := lipgloss.Height(statusBar)
@@ -482,7 +871,17 @@ func (m *StationsModel) updateTableDimensions() {
 
 // IsModalShowing returns true if a modal dialog is currently displayed.
 func (m StationsModel) IsModalShowing() bool {
-	return m.showHiddenModal
+	return m.showHiddenModal || m.showCustomForm
+}
+
+// isCustom returns true if the station was added by the user rather than found on RadioBrowser.
+func (m StationsModel) isCustom(station common.Station) bool {
+	return m.storage != nil && m.storage.IsCustomStation(station.StationUuid)
+}
+
+// isOnRadioBrowser returns true if RadioBrowser knows the station, so that clicks and votes can be sent.
+func (m StationsModel) isOnRadioBrowser(station common.Station) bool {
+	return station.Source == common.StationSourceRadioBrowser && !m.isCustom(station)
 }
 
 // rebuildTablePreservingCursor rebuilds the stations table and restores the cursor position.
@@ -493,7 +892,7 @@ func (m *StationsModel) rebuildTablePreservingCursor(cursorOverride int) {
 	if cursorOverride >= 0 {
 		cursor = cursorOverride
 	}
-	m.stationsTable = newStationsTableModel(m.theme, m.stations, m.storage, m.currentStation)
+	m.stationsTable = newStationsTableModel(m.theme, m.stations, m.storage, m.currentStation, m.origin)
 	m.updateTableDimensions()
 	m.setCursorSafely(cursor)
 }
@@ -0,0 +1,145 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/schedule"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alarmFiredMsg plays the station of an alarm that rang.
+type alarmFiredMsg struct {
+	alarm schedule.Alarm
+	// at is when the alarm rang, the ramp-up starts from it
+	at time.Time
+}
+
+// alarmRampTickMsg is sent every second while the volume of an alarm ramps up.
+type alarmRampTickMsg struct {
+	// ramp identifies the ramp-up that sent it, older ramp-ups are dropped
+	ramp int64
+	now  time.Time
+}
+
+// alarmRampTickCmd sends the next alarmRampTickMsg of the ramp-up in a second.
+func alarmRampTickCmd(ramp int64) tea.Cmd {
+	return tea.Tick(time.Second, func(now time.Time) tea.Msg {
+		return alarmRampTickMsg{ramp: ramp, now: now}
+	})
+}
+
+// alarmRampVolume returns the volume to play at elapsed into an alarm's ramp-up
+// lasting rampUp: up from the first step towards volume, then volume.
+// The elapsed time is rounded down to a multiple of step, so that the volume
+// changes at most once per step.
+func alarmRampVolume(volume int, elapsed, rampUp, step time.Duration) int {
+	if rampUp <= 0 || elapsed >= rampUp {
+		return volume
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if step > 0 {
+		// Playback starts one step up, rather than silent
+		elapsed = elapsed/step*step + step
+	}
+	if elapsed > rampUp {
+		elapsed = rampUp
+	}
+	return int(math.Ceil(float64(volume) * float64(elapsed) / float64(rampUp)))
+}
+
+// handleAlarmMessages plays the station of an alarm and ramps its volume up.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handleAlarmMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case alarmFiredMsg:
+		cmd := m.handleAlarmFired(msg)
+		return true, m, cmd
+
+	case alarmRampTickMsg:
+		if msg.ramp != m.alarmRamp || !m.alarmRamping {
+			return true, m, nil
+		}
+		elapsed := msg.now.Sub(m.alarmRampStart)
+		if elapsed >= m.alarmRampUp {
+			m.alarmRamping = false
+			if m.playbackManager.IsPlaying() && m.alarmRampVolume != m.volume {
+				return true, m, m.applyVolumeCmd(m.volume)
+			}
+			return true, m, nil
+		}
+		cmds := []tea.Cmd{alarmRampTickCmd(m.alarmRamp)}
+		volume := alarmRampVolume(m.volume, elapsed, m.alarmRampUp, m.volumeStep())
+		// The volume is only raised once the station plays
+		if m.playbackManager.IsPlaying() && volume != m.alarmRampVolume {
+			m.alarmRampVolume = volume
+			cmds = append(cmds, m.applyVolumeCmd(volume))
+		}
+		return true, m, tea.Batch(cmds...)
+	}
+	return false, m, nil
+}
+
+// handleAlarmFired plays the station of an alarm at its volume, starting low if it ramps up.
+// The alarm's volume becomes the volume chosen by the user.
+func (m *StationsModel) handleAlarmFired(msg alarmFiredMsg) tea.Cmd {
+	alarm := msg.alarm
+	m.volume = alarm.Volume
+	if m.volume < m.playbackManager.VolumeMin() {
+		m.volume = m.playbackManager.VolumeMin()
+	}
+	if m.volume > m.playbackManager.VolumeMax() {
+		m.volume = m.playbackManager.VolumeMax()
+	}
+
+	m.alarmRamp = nextCountdownID()
+	m.alarmRamping = alarm.RampUp > 0
+	m.alarmStation = uuid.Nil
+	cmds := []tea.Cmd{}
+	if m.alarmRamping {
+		m.alarmRampStart = msg.at
+		m.alarmRampUp = alarm.RampUp
+		m.alarmStation = alarm.Station.StationUuid
+		m.alarmRampVolume = alarmRampVolume(m.volume, 0, m.alarmRampUp, m.volumeStep())
+		cmds = append(cmds, alarmRampTickCmd(m.alarmRamp))
+	}
+
+	for i, station := range m.stations {
+		if station.StationUuid == alarm.Station.StationUuid {
+			m.setCursorSafely(i)
+			break
+		}
+	}
+
+	m.successMsg = i18n.Tf("alarm_ringing", map[string]interface{}{"Time": alarm.TimeOfDay(), "Station": alarm.Station.Name})
+	cmds = append(cmds,
+		playStationCmd(m.playbackManager, alarm.Station, m.playingVolume()),
+		tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
+			return clearSuccessMsg{}
+		}),
+	)
+	return tea.Batch(cmds...)
+}
@@ -20,14 +20,19 @@
 package models
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
-	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/health"
 	"github.com/zi0p4tch0/radiogogo/i18n"
 	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/providers"
 	"github.com/zi0p4tch0/radiogogo/storage"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -37,9 +42,55 @@ import (
 
 type playbackStartedMsg struct {
 	station common.Station
+	// streamURL is the URL the station is actually streamed from, when the
+	// playback manager resolves it (empty otherwise).
+	streamURL string
+	// exits receives the player's exit should it stop by itself, when the
+	// playback manager watches its player (nil otherwise).
+	exits <-chan playback.PlayerExit
+	// reconnectAttempt is the attempt that restarted the station after its
+	// player stopped, or 0 when the user started it.
+	reconnectAttempt int
 }
 type playbackStoppedMsg struct{}
 
+// playbackDiedMsg is sent when the player of station stops by itself, or a
+// reconnection attempt fails.
+type playbackDiedMsg struct {
+	station common.Station
+	// reason is how the player ended, e.g. "exit status 1".
+	reason string
+	// stderr holds the last lines the player wrote to its standard error.
+	stderr string
+}
+
+// reconnectMsg is sent when it's time for a reconnection attempt.
+type reconnectMsg struct {
+	station common.Station
+	attempt int
+}
+
+type playbackPausedMsg struct {
+	paused bool
+}
+
+// timeshiftStateMsg carries the timeshift state of the playing station.
+type timeshiftStateMsg struct {
+	// refresh identifies the refresh loop that read the state, older loops are dropped
+	refresh int
+	state   playback.TimeshiftState
+}
+
+// Song title messages
+
+// songTitleMsg carries a new song title of the station's stream, and the
+// channel the next one will arrive on.
+type songTitleMsg struct {
+	station uuid.UUID
+	title   string
+	titles  <-chan string
+}
+
 // Error messages
 
 type nonFatalError struct {
@@ -53,6 +104,8 @@ type clearNonFatalError struct{}
 type stationCursorMovedMsg struct {
 	offset        int
 	totalStations int
+	// loadingMore is true while the next page of results is being fetched
+	loadingMore bool
 }
 
 // Volume messages
@@ -63,12 +116,19 @@ type volumeDebounceExpiredMsg struct {
 
 type volumeRestartCompleteMsg struct {
 	station common.Station
+	exits   <-chan playback.PlayerExit
+	// keptBuffer is true when only the player was restarted, the timeshift buffer carrying on
+	keptBuffer bool
 }
 
 type volumeRestartFailedMsg struct {
 	err error
 }
 
+type volumeSetFailedMsg struct {
+	err error
+}
+
 // Recording messages
 
 type recordingStartedMsg struct {
@@ -95,6 +155,11 @@ type stationHiddenMsg struct {
 type bookmarksFetchedMsg struct {
 	stations []common.Station
 }
+type bookmarksRefreshedMsg struct {
+	stations []common.Station
+	// offline is true if the API couldn't be reached and saved snapshots are shown
+	offline bool
+}
 type bookmarksFetchFailedMsg struct {
 	err error
 }
@@ -118,11 +183,59 @@ type unhideStationFailedMsg struct {
 }
 type stationsRefetchedMsg struct {
 	stations []common.Station
+	// limit is the number of results that was asked for
+	limit uint64
 }
 type stationsRefetchFailedMsg struct {
 	err error
 }
 
+// Custom station messages
+
+type customStationsFetchedMsg struct {
+	stations []common.Station
+	// selected is the station to put the cursor on, if any (e.g. one just saved)
+	selected uuid.UUID
+}
+type customStationsFetchFailedMsg struct {
+	err error
+}
+type customStationSavedMsg struct {
+	station common.Station
+}
+type customStationSaveFailedMsg struct {
+	err error
+}
+type customStationDeletedMsg struct {
+	station common.Station
+	cursor  int
+}
+type customStationDeleteFailedMsg struct {
+	err error
+}
+
+// Pagination messages
+
+type stationsPageFetchedMsg struct {
+	// source is the search the page belongs to
+	source   stationSource
+	offset   uint64
+	stations []common.Station
+}
+type stationsPageFetchFailedMsg struct {
+	err error
+}
+
+// Sort messages
+
+type stationsResortedMsg struct {
+	order    common.StationOrder
+	reverse  bool
+	stations []common.Station
+	// limit is the number of results that was asked for
+	limit uint64
+}
+
 // Vote messages
 
 type voteSucceededMsg struct {
@@ -149,7 +262,78 @@ func playStationCmd(
 		if err != nil {
 			return nonFatalError{stopPlayback: false, err: err}
 		}
-		return playbackStartedMsg{station: station}
+		return newPlaybackStartedMsg(playbackManager, station)
+	}
+}
+
+// newPlaybackStartedMsg describes the playback of station that just started.
+func newPlaybackStartedMsg(playbackManager playback.PlaybackManagerService, station common.Station) playbackStartedMsg {
+	msg := playbackStartedMsg{station: station}
+	if reporter, ok := playbackManager.(playback.StreamURLReporter); ok {
+		msg.streamURL = reporter.StreamURL()
+	}
+	msg.exits = playerExits(playbackManager)
+	return msg
+}
+
+// playerExits returns the channel the playing player's exit arrives on, or nil if
+// the playback manager doesn't watch its player.
+func playerExits(playbackManager playback.PlaybackManagerService) <-chan playback.PlayerExit {
+	if watcher, ok := playbackManager.(playback.ExitWatcher); ok {
+		return watcher.PlayerExited()
+	}
+	return nil
+}
+
+// Reconnection timing: attempts wait 1s, 2s, 4s... up to maxReconnectDelay.
+// A station that played for reconnectResetAfter before stopping starts over from the first attempt.
+const (
+	baseReconnectDelay  = time.Second
+	maxReconnectDelay   = 30 * time.Second
+	reconnectResetAfter = time.Minute
+)
+
+// reconnectDelay returns how long to wait before the given reconnection attempt (1-based).
+func reconnectDelay(attempt int) time.Duration {
+	delay := baseReconnectDelay
+	for i := 1; i < attempt && delay < maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectDelay {
+		return maxReconnectDelay
+	}
+	return delay
+}
+
+// waitForPlayerExitCmd waits for the player of station to stop by itself.
+// Returns nil once the station is stopped or replaced instead.
+func waitForPlayerExitCmd(station common.Station, exits <-chan playback.PlayerExit) tea.Cmd {
+	return func() tea.Msg {
+		exit, ok := <-exits
+		if !ok {
+			return nil
+		}
+		return playbackDiedMsg{station: station, reason: exit.Reason, stderr: exit.Stderr}
+	}
+}
+
+// scheduleReconnectCmd sends a reconnectMsg for attempt after delay.
+func scheduleReconnectCmd(station common.Station, attempt int, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(t time.Time) tea.Msg {
+		return reconnectMsg{station: station, attempt: attempt}
+	})
+}
+
+// reconnectCmd plays station again after its player stopped. A failure counts
+// as the attempt dying too.
+func reconnectCmd(playbackManager playback.PlaybackManagerService, station common.Station, volume int, attempt int) tea.Cmd {
+	return func() tea.Msg {
+		if err := playbackManager.PlayStation(station, volume); err != nil {
+			return playbackDiedMsg{station: station, reason: err.Error()}
+		}
+		msg := newPlaybackStartedMsg(playbackManager, station)
+		msg.reconnectAttempt = attempt
+		return msg
 	}
 }
 
@@ -167,7 +351,7 @@ func stopStationCmd(playbackManager playback.PlaybackManagerService) tea.Cmd {
 // notifyRadioBrowserCmd notifies the RadioBrowser API that a station was played (click count).
 func notifyRadioBrowserCmd(browser api.RadioBrowserService, station common.Station) tea.Cmd {
 	return func() tea.Msg {
-		_, err := browser.ClickStation(station)
+		_, err := browser.ClickStation(context.Background(), station)
 		if err != nil {
 			return nonFatalError{stopPlayback: false, err: err}
 		}
@@ -188,31 +372,152 @@ func startVolumeDebounceCmd(changeID int64) tea.Cmd {
 }
 
 // restartPlaybackWithVolumeCmd stops and restarts playback with a new volume level.
+// Players that can be restarted on their own keep the station's recording and timeshift buffer.
 func restartPlaybackWithVolumeCmd(
 	pm playback.PlaybackManagerService,
 	station common.Station,
 	volume int,
 ) tea.Cmd {
 	return func() tea.Msg {
+		if restarter, ok := pm.(playback.PlayerRestarter); ok && pm.IsPlaying() && pm.CurrentStation().StationUuid == station.StationUuid {
+			if err := restarter.RestartPlayer(volume); err != nil {
+				return volumeRestartFailedMsg{err: err}
+			}
+			return volumeRestartCompleteMsg{station: station, exits: playerExits(pm), keptBuffer: true}
+		}
 		if err := pm.StopStation(); err != nil {
 			return volumeRestartFailedMsg{err: err}
 		}
 		if err := pm.PlayStation(station, volume); err != nil {
 			return volumeRestartFailedMsg{err: err}
 		}
-		return volumeRestartCompleteMsg{station: station}
+		return volumeRestartCompleteMsg{station: station, exits: playerExits(pm)}
+	}
+}
+
+// setVolumeCmd changes the volume of the playing station in place.
+func setVolumeCmd(controller playback.VolumeController, volume int) tea.Cmd {
+	return func() tea.Msg {
+		if err := controller.SetVolume(volume); err != nil {
+			return volumeSetFailedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// Song title commands
+
+// waitForSongTitleCmd waits for the next song title of the station's stream.
+// Nothing is sent once the stream ends or stops being watched.
+func waitForSongTitleCmd(station uuid.UUID, titles <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		title, ok := <-titles
+		if !ok {
+			return nil
+		}
+		return songTitleMsg{station: station, title: title, titles: titles}
+	}
+}
+
+// startSongCmd logs a song heard on the station in the song history.
+func startSongCmd(storage storage.StationStorageService, station common.Station, title string) tea.Cmd {
+	entry := common.SongHistoryEntry{
+		StationUuid: station.StationUuid,
+		StationName: station.Name,
+		Title:       title,
+		StartedAt:   time.Now(),
+	}
+	return func() tea.Msg {
+		// The history is a nicety: a song that can't be logged doesn't interrupt listening
+		_ = storage.StartSong(entry)
+		return nil
+	}
+}
+
+// endSongCmd ends the song logged last in the song history.
+func endSongCmd(storage storage.StationStorageService) tea.Cmd {
+	return func() tea.Msg {
+		_ = storage.EndSong(time.Now())
+		return nil
+	}
+}
+
+// Pause commands
+
+// togglePauseCmd pauses playback, or resumes it if it's already paused.
+func togglePauseCmd(pauser playback.Pauser) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if pauser.IsPaused() {
+			err = pauser.Resume()
+		} else {
+			err = pauser.Pause()
+		}
+		if err != nil {
+			return nonFatalError{stopPlayback: false, err: err}
+		}
+		return playbackPausedMsg{paused: pauser.IsPaused()}
+	}
+}
+
+// toggleLivePauseCmd holds a timeshifted station, or resumes it from where it was held.
+func toggleLivePauseCmd(timeshifter playback.Timeshifter) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if timeshifter.TimeshiftState().Paused {
+			err = timeshifter.ResumeLive()
+		} else {
+			err = timeshifter.PauseLive()
+		}
+		if err != nil {
+			return nonFatalError{stopPlayback: false, err: err}
+		}
+		return playbackPausedMsg{paused: timeshifter.TimeshiftState().Paused}
 	}
 }
 
+// jumpToLiveCmd drops the timeshift backlog, resuming playback with the live stream.
+func jumpToLiveCmd(timeshifter playback.Timeshifter) tea.Cmd {
+	return func() tea.Msg {
+		if err := timeshifter.JumpToLive(); err != nil {
+			return nonFatalError{stopPlayback: false, err: err}
+		}
+		return playbackPausedMsg{paused: false}
+	}
+}
+
+// timeshiftRefreshInterval is how often the time behind live is updated.
+const timeshiftRefreshInterval = time.Second
+
+// timeshiftStateCmd reads the timeshift state of the playing station.
+func timeshiftStateCmd(timeshifter playback.Timeshifter, refresh int) tea.Cmd {
+	return func() tea.Msg {
+		return timeshiftStateMsg{refresh: refresh, state: timeshifter.TimeshiftState()}
+	}
+}
+
+// scheduleTimeshiftStateCmd reads the timeshift state again after timeshiftRefreshInterval.
+func scheduleTimeshiftStateCmd(timeshifter playback.Timeshifter, refresh int) tea.Cmd {
+	return tea.Tick(timeshiftRefreshInterval, func(time.Time) tea.Msg {
+		return timeshiftStateMsg{refresh: refresh, state: timeshifter.TimeshiftState()}
+	})
+}
+
 // Recording commands
 
-// startRecordingCmd starts recording the current stream to the given output path.
-func startRecordingCmd(pm playback.PlaybackManagerService, outputPath string) tea.Cmd {
+// startRecordingCmd starts recording the current stream to a new file of location,
+// named after the playing station and song title.
+func startRecordingCmd(pm playback.PlaybackManagerService, location playback.RecordingLocation, songTitle string) tea.Cmd {
 	return func() tea.Msg {
-		err := pm.StartRecording(outputPath)
+		outputPath, err := location.Path(pm.CurrentStation(), songTitle, time.Now())
 		if err != nil {
 			return recordingErrorMsg{err: err}
 		}
+		err = pm.StartRecording(outputPath)
+		if err != nil {
+			playback.DiscardRecordingPath(outputPath)
+			return recordingErrorMsg{err: err}
+		}
 		return recordingStartedMsg{filePath: outputPath}
 	}
 }
@@ -228,6 +533,50 @@ func stopRecordingCmd(pm playback.PlaybackManagerService) tea.Cmd {
 	}
 }
 
+// Health check messages
+
+// healthCheckedMsg carries the results of local stream health checks, once saved to storage.
+type healthCheckedMsg struct {
+	results []health.Result
+	// err is set if the results couldn't be saved
+	err error
+	// background is true for the periodic check of bookmarks
+	background bool
+}
+
+// Health check commands
+
+// checkHealthCmd probes the streams of the given stations and saves the results.
+func checkHealthCmd(checker *health.Checker, storage storage.StationStorageService, stations []common.Station) tea.Cmd {
+	return func() tea.Msg {
+		results := checker.CheckAll(context.Background(), stations)
+		return healthCheckedMsg{results: results, err: storage.SaveHealthResults(results)}
+	}
+}
+
+// checkBookmarksHealthCmd probes the streams of bookmarked stations that weren't
+// checked within maxAge, and saves the results. Nothing is sent if none needed a check.
+func checkBookmarksHealthCmd(checker *health.Checker, storage storage.StationStorageService, maxAge time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		bookmarked, err := storage.GetBookmarkedStations()
+		if err != nil {
+			return nil
+		}
+		var stale []common.Station
+		for _, station := range bookmarked {
+			if result, ok := storage.GetHealthResult(station.StationUuid); ok && time.Since(result.CheckedAt) < maxAge {
+				continue
+			}
+			stale = append(stale, station)
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		results := checker.CheckAll(context.Background(), stale)
+		return healthCheckedMsg{results: results, err: storage.SaveHealthResults(results), background: true}
+	}
+}
+
 // Bookmark and hidden station commands
 
 // toggleBookmarkCmd toggles the bookmark status of a station.
@@ -238,6 +587,10 @@ func toggleBookmarkCmd(storage storage.StationStorageService, station common.Sta
 			err = storage.RemoveBookmark(station.StationUuid)
 		} else {
 			err = storage.AddBookmark(station.StationUuid)
+			if err == nil {
+				// Snapshot failures only matter offline; the bookmark itself was saved
+				_ = storage.SaveBookmarkSnapshots([]common.Station{station})
+			}
 		}
 		if err != nil {
 			return bookmarkToggleFailedMsg{err: err}
@@ -268,40 +621,230 @@ func unhideStationCmd(storage storage.StationStorageService, station common.Stat
 	}
 }
 
-// fetchBookmarksCmd fetches all bookmarked stations from storage and the API.
-func fetchBookmarksCmd(browser api.RadioBrowserService, storage storage.StationStorageService) tea.Cmd {
+// savedBookmarks returns the bookmarked stations as saved in storage, without waiting on the
+// network: the snapshots of RadioBrowser stations sorted by name, then the bookmarked custom stations.
+// They're listed in the same order as by loadBookmarkedStations.
+func savedBookmarks(storage storage.StationStorageService) ([]common.Station, error) {
+	allSnapshots, err := storage.GetBookmarkedStations()
+	if err != nil {
+		return nil, err
+	}
+	customStations, err := storage.GetCustomStations()
+	if err != nil {
+		return nil, err
+	}
+
+	stations := make([]common.Station, 0, len(allSnapshots)+len(customStations))
+	for _, s := range allSnapshots {
+		if !storage.IsCustomStation(s.StationUuid) {
+			stations = append(stations, s)
+		}
+	}
+	sortStationsByName(stations)
+	for _, s := range customStations {
+		if storage.IsBookmarked(s.StationUuid) {
+			stations = append(stations, s)
+		}
+	}
+	return stations, nil
+}
+
+// loadBookmarkedStations returns the bookmarked stations. They're fetched from the API when it
+// can be reached, updating their stored snapshots, and taken from the snapshots otherwise
+// (offline is then true). Bookmarks the API no longer knows about are kept from their snapshots.
+// RadioBrowser stations are sorted by name, bookmarked custom stations come from storage and are listed last.
+func loadBookmarkedStations(
+	ctx context.Context,
+	browser api.RadioBrowserService,
+	storage storage.StationStorageService,
+) (stations []common.Station, offline bool, err error) {
+	uuids, err := storage.GetBookmarks()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(uuids) == 0 {
+		return []common.Station{}, false, nil
+	}
+
+	customStations, err := storage.GetCustomStations()
+	if err != nil {
+		return nil, false, err
+	}
+	custom := []common.Station{}
+	for _, s := range customStations {
+		if storage.IsBookmarked(s.StationUuid) {
+			custom = append(custom, s)
+		}
+	}
+
+	radioBrowserUUIDs := make([]uuid.UUID, 0, len(uuids))
+	for _, id := range uuids {
+		if !storage.IsCustomStation(id) {
+			radioBrowserUUIDs = append(radioBrowserUUIDs, id)
+		}
+	}
+	if len(radioBrowserUUIDs) == 0 {
+		return custom, false, nil
+	}
+
+	allSnapshots, snapshotsErr := storage.GetBookmarkedStations()
+	snapshots := make([]common.Station, 0, len(allSnapshots))
+	for _, s := range allSnapshots {
+		if !storage.IsCustomStation(s.StationUuid) {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	stations, err = browser.GetStationsByUUIDs(ctx, radioBrowserUUIDs)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || snapshotsErr != nil || len(snapshots) == 0 {
+			return nil, false, err
+		}
+		sortStationsByName(snapshots)
+		return append(snapshots, custom...), true, nil
+	}
+
+	_ = storage.SaveBookmarkSnapshots(stations)
+
+	fetched := make(map[uuid.UUID]bool, len(stations))
+	for _, s := range stations {
+		fetched[s.StationUuid] = true
+	}
+	for _, s := range snapshots {
+		if !fetched[s.StationUuid] {
+			stations = append(stations, s)
+		}
+	}
+	sortStationsByName(stations)
+	return append(stations, custom...), false, nil
+}
+
+// sortStationsByName sorts stations by name, ignoring case.
+func sortStationsByName(stations []common.Station) {
+	sort.SliceStable(stations, func(i, j int) bool {
+		return strings.ToLower(stations[i].Name) < strings.ToLower(stations[j].Name)
+	})
+}
+
+// savedBookmarkedStations returns the bookmarked stations as saved in storage, sorted by name:
+// the snapshots of RadioBrowser stations and the custom stations. Used where bookmarks
+// are needed without waiting on the network.
+func savedBookmarkedStations(storage storage.StationStorageService) ([]common.Station, error) {
+	snapshots, err := storage.GetBookmarkedStations()
+	if err != nil {
+		return nil, err
+	}
+	customStations, err := storage.GetCustomStations()
+	if err != nil {
+		return nil, err
+	}
+
+	stations := make([]common.Station, 0, len(snapshots)+len(customStations))
+	for _, s := range snapshots {
+		if !storage.IsCustomStation(s.StationUuid) {
+			stations = append(stations, s)
+		}
+	}
+	for _, s := range customStations {
+		if storage.IsBookmarked(s.StationUuid) {
+			stations = append(stations, s)
+		}
+	}
+	sortStationsByName(stations)
+	return stations, nil
+}
+
+// fetchBookmarksCmd lists the bookmarked stations from their snapshots at once, then
+// refreshes them from the API in the background.
+func fetchBookmarksCmd(ctx context.Context, browser api.RadioBrowserService, storage storage.StationStorageService) tea.Cmd {
+	return tea.Sequence(savedBookmarksCmd(storage), refreshBookmarksCmd(ctx, browser, storage))
+}
+
+// savedBookmarksCmd lists the bookmarked stations from their snapshots.
+func savedBookmarksCmd(storage storage.StationStorageService) tea.Cmd {
 	return func() tea.Msg {
-		uuids, err := storage.GetBookmarks()
+		stations, err := savedBookmarks(storage)
 		if err != nil {
 			return bookmarksFetchFailedMsg{err: err}
 		}
-		if len(uuids) == 0 {
-			return bookmarksFetchedMsg{stations: []common.Station{}}
-		}
-		stations, err := browser.GetStationsByUUIDs(uuids)
+		return bookmarksFetchedMsg{stations: stations}
+	}
+}
+
+// refreshBookmarksCmd fetches all bookmarked stations from the API, or from their snapshots when offline.
+func refreshBookmarksCmd(ctx context.Context, browser api.RadioBrowserService, storage storage.StationStorageService) tea.Cmd {
+	return func() tea.Msg {
+		stations, offline, err := loadBookmarkedStations(ctx, browser, storage)
 		if err != nil {
 			return bookmarksFetchFailedMsg{err: err}
 		}
-		return bookmarksFetchedMsg{stations: stations}
+		return bookmarksRefreshedMsg{stations: stations, offline: offline}
 	}
 }
 
-// fetchBookmarksForSearchCmd fetches bookmarks and switches directly to bookmarks view.
-// Used when accessing bookmarks from the search screen.
-func fetchBookmarksForSearchCmd(browser api.RadioBrowserService, storage storage.StationStorageService) tea.Cmd {
+// fetchBookmarksForSearchCmd switches directly to the bookmarks view, listing them from their
+// snapshots; they're refreshed from the API once shown. Used when accessing bookmarks from the
+// search screen.
+func fetchBookmarksForSearchCmd(storage storage.StationStorageService) tea.Cmd {
 	return func() tea.Msg {
-		uuids, err := storage.GetBookmarks()
+		stations, err := savedBookmarks(storage)
 		if err != nil {
-			return switchToErrorModelMsg{err: err.Error(), recoverable: true}
+			return switchToErrorModelMsg{err: err.Error(), recoverable: true, cause: err}
 		}
-		if len(uuids) == 0 {
-			return switchToBookmarksMsg{stations: []common.Station{}}
+		return switchToBookmarksMsg{stations: stations, refresh: true}
+	}
+}
+
+// saveBookmarkSnapshotsCmd refreshes the snapshots of any bookmarked stations among the given ones.
+func saveBookmarkSnapshotsCmd(storage storage.StationStorageService, stations []common.Station) tea.Cmd {
+	return func() tea.Msg {
+		_ = storage.SaveBookmarkSnapshots(stations)
+		return nil
+	}
+}
+
+// Custom station commands
+
+// fetchCustomStationsCmd loads the custom stations from storage, putting the cursor on selected.
+func fetchCustomStationsCmd(storage storage.StationStorageService, selected uuid.UUID) tea.Cmd {
+	return func() tea.Msg {
+		stations, err := storage.GetCustomStations()
+		if err != nil {
+			return customStationsFetchFailedMsg{err: err}
 		}
-		stations, err := browser.GetStationsByUUIDs(uuids)
+		return customStationsFetchedMsg{stations: stations, selected: selected}
+	}
+}
+
+// fetchCustomStationsForSearchCmd loads the custom stations and switches directly to their view.
+// Used when accessing custom stations from the search screen.
+func fetchCustomStationsForSearchCmd(storage storage.StationStorageService) tea.Cmd {
+	return func() tea.Msg {
+		stations, err := storage.GetCustomStations()
 		if err != nil {
 			return switchToErrorModelMsg{err: err.Error(), recoverable: true}
 		}
-		return switchToBookmarksMsg{stations: stations}
+		return switchToCustomStationsMsg{stations: stations}
+	}
+}
+
+// saveCustomStationCmd adds or updates a custom station.
+func saveCustomStationCmd(storage storage.StationStorageService, station common.Station) tea.Cmd {
+	return func() tea.Msg {
+		if err := storage.SaveCustomStation(station); err != nil {
+			return customStationSaveFailedMsg{err: err}
+		}
+		return customStationSavedMsg{station: station}
+	}
+}
+
+// deleteCustomStationCmd deletes a custom station (and its bookmark).
+func deleteCustomStationCmd(storage storage.StationStorageService, station common.Station, cursor int) tea.Cmd {
+	return func() tea.Msg {
+		if err := storage.DeleteCustomStation(station.StationUuid); err != nil {
+			return customStationDeleteFailedMsg{err: err}
+		}
+		return customStationDeletedMsg{station: station, cursor: cursor}
 	}
 }
 
@@ -315,7 +858,7 @@ func fetchHiddenStationsCmd(browser api.RadioBrowserService, storage storage.Sta
 		if len(uuids) == 0 {
 			return hiddenFetchedMsg{stations: []common.Station{}}
 		}
-		stations, err := browser.GetStationsByUUIDs(uuids)
+		stations, err := browser.GetStationsByUUIDs(context.Background(), uuids)
 		if err != nil {
 			return hiddenFetchFailedMsg{err: err}
 		}
@@ -323,14 +866,180 @@ func fetchHiddenStationsCmd(browser api.RadioBrowserService, storage storage.Sta
 	}
 }
 
-// refetchStationsCmd refetches search results from the API using the stored query.
-func refetchStationsCmd(browser api.RadioBrowserService, query common.StationQuery, queryText string) tea.Cmd {
+// stationSource describes the search that produced a list of results,
+// so that it can be run again (refetch, further pages, new sort order).
+type stationSource struct {
+	query     common.StationQuery
+	queryText string
+	// params, when set, is an advanced search (takes precedence over query)
+	params *common.StationSearchParams
+	// list, when set, is one of RadioBrowser's ready-made lists (takes precedence over everything else)
+	list    common.StationList
+	order   common.StationOrder
+	reverse bool
+	// provider is where query/queryText are searched (nil means RadioBrowser)
+	provider providers.StationProvider
+}
+
+// withOrder returns a copy of src sorted by another order.
+func (src stationSource) withOrder(order common.StationOrder, reverse bool) stationSource {
+	src.order = order
+	src.reverse = reverse
+	return src
+}
+
+// geoSearchLimit caps how many stations a search around a point fetches.
+const geoSearchLimit = 1000
+
+// fetchStations runs a search again for a range of results.
+// Ready-made lists come in their own order and searches around a point
+// are sorted nearest-first; other searches use the order in src.
+func fetchStations(
+	ctx context.Context,
+	browser api.RadioBrowserService,
+	src stationSource,
+	offset uint64,
+	limit uint64,
+) ([]common.Station, error) {
+	if src.list != "" {
+		return browser.GetStationList(ctx, src.list, offset, limit)
+	}
+	if src.params != nil && src.params.Near != nil {
+		// RadioBrowser can't sort by distance: every station in range is fetched
+		// at once and sorted here, so there are no further pages
+		if offset > 0 {
+			return []common.Station{}, nil
+		}
+		p := *src.params
+		p.Offset = 0
+		p.Limit = geoSearchLimit
+		stations, err := browser.SearchStations(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		common.SortByDistance(stations, *p.Near)
+		return stations, nil
+	}
+	if src.params != nil {
+		p := *src.params
+		p.Order = src.order
+		p.Reverse = src.reverse
+		p.Offset = offset
+		p.Limit = limit
+		return browser.SearchStations(ctx, p)
+	}
+	provider := src.provider
+	if provider == nil {
+		provider = providers.NewRadioBrowserProvider(browser)
+	}
+	return provider.Search(ctx, src.query, src.queryText, src.order, src.reverse, offset, limit)
+}
+
+// refetchStationsCmd refetches the first limit search results from the API.
+func refetchStationsCmd(ctx context.Context, browser api.RadioBrowserService, src stationSource, limit uint64) tea.Cmd {
 	return func() tea.Msg {
-		stations, err := browser.GetStations(query, queryText, "votes", true, 0, 100, true)
+		stations, err := fetchStations(ctx, browser, src, 0, limit)
 		if err != nil {
 			return stationsRefetchFailedMsg{err: err}
 		}
-		return stationsRefetchedMsg{stations: stations}
+		return stationsRefetchedMsg{stations: stations, limit: limit}
+	}
+}
+
+// fetchNextPageCmd fetches the page of search results starting at offset.
+func fetchNextPageCmd(browser api.RadioBrowserService, src stationSource, offset uint64, limit uint64) tea.Cmd {
+	return func() tea.Msg {
+		stations, err := fetchStations(context.Background(), browser, src, offset, limit)
+		if err != nil {
+			return stationsPageFetchFailedMsg{err: err}
+		}
+		return stationsPageFetchedMsg{source: src, offset: offset, stations: stations}
+	}
+}
+
+// resortStationsCmd runs the search again from the first page in the order of src.
+func resortStationsCmd(browser api.RadioBrowserService, src stationSource, limit uint64) tea.Cmd {
+	return func() tea.Msg {
+		stations, err := fetchStations(context.Background(), browser, src, 0, limit)
+		if err != nil {
+			return stationsRefetchFailedMsg{err: err}
+		}
+		return stationsResortedMsg{order: src.order, reverse: src.reverse, stations: stations, limit: limit}
+	}
+}
+
+// source returns the search that produced the current results.
+func (m StationsModel) source() stationSource {
+	return stationSource{
+		query:     m.lastQuery,
+		queryText: m.lastQueryText,
+		params:    m.lastSearchParams,
+		list:      m.lastList,
+		order:     m.sortOrder,
+		reverse:   m.sortReverse,
+		provider:  m.provider,
+	}
+}
+
+// refetchCmd refetches the current search results, using whichever kind of search produced them.
+// Every page loaded so far is fetched again so the list doesn't shrink. It follows a change
+// (a vote, an unhidden station) that cached results predate, so it bypasses the response cache.
+func (m StationsModel) refetchCmd() tea.Cmd {
+	return m.refetchWithContextCmd(api.WithForceRefresh(context.Background()))
+}
+
+// refetchWithContextCmd is refetchCmd with a custom request context (e.g. to bypass the response cache).
+func (m StationsModel) refetchWithContextCmd(ctx context.Context) tea.Cmd {
+	limit := uint64(m.pageSize)
+	if m.fetchedCount > limit {
+		limit = m.fetchedCount
+	}
+	return refetchStationsCmd(ctx, m.browser, m.source(), limit)
+}
+
+// resort switches to a new sort order and re-queries the search results in it.
+// The order is also remembered in config for the next search.
+func (m *StationsModel) resort(order common.StationOrder, reverse bool) tea.Cmd {
+	m.sortOrder = order
+	m.sortReverse = reverse
+	return tea.Batch(
+		resortStationsCmd(m.browser, m.source().withOrder(order, reverse), uint64(m.pageSize)),
+		func() tea.Msg {
+			return sortOrderChangedMsg{order: order, reverse: reverse}
+		},
+		m.updateCommandsCmd(),
+	)
+}
+
+// loadMoreThreshold is how close (in rows) the cursor must get to the end of
+// the list before the next page of results is fetched.
+const loadMoreThreshold = 10
+
+// loadMoreIfNeeded starts fetching the next page of search results when the
+// cursor nears the end of the list. Returns nil if there's nothing to load.
+func (m *StationsModel) loadMoreIfNeeded() tea.Cmd {
+	if m.viewMode != viewModeSearchResults || !m.hasMorePages || m.loadingMore || m.origin != nil {
+		return nil
+	}
+	if m.stationsTable.Cursor() < len(m.stations)-loadMoreThreshold {
+		return nil
+	}
+	m.loadingMore = true
+	return tea.Batch(
+		fetchNextPageCmd(m.browser, m.source(), m.fetchedCount, uint64(m.pageSize)),
+		m.cursorMovedCmd(),
+	)
+}
+
+// cursorMovedCmd reports the cursor position and list size to the header.
+func (m StationsModel) cursorMovedCmd() tea.Cmd {
+	msg := stationCursorMovedMsg{
+		offset:        m.stationsTable.Cursor(),
+		totalStations: len(m.stations),
+		loadingMore:   m.loadingMore,
+	}
+	return func() tea.Msg {
+		return msg
 	}
 }
 
@@ -358,7 +1067,7 @@ func voteStationCmd(
 		}
 
 		// Call API
-		resp, err := browser.VoteStation(station)
+		resp, err := browser.VoteStation(context.Background(), station)
 		if err != nil {
 			return voteFailedMsg{err: err}
 		}
@@ -388,7 +1097,19 @@ func clearErrorAfterDelayCmd() tea.Cmd {
 
 // updateCommandsCmd returns a command that updates the bottom bar with appropriate commands
 // based on the current view mode and playback state.
-func updateCommandsCmd(viewMode stationsViewMode, isPlaying bool, volume int, volumeIsPercentage bool, isRecording bool, kb config.Keybindings) tea.Cmd {
+// The sort order is only shown for search results, and only if they can be re-sorted
+// (ready-made lists can't). The pause and jump to live keys are only shown while the
+// playing station can be paused, or is played through the timeshift buffer.
+func (m StationsModel) updateCommandsCmd() tea.Cmd {
+	viewMode := m.viewMode
+	isPlaying := m.currentStation.StationUuid != uuid.Nil
+	volume := m.volume
+	volumeIsPercentage := m.playbackManager.VolumeIsPercentage()
+	isRecording := isPlaying && m.playbackManager.IsRecording()
+	canPause := isPlaying && m.canPause()
+	isTimeshifting := isPlaying && m.isTimeshifting()
+	sortLabel := m.sortLabel()
+	kb := m.keybindings
 	return func() tea.Msg {
 
 		// Row 1: Navigation and playback
@@ -401,9 +1122,13 @@ func updateCommandsCmd(viewMode stationsViewMode, isPlaying bool, volume int, vo
 				i18n.T("cmd_move"),
 			}
 		} else {
+			backKey := kb.BookmarksView
+			if viewMode == viewModeCustom {
+				backKey = kb.CustomStations
+			}
 			commands = []string{
 				i18n.Tf("cmd_quit", map[string]interface{}{"Key": kb.Quit}),
-				i18n.Tf("cmd_back", map[string]interface{}{"Key": kb.BookmarksView}),
+				i18n.Tf("cmd_back", map[string]interface{}{"Key": backKey}),
 				i18n.T("cmd_enter_play"),
 				i18n.T("cmd_move"),
 			}
@@ -421,21 +1146,25 @@ func updateCommandsCmd(viewMode stationsViewMode, isPlaying bool, volume int, vo
 		}
 
 		if isPlaying {
+			recordCommand := i18n.Tf("cmd_record", map[string]interface{}{"Key": kb.Record})
 			if isRecording {
-				commands = append(commands,
-					i18n.Tf("cmd_stop_record", map[string]interface{}{"Key": kb.Record}),
-					i18n.Tf("cmd_stop", map[string]interface{}{"Key": kb.StopPlayback}),
-					i18n.Tf("cmd_volume", map[string]interface{}{"VolumeDown": kb.VolumeDown, "VolumeUp": kb.VolumeUp}),
-					volumeDisplay,
-				)
-			} else {
-				commands = append(commands,
-					i18n.Tf("cmd_record", map[string]interface{}{"Key": kb.Record}),
-					i18n.Tf("cmd_stop", map[string]interface{}{"Key": kb.StopPlayback}),
-					i18n.Tf("cmd_volume", map[string]interface{}{"VolumeDown": kb.VolumeDown, "VolumeUp": kb.VolumeUp}),
-					volumeDisplay,
-				)
+				recordCommand = i18n.Tf("cmd_stop_record", map[string]interface{}{"Key": kb.Record})
+			}
+			commands = append(commands,
+				recordCommand,
+				i18n.Tf("cmd_stop", map[string]interface{}{"Key": kb.StopPlayback}),
+			)
+			if canPause {
+				commands = append(commands, i18n.Tf("cmd_pause", map[string]interface{}{"Key": kb.Pause}))
+			}
+			if isTimeshifting {
+				commands = append(commands, i18n.Tf("cmd_jump_to_live", map[string]interface{}{"Key": kb.JumpToLive}))
 			}
+			commands = append(commands,
+				i18n.Tf("cmd_sleep_timer", map[string]interface{}{"Key": kb.SleepTimer, "InputKey": kb.SleepTimerInput}),
+				i18n.Tf("cmd_volume", map[string]interface{}{"VolumeDown": kb.VolumeDown, "VolumeUp": kb.VolumeUp}),
+				volumeDisplay,
+			)
 		} else {
 			commands = append(commands,
 				i18n.Tf("cmd_volume", map[string]interface{}{"VolumeDown": kb.VolumeDown, "VolumeUp": kb.VolumeUp}),
@@ -449,13 +1178,42 @@ func updateCommandsCmd(viewMode stationsViewMode, isPlaying bool, volume int, vo
 			secondaryCommands = []string{
 				i18n.Tf("cmd_bookmark", map[string]interface{}{"Key": kb.BookmarkToggle}),
 				i18n.Tf("cmd_bookmarks", map[string]interface{}{"Key": kb.BookmarksView}),
+				i18n.Tf("cmd_custom_stations", map[string]interface{}{"Key": kb.CustomStations}),
 				i18n.Tf("cmd_vote", map[string]interface{}{"Key": kb.Vote}),
 				i18n.Tf("cmd_hide", map[string]interface{}{"Key": kb.HideStation}),
 				i18n.Tf("cmd_manage_hidden", map[string]interface{}{"Key": kb.ManageHidden}),
 			}
+			if sortLabel != "" {
+				secondaryCommands = append(secondaryCommands,
+					i18n.Tf("cmd_sort", map[string]interface{}{"Key": kb.SortOrder, "ReverseKey": kb.SortDirection, "Order": sortLabel}),
+				)
+			}
+			secondaryCommands = append(secondaryCommands,
+				i18n.Tf("cmd_refresh", map[string]interface{}{"Key": kb.Refresh}),
+				i18n.Tf("cmd_check_health", map[string]interface{}{"Key": kb.CheckHealth}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+			)
+		} else if viewMode == viewModeCustom {
+			// Custom stations live in storage only, so there's nothing to refresh
+			secondaryCommands = []string{
+				i18n.Tf("cmd_add_station", map[string]interface{}{"Key": kb.AddStation}),
+				i18n.Tf("cmd_edit_station", map[string]interface{}{"Key": kb.EditStation}),
+				i18n.Tf("cmd_delete_station", map[string]interface{}{"Key": kb.DeleteStation}),
+				i18n.Tf("cmd_bookmark", map[string]interface{}{"Key": kb.BookmarkToggle}),
+				i18n.Tf("cmd_check_health", map[string]interface{}{"Key": kb.CheckHealth}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+			}
 		} else {
 			// "B: back" is already in primary row, no hide commands in bookmarks mode
-			secondaryCommands = []string{i18n.Tf("cmd_bookmark", map[string]interface{}{"Key": kb.BookmarkToggle})}
+			secondaryCommands = []string{
+				i18n.Tf("cmd_bookmark", map[string]interface{}{"Key": kb.BookmarkToggle}),
+				i18n.Tf("cmd_refresh", map[string]interface{}{"Key": kb.Refresh}),
+				i18n.Tf("cmd_check_health", map[string]interface{}{"Key": kb.CheckHealth}),
+				i18n.Tf("cmd_alarms", map[string]interface{}{"Key": kb.Alarms}),
+				i18n.Tf("cmd_recordings", map[string]interface{}{"Key": kb.Recordings}),
+			}
 		}
 
 		return bottomBarUpdateMsg{
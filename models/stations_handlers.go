@@ -20,12 +20,16 @@
 package models
 
 import (
+	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/i18n"
 	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/storage"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -36,27 +40,129 @@ import (
 func (m StationsModel) handlePlaybackMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case playbackStartedMsg:
+		resetTitle := m.stopSongTitles()
 		m.currentStation = msg.station
+		m.streamURL = msg.streamURL
+		m.reconnectAttempt = msg.reconnectAttempt
+		m.reconnecting = false
+		m.playingSince = time.Now()
+		resumeRecording := m.resumeRecording && msg.reconnectAttempt > 0
+		m.resumeRecording = false
+		m.resetTimeshift()
+		// The player starts at the chosen volume, the sleep timer fades it again if it has to
+		m.sleepFading = false
+		// Another station, or the alarm's station reconnecting at the chosen volume, ends a ramp-up
+		if msg.station.StationUuid != m.alarmStation || msg.reconnectAttempt > 0 {
+			m.alarmRamping = false
+		}
+		if msg.reconnectAttempt > 0 {
+			// Clears the reconnecting message
+			m.err = ""
+		}
 		m.volumeChangePending = false
 		m.currentStationSpinner = spinner.New()
 		m.currentStationSpinner.Spinner = spinner.Dot
 		m.currentStationSpinner.Style = m.theme.PrimaryText
 		// Rebuild table to show ▶ indicator and recalculate layout for new status bar height
 		m.rebuildTablePreservingCursor(-1)
-		return true, m, tea.Batch(
+		cmds := []tea.Cmd{
 			m.currentStationSpinner.Tick,
-			notifyRadioBrowserCmd(m.browser, m.currentStation),
-			updateCommandsCmd(m.viewMode, true, m.volume, m.playbackManager.VolumeIsPercentage(), m.playbackManager.IsRecording(), m.keybindings),
+			m.updateCommandsCmd(),
 			func() tea.Msg { return playbackStatusMsg{status: PlaybackPlaying} },
-			func() tea.Msg { return recordingStatusMsg{isRecording: false} },
-		)
+		}
+		if resumeRecording {
+			// Playing the station again stopped its recording, which goes on in a new file
+			m.successMsg = i18n.T("recording_resumed")
+			cmds = append(cmds,
+				startRecordingCmd(m.playbackManager, m.recordingLocation, m.songTitle),
+				tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+					return clearSuccessMsg{}
+				}),
+			)
+		} else {
+			cmds = append(cmds, func() tea.Msg { return recordingStatusMsg{isRecording: false} })
+		}
+		// Custom stations and stations from other sources are unknown to RadioBrowser, so there's no click to count.
+		// Reconnecting isn't a new click either.
+		if m.isOnRadioBrowser(m.currentStation) && msg.reconnectAttempt == 0 {
+			cmds = append(cmds, notifyRadioBrowserCmd(m.browser, m.currentStation))
+		}
+		if msg.exits != nil {
+			cmds = append(cmds, waitForPlayerExitCmd(m.currentStation, msg.exits))
+		}
+		if m.songTitleWatcher != nil {
+			titles := m.songTitleWatcher.Start(m.playingStreamURL())
+			cmds = append(cmds, resetTitle, waitForSongTitleCmd(m.currentStation.StationUuid, titles))
+		}
+		return true, m, tea.Batch(cmds...)
+	case playbackPausedMsg:
+		status := PlaybackPlaying
+		if msg.paused {
+			status = PlaybackPaused
+		}
+		statusCmd := func() tea.Msg { return playbackStatusMsg{status: status} }
+		// The now playing box follows how far behind live a timeshifted station is
+		if timeshifter, ok := m.timeshifter(); ok {
+			m.timeshiftRefresh++
+			return true, m, tea.Batch(statusCmd, timeshiftStateCmd(timeshifter, m.timeshiftRefresh))
+		}
+		return true, m, statusCmd
+	case timeshiftStateMsg:
+		if msg.refresh != m.timeshiftRefresh {
+			return true, m, nil
+		}
+		m.timeshift = msg.state
+		// The timeshift line changes the height of the now playing box
+		m.updateTableDimensions()
+		timeshifter, ok := m.timeshifter()
+		if !ok || (!msg.state.Paused && msg.state.Behind == 0) {
+			return true, m, nil
+		}
+		return true, m, scheduleTimeshiftStateCmd(timeshifter, msg.refresh)
+	case songTitleMsg:
+		// Titles of a station that's no longer playing are dropped
+		if msg.station != m.currentStation.StationUuid {
+			return true, m, nil
+		}
+		m.songTitle = msg.title
+		// The song line changes the height of the now playing box
+		m.updateTableDimensions()
+		cmds := []tea.Cmd{
+			tea.SetWindowTitle(windowTitle(m.currentStation, m.songTitle)),
+			waitForSongTitleCmd(msg.station, msg.titles),
+		}
+		if m.storage != nil {
+			cmds = append(cmds, startSongCmd(m.storage, m.currentStation, m.songTitle))
+		}
+		return true, m, tea.Batch(cmds...)
+	case playbackDiedMsg:
+		// Deaths of a station that's no longer playing are dropped
+		if msg.station.StationUuid != m.currentStation.StationUuid {
+			return true, m, nil
+		}
+		cmd := m.handlePlaybackDied(msg)
+		return true, m, cmd
+	case reconnectMsg:
+		// The user stopped or changed station while waiting
+		if !m.reconnecting || msg.attempt != m.reconnectAttempt || msg.station.StationUuid != m.currentStation.StationUuid {
+			return true, m, nil
+		}
+		return true, m, reconnectCmd(m.playbackManager, msg.station, m.volume, msg.attempt)
 	case playbackStoppedMsg:
+		resetTitle := m.stopSongTitles()
 		m.currentStation = common.Station{}
+		m.streamURL = ""
+		m.reconnecting = false
+		m.reconnectAttempt = 0
+		m.resumeRecording = false
+		m.resetTimeshift()
+		m.alarmRamping = false
 		m.currentStationSpinner = spinner.Model{}
 		// Rebuild table to remove ▶ indicator and recalculate layout for new status bar height
 		m.rebuildTablePreservingCursor(-1)
 		return true, m, tea.Batch(
-			updateCommandsCmd(m.viewMode, false, m.volume, m.playbackManager.VolumeIsPercentage(), false, m.keybindings),
+			resetTitle,
+			m.updateCommandsCmd(),
 			func() tea.Msg { return playbackStatusMsg{status: PlaybackIdle} },
 			func() tea.Msg { return recordingStatusMsg{isRecording: false} },
 		)
@@ -98,10 +204,22 @@ func (m StationsModel) handleVolumeMessages(msg tea.Msg) (bool, StationsModel, t
 		return true, m, nil
 	case volumeRestartCompleteMsg:
 		m.currentStation = msg.station
-		return true, m, func() tea.Msg { return playbackStatusMsg{status: PlaybackPlaying} }
+		// A restarted station starts from live, with a new timeshift buffer
+		if !msg.keptBuffer {
+			m.resetTimeshift()
+		}
+		m.updateTableDimensions()
+		cmds := []tea.Cmd{func() tea.Msg { return playbackStatusMsg{status: PlaybackPlaying} }}
+		if msg.exits != nil {
+			cmds = append(cmds, waitForPlayerExitCmd(m.currentStation, msg.exits))
+		}
+		return true, m, tea.Batch(cmds...)
 	case volumeRestartFailedMsg:
 		m.err = i18n.Tf("error_volume_change", map[string]interface{}{"Error": msg.err})
 		return true, m, clearErrorAfterDelayCmd()
+	case volumeSetFailedMsg:
+		m.err = i18n.Tf("error_volume_change", map[string]interface{}{"Error": msg.err})
+		return true, m, clearErrorAfterDelayCmd()
 	}
 	return false, m, nil
 }
@@ -112,12 +230,12 @@ func (m StationsModel) handleRecordingMessages(msg tea.Msg) (bool, StationsModel
 	switch msg := msg.(type) {
 	case recordingStartedMsg:
 		return true, m, tea.Batch(
-			updateCommandsCmd(m.viewMode, true, m.volume, m.playbackManager.VolumeIsPercentage(), true, m.keybindings),
+			m.updateCommandsCmd(),
 			func() tea.Msg { return recordingStatusMsg{isRecording: true} },
 		)
 	case recordingStoppedMsg:
 		return true, m, tea.Batch(
-			updateCommandsCmd(m.viewMode, true, m.volume, m.playbackManager.VolumeIsPercentage(), false, m.keybindings),
+			m.updateCommandsCmd(),
 			func() tea.Msg { return recordingStatusMsg{isRecording: false} },
 		)
 	case recordingErrorMsg:
@@ -134,7 +252,7 @@ func (m StationsModel) handleBookmarkMessages(msg tea.Msg) (bool, StationsModel,
 	case bookmarkToggledMsg:
 		if m.viewMode == viewModeBookmarks {
 			m.savedCursor = m.stationsTable.Cursor()
-			return true, m, fetchBookmarksCmd(m.browser, m.storage)
+			return true, m, fetchBookmarksCmd(context.Background(), m.browser, m.storage)
 		}
 		m.rebuildTablePreservingCursor(-1)
 		return true, m, nil
@@ -151,15 +269,35 @@ func (m StationsModel) handleBookmarkMessages(msg tea.Msg) (bool, StationsModel,
 		m.stations = msg.stations
 		m.rebuildTablePreservingCursor(cursorToRestore)
 		return true, m, tea.Batch(
-			updateCommandsCmd(m.viewMode, m.playbackManager.IsPlaying(), m.volume, m.playbackManager.VolumeIsPercentage(), m.playbackManager.IsRecording(), m.keybindings),
-			func() tea.Msg {
-				return stationCursorMovedMsg{
-					offset:        m.stationsTable.Cursor(),
-					totalStations: len(m.stations),
-				}
-			},
+			m.updateCommandsCmd(),
+			m.cursorMovedCmd(),
 		)
 
+	case bookmarksRefreshedMsg:
+		// The bookmarks view was left before the API answered
+		if m.viewMode != viewModeBookmarks {
+			return true, m, nil
+		}
+		// The cursor stays on the same station, wherever it's listed now
+		cursor := m.stationsTable.Cursor()
+		if cursor < len(m.stations) {
+			selected := m.stations[cursor].StationUuid
+			for i, s := range msg.stations {
+				if s.StationUuid == selected {
+					cursor = i
+					break
+				}
+			}
+		}
+		m.stations = msg.stations
+		m.rebuildTablePreservingCursor(cursor)
+		cmds := []tea.Cmd{m.cursorMovedCmd()}
+		if msg.offline {
+			m.err = i18n.T("bookmarks_offline")
+			cmds = append(cmds, clearErrorAfterDelayCmd())
+		}
+		return true, m, tea.Batch(cmds...)
+
 	case bookmarksFetchFailedMsg:
 		m.err = i18n.Tf("error_load_bookmarks", map[string]interface{}{"Error": msg.err})
 		return true, m, clearErrorAfterDelayCmd()
@@ -171,6 +309,61 @@ func (m StationsModel) handleBookmarkMessages(msg tea.Msg) (bool, StationsModel,
 	return false, m, nil
 }
 
+// handleCustomStationMessages handles custom station-related messages.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handleCustomStationMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case customStationsFetchedMsg:
+		cursor := 0
+		if m.viewMode == viewModeSearchResults {
+			m.savedStations = m.stations
+			m.savedCursor = m.stationsTable.Cursor()
+		} else if m.viewMode == viewModeCustom {
+			cursor = m.stationsTable.Cursor()
+		}
+		for i, s := range msg.stations {
+			if msg.selected != uuid.Nil && s.StationUuid == msg.selected {
+				cursor = i
+				break
+			}
+		}
+		m.viewMode = viewModeCustom
+		m.stations = msg.stations
+		m.rebuildTablePreservingCursor(cursor)
+		return true, m, tea.Batch(
+			m.updateCommandsCmd(),
+			m.cursorMovedCmd(),
+		)
+
+	case customStationsFetchFailedMsg:
+		m.err = i18n.Tf("error_load_custom", map[string]interface{}{"Error": msg.err})
+		return true, m, clearErrorAfterDelayCmd()
+
+	case customStationSavedMsg:
+		m.successMsg = i18n.Tf("custom_saved", map[string]interface{}{"Name": msg.station.Name})
+		return true, m, tea.Batch(
+			fetchCustomStationsCmd(m.storage, msg.station.StationUuid),
+			tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+				return clearSuccessMsg{}
+			}),
+		)
+
+	case customStationSaveFailedMsg:
+		m.err = i18n.Tf("error_custom_save", map[string]interface{}{"Error": msg.err})
+		return true, m, clearErrorAfterDelayCmd()
+
+	case customStationDeletedMsg:
+		m.stations = removeStationByUUID(m.stations, msg.station.StationUuid)
+		m.rebuildTablePreservingCursor(msg.cursor)
+		return true, m, m.cursorMovedCmd()
+
+	case customStationDeleteFailedMsg:
+		m.err = i18n.Tf("error_custom_delete", map[string]interface{}{"Error": msg.err})
+		return true, m, clearErrorAfterDelayCmd()
+	}
+	return false, m, nil
+}
+
 // handleHiddenStationMessages handles hidden station-related messages.
 // Returns (handled, model, cmd) where handled indicates if the message was processed.
 func (m StationsModel) handleHiddenStationMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
@@ -178,12 +371,7 @@ func (m StationsModel) handleHiddenStationMessages(msg tea.Msg) (bool, StationsM
 	case stationHiddenMsg:
 		m.stations = removeStationByUUID(m.stations, msg.station.StationUuid)
 		m.rebuildTablePreservingCursor(msg.cursor)
-		return true, m, func() tea.Msg {
-			return stationCursorMovedMsg{
-				offset:        m.stationsTable.Cursor(),
-				totalStations: len(m.stations),
-			}
-		}
+		return true, m, m.cursorMovedCmd()
 
 	case hiddenFetchedMsg:
 		m.hiddenStations = msg.stations
@@ -213,7 +401,7 @@ func (m StationsModel) handleHiddenStationMessages(msg tea.Msg) (bool, StationsM
 			m.showHiddenModal = false
 			if m.needsRefetch {
 				m.needsRefetch = false
-				return true, m, refetchStationsCmd(m.browser, m.lastQuery, m.lastQueryText)
+				return true, m, m.refetchCmd()
 			}
 		}
 		return true, m, nil
@@ -226,15 +414,12 @@ func (m StationsModel) handleHiddenStationMessages(msg tea.Msg) (bool, StationsM
 			}
 		}
 		m.stations = filtered
+		m.fetchedCount = uint64(len(msg.stations))
+		m.hasMorePages = msg.limit > 0 && m.fetchedCount >= msg.limit
 		// Restore cursor position (used by vote success and unhide)
 		m.rebuildTablePreservingCursor(m.savedCursor)
 		m.savedCursor = 0
-		return true, m, func() tea.Msg {
-			return stationCursorMovedMsg{
-				offset:        m.stationsTable.Cursor(),
-				totalStations: len(m.stations),
-			}
-		}
+		return true, m, m.cursorMovedCmd()
 
 	case stationsRefetchFailedMsg:
 		m.err = i18n.Tf("error_refresh_stations", map[string]interface{}{"Error": msg.err})
@@ -243,6 +428,109 @@ func (m StationsModel) handleHiddenStationMessages(msg tea.Msg) (bool, StationsM
 	return false, m, nil
 }
 
+// handlePaginationMessages handles the arrival of further pages of search results.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handlePaginationMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stationsPageFetchedMsg:
+		m.loadingMore = false
+		// Drop pages that no longer fit: results were refetched, re-sorted or replaced,
+		// or bookmarks are shown meanwhile
+		if msg.source != m.source() || msg.offset != m.fetchedCount || m.viewMode != viewModeSearchResults {
+			return true, m, m.cursorMovedCmd()
+		}
+		m.fetchedCount += uint64(len(msg.stations))
+		m.hasMorePages = len(msg.stations) >= m.pageSize
+		m.stations = appendNewStations(m.stations, msg.stations, m.storage)
+		m.rebuildTablePreservingCursor(-1)
+		// If the whole page was hidden or already listed, keep going
+		return true, m, tea.Batch(m.cursorMovedCmd(), m.loadMoreIfNeeded())
+
+	case stationsPageFetchFailedMsg:
+		m.loadingMore = false
+		m.err = i18n.Tf("error_load_more", map[string]interface{}{"Error": msg.err})
+		return true, m, tea.Batch(m.cursorMovedCmd(), clearErrorAfterDelayCmd())
+	}
+	return false, m, nil
+}
+
+// handleSortMessages handles search results arriving in a new sort order.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handleSortMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case stationsResortedMsg:
+		// Drop results of an order that was switched away from, or that arrive
+		// while bookmarks are shown (the saved results are restored on return)
+		if msg.order != m.sortOrder || msg.reverse != m.sortReverse || m.viewMode != viewModeSearchResults {
+			return true, m, nil
+		}
+		m.stations = filterHiddenStations(msg.stations, m.storage)
+		m.fetchedCount = uint64(len(msg.stations))
+		m.hasMorePages = msg.limit > 0 && m.fetchedCount >= msg.limit
+		m.loadingMore = false
+		// Keep the playing station under the cursor if it's still listed
+		cursor := 0
+		for i, s := range m.stations {
+			if m.currentStation.StationUuid != uuid.Nil && s.StationUuid == m.currentStation.StationUuid {
+				cursor = i
+				break
+			}
+		}
+		m.rebuildTablePreservingCursor(cursor)
+		return true, m, m.cursorMovedCmd()
+	}
+	return false, m, nil
+}
+
+// appendNewStations appends a page of results to the list, skipping hidden stations
+// and stations already listed (results can shift between pages as votes change).
+func appendNewStations(stations []common.Station, page []common.Station, storage storage.StationStorageService) []common.Station {
+	seen := make(map[uuid.UUID]bool, len(stations))
+	for _, s := range stations {
+		seen[s.StationUuid] = true
+	}
+	result := append([]common.Station{}, stations...)
+	for _, s := range filterHiddenStations(page, storage) {
+		if !seen[s.StationUuid] {
+			seen[s.StationUuid] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// handleHealthMessages handles the results of local stream health checks.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handleHealthMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case healthCheckedMsg:
+		m.rebuildTablePreservingCursor(-1)
+		if msg.background {
+			return true, m, nil
+		}
+		m.checkingHealth = false
+		if msg.err != nil {
+			m.successMsg = ""
+			m.err = i18n.Tf("error_health_save", map[string]interface{}{"Error": msg.err})
+			return true, m, clearErrorAfterDelayCmd()
+		}
+		playable := 0
+		for _, result := range msg.results {
+			if result.OK() {
+				playable++
+			}
+		}
+		m.successMsg = i18n.Tfn("health_checked", len(msg.results), map[string]interface{}{
+			"Count":    len(msg.results),
+			"Playable": playable,
+		})
+		return true, m, tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+			return clearSuccessMsg{}
+		})
+	}
+	return false, m, nil
+}
+
 // clearSuccessMsg clears the success message from the status bar.
 type clearSuccessMsg struct{}
 
@@ -254,7 +542,7 @@ func (m StationsModel) handleVoteMessages(msg tea.Msg) (bool, StationsModel, tea
 		m.successMsg = i18n.T("vote_success")
 		m.savedCursor = msg.cursor
 		return true, m, tea.Batch(
-			refetchStationsCmd(m.browser, m.lastQuery, m.lastQueryText),
+			m.refetchCmd(),
 			tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
 				return clearSuccessMsg{}
 			}),
@@ -282,17 +570,24 @@ func (m StationsModel) handleKeyMessage(msg tea.KeyMsg) (bool, StationsModel, te
 	if handled, cmd := m.handleHiddenModalInput(msg); handled {
 		return true, m, cmd
 	}
+	if handled, cmd := m.handleCustomFormInput(msg); handled {
+		return true, m, cmd
+	}
+	if handled, cmd := m.handleSleepInput(msg, time.Now()); handled {
+		return true, m, cmd
+	}
 
 	key := msg.String()
 
+	// Any key other than delete cancels a pending delete
+	if m.deleteCandidate.StationUuid != uuid.Nil && key != m.keybindings.DeleteStation {
+		m.deleteCandidate = common.Station{}
+		m.updateTableDimensions()
+	}
+
 	// Navigation keys - just track cursor movement
 	if key == "up" || key == "down" || key == m.keybindings.NavigateDown || key == m.keybindings.NavigateUp {
-		return false, m, func() tea.Msg {
-			return stationCursorMovedMsg{
-				offset:        m.stationsTable.Cursor(),
-				totalStations: len(m.stations),
-			}
-		}
+		return false, m, m.cursorMovedCmd()
 	}
 
 	switch {
@@ -306,11 +601,29 @@ func (m StationsModel) handleKeyMessage(msg tea.KeyMsg) (bool, StationsModel, te
 		}
 
 	case key == m.keybindings.Quit:
-		return true, m, tea.Sequence(stopStationCmd(m.playbackManager), quitCmd)
+		return true, m, tea.Sequence(stopStationCmd(m.playbackManager), m.endSongOnQuitCmd(), quitCmd)
 
 	case key == m.keybindings.Search:
 		return true, m, func() tea.Msg { return switchToSearchModelMsg{} }
 
+	case key == m.keybindings.Alarms && m.storage != nil:
+		return true, m, func() tea.Msg { return switchToAlarmsModelMsg{} }
+
+	case key == m.keybindings.Recordings && m.storage != nil:
+		return true, m, func() tea.Msg { return switchToRecordingsModelMsg{} }
+
+	case key == m.keybindings.Pause:
+		return true, m, m.handlePauseToggle()
+
+	case key == m.keybindings.JumpToLive:
+		return true, m, m.handleJumpToLive()
+
+	case key == m.keybindings.SleepTimer:
+		return true, m, m.handleSleepTimerCycle(time.Now())
+
+	case key == m.keybindings.SleepTimerInput:
+		return true, m, m.openSleepInput()
+
 	case key == m.keybindings.VolumeDown:
 		return true, m, m.handleVolumeChange(-1)
 
@@ -349,7 +662,30 @@ func (m StationsModel) handleKeyMessage(msg tea.KeyMsg) (bool, StationsModel, te
 		return true, m, hideStationCmd(m.storage, station, m.stationsTable.Cursor())
 
 	case key == m.keybindings.BookmarksView:
-		return m.handleBookmarksViewToggle()
+		return m.handleListViewToggle(viewModeBookmarks)
+
+	case key == m.keybindings.CustomStations:
+		return m.handleListViewToggle(viewModeCustom)
+
+	case m.viewMode == viewModeCustom && key == m.keybindings.AddStation:
+		m.customForm = NewCustomStationForm(m.theme)
+		m.showCustomForm = true
+		return true, m, m.customForm.Init()
+
+	case m.viewMode == viewModeCustom && key == m.keybindings.EditStation:
+		if len(m.stations) == 0 {
+			return true, m, nil
+		}
+		m.customForm = NewCustomStationFormFor(m.theme, m.stations[m.stationsTable.Cursor()])
+		m.showCustomForm = true
+		return true, m, m.customForm.Init()
+
+	case m.viewMode == viewModeCustom && key == m.keybindings.DeleteStation:
+		if len(m.stations) == 0 {
+			return true, m, nil
+		}
+		cmd := m.handleCustomStationDelete()
+		return true, m, cmd
 
 	case key == m.keybindings.ManageHidden:
 		if m.viewMode != viewModeSearchResults {
@@ -362,8 +698,38 @@ func (m StationsModel) handleKeyMessage(msg tea.KeyMsg) (bool, StationsModel, te
 			return true, m, nil
 		}
 		station := m.stations[m.stationsTable.Cursor()]
+		if !m.isOnRadioBrowser(station) {
+			return true, m, nil
+		}
 		return true, m, voteStationCmd(m.browser, m.storage, station, m.stationsTable.Cursor())
 
+	case key == m.keybindings.SortOrder:
+		if m.viewMode != viewModeSearchResults || !m.canResort() {
+			return true, m, nil
+		}
+		order := m.sortOrder.Next()
+		cmd := m.resort(order, order.DefaultReverse())
+		return true, m, cmd
+
+	case key == m.keybindings.SortDirection:
+		if m.viewMode != viewModeSearchResults || !m.canResort() {
+			return true, m, nil
+		}
+		cmd := m.resort(m.sortOrder, !m.sortReverse)
+		return true, m, cmd
+
+	case key == m.keybindings.Refresh:
+		cmd := m.handleForceRefresh()
+		return true, m, cmd
+
+	case key == m.keybindings.CheckHealth:
+		if m.healthChecker == nil || m.checkingHealth || len(m.stations) == 0 {
+			return true, m, nil
+		}
+		m.checkingHealth = true
+		m.successMsg = i18n.Tfn("health_checking", len(m.stations), map[string]interface{}{"Count": len(m.stations)})
+		return true, m, checkHealthCmd(m.healthChecker, m.storage, m.stations)
+
 	case key == "enter":
 		if len(m.stations) == 0 {
 			return true, m, nil
@@ -375,22 +741,30 @@ func (m StationsModel) handleKeyMessage(msg tea.KeyMsg) (bool, StationsModel, te
 	return false, m, nil
 }
 
-// handleBookmarksViewToggle handles toggling between search results and bookmarks view.
-func (m StationsModel) handleBookmarksViewToggle() (bool, StationsModel, tea.Cmd) {
+// handleListViewToggle handles toggling between search results and the bookmarks
+// or custom stations view. The key of the other list view does nothing.
+func (m StationsModel) handleListViewToggle(mode stationsViewMode) (bool, StationsModel, tea.Cmd) {
 	if m.viewMode == viewModeSearchResults {
-		return true, m, tea.Sequence(
-			stopStationCmd(m.playbackManager),
-			fetchBookmarksCmd(m.browser, m.storage),
-		)
+		fetch := fetchBookmarksCmd(context.Background(), m.browser, m.storage)
+		if mode == viewModeCustom {
+			fetch = fetchCustomStationsCmd(m.storage, uuid.Nil)
+		}
+		return true, m, tea.Sequence(stopStationCmd(m.playbackManager), fetch)
+	}
+	if m.viewMode != mode {
+		return true, m, nil
 	}
 
-	// Return from bookmarks to previous stations (or search if none saved)
+	// Return to previous stations (or search if none saved)
 	if len(m.savedStations) > 0 {
 		if err := m.playbackManager.StopStation(); err != nil {
 			m.err = err.Error()
 			// Continue anyway - we're transitioning views
 		}
+		resetTitle := m.stopSongTitles()
 		m.currentStation = common.Station{}
+		m.streamURL = ""
+		m.resetTimeshift()
 		m.currentStationSpinner = spinner.Model{}
 
 		m.viewMode = viewModeSearchResults
@@ -400,19 +774,59 @@ func (m StationsModel) handleBookmarksViewToggle() (bool, StationsModel, tea.Cmd
 		m.savedCursor = 0
 		m.rebuildTablePreservingCursor(cursorToRestore)
 		return true, m, tea.Batch(
-			updateCommandsCmd(m.viewMode, false, m.volume, m.playbackManager.VolumeIsPercentage(), false, m.keybindings),
+			resetTitle,
+			m.updateCommandsCmd(),
 			func() tea.Msg { return playbackStatusMsg{status: PlaybackIdle} },
-			func() tea.Msg {
-				return stationCursorMovedMsg{
-					offset:        m.stationsTable.Cursor(),
-					totalStations: len(m.stations),
-				}
-			},
+			m.cursorMovedCmd(),
 		)
 	}
 	return true, m, func() tea.Msg { return switchToSearchModelMsg{} }
 }
 
+// handleForceRefresh fetches the listed stations again from the API, bypassing the response cache.
+// The cursor stays where it is.
+func (m *StationsModel) handleForceRefresh() tea.Cmd {
+	ctx := api.WithForceRefresh(context.Background())
+	m.savedCursor = m.stationsTable.Cursor()
+	if m.viewMode == viewModeBookmarks {
+		return fetchBookmarksCmd(ctx, m.browser, m.storage)
+	}
+	if m.viewMode == viewModeCustom {
+		return nil
+	}
+	return m.refetchWithContextCmd(ctx)
+}
+
+// handleCustomStationDelete asks for confirmation the first time the delete key is pressed
+// on a custom station, and deletes it the second time. Playback (and recording) of the
+// station is stopped first.
+func (m *StationsModel) handleCustomStationDelete() tea.Cmd {
+	cursor := m.stationsTable.Cursor()
+	station := m.stations[cursor]
+	if m.deleteCandidate.StationUuid != station.StationUuid {
+		m.deleteCandidate = station
+		m.updateTableDimensions()
+		return nil
+	}
+	m.deleteCandidate = common.Station{}
+	m.updateTableDimensions()
+
+	if m.currentStation.StationUuid == station.StationUuid {
+		if m.playbackManager.IsRecording() {
+			return tea.Sequence(
+				stopRecordingCmd(m.playbackManager),
+				stopStationCmd(m.playbackManager),
+				deleteCustomStationCmd(m.storage, station, cursor),
+			)
+		}
+		return tea.Sequence(
+			stopStationCmd(m.playbackManager),
+			deleteCustomStationCmd(m.storage, station, cursor),
+		)
+	}
+	return deleteCustomStationCmd(m.storage, station, cursor)
+}
+
 // handleVolumeChange handles volume increase or decrease with debouncing.
 // direction should be positive for increase, negative for decrease.
 func (m *StationsModel) handleVolumeChange(direction int) tea.Cmd {
@@ -427,17 +841,172 @@ func (m *StationsModel) handleVolumeChange(direction int) tea.Cmd {
 	}
 
 	m.volume = newVolume
+	// The sleep timer keeps fading out, from the new volume, while a ramp-up ends there
+	m.sleepFading = false
+	m.alarmRamping = false
 	if m.playbackManager.IsPlaying() {
+		// Players with live volume control change it in place, no restart needed
+		if controller, ok := m.playbackManager.(playback.VolumeController); ok {
+			return tea.Batch(
+				m.updateCommandsCmd(),
+				setVolumeCmd(controller, m.volume),
+			)
+		}
 		changeID := time.Now().UnixNano()
 		m.pendingVolumeChangeID = changeID
 		m.volumeChangePending = true
 		return tea.Batch(
-			updateCommandsCmd(m.viewMode, true, m.volume, m.playbackManager.VolumeIsPercentage(), m.playbackManager.IsRecording(), m.keybindings),
+			m.updateCommandsCmd(),
 			startVolumeDebounceCmd(changeID),
 			func() tea.Msg { return playbackStatusMsg{status: PlaybackRestarting} },
 		)
 	}
-	return updateCommandsCmd(m.viewMode, false, m.volume, m.playbackManager.VolumeIsPercentage(), false, m.keybindings)
+	return m.updateCommandsCmd()
+}
+
+// handlePlaybackDied handles the player of the playing station stopping by itself:
+// playback is restarted after a delay growing with each attempt, until attempts
+// run out and the station is stopped.
+func (m *StationsModel) handlePlaybackDied(msg playbackDiedMsg) tea.Cmd {
+	resetTitle := m.stopSongTitles()
+	// A station that played for a while starts over from the first attempt
+	if !m.playingSince.IsZero() && time.Since(m.playingSince) >= reconnectResetAfter {
+		m.reconnectAttempt = 0
+	}
+	m.playingSince = time.Time{}
+	reason := describePlayerExit(msg.reason, msg.stderr)
+
+	if m.reconnectAttempt < m.maxReconnectAttempts {
+		m.reconnectAttempt++
+		m.reconnecting = true
+		// The recorder has its own connection, but playing the station again stops it
+		m.resumeRecording = m.resumeRecording || m.playbackManager.IsRecording()
+		delay := reconnectDelay(m.reconnectAttempt)
+		m.err = i18n.Tf("playback_reconnecting", map[string]interface{}{
+			"Reason":   reason,
+			"Seconds":  int(delay.Seconds()),
+			"Attempt":  m.reconnectAttempt,
+			"Attempts": m.maxReconnectAttempts,
+		})
+		m.updateTableDimensions()
+		return tea.Batch(
+			resetTitle,
+			func() tea.Msg { return playbackStatusMsg{status: PlaybackReconnecting} },
+			scheduleReconnectCmd(msg.station, m.reconnectAttempt, delay),
+		)
+	}
+
+	m.reconnecting = false
+	m.reconnectAttempt = 0
+	m.resumeRecording = false
+	m.err = i18n.Tf("playback_died", map[string]interface{}{"Reason": reason})
+	return tea.Batch(resetTitle, stopStationCmd(m.playbackManager))
+}
+
+// describePlayerExit summarizes why a player stopped: the last line it printed,
+// which usually names the problem, followed by how it exited.
+func describePlayerExit(reason string, stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	lastLine := strings.TrimSpace(lines[len(lines)-1])
+	if lastLine == "" {
+		return reason
+	}
+	if reason == "" {
+		return lastLine
+	}
+	return lastLine + " (" + reason + ")"
+}
+
+// stopSongTitles stops following the song titles of the playing station.
+// Returns a command restoring the terminal title and ending the song in the
+// history if a song was shown, or nil.
+func (m *StationsModel) stopSongTitles() tea.Cmd {
+	if m.songTitleWatcher != nil {
+		m.songTitleWatcher.Stop()
+	}
+	if m.songTitle == "" {
+		return nil
+	}
+	m.songTitle = ""
+	resetTitle := tea.SetWindowTitle(windowTitle(m.currentStation, ""))
+	if m.storage == nil {
+		return resetTitle
+	}
+	return tea.Batch(resetTitle, endSongCmd(m.storage))
+}
+
+// endSongOnQuitCmd ends the song in the history when quitting while one is shown, or returns nil.
+func (m StationsModel) endSongOnQuitCmd() tea.Cmd {
+	if m.songTitle == "" || m.storage == nil {
+		return nil
+	}
+	return endSongCmd(m.storage)
+}
+
+// handlePauseToggle handles the pause key press.
+// Stations played through the timeshift buffer keep downloading while paused;
+// otherwise pausing is only possible with players that support it (e.g. mpv).
+func (m *StationsModel) handlePauseToggle() tea.Cmd {
+	if !m.playbackManager.IsPlaying() {
+		return nil
+	}
+
+	if timeshifter, ok := m.timeshifter(); ok {
+		return toggleLivePauseCmd(timeshifter)
+	}
+
+	pauser, ok := m.playbackManager.(playback.Pauser)
+	if !ok {
+		m.err = i18n.Tf("error_pause_unsupported", map[string]interface{}{"Player": m.playbackManager.Name()})
+		return clearErrorAfterDelayCmd()
+	}
+	return togglePauseCmd(pauser)
+}
+
+// isTimeshifting reports whether the playing station goes through the timeshift buffer.
+func (m StationsModel) isTimeshifting() bool {
+	_, ok := m.timeshifter()
+	return ok
+}
+
+// canPause reports whether the playing station can be paused: through the timeshift
+// buffer, or by a player that pauses itself.
+func (m StationsModel) canPause() bool {
+	if m.isTimeshifting() {
+		return true
+	}
+	_, ok := m.playbackManager.(playback.Pauser)
+	return ok
+}
+
+// handleJumpToLive handles the jump to live key press.
+// Only stations played through the timeshift buffer can fall behind live.
+func (m *StationsModel) handleJumpToLive() tea.Cmd {
+	if !m.playbackManager.IsPlaying() {
+		return nil
+	}
+
+	timeshifter, ok := m.timeshifter()
+	if !ok {
+		m.err = i18n.T("error_timeshift_unavailable")
+		return clearErrorAfterDelayCmd()
+	}
+	return jumpToLiveCmd(timeshifter)
+}
+
+// timeshifter returns the playback manager if the playing station goes through its timeshift buffer.
+func (m StationsModel) timeshifter() (playback.Timeshifter, bool) {
+	timeshifter, ok := m.playbackManager.(playback.Timeshifter)
+	if !ok || !timeshifter.IsTimeshifting() {
+		return nil, false
+	}
+	return timeshifter, true
+}
+
+// resetTimeshift forgets the timeshift state of the previous station and stops refreshing it.
+func (m *StationsModel) resetTimeshift() {
+	m.timeshift = playback.TimeshiftState{}
+	m.timeshiftRefresh++
 }
 
 // handleRecordingToggle handles the recording toggle key press.
@@ -455,7 +1024,5 @@ func (m *StationsModel) handleRecordingToggle() tea.Cmd {
 		return clearErrorAfterDelayCmd()
 	}
 
-	station := m.playbackManager.CurrentStation()
-	filename := playback.GenerateRecordingFilename(station.Name, station.Codec)
-	return startRecordingCmd(m.playbackManager, filename)
+	return startRecordingCmd(m.playbackManager, m.recordingLocation, m.songTitle)
 }
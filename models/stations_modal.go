@@ -60,11 +60,11 @@ func (m *StationsModel) handleHiddenModalInput(msg tea.KeyMsg) (bool, tea.Cmd) {
 		if m.needsRefetch {
 			m.needsRefetch = false
 			return true, tea.Batch(
-				refetchStationsCmd(m.browser, m.lastQuery, m.lastQueryText),
-				updateCommandsCmd(m.viewMode, m.playbackManager.IsPlaying(), m.volume, m.playbackManager.VolumeIsPercentage(), m.playbackManager.IsRecording(), m.keybindings),
+				m.refetchCmd(),
+				m.updateCommandsCmd(),
 			)
 		}
-		return true, updateCommandsCmd(m.viewMode, m.playbackManager.IsPlaying(), m.volume, m.playbackManager.VolumeIsPercentage(), m.playbackManager.IsRecording(), m.keybindings)
+		return true, m.updateCommandsCmd()
 	}
 	return true, nil
 }
@@ -98,3 +98,35 @@ func (m StationsModel) renderWithModal(baseView string) string {
 	// Center the modal on the screen
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
 }
+
+// handleCustomFormInput processes keyboard input when the custom station form is open.
+// Returns true if the input was handled (form is showing), false otherwise.
+func (m *StationsModel) handleCustomFormInput(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if !m.showCustomForm {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.showCustomForm = false
+		return true, nil
+	case "enter":
+		station, err := m.customForm.Station()
+		if err != nil {
+			m.customForm.SetError(err.Error())
+			return true, nil
+		}
+		m.showCustomForm = false
+		return true, saveCustomStationCmd(m.storage, station)
+	}
+
+	var cmd tea.Cmd
+	m.customForm, cmd = m.customForm.Update(msg)
+	return true, cmd
+}
+
+// renderWithCustomForm renders the custom station form as a modal centered on the screen.
+func (m StationsModel) renderWithCustomForm() string {
+	modal := m.theme.ModalStyle.Render(m.customForm.View())
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/playback"
+)
+
+// sleepTimerPresets are the durations the sleep timer key cycles through before turning it off.
+var sleepTimerPresets = []time.Duration{15 * time.Minute, 30 * time.Minute, 60 * time.Minute, 90 * time.Minute}
+
+const (
+	// sleepFadeDuration is how long before the sleep timer ends the volume starts fading out.
+	sleepFadeDuration = time.Minute
+	// volumeRestartStep is how often an alarm ramp-up or a sleep timer fade changes the
+	// volume with players that have to restart to change it; other players do it every second.
+	volumeRestartStep = 15 * time.Second
+	// maxSleepTimer is the longest duration that can be typed in.
+	maxSleepTimer = 24 * time.Hour
+)
+
+// countdownIDs hands out the ids of sleep timer countdowns and alarm ramp-ups.
+// It outlives the StationsModel, which is recreated with every new list, so that
+// the ticks of an earlier countdown never match a later one.
+var countdownIDs atomic.Int64
+
+// nextCountdownID returns an id no countdown has used yet.
+func nextCountdownID() int64 {
+	return countdownIDs.Add(1)
+}
+
+// sleepTimerTickMsg is sent every second while the sleep timer runs.
+type sleepTimerTickMsg struct {
+	// timer identifies the countdown that sent it, older countdowns are dropped
+	timer int64
+	now   time.Time
+}
+
+// sleepTimerStatusMsg updates the header's sleep timer countdown.
+type sleepTimerStatusMsg struct {
+	// remaining is the time left before playback stops, 0 when the timer is off
+	remaining time.Duration
+}
+
+// sleepTimerTickCmd sends the next sleepTimerTickMsg of the countdown in a second.
+func sleepTimerTickCmd(timer int64) tea.Cmd {
+	return tea.Tick(time.Second, func(now time.Time) tea.Msg {
+		return sleepTimerTickMsg{timer: timer, now: now}
+	})
+}
+
+func sleepTimerStatusCmd(remaining time.Duration) tea.Cmd {
+	return func() tea.Msg { return sleepTimerStatusMsg{remaining: remaining} }
+}
+
+// nextSleepTimerPreset returns the preset following current: the first one when
+// the timer is off, and 0 (off) after the last one or a typed duration.
+func nextSleepTimerPreset(current time.Duration) time.Duration {
+	if current == 0 {
+		return sleepTimerPresets[0]
+	}
+	for i, preset := range sleepTimerPresets {
+		if preset == current && i+1 < len(sleepTimerPresets) {
+			return sleepTimerPresets[i+1]
+		}
+	}
+	return 0
+}
+
+// parseSleepDuration parses a typed sleep timer duration: a number of minutes
+// ("45") or a duration such as "1h30m".
+func parseSleepDuration(input string) (time.Duration, error) {
+	input = strings.TrimSpace(input)
+	var d time.Duration
+	if minutes, err := strconv.Atoi(input); err == nil {
+		d = time.Duration(minutes) * time.Minute
+	} else if d, err = time.ParseDuration(input); err != nil {
+		return 0, err
+	}
+	if d <= 0 || d > maxSleepTimer {
+		return 0, errors.New("out of range")
+	}
+	return d, nil
+}
+
+// sleepFadeVolume returns the volume to play at with remaining time left on the
+// sleep timer: volume until the last sleepFadeDuration, then down towards 0.
+// The remaining time is rounded up to a multiple of step, so that the volume
+// changes at most once per step.
+func sleepFadeVolume(volume int, remaining, step time.Duration) int {
+	if remaining >= sleepFadeDuration {
+		return volume
+	}
+	if remaining <= 0 {
+		return 0
+	}
+	if step > 0 {
+		remaining = (remaining + step - 1) / step * step
+	}
+	return int(math.Ceil(float64(volume) * float64(remaining) / float64(sleepFadeDuration)))
+}
+
+// formatCountdown formats a duration as m:ss, or h:mm:ss from an hour on.
+func formatCountdown(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// handleSleepTimerMessages handles the sleep timer countdown.
+// Returns (handled, model, cmd) where handled indicates if the message was processed.
+func (m StationsModel) handleSleepTimerMessages(msg tea.Msg) (bool, StationsModel, tea.Cmd) {
+	tick, ok := msg.(sleepTimerTickMsg)
+	if !ok {
+		return false, m, nil
+	}
+	if tick.timer != m.sleepTimer || m.sleepAt.IsZero() {
+		return true, m, nil
+	}
+
+	remaining := m.sleepAt.Sub(tick.now)
+	if remaining <= 0 {
+		m.sleepAt = time.Time{}
+		m.sleepDuration = 0
+		m.sleepFading = false
+		cmds := []tea.Cmd{sleepTimerStatusCmd(0)}
+		if m.playbackManager.IsPlaying() {
+			// Stopping the station also stops its recording
+			m.successMsg = i18n.T("sleep_timer_ended")
+			cmds = append(cmds, stopStationCmd(m.playbackManager), tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+				return clearSuccessMsg{}
+			}))
+		}
+		return true, m, tea.Batch(cmds...)
+	}
+
+	cmds := []tea.Cmd{sleepTimerStatusCmd(remaining), sleepTimerTickCmd(m.sleepTimer)}
+	if m.playbackManager.IsPlaying() && remaining < sleepFadeDuration {
+		volume := sleepFadeVolume(m.volume, remaining, m.volumeStep())
+		if volume != m.playingVolume() {
+			m.sleepFading = true
+			m.sleepFadeVolume = volume
+			cmds = append(cmds, m.applyVolumeCmd(volume))
+		}
+	}
+	return true, m, tea.Batch(cmds...)
+}
+
+// handleSleepTimerCycle handles the sleep timer key press: 15, 30, 60 and 90
+// minutes, then off.
+func (m *StationsModel) handleSleepTimerCycle(now time.Time) tea.Cmd {
+	return m.setSleepTimer(nextSleepTimerPreset(m.sleepDuration), now)
+}
+
+// setSleepTimer stops playback after d, or turns the sleep timer off if d is 0.
+// A fade in progress is undone.
+func (m *StationsModel) setSleepTimer(d time.Duration, now time.Time) tea.Cmd {
+	m.sleepTimer = nextCountdownID()
+	m.sleepDuration = d
+
+	var restore tea.Cmd
+	if m.sleepFading {
+		m.sleepFading = false
+		if m.playbackManager.IsPlaying() {
+			restore = m.applyVolumeCmd(m.volume)
+		}
+	}
+
+	if d == 0 {
+		m.sleepAt = time.Time{}
+		return tea.Batch(restore, sleepTimerStatusCmd(0))
+	}
+	m.sleepAt = now.Add(d)
+	return tea.Batch(restore, sleepTimerStatusCmd(d), sleepTimerTickCmd(m.sleepTimer))
+}
+
+// volumeStep returns how often a ramp-up or a fade changes the volume: every second with players
+// that change it in place, every volumeRestartStep with players that restart.
+func (m StationsModel) volumeStep() time.Duration {
+	if _, ok := m.playbackManager.(playback.VolumeController); ok {
+		return time.Second
+	}
+	return volumeRestartStep
+}
+
+// playingVolume returns the volume the station plays at, lowered while an alarm
+// ramps up or the sleep timer fades out.
+func (m StationsModel) playingVolume() int {
+	if m.alarmRamping {
+		return m.alarmRampVolume
+	}
+	if m.sleepFading {
+		return m.sleepFadeVolume
+	}
+	return m.volume
+}
+
+// applyVolumeCmd changes the volume of the playing station, without changing the
+// volume chosen by the user.
+func (m StationsModel) applyVolumeCmd(volume int) tea.Cmd {
+	if controller, ok := m.playbackManager.(playback.VolumeController); ok {
+		return setVolumeCmd(controller, volume)
+	}
+	return restartPlaybackWithVolumeCmd(m.playbackManager, m.currentStation, volume)
+}
+
+// newSleepInput creates the input the sleep timer duration is typed in.
+func newSleepInput(theme Theme) textinput.Model {
+	input := textinput.New()
+	input.Prompt = i18n.T("sleep_timer_prompt") + " "
+	input.Placeholder = "45"
+	input.CharLimit = 10
+	input.Width = 12
+	input.PromptStyle = theme.SecondaryText
+	input.TextStyle = theme.Text
+	input.PlaceholderStyle = theme.TertiaryText
+	return input
+}
+
+// openSleepInput shows the input where the sleep timer duration is typed in.
+func (m *StationsModel) openSleepInput() tea.Cmd {
+	m.sleepInput = newSleepInput(m.theme)
+	m.showSleepInput = true
+	m.updateTableDimensions()
+	return m.sleepInput.Focus()
+}
+
+// handleSleepInput handles key presses while the sleep timer duration is typed in.
+// Returns true if the input captured the key.
+func (m *StationsModel) handleSleepInput(msg tea.KeyMsg, now time.Time) (bool, tea.Cmd) {
+	if !m.showSleepInput {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.showSleepInput = false
+		m.updateTableDimensions()
+		return true, nil
+	case "enter":
+		m.showSleepInput = false
+		m.updateTableDimensions()
+		d, err := parseSleepDuration(m.sleepInput.Value())
+		if err != nil {
+			m.err = i18n.Tf("error_sleep_timer_invalid", map[string]interface{}{"Input": m.sleepInput.Value()})
+			return true, clearErrorAfterDelayCmd()
+		}
+		return true, m.setSleepTimer(d, now)
+	}
+
+	var cmd tea.Cmd
+	m.sleepInput, cmd = m.sleepInput.Update(msg)
+	return true, cmd
+}
@@ -20,32 +20,69 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/api"
 	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/health"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
 	"github.com/zi0p4tch0/radiogogo/mocks"
+	"github.com/zi0p4tch0/radiogogo/playback"
+	"github.com/zi0p4tch0/radiogogo/providers"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
 )
 
 var defaultStationsKeybindings = config.Keybindings{
-	Quit:           "q",
-	Search:         "s",
-	Record:         "r",
-	BookmarkToggle: "b",
-	BookmarksView:  "B",
-	HideStation:    "h",
-	ManageHidden:   "H",
-	ChangeLanguage: "L",
-	VolumeDown:     "9",
-	VolumeUp:       "0",
-	NavigateDown:   "j",
-	NavigateUp:     "k",
-	StopPlayback:   "ctrl+k",
+	Quit:            "q",
+	Search:          "s",
+	Record:          "r",
+	BookmarkToggle:  "b",
+	BookmarksView:   "B",
+	HideStation:     "h",
+	ManageHidden:    "H",
+	ChangeLanguage:  "L",
+	VolumeDown:      "9",
+	VolumeUp:        "0",
+	NavigateDown:    "j",
+	NavigateUp:      "k",
+	StopPlayback:    "ctrl+k",
+	SortOrder:       "o",
+	SortDirection:   "O",
+	Refresh:         "ctrl+r",
+	CustomStations:  "C",
+	Source:          "alt+s",
+	AddStation:      "a",
+	EditStation:     "e",
+	DeleteStation:   "D",
+	CheckHealth:     "c",
+	Pause:           "p",
+	History:         "ctrl+y",
+	ExportCSV:       "ctrl+x",
+	ExportJSON:      "ctrl+j",
+	JumpToLive:      "l",
+	SleepTimer:      "z",
+	SleepTimerInput: "Z",
+	Alarms:          "alt+a",
+	Recordings:      "alt+r",
 }
 
 func createTestStation(name string) common.Station {
@@ -302,6 +339,18 @@ func executeSequenceCommands(msg tea.Msg) {
 	}
 }
 
+// runSequence runs the commands of a tea.Sequence message in order, returning their messages.
+func runSequence(msg tea.Msg) []tea.Msg {
+	var msgs []tea.Msg
+	seqValue := reflect.ValueOf(msg)
+	for i := 0; i < seqValue.Len(); i++ {
+		if cmd, ok := seqValue.Index(i).Interface().(tea.Cmd); ok && cmd != nil {
+			msgs = append(msgs, cmd())
+		}
+	}
+	return msgs
+}
+
 func TestHideStation_StopsPlaybackWhenHidingPlayingStation(t *testing.T) {
 	station := createTestStation("Test Radio")
 	stations := []common.Station{station}
@@ -389,6 +438,91 @@ func TestHideStation_StopsPlaybackWhenHidingPlayingStation(t *testing.T) {
 	})
 }
 
+func TestStationsModel_RecordingLocation(t *testing.T) {
+	station := createTestStation("Jazz FM")
+	station.Codec = "AAC"
+
+	newRecordingModel := func(pm *mocks.MockPlaybackManagerService, location playback.RecordingLocation) StationsModel {
+		model := NewStationsModel(Theme{}, nil, pm, &mocks.MockStationStorageService{}, []common.Station{station}, viewModeSearchResults, "", "", defaultStationsKeybindings)
+		model.currentStation = station
+		model.SetRecordingLocation(location)
+		return model
+	}
+
+	t.Run("records into the configured directory and template", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "recordings")
+		var recordedPath string
+		mockPM := &mocks.MockPlaybackManagerService{
+			IsPlayingResult:            true,
+			IsRecordingAvailableResult: true,
+			CurrentStationResult:       station,
+			StartRecordingFunc: func(outputPath string) error {
+				recordedPath = outputPath
+				return nil
+			},
+		}
+		model := newRecordingModel(mockPM, playback.RecordingLocation{Directory: dir, FilenameTemplate: "{station}/{title}"})
+		model.songTitle = "Miles Davis - So What"
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if assert.NotNil(t, cmd) {
+			msg := cmd()
+			assert.Equal(t, recordingStartedMsg{filePath: recordedPath}, msg)
+		}
+		assert.Equal(t, filepath.Join(dir, "jazz_fm", "miles_davis_-_so_what.aac"), recordedPath)
+	})
+
+	t.Run("reports template errors without recording", func(t *testing.T) {
+		startCalled := false
+		mockPM := &mocks.MockPlaybackManagerService{
+			IsPlayingResult:            true,
+			IsRecordingAvailableResult: true,
+			CurrentStationResult:       station,
+			StartRecordingFunc: func(outputPath string) error {
+				startCalled = true
+				return nil
+			},
+		}
+		model := newRecordingModel(mockPM, playback.RecordingLocation{Directory: t.TempDir(), FilenameTemplate: "../{station}"})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if assert.NotNil(t, cmd) {
+			assert.IsType(t, recordingErrorMsg{}, cmd())
+		}
+		assert.False(t, startCalled)
+	})
+
+	t.Run("removes the file if recording doesn't start", func(t *testing.T) {
+		dir := t.TempDir()
+		mockPM := &mocks.MockPlaybackManagerService{
+			IsPlayingResult:            true,
+			IsRecordingAvailableResult: true,
+			CurrentStationResult:       station,
+			StartRecordingFunc: func(outputPath string) error {
+				return errors.New("ffmpeg failed")
+			},
+		}
+		model := newRecordingModel(mockPM, playback.RecordingLocation{Directory: dir})
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if assert.NotNil(t, cmd) {
+			assert.IsType(t, recordingErrorMsg{}, cmd())
+		}
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestStationsModel_ScheduledRecordingsKey(t *testing.T) {
+	model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r"), Alt: true})
+
+	assert.NotNil(t, cmd)
+	assert.IsType(t, switchToRecordingsModelMsg{}, cmd())
+}
+
 func TestHideStation_StopsRecordingAndPlaybackWhenHidingRecordingStation(t *testing.T) {
 	station := createTestStation("Test Radio")
 	stations := []common.Station{station}
@@ -509,6 +643,25 @@ func TestFormatNumber(t *testing.T) {
 	}
 }
 
+func TestFormatDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    float64
+		expected string
+	}{
+		{"under one km", 0.4, "<1 km"},
+		{"under ten km keeps a decimal", 7.26, "7.3 km"},
+		{"ten km and more is rounded", 42.6, "43 km"},
+		{"far away", 1234, "1234 km"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatDistance(tt.input))
+		})
+	}
+}
+
 func TestMin(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -561,7 +714,7 @@ func TestQualityStringFormatting(t *testing.T) {
 			}
 
 			mockStorage := &mocks.MockStationStorageService{}
-			table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+			table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 			// Get the first row (quality is column index 2)
 			rows := table.Rows()
@@ -581,7 +734,7 @@ func TestQualityStringFormatting(t *testing.T) {
 		}
 
 		mockStorage := &mocks.MockStationStorageService{}
-		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 		rows := table.Rows()
 		assert.NotEmpty(t, rows)
@@ -600,7 +753,7 @@ func TestQualityStringFormatting(t *testing.T) {
 		}
 
 		mockStorage := &mocks.MockStationStorageService{}
-		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 		rows := table.Rows()
 		qualityColumn := rows[0][2]
@@ -617,7 +770,7 @@ func TestQualityStringFormatting(t *testing.T) {
 		}
 
 		mockStorage := &mocks.MockStationStorageService{}
-		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 		rows := table.Rows()
 		qualityColumn := rows[0][2]
@@ -634,7 +787,7 @@ func TestQualityStringFormatting(t *testing.T) {
 		}
 
 		mockStorage := &mocks.MockStationStorageService{}
-		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 		rows := table.Rows()
 		qualityColumn := rows[0][2]
@@ -652,7 +805,7 @@ func TestQualityStringFormatting(t *testing.T) {
 		}
 
 		mockStorage := &mocks.MockStationStorageService{}
-		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{})
+		table := newStationsTableModel(Theme{}, []common.Station{station}, mockStorage, common.Station{}, nil)
 
 		rows := table.Rows()
 		qualityColumn := rows[0][2]
@@ -661,3 +814,2304 @@ func TestQualityStringFormatting(t *testing.T) {
 		assert.NotContains(t, qualityColumn, "★")
 	})
 }
+
+func createTestStations(count int) []common.Station {
+	stations := make([]common.Station, count)
+	for i := range stations {
+		stations[i] = createTestStation("Radio " + strconv.Itoa(i))
+	}
+	return stations
+}
+
+// loadedModel sizes model like a 120x30 terminal and sends it loaded, the message
+// its Init command loads the screen with.
+func loadedModel[M tea.Model, P interface {
+	*M
+	SetWidthAndHeight(width int, height int)
+}](model M, loaded tea.Msg) M {
+	P(&model).SetWidthAndHeight(120, 30)
+	newModel, _ := model.Update(loaded)
+	return newModel.(M)
+}
+
+// findMsgInCmd runs cmd (descending into batches) and returns the first message matching match.
+func findMsgInCmd(cmd tea.Cmd, match func(tea.Msg) bool) tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if found := findMsgInCmd(c, match); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	if match(msg) {
+		return msg
+	}
+	return nil
+}
+
+func TestStationsModel_Pagination(t *testing.T) {
+
+	newPagedModel := func(browser *mocks.MockRadioBrowserService, stations []common.Station, pageSize int) StationsModel {
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.browser = browser
+		model.lastQuery = common.StationQueryByTag
+		model.lastQueryText = "pop"
+		model.EnablePaging(pageSize, len(stations))
+		model.SetWidthAndHeight(120, 40)
+		return model
+	}
+
+	moveTo := func(model StationsModel, row int) StationsModel {
+		model.stationsTable.SetCursor(row - 1)
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+		return newModel.(StationsModel)
+	}
+
+	t.Run("a short first page disables paging", func(t *testing.T) {
+		model := newPagedModel(&mocks.MockRadioBrowserService{}, createTestStations(5), 20)
+
+		assert.False(t, model.hasMorePages)
+	})
+
+	t.Run("fetches the next page when the cursor nears the end", func(t *testing.T) {
+		var gotOffset, gotLimit uint64
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				assert.Equal(t, common.StationQueryByTag, stationQuery)
+				assert.Equal(t, "pop", searchTerm)
+				gotOffset, gotLimit = offset, limit
+				return createTestStations(20), nil
+			},
+		}
+		model := newPagedModel(browser, createTestStations(20), 20)
+
+		// Far from the end: nothing happens
+		model = moveTo(model, 2)
+		assert.False(t, model.loadingMore)
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+		model = newModel.(StationsModel)
+		assert.True(t, model.loadingMore)
+
+		page := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(stationsPageFetchedMsg)
+			return ok
+		})
+		assert.NotNil(t, page)
+		assert.Equal(t, uint64(20), gotOffset)
+		assert.Equal(t, uint64(20), gotLimit)
+
+		cursor := model.stationsTable.Cursor()
+		newModel, _ = model.Update(page)
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.loadingMore)
+		assert.True(t, model.hasMorePages)
+		assert.Len(t, model.stations, 40)
+		assert.Equal(t, uint64(40), model.fetchedCount)
+		assert.Equal(t, cursor, model.stationsTable.Cursor())
+	})
+
+	t.Run("reports loading more to the header", func(t *testing.T) {
+		model := newPagedModel(&mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				return nil, nil
+			},
+		}, createTestStations(20), 20)
+
+		model.stationsTable.SetCursor(18)
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+
+		moved := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			m, ok := msg.(stationCursorMovedMsg)
+			return ok && m.loadingMore
+		})
+		assert.NotNil(t, moved)
+		assert.Equal(t, 20, moved.(stationCursorMovedMsg).totalStations)
+	})
+
+	t.Run("stops after a short page and skips hidden and duplicate stations", func(t *testing.T) {
+		stations := createTestStations(20)
+		hidden := createTestStation("Hidden")
+		model := newPagedModel(&mocks.MockRadioBrowserService{}, stations, 20)
+		model.storage = &mocks.MockStationStorageService{
+			IsHiddenFunc: func(id uuid.UUID) bool { return id == hidden.StationUuid },
+		}
+		model.loadingMore = true
+
+		page := []common.Station{stations[19], hidden, createTestStation("New")}
+		newModel, _ := model.Update(stationsPageFetchedMsg{source: model.source(), offset: 20, stations: page})
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.hasMorePages)
+		assert.Equal(t, uint64(23), model.fetchedCount)
+		assert.Len(t, model.stations, 21)
+		assert.Equal(t, "New", model.stations[20].Name)
+	})
+
+	t.Run("keeps the playing station highlighted when appending", func(t *testing.T) {
+		stations := createTestStations(20)
+		model := newPagedModel(&mocks.MockRadioBrowserService{}, stations, 20)
+		model.currentStation = stations[3]
+		model.loadingMore = true
+
+		newModel, _ := model.Update(stationsPageFetchedMsg{source: model.source(), offset: 20, stations: createTestStations(20)})
+		model = newModel.(StationsModel)
+
+		assert.Contains(t, model.stationsTable.Rows()[3][0], "▶ ")
+		assert.Equal(t, stations[3], model.currentStation)
+	})
+
+	t.Run("ignores pages for outdated offsets", func(t *testing.T) {
+		model := newPagedModel(&mocks.MockRadioBrowserService{}, createTestStations(20), 20)
+		model.loadingMore = true
+
+		newModel, _ := model.Update(stationsPageFetchedMsg{source: model.source(), offset: 40, stations: createTestStations(20)})
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.loadingMore)
+		assert.Len(t, model.stations, 20)
+	})
+
+	t.Run("shows an error and allows retrying when a page fails", func(t *testing.T) {
+		model := newPagedModel(&mocks.MockRadioBrowserService{}, createTestStations(20), 20)
+		model.loadingMore = true
+
+		newModel, _ := model.Update(stationsPageFetchFailedMsg{err: io.EOF})
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.loadingMore)
+		assert.True(t, model.hasMorePages)
+		assert.Contains(t, model.err, "EOF")
+	})
+
+	t.Run("refetches every loaded page", func(t *testing.T) {
+		var gotLimit uint64
+		browser := &mocks.MockRadioBrowserService{
+			SearchStationsFunc: func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+				assert.Equal(t, uint64(0), params.Offset)
+				gotLimit = params.Limit
+				return createTestStations(35), nil
+			},
+		}
+		model := newPagedModel(browser, createTestStations(20), 20)
+		model.lastSearchParams = &common.StationSearchParams{Codec: "AAC"}
+		model.fetchedCount = 40
+
+		msg := model.refetchCmd()()
+		assert.Equal(t, uint64(40), gotLimit)
+
+		newModel, _ := model.Update(msg)
+		model = newModel.(StationsModel)
+		assert.Len(t, model.stations, 35)
+		assert.Equal(t, uint64(35), model.fetchedCount)
+		assert.False(t, model.hasMorePages)
+	})
+}
+
+func TestStationsModel_Sort(t *testing.T) {
+
+	newSortedModel := func(browser *mocks.MockRadioBrowserService, stations []common.Station) StationsModel {
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.browser = browser
+		model.lastQuery = common.StationQueryByTag
+		model.lastQueryText = "pop"
+		model.EnablePaging(20, len(stations))
+		model.SetSort(common.StationOrderVotes, true)
+		model.SetWidthAndHeight(120, 40)
+		return model
+	}
+
+	pressKey := func(model StationsModel, key string) (StationsModel, tea.Cmd) {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(StationsModel), cmd
+	}
+
+	findResorted := func(cmd tea.Cmd) tea.Msg {
+		return findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(stationsResortedMsg)
+			return ok
+		})
+	}
+
+	t.Run("cycles the sort field and re-queries in its natural direction", func(t *testing.T) {
+		var gotOrder string
+		var gotReverse bool
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				gotOrder, gotReverse = order, reverse
+				assert.Equal(t, "pop", searchTerm)
+				assert.Equal(t, uint64(0), offset)
+				return createTestStations(20), nil
+			},
+		}
+		model := newSortedModel(browser, createTestStations(20))
+
+		model, cmd := pressKey(model, "o")
+
+		assert.Equal(t, common.StationOrderClickCount, model.sortOrder)
+		assert.True(t, model.sortReverse)
+		assert.NotNil(t, findResorted(cmd))
+		assert.Equal(t, "clickcount", gotOrder)
+		assert.True(t, gotReverse)
+
+		saved := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(sortOrderChangedMsg)
+			return ok
+		})
+		assert.Equal(t, sortOrderChangedMsg{order: common.StationOrderClickCount, reverse: true}, saved)
+	})
+
+	t.Run("flips the sort direction", func(t *testing.T) {
+		var gotReverse = true
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				assert.Equal(t, "votes", order)
+				gotReverse = reverse
+				return createTestStations(20), nil
+			},
+		}
+		model := newSortedModel(browser, createTestStations(20))
+
+		model, cmd := pressKey(model, "O")
+
+		assert.False(t, model.sortReverse)
+		assert.NotNil(t, findResorted(cmd))
+		assert.False(t, gotReverse)
+	})
+
+	t.Run("keeps the playing station under the cursor", func(t *testing.T) {
+		stations := createTestStations(20)
+		playing := stations[2]
+		resorted := append([]common.Station{}, stations[10:]...)
+		resorted = append(resorted, stations[:10]...)
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				return resorted, nil
+			},
+		}
+		model := newSortedModel(browser, stations)
+		model.currentStation = playing
+		model.loadingMore = true
+
+		model, cmd := pressKey(model, "o")
+		newModel, _ := model.Update(findResorted(cmd))
+		model = newModel.(StationsModel)
+
+		assert.Equal(t, resorted, model.stations)
+		assert.Equal(t, 12, model.stationsTable.Cursor())
+		assert.Equal(t, playing.StationUuid, model.currentStation.StationUuid)
+		assert.Contains(t, model.stationsTable.Rows()[12][0], "▶")
+		assert.False(t, model.loadingMore)
+		assert.True(t, model.hasMorePages)
+		assert.Equal(t, uint64(20), model.fetchedCount)
+	})
+
+	t.Run("drops results of an order that was switched away from", func(t *testing.T) {
+		stations := createTestStations(20)
+		model := newSortedModel(&mocks.MockRadioBrowserService{}, stations)
+
+		newModel, _ := model.Update(stationsResortedMsg{
+			order:    common.StationOrderName,
+			reverse:  false,
+			stations: createTestStations(3),
+			limit:    20,
+		})
+
+		assert.Equal(t, stations, newModel.(StationsModel).stations)
+	})
+
+	t.Run("is disabled in bookmarks view", func(t *testing.T) {
+		model := newSortedModel(&mocks.MockRadioBrowserService{}, createTestStations(5))
+		model.viewMode = viewModeBookmarks
+
+		model, cmd := pressKey(model, "o")
+
+		assert.Nil(t, cmd)
+		assert.Equal(t, common.StationOrderVotes, model.sortOrder)
+	})
+
+	t.Run("shows the sort order in the bottom bar", func(t *testing.T) {
+		model := newSortedModel(&mocks.MockRadioBrowserService{}, createTestStations(5))
+
+		msg := model.updateCommandsCmd()()
+
+		assert.Contains(t, msg.(bottomBarUpdateMsg).secondaryCommands, "o/O: sort (Votes ↓)")
+	})
+}
+
+func TestStationsModel_ForceRefresh(t *testing.T) {
+
+	forcedBrowser := func(stations []common.Station, forced *bool) *mocks.MockRadioBrowserService {
+		return &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				*forced = api.IsForceRefresh(ctx)
+				return stations, nil
+			},
+		}
+	}
+
+	t.Run("refetches search results bypassing the cache after a vote", func(t *testing.T) {
+		stations := createTestStations(10)
+		var forced bool
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.browser = forcedBrowser(stations, &forced)
+
+		_, cmd := model.Update(voteSucceededMsg{cursor: 2})
+
+		// The first command of the batch refetches, the second clears the message later
+		batch := cmd().(tea.BatchMsg)
+		assert.IsType(t, stationsRefetchedMsg{}, batch[0]())
+		assert.True(t, forced)
+	})
+
+	t.Run("refetches search results bypassing the cache after unhiding a station", func(t *testing.T) {
+		stations := createTestStations(10)
+		var forced bool
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.browser = forcedBrowser(stations, &forced)
+		model.hiddenStations = []common.Station{stations[0]}
+		model.showHiddenModal = true
+
+		_, cmd := model.Update(stationUnhiddenMsg{station: stations[0]})
+
+		assert.IsType(t, stationsRefetchedMsg{}, cmd())
+		assert.True(t, forced)
+	})
+
+	t.Run("refetches search results bypassing the cache and keeps the cursor", func(t *testing.T) {
+		stations := createTestStations(10)
+		var forced bool
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				forced = api.IsForceRefresh(ctx)
+				return stations, nil
+			},
+		}
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.browser = browser
+		model.SetWidthAndHeight(120, 40)
+		model.stationsTable.SetCursor(4)
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+		model = newModel.(StationsModel)
+		assert.NotNil(t, cmd)
+
+		msg := cmd()
+		assert.IsType(t, stationsRefetchedMsg{}, msg)
+		assert.True(t, forced)
+
+		newModel, _ = model.Update(msg)
+		assert.Equal(t, 4, newModel.(StationsModel).stationsTable.Cursor())
+	})
+
+	t.Run("refetches bookmarks in bookmarks view", func(t *testing.T) {
+		bookmarked := createTestStation("Bookmarked")
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				return []common.Station{bookmarked}, nil
+			},
+		}
+		model := createTestStationsModel([]common.Station{bookmarked}, defaultStationsKeybindings)
+		model.browser = browser
+		model.storage = &mocks.MockStationStorageService{
+			GetBookmarksFunc: func() ([]uuid.UUID, error) {
+				return []uuid.UUID{bookmarked.StationUuid}, nil
+			},
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) {
+				return []common.Station{bookmarked}, nil
+			},
+		}
+		model.viewMode = viewModeBookmarks
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+
+		assert.Equal(t, []tea.Msg{
+			bookmarksFetchedMsg{stations: []common.Station{bookmarked}},
+			bookmarksRefreshedMsg{stations: []common.Station{bookmarked}},
+		}, runSequence(cmd()))
+	})
+}
+
+func TestStationsModel_OfflineBookmarks(t *testing.T) {
+
+	online := createTestStation("Online")
+	saved := createTestStation("Saved")
+
+	newStorage := func(snapshots []common.Station, saveCalls *[][]common.Station) *mocks.MockStationStorageService {
+		return &mocks.MockStationStorageService{
+			GetBookmarksFunc: func() ([]uuid.UUID, error) {
+				return []uuid.UUID{online.StationUuid, saved.StationUuid}, nil
+			},
+			GetBookmarkedStationsFunc: func() ([]common.Station, error) {
+				return snapshots, nil
+			},
+			SaveBookmarkSnapshotsFunc: func(stations []common.Station) error {
+				*saveCalls = append(*saveCalls, stations)
+				return nil
+			},
+		}
+	}
+
+	t.Run("refreshes snapshots when the API is reachable", func(t *testing.T) {
+		var saveCalls [][]common.Station
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				return []common.Station{online}, nil
+			},
+		}
+
+		msg := refreshBookmarksCmd(context.Background(), browser, newStorage([]common.Station{saved}, &saveCalls))()
+
+		// Stations the API doesn't return anymore are kept from their snapshots
+		assert.Equal(t, bookmarksRefreshedMsg{stations: []common.Station{online, saved}}, msg)
+		assert.Equal(t, [][]common.Station{{online}}, saveCalls)
+	})
+
+	t.Run("falls back to snapshots when the API is unreachable", func(t *testing.T) {
+		var saveCalls [][]common.Station
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				return nil, errors.New("network unreachable")
+			},
+		}
+
+		msg := refreshBookmarksCmd(context.Background(), browser, newStorage([]common.Station{saved, online}, &saveCalls))()
+
+		assert.Equal(t, bookmarksRefreshedMsg{stations: []common.Station{online, saved}, offline: true}, msg)
+		assert.Empty(t, saveCalls)
+	})
+
+	t.Run("fails when offline without snapshots", func(t *testing.T) {
+		var saveCalls [][]common.Station
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				return nil, errors.New("network unreachable")
+			},
+		}
+
+		msg := refreshBookmarksCmd(context.Background(), browser, newStorage(nil, &saveCalls))()
+
+		assert.IsType(t, bookmarksFetchFailedMsg{}, msg)
+	})
+
+	t.Run("lists the snapshots before asking the API", func(t *testing.T) {
+		var saveCalls [][]common.Station
+		asked := false
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				asked = true
+				return []common.Station{online}, nil
+			},
+		}
+		storage := newStorage([]common.Station{saved, online}, &saveCalls)
+
+		msg := savedBookmarksCmd(storage)()
+
+		assert.Equal(t, bookmarksFetchedMsg{stations: []common.Station{online, saved}}, msg)
+		assert.False(t, asked)
+		assert.Equal(t, []tea.Msg{msg, bookmarksRefreshedMsg{stations: []common.Station{online, saved}}}, runSequence(fetchBookmarksCmd(context.Background(), browser, storage)()))
+		assert.True(t, asked)
+	})
+
+	t.Run("keeps the cursor on the same station when refreshed", func(t *testing.T) {
+		other := createTestStation("Other")
+		model := createTestStationsModel([]common.Station{online, saved}, defaultStationsKeybindings)
+		model.viewMode = viewModeBookmarks
+		model.stationsTable.SetCursor(1)
+
+		newModel, _ := model.Update(bookmarksRefreshedMsg{stations: []common.Station{online, other, saved}})
+
+		assert.Equal(t, 2, newModel.(StationsModel).stationsTable.Cursor())
+		assert.Len(t, newModel.(StationsModel).stations, 3)
+	})
+
+	t.Run("drops a refresh after the bookmarks view was left", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(3), defaultStationsKeybindings)
+
+		newModel, cmd := model.Update(bookmarksRefreshedMsg{stations: []common.Station{saved}})
+
+		assert.Nil(t, cmd)
+		assert.Len(t, newModel.(StationsModel).stations, 3)
+	})
+
+	t.Run("tells the user the bookmarks are offline snapshots", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(3), defaultStationsKeybindings)
+		newModel, _ := model.Update(bookmarksFetchedMsg{stations: []common.Station{saved}})
+
+		newModel, _ = newModel.(StationsModel).Update(bookmarksRefreshedMsg{stations: []common.Station{saved}, offline: true})
+
+		assert.Equal(t, viewModeBookmarks, newModel.(StationsModel).viewMode)
+		assert.Equal(t, i18n.T("bookmarks_offline"), newModel.(StationsModel).err)
+	})
+
+	t.Run("saves a snapshot when bookmarking", func(t *testing.T) {
+		var saveCalls [][]common.Station
+		storage := &mocks.MockStationStorageService{
+			SaveBookmarkSnapshotsFunc: func(stations []common.Station) error {
+				saveCalls = append(saveCalls, stations)
+				return nil
+			},
+		}
+
+		msg := toggleBookmarkCmd(storage, online)()
+
+		assert.Equal(t, bookmarkToggledMsg{station: online}, msg)
+		assert.Equal(t, [][]common.Station{{online}}, saveCalls)
+	})
+}
+
+func TestStationsModel_Nearby(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	berlin := common.GeoPoint{Lat: 52.52, Long: 13.405}
+
+	stationAt := func(name string, lat, long float64) common.Station {
+		station := createTestStation(name)
+		station.GeoLat = &lat
+		station.GeoLong = &long
+		return station
+	}
+
+	t.Run("fetches every station in range at once and sorts them nearest-first", func(t *testing.T) {
+		potsdam := stationAt("Potsdam", 52.39, 13.06)
+		mitte := stationAt("Mitte", 52.52, 13.40)
+		unknown := createTestStation("Unknown")
+		var got common.StationSearchParams
+		browser := &mocks.MockRadioBrowserService{
+			SearchStationsFunc: func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+				got = params
+				return []common.Station{unknown, potsdam, mitte}, nil
+			},
+		}
+		params := common.StationSearchParams{Near: &berlin, GeoDistance: 50000}
+
+		stations, err := fetchStations(context.Background(), browser, stationSource{params: &params}, 0, 20)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{mitte, potsdam, unknown}, stations)
+		assert.Equal(t, uint64(0), got.Offset)
+		assert.Equal(t, uint64(geoSearchLimit), got.Limit)
+		assert.Equal(t, uint64(50000), got.GeoDistance)
+	})
+
+	t.Run("has no further pages", func(t *testing.T) {
+		browser := &mocks.MockRadioBrowserService{
+			SearchStationsFunc: func(ctx context.Context, params common.StationSearchParams) ([]common.Station, error) {
+				t.Fatal("should not query the API")
+				return nil, nil
+			},
+		}
+		params := common.StationSearchParams{Near: &berlin}
+
+		stations, err := fetchStations(context.Background(), browser, stationSource{params: &params}, 20, 20)
+
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+	})
+
+	t.Run("shows the distance from the origin", func(t *testing.T) {
+		stations := []common.Station{stationAt("Mitte", 52.52, 13.40), stationAt("Potsdam", 52.39, 13.06), createTestStation("Unknown")}
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.SetWidthAndHeight(120, 40)
+
+		assert.Len(t, model.stationsTable.Columns(), 6)
+
+		model.SetOrigin(berlin)
+
+		columns := model.stationsTable.Columns()
+		assert.Equal(t, "Distance", columns[len(columns)-1].Title)
+		rows := model.stationsTable.Rows()
+		assert.Equal(t, "<1 km", rows[0][len(columns)-1])
+		assert.Equal(t, "27 km", rows[1][len(columns)-1])
+		assert.Equal(t, "—", rows[2][len(columns)-1])
+	})
+
+	t.Run("can't be re-sorted or paged", func(t *testing.T) {
+		browser := &mocks.MockRadioBrowserService{}
+		model := createTestStationsModel(createTestStations(30), defaultStationsKeybindings)
+		model.browser = browser
+		model.EnablePaging(30, 30)
+		model.SetOrigin(berlin)
+		model.SetWidthAndHeight(120, 40)
+
+		assert.False(t, model.hasMorePages)
+		assert.Equal(t, "", model.sortLabel())
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+		assert.Nil(t, cmd)
+		assert.Equal(t, model.sortOrder, newModel.(StationsModel).sortOrder)
+	})
+}
+
+func TestStationsModel_CustomStations(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	custom := createTestStation("Team Radio")
+	custom.Url = common.RadioGoGoURL{URL: url.URL{Scheme: "http", Host: "radio.internal:8000", Path: "/live"}}
+	custom.UrlResolved = custom.Url
+	custom.Bitrate = 192
+
+	newCustomStorage := func(stations ...common.Station) *mocks.MockStationStorageService {
+		return &mocks.MockStationStorageService{
+			GetCustomStationsFunc: func() ([]common.Station, error) {
+				return stations, nil
+			},
+			IsCustomStationFunc: func(stationUUID uuid.UUID) bool {
+				for _, s := range stations {
+					if s.StationUuid == stationUUID {
+						return true
+					}
+				}
+				return false
+			},
+		}
+	}
+
+	newCustomModel := func(storage *mocks.MockStationStorageService, stations ...common.Station) StationsModel {
+		model := NewStationsModel(
+			Theme{},
+			&mocks.MockRadioBrowserService{},
+			&mocks.MockPlaybackManagerService{VolumeDefaultResult: 50, VolumeMaxResult: 100},
+			storage,
+			stations,
+			viewModeCustom,
+			"",
+			"",
+			defaultStationsKeybindings,
+		)
+		model.SetWidthAndHeight(120, 40)
+		return model
+	}
+
+	typeInto := func(model StationsModel, text string) StationsModel {
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+		return newModel.(StationsModel)
+	}
+
+	press := func(model StationsModel, key tea.KeyMsg) (StationsModel, tea.Cmd) {
+		newModel, cmd := model.Update(key)
+		return newModel.(StationsModel), cmd
+	}
+
+	t.Run("opens from search results and goes back", func(t *testing.T) {
+		storage := newCustomStorage(custom)
+		model := createTestStationsModel(createTestStations(3), defaultStationsKeybindings)
+		model.storage = storage
+
+		_, cmd := press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+		assert.NotNil(t, cmd)
+
+		newModel, _ := model.Update(customStationsFetchedMsg{stations: []common.Station{custom}})
+		model = newModel.(StationsModel)
+		assert.Equal(t, viewModeCustom, model.viewMode)
+		assert.Equal(t, []common.Station{custom}, model.stations)
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+		assert.Equal(t, viewModeCustom, model.viewMode, "the bookmarks key does nothing in the custom view")
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("C")})
+		assert.Equal(t, viewModeSearchResults, model.viewMode)
+		assert.Len(t, model.stations, 3)
+	})
+
+	t.Run("shows placeholders for clicks, votes and status", func(t *testing.T) {
+		model := newCustomModel(newCustomStorage(custom), custom)
+
+		row := model.stationsTable.Rows()[0]
+		assert.Equal(t, "Team Radio", row[0])
+		assert.Equal(t, "—", row[len(row)-1])
+		assert.Equal(t, "—", row[len(row)-2])
+		assert.Equal(t, "—", row[len(row)-3])
+	})
+
+	t.Run("adds a station through the form", func(t *testing.T) {
+		var saved []common.Station
+		storage := newCustomStorage()
+		storage.SaveCustomStationFunc = func(station common.Station) error {
+			saved = append(saved, station)
+			return nil
+		}
+		model := newCustomModel(storage)
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		assert.True(t, model.IsModalShowing())
+		assert.False(t, model.customForm.IsEditing())
+
+		model = typeInto(model, "Office Jazz")
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyTab})
+		model = typeInto(model, "https://jazz.internal/stream")
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyTab})
+		model = typeInto(model, "aac")
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyTab})
+		model = typeInto(model, "96")
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyTab})
+		model = typeInto(model, "jazz, office")
+
+		model, cmd := press(model, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.False(t, model.IsModalShowing())
+
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(customStationSavedMsg)
+			return ok
+		})
+		assert.NotNil(t, msg)
+		assert.Len(t, saved, 1)
+		assert.NotEqual(t, uuid.Nil, saved[0].StationUuid)
+		assert.Equal(t, "Office Jazz", saved[0].Name)
+		assert.Equal(t, "https://jazz.internal/stream", saved[0].Url.URL.String())
+		assert.Equal(t, "AAC", saved[0].Codec)
+		assert.Equal(t, uint64(96), saved[0].Bitrate)
+		assert.Equal(t, "jazz,office", saved[0].Tags)
+	})
+
+	t.Run("keeps the form open when it's invalid", func(t *testing.T) {
+		storage := newCustomStorage()
+		storage.SaveCustomStationFunc = func(station common.Station) error {
+			t.Fatal("should not save an invalid station")
+			return nil
+		}
+		model := newCustomModel(storage)
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		model = typeInto(model, "No URL")
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyTab})
+		model = typeInto(model, "not a url")
+
+		model, cmd := press(model, tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.Nil(t, cmd)
+		assert.True(t, model.IsModalShowing())
+		assert.Contains(t, model.View(), "not a url")
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyEsc})
+		assert.False(t, model.IsModalShowing())
+	})
+
+	t.Run("edits the selected station keeping its UUID", func(t *testing.T) {
+		model := newCustomModel(newCustomStorage(custom), custom)
+
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+		assert.True(t, model.customForm.IsEditing())
+
+		station, err := model.customForm.Station()
+		assert.NoError(t, err)
+		assert.Equal(t, custom.StationUuid, station.StationUuid)
+		assert.Equal(t, custom.Name, station.Name)
+		assert.Equal(t, custom.Url, station.Url)
+		assert.Equal(t, custom.Bitrate, station.Bitrate)
+	})
+
+	t.Run("deletes a station after confirmation", func(t *testing.T) {
+		var deleted []uuid.UUID
+		other := createTestStation("Other")
+		storage := newCustomStorage(custom, other)
+		storage.DeleteCustomStationFunc = func(stationUUID uuid.UUID) error {
+			deleted = append(deleted, stationUUID)
+			return nil
+		}
+		model := newCustomModel(storage, custom, other)
+
+		model, cmd := press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		assert.Nil(t, cmd)
+		assert.Contains(t, model.View(), "Press D again to confirm")
+
+		// Any other key cancels
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+		model, _ = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+		model, cmd = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		assert.Nil(t, cmd)
+
+		model, cmd = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+		msg := cmd()
+		assert.Equal(t, customStationDeletedMsg{station: custom, cursor: 0}, msg)
+		assert.Equal(t, []uuid.UUID{custom.StationUuid}, deleted)
+
+		newModel, _ := model.Update(msg)
+		assert.Equal(t, []common.Station{other}, newModel.(StationsModel).stations)
+	})
+
+	t.Run("doesn't count clicks or votes", func(t *testing.T) {
+		browser := &mocks.MockRadioBrowserService{
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+				t.Fatal("should not notify RadioBrowser")
+				return common.ClickStationResponse{}, nil
+			},
+			VoteStationFunc: func(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
+				t.Fatal("should not vote")
+				return common.VoteStationResponse{}, nil
+			},
+		}
+		model := createTestStationsModel([]common.Station{custom}, defaultStationsKeybindings)
+		model.browser = browser
+		model.storage = newCustomStorage(custom)
+
+		_, cmd := model.Update(playbackStartedMsg{station: custom})
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		_, cmd = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("mixes bookmarked custom stations into bookmarks", func(t *testing.T) {
+		online := createTestStation("Online")
+		storage := newCustomStorage(custom)
+		storage.GetBookmarksFunc = func() ([]uuid.UUID, error) {
+			return []uuid.UUID{custom.StationUuid, online.StationUuid}, nil
+		}
+		storage.IsBookmarkedFunc = func(stationUUID uuid.UUID) bool { return true }
+		var requested []uuid.UUID
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				requested = uuids
+				return []common.Station{online}, nil
+			},
+		}
+
+		msg := refreshBookmarksCmd(context.Background(), browser, storage)()
+
+		assert.Equal(t, bookmarksRefreshedMsg{stations: []common.Station{online, custom}}, msg)
+		assert.Equal(t, []uuid.UUID{online.StationUuid}, requested)
+	})
+
+	t.Run("doesn't query the API when only custom stations are bookmarked", func(t *testing.T) {
+		storage := newCustomStorage(custom)
+		storage.GetBookmarksFunc = func() ([]uuid.UUID, error) {
+			return []uuid.UUID{custom.StationUuid}, nil
+		}
+		storage.IsBookmarkedFunc = func(stationUUID uuid.UUID) bool { return true }
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsByUUIDsFunc: func(ctx context.Context, uuids []uuid.UUID) ([]common.Station, error) {
+				t.Fatal("should not query the API")
+				return nil, nil
+			},
+		}
+
+		msg := refreshBookmarksCmd(context.Background(), browser, storage)()
+
+		assert.Equal(t, bookmarksRefreshedMsg{stations: []common.Station{custom}}, msg)
+	})
+}
+
+func TestStationsModel_Sources(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	fromPlaylist := createTestStation("Local FM")
+	fromPlaylist.Source = common.StationSourcePlaylists
+
+	t.Run("shows where stations come from when not all are on RadioBrowser", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{createTestStation("Online")}, defaultStationsKeybindings)
+		for _, column := range model.stationsTable.Columns() {
+			assert.NotEqual(t, "Source", column.Title)
+		}
+
+		model = createTestStationsModel([]common.Station{createTestStation("Online"), fromPlaylist}, defaultStationsKeybindings)
+
+		columns := model.stationsTable.Columns()
+		assert.Equal(t, "Source", columns[len(columns)-1].Title)
+		rows := model.stationsTable.Rows()
+		assert.Equal(t, "RadioBrowser", rows[0][len(columns)-1])
+		assert.Equal(t, "Playlists", rows[1][len(columns)-1])
+		assert.Equal(t, "—", rows[1][3])
+	})
+
+	t.Run("doesn't count clicks or votes", func(t *testing.T) {
+		browser := &mocks.MockRadioBrowserService{
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+				t.Fatal("should not notify RadioBrowser")
+				return common.ClickStationResponse{}, nil
+			},
+			VoteStationFunc: func(ctx context.Context, station common.Station) (common.VoteStationResponse, error) {
+				t.Fatal("should not vote")
+				return common.VoteStationResponse{}, nil
+			},
+		}
+		model := createTestStationsModel([]common.Station{fromPlaylist}, defaultStationsKeybindings)
+		model.browser = browser
+
+		_, cmd := model.Update(playbackStartedMsg{station: fromPlaylist})
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("fetches further stations from the provider it was searched with", func(t *testing.T) {
+		dir := t.TempDir()
+		playlist := "#EXTM3U\n#EXTINF:-1,Jazz One\nhttp://one.example/jazz\n#EXTINF:-1,Jazz Two\nhttp://two.example/jazz\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "jazz.m3u"), []byte(playlist), 0o644))
+		browser := &mocks.MockRadioBrowserService{
+			GetStationsFunc: func(ctx context.Context, stationQuery common.StationQuery, searchTerm string, order string, reverse bool, offset uint64, limit uint64, hideBroken bool) ([]common.Station, error) {
+				t.Fatal("should not query the API")
+				return nil, nil
+			},
+		}
+		model := createTestStationsModel(nil, defaultStationsKeybindings)
+		model.lastQuery = common.StationQueryByName
+		model.lastQueryText = "jazz"
+		model.SetProvider(providers.NewPlaylistDirProvider(dir))
+
+		stations, err := fetchStations(context.Background(), browser, model.source(), 1, 20)
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+		assert.Equal(t, "Jazz Two", stations[0].Name)
+		assert.Equal(t, common.StationSourcePlaylists, stations[0].Source)
+	})
+}
+
+// newHealthStorage returns a MockStationStorageService keeping health check results in a map.
+func newHealthStorage(results ...health.Result) *mocks.MockStationStorageService {
+	stored := make(map[uuid.UUID]health.Result)
+	for _, result := range results {
+		stored[result.StationUuid] = result
+	}
+	return &mocks.MockStationStorageService{
+		GetHealthResultFunc: func(stationUUID uuid.UUID) (health.Result, bool) {
+			result, ok := stored[stationUUID]
+			return result, ok
+		},
+		SaveHealthResultsFunc: func(results []health.Result) error {
+			for _, result := range results {
+				stored[result.StationUuid] = result
+			}
+			return nil
+		},
+	}
+}
+
+// newTestHealthChecker returns a Checker whose streams answer with the given status code.
+func newTestHealthChecker(statusCode int) *health.Checker {
+	client := &mocks.MockHttpClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: statusCode,
+				Status:     http.StatusText(statusCode),
+				Header:     http.Header{"Content-Type": []string{"audio/mpeg"}},
+				Body:       io.NopCloser(strings.NewReader("audio")),
+			}, nil
+		},
+	}
+	return health.NewCheckerWithClient(client, 2, time.Second)
+}
+
+func TestStationsModel_Health(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	t.Run("shows the latest local check next to RadioBrowser's", func(t *testing.T) {
+		checked := createTestStation("Checked")
+		unchecked := createTestStation("Unchecked")
+
+		model := createTestStationsModel([]common.Station{checked, unchecked}, defaultStationsKeybindings)
+		assert.Len(t, model.stationsTable.Columns(), 6)
+
+		model.storage = newHealthStorage(health.Result{
+			StationUuid: checked.StationUuid,
+			Status:      health.StatusOK,
+			CheckedAt:   time.Now().Add(-5 * time.Minute),
+		})
+		model.rebuildTablePreservingCursor(-1)
+
+		columns := model.stationsTable.Columns()
+		assert.Len(t, columns, 7)
+		assert.Equal(t, "Status", columns[5].Title)
+		assert.Equal(t, "Local", columns[6].Title)
+		rows := model.stationsTable.Rows()
+		assert.Equal(t, "✓ 5m", rows[0][6])
+		assert.Equal(t, "—", rows[1][6])
+	})
+
+	t.Run("fits the smallest terminal with the local and source columns", func(t *testing.T) {
+		checked := createTestStation("A station with a name much longer than its column")
+		fromPlaylist := createTestStation("Local FM")
+		fromPlaylist.Source = common.StationSourcePlaylists
+
+		model := createTestStationsModel([]common.Station{checked, fromPlaylist}, defaultStationsKeybindings)
+		model.theme = NewTheme(config.NewDefaultConfig())
+		model.storage = newHealthStorage(health.Result{StationUuid: checked.StationUuid, Status: health.StatusOK, CheckedAt: time.Now()})
+		model.rebuildTablePreservingCursor(-1)
+		model.SetWidthAndHeight(minTerminalWidth, 40)
+
+		columns := model.stationsTable.Columns()
+		assert.Equal(t, "Local", columns[6].Title)
+		assert.Equal(t, "Source", columns[7].Title)
+		assert.Less(t, columns[0].Width, stationNameWidth)
+		for _, line := range strings.Split(model.stationsTable.View(), "\n") {
+			assert.LessOrEqual(t, lipgloss.Width(line), minTerminalWidth)
+		}
+
+		// Wider terminals get the full name column back
+		model.SetWidthAndHeight(160, 40)
+		assert.Equal(t, stationNameWidth, model.stationsTable.Columns()[0].Width)
+	})
+
+	t.Run("checks the listed streams on demand", func(t *testing.T) {
+		stations := []common.Station{createTestStation("One"), createTestStation("Two")}
+		storage := newHealthStorage()
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.storage = storage
+		model.SetHealthChecker(newTestHealthChecker(http.StatusOK))
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+		model = newModel.(StationsModel)
+		assert.True(t, model.checkingHealth)
+		assert.Equal(t, "Checking 2 streams…", model.successMsg)
+
+		// Already checking: nothing more happens
+		_, again := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+		assert.Nil(t, again)
+
+		msg := cmd()
+		assert.Len(t, msg.(healthCheckedMsg).results, 2)
+		assert.False(t, msg.(healthCheckedMsg).background)
+
+		newModel, _ = model.Update(msg)
+		model = newModel.(StationsModel)
+		assert.False(t, model.checkingHealth)
+		assert.Equal(t, "Checked 2 streams: 2 playable", model.successMsg)
+		assert.Equal(t, "✓ now", model.stationsTable.Rows()[0][6])
+	})
+
+	t.Run("does nothing without a checker", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{createTestStation("One")}, defaultStationsKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("reports results that couldn't be saved", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{createTestStation("One")}, defaultStationsKeybindings)
+		model.checkingHealth = true
+
+		newModel, _ := model.Update(healthCheckedMsg{err: errors.New("disk full")})
+
+		assert.False(t, newModel.(StationsModel).checkingHealth)
+		assert.Contains(t, newModel.(StationsModel).err, "disk full")
+	})
+
+	t.Run("background checks only refresh the table", func(t *testing.T) {
+		station := createTestStation("One")
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.storage = newHealthStorage(health.Result{StationUuid: station.StationUuid, Status: health.StatusUnreachable, CheckedAt: time.Now()})
+
+		newModel, cmd := model.Update(healthCheckedMsg{background: true})
+
+		assert.Nil(t, cmd)
+		assert.Empty(t, newModel.(StationsModel).successMsg)
+		assert.Equal(t, "✗ now", newModel.(StationsModel).stationsTable.Rows()[0][6])
+	})
+
+	t.Run("checks bookmarks not checked recently", func(t *testing.T) {
+		fresh := createTestStation("Fresh")
+		stale := createTestStation("Stale")
+		never := createTestStation("Never")
+		storage := newHealthStorage(
+			health.Result{StationUuid: fresh.StationUuid, Status: health.StatusOK, CheckedAt: time.Now().Add(-time.Minute)},
+			health.Result{StationUuid: stale.StationUuid, Status: health.StatusOK, CheckedAt: time.Now().Add(-2 * time.Hour)},
+		)
+		storage.GetBookmarkedStationsFunc = func() ([]common.Station, error) {
+			return []common.Station{fresh, stale, never}, nil
+		}
+
+		msg := checkBookmarksHealthCmd(newTestHealthChecker(http.StatusNotFound), storage, time.Hour)()
+
+		results := msg.(healthCheckedMsg).results
+		assert.True(t, msg.(healthCheckedMsg).background)
+		assert.Len(t, results, 2)
+		assert.Equal(t, stale.StationUuid, results[0].StationUuid)
+		assert.Equal(t, never.StationUuid, results[1].StationUuid)
+		assert.Equal(t, health.StatusHTTPError, results[0].Status)
+
+		// Everything is fresh now
+		assert.Nil(t, checkBookmarksHealthCmd(newTestHealthChecker(http.StatusOK), storage, time.Hour)())
+	})
+}
+
+func TestFormatAge(t *testing.T) {
+	_ = i18n.Init("en")
+
+	assert.Equal(t, "now", formatAge(30*time.Second))
+	assert.Equal(t, "5m", formatAge(5*time.Minute+20*time.Second))
+	assert.Equal(t, "3h", formatAge(3*time.Hour+59*time.Minute))
+	assert.Equal(t, "2d", formatAge(50*time.Hour))
+}
+
+func TestStationsModel_LivePlayback(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	newLiveModel := func(pm *mocks.MockLivePlaybackManagerService) StationsModel {
+		pm.NameResult = "mpv"
+		pm.IsPlayingResult = true
+		pm.VolumeDefaultResult = 50
+		pm.VolumeMaxResult = 100
+		model := createTestStationsModel([]common.Station{createTestStation("Test Radio")}, defaultStationsKeybindings)
+		model.playbackManager = pm
+		return model
+	}
+
+	t.Run("changes volume in place without restarting playback", func(t *testing.T) {
+		var setVolume int
+		pm := &mocks.MockLivePlaybackManagerService{
+			SetVolumeFunc: func(volume int) error {
+				setVolume = volume
+				return nil
+			},
+		}
+		model := newLiveModel(pm)
+
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+		model = newModel.(StationsModel)
+
+		assert.Equal(t, 60, model.volume)
+		assert.False(t, model.volumeChangePending)
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+		assert.Equal(t, 60, setVolume)
+	})
+
+	t.Run("restarts playback for players without live volume", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{createTestStation("Test Radio")}, defaultStationsKeybindings)
+		model.playbackManager.(*mocks.MockPlaybackManagerService).IsPlayingResult = true
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+
+		assert.True(t, newModel.(StationsModel).volumeChangePending)
+	})
+
+	t.Run("shows an error when the volume can't be changed", func(t *testing.T) {
+		model := newLiveModel(&mocks.MockLivePlaybackManagerService{})
+
+		newModel, _ := model.Update(volumeSetFailedMsg{err: errors.New("ipc closed")})
+
+		assert.Contains(t, newModel.(StationsModel).err, "ipc closed")
+	})
+
+	t.Run("pause key pauses and resumes playback", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newLiveModel(pm)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		msg := cmd()
+		assert.Equal(t, playbackPausedMsg{paused: true}, msg)
+		assert.True(t, pm.IsPaused())
+
+		_, cmd = model.Update(msg)
+		assert.Equal(t, playbackStatusMsg{status: PlaybackPaused}, cmd())
+
+		_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		msg = cmd()
+		assert.Equal(t, playbackPausedMsg{paused: false}, msg)
+
+		_, cmd = model.Update(msg)
+		assert.Equal(t, playbackStatusMsg{status: PlaybackPlaying}, cmd())
+	})
+
+	t.Run("pause key reports players that can't pause", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{createTestStation("Test Radio")}, defaultStationsKeybindings)
+		model.playbackManager.(*mocks.MockPlaybackManagerService).IsPlayingResult = true
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+		assert.Equal(t, "Pausing isn't supported by ffplay", newModel.(StationsModel).err)
+	})
+
+	t.Run("pause key does nothing when idle", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newLiveModel(pm)
+		pm.IsPlayingResult = false
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+		assert.Nil(t, cmd)
+		assert.False(t, pm.IsPaused())
+	})
+}
+
+// newSongTitleServer serves an ICY stream announcing each title in turn, then ends.
+func newSongTitleServer(t *testing.T, titles ...string) *httptest.Server {
+	audio := []byte("0123456789abcdef")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("icy-metaint", strconv.Itoa(len(audio)))
+		for _, title := range titles {
+			meta := []byte("StreamTitle='" + title + "';")
+			padded := make([]byte, (len(meta)+15)/16*16)
+			copy(padded, meta)
+			_, _ = w.Write(audio)
+			_, _ = w.Write(append([]byte{byte(len(padded) / 16)}, padded...))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStationsModel_SongTitles(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	isSongTitle := func(msg tea.Msg) bool {
+		_, ok := msg.(songTitleMsg)
+		return ok
+	}
+
+	t.Run("shows the song title of the playing station", func(t *testing.T) {
+		server := newSongTitleServer(t, "Daft Punk - One More Time", "Air - La femme d'argent")
+		station := createTestStation("Test Radio")
+		streamURL, _ := url.Parse(server.URL)
+		station.UrlResolved = common.RadioGoGoURL{URL: *streamURL}
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.browser = &mocks.MockRadioBrowserService{
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+				return common.ClickStationResponse{}, nil
+			},
+		}
+		model.SetSongTitleWatcher(icy.NewWatcher(icy.NewClient()))
+		model.SetWidthAndHeight(120, 40)
+
+		newModel, cmd := model.Update(playbackStartedMsg{station: station})
+		model = newModel.(StationsModel)
+		msg := findMsgInCmd(cmd, isSongTitle)
+		assert.Equal(t, "Daft Punk - One More Time", msg.(songTitleMsg).title)
+
+		newModel, cmd = model.Update(msg)
+		model = newModel.(StationsModel)
+		assert.Equal(t, "Daft Punk - One More Time", model.songTitle)
+		assert.Contains(t, model.renderNowPlayingBox(), "♪ Daft Punk – One More Time")
+
+		// The next title arrives on the same channel
+		msg = findMsgInCmd(cmd, isSongTitle)
+		assert.Equal(t, "Air - La femme d'argent", msg.(songTitleMsg).title)
+	})
+
+	t.Run("ignores titles of a station that's no longer playing", func(t *testing.T) {
+		station := createTestStation("Test Radio")
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.currentStation = station
+
+		newModel, cmd := model.Update(songTitleMsg{station: uuid.New(), title: "Old Song"})
+
+		assert.Nil(t, cmd)
+		assert.Empty(t, newModel.(StationsModel).songTitle)
+	})
+
+	t.Run("clears the song title when playback stops", func(t *testing.T) {
+		station := createTestStation("Test Radio")
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.SetSongTitleWatcher(icy.NewWatcher(icy.NewClient()))
+		model.currentStation = station
+		model.songTitle = "Daft Punk - One More Time"
+
+		newModel, _ := model.Update(playbackStoppedMsg{})
+
+		assert.Empty(t, newModel.(StationsModel).songTitle)
+		assert.NotContains(t, newModel.(StationsModel).renderNowPlayingBox(), "♪")
+	})
+
+	t.Run("logs songs in the history until playback stops", func(t *testing.T) {
+		station := createTestStation("Test Radio")
+		var started []common.SongHistoryEntry
+		ended := 0
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.storage = &mocks.MockStationStorageService{
+			StartSongFunc: func(entry common.SongHistoryEntry) error {
+				started = append(started, entry)
+				return nil
+			},
+			EndSongFunc: func(time.Time) error {
+				ended++
+				return nil
+			},
+		}
+		model.currentStation = station
+		titles := make(chan string)
+		close(titles)
+
+		newModel, cmd := model.Update(songTitleMsg{station: station.StationUuid, title: "Daft Punk - One More Time", titles: titles})
+		model = newModel.(StationsModel)
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		assert.Len(t, started, 1)
+		assert.Equal(t, station.StationUuid, started[0].StationUuid)
+		assert.Equal(t, "Test Radio", started[0].StationName)
+		assert.Equal(t, "Daft Punk - One More Time", started[0].Title)
+		assert.WithinDuration(t, time.Now(), started[0].StartedAt, time.Minute)
+
+		_, cmd = model.Update(playbackStoppedMsg{})
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		assert.Equal(t, 1, ended)
+	})
+}
+
+func TestFormatSongTitle(t *testing.T) {
+	assert.Equal(t, "Daft Punk – One More Time", formatSongTitle("Daft Punk - One More Time"))
+	assert.Equal(t, "Jay-Z – Song - Remix", formatSongTitle("Jay-Z - Song - Remix"))
+	assert.Equal(t, "Station ID", formatSongTitle("Station ID"))
+
+	assert.Equal(t, "radiogogo", windowTitle(createTestStation("Test Radio"), ""))
+	assert.Equal(t, "Air – Playground Love · Test Radio", windowTitle(createTestStation("Test Radio"), "Air - Playground Love"))
+}
+
+func TestStationsModel_StreamURL(t *testing.T) {
+	t.Run("playStationCmd reports the resolved stream URL", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{StreamURLResult: "http://cdn.example.com/live.mp3"}
+
+		msg := playStationCmd(pm, createTestStation("Test Radio"), 50)()
+
+		assert.Equal(t, "http://cdn.example.com/live.mp3", msg.(playbackStartedMsg).streamURL)
+	})
+
+	t.Run("playStationCmd leaves the stream URL empty for managers that don't resolve", func(t *testing.T) {
+		msg := playStationCmd(&mocks.MockPlaybackManagerService{}, createTestStation("Test Radio"), 50)()
+
+		assert.Empty(t, msg.(playbackStartedMsg).streamURL)
+	})
+
+	t.Run("shows the resolved stream URL in the now playing box", func(t *testing.T) {
+		station := createTestStation("Test Radio")
+		u, _ := url.Parse("http://example.com/listen.pls")
+		station.Url = common.RadioGoGoURL{URL: *u}
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.SetWidthAndHeight(120, 40)
+
+		newModel, _ := model.Update(playbackStartedMsg{station: station, streamURL: "http://cdn.example.com/live.mp3"})
+		model = newModel.(StationsModel)
+
+		assert.Contains(t, model.renderNowPlayingBox(), "🔗 http://cdn.example.com/live.mp3")
+
+		newModel, _ = model.Update(playbackStoppedMsg{})
+		assert.Empty(t, newModel.(StationsModel).streamURL)
+	})
+
+	t.Run("hides the stream URL when it's the station's own", func(t *testing.T) {
+		station := createTestStation("Test Radio")
+		u, _ := url.Parse("http://example.com/stream")
+		station.Url = common.RadioGoGoURL{URL: *u}
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.SetWidthAndHeight(120, 40)
+
+		newModel, _ := model.Update(playbackStartedMsg{station: station, streamURL: "http://example.com/stream"})
+
+		assert.NotContains(t, newModel.(StationsModel).renderNowPlayingBox(), "🔗")
+	})
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "http://example.com", truncateText("http://example.com", 30))
+	assert.Equal(t, "http://exa…", truncateText("http://example.com", 11))
+	assert.Equal(t, "http://example.com", truncateText("http://example.com", 0))
+}
+
+func TestStationsModel_Reconnect(t *testing.T) {
+	newReconnectModel := func(pm playback.PlaybackManagerService, attempts int) (StationsModel, common.Station) {
+		station := createTestStation("Test Radio")
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.playbackManager = pm
+		model.browser = &mocks.MockRadioBrowserService{
+			ClickStationFunc: func(ctx context.Context, station common.Station) (common.ClickStationResponse, error) {
+				return common.ClickStationResponse{}, nil
+			},
+		}
+		model.SetReconnectAttempts(attempts)
+		model.SetWidthAndHeight(120, 40)
+		model.currentStation = station
+		model.playingSince = time.Now()
+		return model, station
+	}
+	isDied := func(msg tea.Msg) bool {
+		_, ok := msg.(playbackDiedMsg)
+		return ok
+	}
+
+	t.Run("playStationCmd passes on the player's exits", func(t *testing.T) {
+		exits := make(chan playback.PlayerExit)
+		pm := &mocks.MockLivePlaybackManagerService{PlayerExitedResult: exits}
+
+		msg := playStationCmd(pm, createTestStation("Test Radio"), 50)()
+
+		assert.Equal(t, (<-chan playback.PlayerExit)(exits), msg.(playbackStartedMsg).exits)
+	})
+
+	t.Run("waits for the player of a started station to stop", func(t *testing.T) {
+		exits := make(chan playback.PlayerExit, 1)
+		exits <- playback.PlayerExit{Reason: "exit status 1", Stderr: "Connection reset by peer"}
+		close(exits)
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{}, 3)
+
+		_, cmd := model.Update(playbackStartedMsg{station: station, exits: exits})
+		msg := findMsgInCmd(cmd, isDied)
+
+		assert.Equal(t, playbackDiedMsg{station: station, reason: "exit status 1", stderr: "Connection reset by peer"}, msg)
+	})
+
+	t.Run("a stopped player isn't a death", func(t *testing.T) {
+		exits := make(chan playback.PlayerExit)
+		close(exits)
+
+		assert.Nil(t, waitForPlayerExitCmd(createTestStation("Test Radio"), exits)())
+	})
+
+	t.Run("schedules a reconnection when the player dies", func(t *testing.T) {
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{}, 3)
+
+		newModel, cmd := model.Update(playbackDiedMsg{station: station, reason: "exit status 1", stderr: "Connection reset by peer"})
+		model = newModel.(StationsModel)
+
+		assert.NotNil(t, cmd)
+		assert.True(t, model.reconnecting)
+		assert.Equal(t, 1, model.reconnectAttempt)
+		assert.Contains(t, model.err, "Connection reset by peer (exit status 1)")
+		assert.Contains(t, model.err, "attempt 1 of 3")
+		assert.Equal(t, station.StationUuid, model.currentStation.StationUuid)
+	})
+
+	t.Run("reconnects the station when it's time", func(t *testing.T) {
+		played := 0
+		pm := &mocks.MockPlaybackManagerService{
+			PlayStationFunc: func(station common.Station, volume int) error {
+				played++
+				return nil
+			},
+		}
+		model, station := newReconnectModel(pm, 3)
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+		model = newModel.(StationsModel)
+
+		_, cmd := model.Update(reconnectMsg{station: station, attempt: 1})
+		msg := cmd()
+
+		assert.Equal(t, 1, played)
+		assert.Equal(t, 1, msg.(playbackStartedMsg).reconnectAttempt)
+
+		newModel, _ = model.Update(msg)
+		model = newModel.(StationsModel)
+		assert.False(t, model.reconnecting)
+		assert.Equal(t, 1, model.reconnectAttempt)
+		assert.Empty(t, model.err)
+	})
+
+	t.Run("records again once reconnected", func(t *testing.T) {
+		recordedTo := ""
+		pm := &mocks.MockPlaybackManagerService{
+			IsRecordingResult: true,
+			StartRecordingFunc: func(outputPath string) error {
+				recordedTo = outputPath
+				return nil
+			},
+		}
+		model, station := newReconnectModel(pm, 3)
+		pm.CurrentStationResult = station
+		model.recordingLocation = playback.RecordingLocation{Directory: t.TempDir()}
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+		model = newModel.(StationsModel)
+		assert.True(t, model.resumeRecording)
+
+		// Playing the station again stopped the recording
+		pm.IsRecordingResult = false
+		newModel, cmd := model.Update(playbackStartedMsg{station: station, reconnectAttempt: 1})
+		model = newModel.(StationsModel)
+
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(recordingStartedMsg)
+			return ok
+		})
+		assert.NotNil(t, msg)
+		assert.Equal(t, recordedTo, msg.(recordingStartedMsg).filePath)
+		assert.Equal(t, "Reconnected, the recording goes on in a new file", model.successMsg)
+		assert.False(t, model.resumeRecording)
+	})
+
+	t.Run("doesn't record again after reconnecting without a recording", func(t *testing.T) {
+		recorded := false
+		pm := &mocks.MockPlaybackManagerService{
+			StartRecordingFunc: func(outputPath string) error {
+				recorded = true
+				return nil
+			},
+		}
+		model, station := newReconnectModel(pm, 3)
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+
+		_, cmd := newModel.(StationsModel).Update(playbackStartedMsg{station: station, reconnectAttempt: 1})
+		findMsgInCmd(cmd, func(msg tea.Msg) bool { return false })
+
+		assert.False(t, recorded)
+	})
+
+	t.Run("forgets the recording when the user stops while reconnecting", func(t *testing.T) {
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{IsRecordingResult: true}, 3)
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+		newModel, _ = newModel.(StationsModel).Update(playbackStoppedMsg{})
+
+		assert.False(t, newModel.(StationsModel).resumeRecording)
+	})
+
+	t.Run("a failed reconnection counts as another death", func(t *testing.T) {
+		pm := &mocks.MockPlaybackManagerService{
+			PlayStationFunc: func(station common.Station, volume int) error {
+				return errors.New("connection refused")
+			},
+		}
+		station := createTestStation("Test Radio")
+
+		msg := reconnectCmd(pm, station, 50, 2)()
+
+		assert.Equal(t, playbackDiedMsg{station: station, reason: "connection refused"}, msg)
+	})
+
+	t.Run("ignores reconnections after the user stopped", func(t *testing.T) {
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{}, 3)
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+		newModel, _ = newModel.(StationsModel).Update(playbackStoppedMsg{})
+		model = newModel.(StationsModel)
+
+		_, cmd := model.Update(reconnectMsg{station: station, attempt: 1})
+
+		assert.Nil(t, cmd)
+		assert.False(t, model.reconnecting)
+		assert.Zero(t, model.reconnectAttempt)
+	})
+
+	t.Run("gives up once attempts run out", func(t *testing.T) {
+		pm := &mocks.MockPlaybackManagerService{}
+		model, station := newReconnectModel(pm, 2)
+		model.reconnectAttempt = 2
+		model.playingSince = time.Time{}
+
+		newModel, cmd := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.reconnecting)
+		assert.Contains(t, model.err, "Stream stopped: exit status 1")
+		assert.NotNil(t, findMsgInCmd(cmd, func(msg tea.Msg) bool {
+			_, ok := msg.(playbackStoppedMsg)
+			return ok
+		}))
+	})
+
+	t.Run("doesn't reconnect when disabled", func(t *testing.T) {
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{}, 0)
+
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+
+		assert.False(t, newModel.(StationsModel).reconnecting)
+		assert.Contains(t, newModel.(StationsModel).err, "Stream stopped")
+	})
+
+	t.Run("starts over after playing for a while", func(t *testing.T) {
+		model, station := newReconnectModel(&mocks.MockPlaybackManagerService{}, 2)
+		model.reconnectAttempt = 2
+		model.playingSince = time.Now().Add(-2 * reconnectResetAfter)
+
+		newModel, _ := model.Update(playbackDiedMsg{station: station, reason: "exit status 1"})
+
+		assert.True(t, newModel.(StationsModel).reconnecting)
+		assert.Equal(t, 1, newModel.(StationsModel).reconnectAttempt)
+	})
+
+	t.Run("ignores deaths of other stations", func(t *testing.T) {
+		model, _ := newReconnectModel(&mocks.MockPlaybackManagerService{}, 3)
+
+		newModel, cmd := model.Update(playbackDiedMsg{station: createTestStation("Other Radio"), reason: "exit status 1"})
+
+		assert.Nil(t, cmd)
+		assert.False(t, newModel.(StationsModel).reconnecting)
+	})
+}
+
+func TestReconnectDelay(t *testing.T) {
+	assert.Equal(t, time.Second, reconnectDelay(1))
+	assert.Equal(t, 2*time.Second, reconnectDelay(2))
+	assert.Equal(t, 4*time.Second, reconnectDelay(3))
+	assert.Equal(t, 16*time.Second, reconnectDelay(5))
+	assert.Equal(t, maxReconnectDelay, reconnectDelay(6))
+	assert.Equal(t, maxReconnectDelay, reconnectDelay(20))
+}
+
+func TestDescribePlayerExit(t *testing.T) {
+	assert.Equal(t, "exit status 1", describePlayerExit("exit status 1", ""))
+	assert.Equal(t, "Connection reset by peer (exit status 1)", describePlayerExit("exit status 1", "Opening...\nConnection reset by peer\n"))
+	assert.Equal(t, "connection refused", describePlayerExit("", "connection refused"))
+}
+
+func TestStationsModel_Timeshift(t *testing.T) {
+	newTimeshiftModel := func(pm *mocks.MockLivePlaybackManagerService) StationsModel {
+		pm.NameResult = "ffplay"
+		pm.IsPlayingResult = true
+		pm.TimeshiftingResult = true
+		pm.TimeshiftStateResult = playback.TimeshiftState{Capacity: 16 * 1024 * 1024}
+		station := createTestStation("Test Radio")
+		station.Bitrate = 128
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.playbackManager = pm
+		model.SetWidthAndHeight(120, 40)
+		model.currentStation = station
+		return model
+	}
+
+	t.Run("pause key holds the station while it keeps buffering", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		msg := cmd()
+		assert.Equal(t, playbackPausedMsg{paused: true}, msg)
+		assert.True(t, pm.TimeshiftStateResult.Paused)
+		// The player itself isn't paused
+		assert.False(t, pm.IsPaused())
+
+		newModel, cmd := model.Update(msg)
+		status := findMsgInCmd(cmd, func(msg tea.Msg) bool { _, ok := msg.(playbackStatusMsg); return ok })
+		assert.Equal(t, playbackStatusMsg{status: PlaybackPaused}, status)
+		state := findMsgInCmd(cmd, func(msg tea.Msg) bool { _, ok := msg.(timeshiftStateMsg); return ok })
+		assert.Equal(t, timeshiftStateMsg{refresh: newModel.(StationsModel).timeshiftRefresh, state: pm.TimeshiftStateResult}, state)
+
+		_, cmd = newModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+		assert.Equal(t, playbackPausedMsg{paused: false}, cmd())
+		assert.False(t, pm.TimeshiftStateResult.Paused)
+	})
+
+	t.Run("shows how far behind live playback is", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+
+		// 90 seconds at 128 kbps
+		state := playback.TimeshiftState{Paused: true, Behind: 90 * 16000, Capacity: 16 * 1024 * 1024}
+		newModel, cmd := model.Update(timeshiftStateMsg{refresh: model.timeshiftRefresh, state: state})
+
+		assert.NotNil(t, cmd)
+		assert.Contains(t, newModel.(StationsModel).View(), "Paused · 1:30 behind live")
+
+		state.Paused = false
+		newModel, cmd = newModel.Update(timeshiftStateMsg{refresh: model.timeshiftRefresh, state: state})
+		assert.NotNil(t, cmd)
+		assert.Contains(t, newModel.(StationsModel).View(), "1:30 behind live · l: jump to live")
+	})
+
+	t.Run("warns when the buffer is full", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+
+		state := playback.TimeshiftState{Paused: true, Behind: 1024, Capacity: 1024}
+		newModel, _ := model.Update(timeshiftStateMsg{refresh: model.timeshiftRefresh, state: state})
+
+		assert.Contains(t, newModel.(StationsModel).View(), "buffer full")
+	})
+
+	t.Run("stops refreshing once back to live", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+		model.timeshift = playback.TimeshiftState{Behind: 1024}
+
+		newModel, cmd := model.Update(timeshiftStateMsg{refresh: model.timeshiftRefresh, state: playback.TimeshiftState{}})
+
+		assert.Nil(t, cmd)
+		assert.NotContains(t, newModel.(StationsModel).View(), "behind live")
+	})
+
+	t.Run("drops refreshes of an older loop", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+
+		newModel, cmd := model.Update(timeshiftStateMsg{refresh: model.timeshiftRefresh - 1, state: playback.TimeshiftState{Paused: true}})
+
+		assert.Nil(t, cmd)
+		assert.False(t, newModel.(StationsModel).timeshift.Paused)
+	})
+
+	t.Run("jump to live key drops the backlog", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+		pm.TimeshiftStateResult.Paused = true
+		pm.TimeshiftStateResult.Behind = 1024
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+
+		assert.Equal(t, playbackPausedMsg{paused: false}, cmd())
+		assert.Equal(t, int64(0), pm.TimeshiftStateResult.Behind)
+	})
+
+	t.Run("jump to live key reports streams without a timeshift buffer", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+		pm.TimeshiftingResult = false
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+
+		assert.Equal(t, "The timeshift buffer isn't available for this stream", newModel.(StationsModel).err)
+	})
+
+	t.Run("stopping forgets the timeshift state", func(t *testing.T) {
+		pm := &mocks.MockLivePlaybackManagerService{}
+		model := newTimeshiftModel(pm)
+		model.timeshift = playback.TimeshiftState{Paused: true, Behind: 1024}
+		refresh := model.timeshiftRefresh
+
+		newModel, _ := model.Update(playbackStoppedMsg{})
+
+		assert.Equal(t, playback.TimeshiftState{}, newModel.(StationsModel).timeshift)
+		assert.NotEqual(t, refresh, newModel.(StationsModel).timeshiftRefresh)
+	})
+}
+
+func TestRestartPlaybackWithVolumeCmd(t *testing.T) {
+	station := createTestStation("Test Radio")
+
+	t.Run("restarts only the player when it can", func(t *testing.T) {
+		restartedAt := -1
+		stopped := false
+		pm := &mocks.MockRestartingPlaybackManagerService{
+			MockPlaybackManagerService: mocks.MockPlaybackManagerService{
+				IsPlayingResult:      true,
+				CurrentStationResult: station,
+				StopStationFunc: func() error {
+					stopped = true
+					return nil
+				},
+			},
+			RestartPlayerFunc: func(volume int) error {
+				restartedAt = volume
+				return nil
+			},
+		}
+
+		msg := restartPlaybackWithVolumeCmd(pm, station, 40)()
+
+		assert.Equal(t, volumeRestartCompleteMsg{station: station, keptBuffer: true}, msg)
+		assert.Equal(t, 40, restartedAt)
+		assert.False(t, stopped)
+	})
+
+	t.Run("replays the station otherwise", func(t *testing.T) {
+		played := false
+		pm := &mocks.MockPlaybackManagerService{
+			IsPlayingResult:      true,
+			CurrentStationResult: station,
+			PlayStationFunc: func(common.Station, int) error {
+				played = true
+				return nil
+			},
+		}
+
+		msg := restartPlaybackWithVolumeCmd(pm, station, 40)()
+
+		assert.Equal(t, volumeRestartCompleteMsg{station: station}, msg)
+		assert.True(t, played)
+	})
+
+	t.Run("a kept buffer keeps its timeshift state", func(t *testing.T) {
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.timeshift = playback.TimeshiftState{Paused: true, Behind: 1024}
+
+		newModel, _ := model.Update(volumeRestartCompleteMsg{station: station, keptBuffer: true})
+		assert.Equal(t, playback.TimeshiftState{Paused: true, Behind: 1024}, newModel.(StationsModel).timeshift)
+
+		newModel, _ = model.Update(volumeRestartCompleteMsg{station: station})
+		assert.Equal(t, playback.TimeshiftState{}, newModel.(StationsModel).timeshift)
+	})
+}
+
+func TestFormatTimeshiftBehind(t *testing.T) {
+	assert.Equal(t, "0:00", formatTimeshiftBehind(0, 128))
+	assert.Equal(t, "1:30", formatTimeshiftBehind(90*16000, 128))
+	assert.Equal(t, "1:00:05", formatTimeshiftBehind(3605*16000, 128))
+	assert.Equal(t, "512 KB", formatTimeshiftBehind(512*1024, 0))
+	assert.Equal(t, "1.5 MB", formatTimeshiftBehind(3*512*1024, 0))
+}
+
+func TestStationsModel_SleepTimer(t *testing.T) {
+	newSleepModel := func(pm playback.PlaybackManagerService) StationsModel {
+		station := createTestStation("Test Radio")
+		model := createTestStationsModel([]common.Station{station}, defaultStationsKeybindings)
+		model.playbackManager = pm
+		model.SetWidthAndHeight(120, 40)
+		model.currentStation = station
+		model.volume = 80
+		return model
+	}
+	press := func(model StationsModel, key string) (StationsModel, tea.Cmd) {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return newModel.(StationsModel), cmd
+	}
+	tick := func(model StationsModel, remaining time.Duration) (StationsModel, tea.Cmd) {
+		now := time.Now()
+		model.sleepAt = now.Add(remaining)
+		newModel, cmd := model.Update(sleepTimerTickMsg{timer: model.sleepTimer, now: now})
+		return newModel.(StationsModel), cmd
+	}
+
+	t.Run("key cycles through the presets, then off", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})
+
+		for _, want := range []time.Duration{15 * time.Minute, 30 * time.Minute, 60 * time.Minute, 90 * time.Minute} {
+			before := time.Now()
+			model, _ = press(model, "z")
+			assert.Equal(t, want, model.sleepDuration)
+			assert.WithinDuration(t, before.Add(want), model.sleepAt, time.Second)
+		}
+
+		model, cmd := press(model, "z")
+		assert.Equal(t, time.Duration(0), model.sleepDuration)
+		assert.True(t, model.sleepAt.IsZero())
+		assert.Equal(t, sleepTimerStatusMsg{remaining: 0}, findMsgInCmd(cmd, func(msg tea.Msg) bool { _, ok := msg.(sleepTimerStatusMsg); return ok }))
+	})
+
+	t.Run("takes a typed duration", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})
+
+		model, _ = press(model, "Z")
+		assert.True(t, model.showSleepInput)
+		assert.Contains(t, model.View(), "Stop playback in")
+		model, _ = press(model, "4")
+		model, _ = press(model, "5")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(StationsModel)
+
+		assert.False(t, model.showSleepInput)
+		assert.Equal(t, 45*time.Minute, model.sleepDuration)
+		assert.False(t, model.sleepAt.IsZero())
+	})
+
+	t.Run("rejects typed durations it can't use", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})
+
+		model, _ = press(model, "Z")
+		model, _ = press(model, "soon")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.Contains(t, newModel.(StationsModel).err, `"soon" isn't a sleep timer duration`)
+		assert.True(t, newModel.(StationsModel).sleepAt.IsZero())
+	})
+
+	t.Run("esc closes the input", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})
+
+		model, _ = press(model, "Z")
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+		assert.False(t, newModel.(StationsModel).showSleepInput)
+		assert.True(t, newModel.(StationsModel).sleepAt.IsZero())
+	})
+
+	t.Run("counts down without touching the volume before the last minute", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockLivePlaybackManagerService{MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true}})
+
+		model, cmd := tick(model, 10*time.Minute)
+
+		assert.NotNil(t, cmd)
+		assert.False(t, model.sleepFading)
+	})
+
+	t.Run("fades out over the last minute", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockLivePlaybackManagerService{MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true}})
+
+		model, _ = tick(model, 30*time.Second)
+
+		assert.True(t, model.sleepFading)
+		assert.Equal(t, 40, model.sleepFadeVolume)
+		// The chosen volume is kept for the next station
+		assert.Equal(t, 80, model.volume)
+	})
+
+	t.Run("fades out in fewer steps with players that restart", func(t *testing.T) {
+		restartedAt := -1
+		pm := &mocks.MockRestartingPlaybackManagerService{
+			MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true},
+			RestartPlayerFunc: func(volume int) error {
+				restartedAt = volume
+				return nil
+			},
+		}
+		model := newSleepModel(pm)
+		pm.CurrentStationResult = model.currentStation
+
+		// Steps of 15s: 50s plays like a minute, at full volume
+		model, _ = tick(model, 50*time.Second)
+		assert.False(t, model.sleepFading)
+
+		model, cmd := tick(model, 40*time.Second)
+		findMsgInCmd(cmd, func(msg tea.Msg) bool { _, ok := msg.(volumeRestartCompleteMsg); return ok })
+
+		assert.True(t, model.sleepFading)
+		assert.Equal(t, 60, model.sleepFadeVolume)
+		assert.Equal(t, 60, restartedAt)
+
+		// No restart until the next step
+		model, _ = tick(model, 35*time.Second)
+		assert.Equal(t, 60, model.sleepFadeVolume)
+	})
+
+	t.Run("stops playback when the time is up", func(t *testing.T) {
+		pm := &mocks.MockPlaybackManagerService{IsPlayingResult: true}
+		stopped := false
+		pm.StopStationFunc = func() error {
+			stopped = true
+			return nil
+		}
+		model := newSleepModel(pm)
+		model.sleepDuration = 15 * time.Minute
+		model.sleepFading = true
+
+		model, cmd := tick(model, -time.Second)
+
+		assert.True(t, model.sleepAt.IsZero())
+		assert.Equal(t, time.Duration(0), model.sleepDuration)
+		assert.Equal(t, "Sleep timer ended, playback stopped", model.successMsg)
+		msg := findMsgInCmd(cmd, func(msg tea.Msg) bool { _, ok := msg.(playbackStoppedMsg); return ok })
+		assert.Equal(t, playbackStoppedMsg{}, msg)
+		assert.True(t, stopped)
+	})
+
+	t.Run("drops ticks of an older countdown", func(t *testing.T) {
+		model := newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})
+		model.sleepAt = time.Now().Add(time.Minute)
+
+		_, cmd := model.Update(sleepTimerTickMsg{timer: model.sleepTimer - 1, now: time.Now()})
+
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("a recreated model drops ticks of the earlier countdown", func(t *testing.T) {
+		earlier, _ := press(newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true}), "z")
+		model, _ := press(newSleepModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true}), "z")
+
+		assert.NotEqual(t, earlier.sleepTimer, model.sleepTimer)
+		_, cmd := model.Update(sleepTimerTickMsg{timer: earlier.sleepTimer, now: time.Now()})
+		assert.Nil(t, cmd)
+	})
+
+	t.Run("turning the timer off restores the volume", func(t *testing.T) {
+		var volume int
+		pm := &mocks.MockLivePlaybackManagerService{
+			MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true},
+			SetVolumeFunc: func(v int) error {
+				volume = v
+				return nil
+			},
+		}
+		model := newSleepModel(pm)
+		model.sleepDuration = 90 * time.Minute
+		model.sleepAt = time.Now().Add(30 * time.Second)
+		model.sleepFading = true
+		model.sleepFadeVolume = 40
+
+		model, cmd := press(model, "z")
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		assert.False(t, model.sleepFading)
+		assert.Equal(t, 80, volume)
+	})
+}
+
+func TestNextSleepTimerPreset(t *testing.T) {
+	assert.Equal(t, 15*time.Minute, nextSleepTimerPreset(0))
+	assert.Equal(t, 30*time.Minute, nextSleepTimerPreset(15*time.Minute))
+	assert.Equal(t, 90*time.Minute, nextSleepTimerPreset(60*time.Minute))
+	assert.Equal(t, time.Duration(0), nextSleepTimerPreset(90*time.Minute))
+	assert.Equal(t, time.Duration(0), nextSleepTimerPreset(45*time.Minute))
+}
+
+func TestParseSleepDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"45":    45 * time.Minute,
+		" 20 ":  20 * time.Minute,
+		"1h30m": 90 * time.Minute,
+		"90s":   90 * time.Second,
+		"24h":   24 * time.Hour,
+	} {
+		got, err := parseSleepDuration(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "soon", "0", "-5", "25h"} {
+		_, err := parseSleepDuration(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestSleepFadeVolume(t *testing.T) {
+	assert.Equal(t, 80, sleepFadeVolume(80, 5*time.Minute, time.Second))
+	assert.Equal(t, 80, sleepFadeVolume(80, time.Minute, time.Second))
+	assert.Equal(t, 40, sleepFadeVolume(80, 30*time.Second, time.Second))
+	assert.Equal(t, 2, sleepFadeVolume(80, time.Second, time.Second))
+	assert.Equal(t, 0, sleepFadeVolume(80, 0, time.Second))
+	// Rounded up to the second: 29.5s plays like 30s
+	assert.Equal(t, 40, sleepFadeVolume(80, 29500*time.Millisecond, time.Second))
+	// Steps of 15s: 20s plays like 30s
+	assert.Equal(t, 40, sleepFadeVolume(80, 20*time.Second, 15*time.Second))
+	assert.Equal(t, 20, sleepFadeVolume(80, time.Second, 15*time.Second))
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "0:05", formatCountdown(5*time.Second))
+	assert.Equal(t, "15:00", formatCountdown(15*time.Minute))
+	assert.Equal(t, "1:30:00", formatCountdown(90*time.Minute))
+}
+
+func TestStationsModel_Alarm(t *testing.T) {
+	_ = i18n.Init("en")
+
+	newAlarmModel := func(pm playback.PlaybackManagerService) (StationsModel, schedule.Alarm) {
+		stations := []common.Station{createTestStation("Jazz FM"), createTestStation("Morning Radio")}
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.playbackManager = pm
+		model.SetWidthAndHeight(120, 40)
+		model.volume = 80
+		alarm := schedule.Alarm{ID: 1, Station: stations[1], Hour: 7, Minute: 30, Volume: 60, RampUp: 5 * time.Minute, Enabled: true}
+		return model, alarm
+	}
+	livePlayer := func(volume *int) *mocks.MockLivePlaybackManagerService {
+		return &mocks.MockLivePlaybackManagerService{
+			MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true, VolumeMaxResult: 100},
+			SetVolumeFunc: func(v int) error {
+				*volume = v
+				return nil
+			},
+		}
+	}
+	fire := func(model StationsModel, alarm schedule.Alarm, at time.Time) StationsModel {
+		newModel, _ := model.Update(alarmFiredMsg{alarm: alarm, at: at})
+		return newModel.(StationsModel)
+	}
+	tick := func(model StationsModel, now time.Time) (StationsModel, tea.Cmd) {
+		newModel, cmd := model.Update(alarmRampTickMsg{ramp: model.alarmRamp, now: now})
+		return newModel.(StationsModel), cmd
+	}
+
+	t.Run("plays the alarm's station, starting low", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+
+		model = fire(model, alarm, time.Now())
+
+		assert.Equal(t, 60, model.volume)
+		assert.True(t, model.alarmRamping)
+		assert.Equal(t, 1, model.alarmRampVolume)
+		assert.Equal(t, 1, model.playingVolume())
+		assert.Equal(t, 1, model.stationsTable.Cursor())
+		assert.Equal(t, "⏰ 07:30 alarm: playing Morning Radio", model.successMsg)
+	})
+
+	t.Run("plays at the alarm's volume right away without a ramp-up", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		alarm.RampUp = 0
+
+		model = fire(model, alarm, time.Now())
+
+		assert.False(t, model.alarmRamping)
+		assert.Equal(t, 60, model.playingVolume())
+	})
+
+	t.Run("keeps the alarm's volume within the player's range", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		alarm.Volume = 150
+
+		model = fire(model, alarm, time.Now())
+
+		assert.Equal(t, 100, model.volume)
+	})
+
+	t.Run("raises the volume as the ramp-up goes on", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		at := time.Now()
+		model = fire(model, alarm, at)
+
+		model, cmd := tick(model, at.Add(150*time.Second))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, 31, model.alarmRampVolume)
+		assert.True(t, model.alarmRamping)
+	})
+
+	t.Run("raises the volume in fewer steps with players that restart", func(t *testing.T) {
+		model, alarm := newAlarmModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true, VolumeMaxResult: 100})
+		at := time.Now()
+		model = fire(model, alarm, at)
+		assert.Equal(t, 3, model.alarmRampVolume)
+
+		model, _ = tick(model, at.Add(10*time.Second))
+		assert.Equal(t, 3, model.alarmRampVolume)
+
+		model, _ = tick(model, at.Add(20*time.Second))
+		assert.Equal(t, 6, model.alarmRampVolume)
+	})
+
+	t.Run("waits for the station to play before raising the volume", func(t *testing.T) {
+		var volume int
+		pm := livePlayer(&volume)
+		pm.IsPlayingResult = false
+		model, alarm := newAlarmModel(pm)
+		at := time.Now()
+		model = fire(model, alarm, at)
+
+		model, cmd := tick(model, at.Add(150*time.Second))
+
+		assert.NotNil(t, cmd)
+		assert.Equal(t, 1, model.alarmRampVolume)
+	})
+
+	t.Run("ends at the alarm's volume", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		at := time.Now()
+		model = fire(model, alarm, at)
+
+		model, cmd := tick(model, at.Add(5*time.Minute))
+		findMsgInCmd(cmd, func(tea.Msg) bool { return false })
+
+		assert.False(t, model.alarmRamping)
+		assert.Equal(t, 60, volume)
+		assert.Equal(t, 60, model.playingVolume())
+	})
+
+	t.Run("drops ticks of an older ramp-up", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		at := time.Now()
+		model = fire(model, alarm, at)
+
+		newModel, cmd := model.Update(alarmRampTickMsg{ramp: model.alarmRamp - 1, now: at.Add(time.Minute)})
+
+		assert.Nil(t, cmd)
+		assert.Equal(t, 1, newModel.(StationsModel).alarmRampVolume)
+	})
+
+	t.Run("changing the volume ends the ramp-up", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		model = fire(model, alarm, time.Now())
+
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("0")})
+
+		assert.False(t, newModel.(StationsModel).alarmRamping)
+	})
+
+	t.Run("playing another station ends the ramp-up", func(t *testing.T) {
+		var volume int
+		model, alarm := newAlarmModel(livePlayer(&volume))
+		model = fire(model, alarm, time.Now())
+
+		newModel, _ := model.Update(playbackStartedMsg{station: alarm.Station})
+		assert.True(t, newModel.(StationsModel).alarmRamping)
+
+		newModel, _ = newModel.Update(playbackStartedMsg{station: model.stations[0]})
+		assert.False(t, newModel.(StationsModel).alarmRamping)
+	})
+
+	t.Run("key opens the alarms", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Alt: true})
+
+		assert.NotNil(t, cmd)
+		assert.IsType(t, switchToAlarmsModelMsg{}, cmd())
+	})
+}
+
+func TestAlarmRampVolume(t *testing.T) {
+	assert.Equal(t, 1, alarmRampVolume(60, 0, 5*time.Minute, time.Second))
+	assert.Equal(t, 31, alarmRampVolume(60, 150*time.Second, 5*time.Minute, time.Second))
+	assert.Equal(t, 60, alarmRampVolume(60, 5*time.Minute, 5*time.Minute, time.Second))
+	assert.Equal(t, 60, alarmRampVolume(60, time.Hour, 5*time.Minute, time.Second))
+	assert.Equal(t, 60, alarmRampVolume(60, 0, 0, time.Second))
+	// Steps of 15s: 20s plays like 30s
+	assert.Equal(t, 6, alarmRampVolume(60, 20*time.Second, 5*time.Minute, 15*time.Second))
+}
+
+func TestStationsModel_UpdateCommandsCmd(t *testing.T) {
+	_ = i18n.Init("en")
+
+	commands := func(model StationsModel) []string {
+		return model.updateCommandsCmd()().(bottomBarUpdateMsg).commands
+	}
+	playingModel := func(pm playback.PlaybackManagerService) StationsModel {
+		stations := createTestStations(1)
+		model := createTestStationsModel(stations, defaultStationsKeybindings)
+		model.playbackManager = pm
+		model.currentStation = stations[0]
+		return model
+	}
+	livePlaybackManager := func() *mocks.MockLivePlaybackManagerService {
+		return &mocks.MockLivePlaybackManagerService{MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true}}
+	}
+
+	t.Run("shows the pause key while the station can be paused", func(t *testing.T) {
+		assert.Contains(t, commands(playingModel(livePlaybackManager())), "p: pause")
+		assert.NotContains(t, commands(playingModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})), "p: pause")
+
+		model := playingModel(livePlaybackManager())
+		model.currentStation = common.Station{}
+		assert.NotContains(t, commands(model), "p: pause")
+	})
+
+	t.Run("shows the jump to live key only while timeshifting", func(t *testing.T) {
+		pm := livePlaybackManager()
+		assert.NotContains(t, commands(playingModel(pm)), "l: go live")
+
+		pm.TimeshiftingResult = true
+		assert.Contains(t, commands(playingModel(pm)), "l: go live")
+	})
+
+	t.Run("shows the stop recording key while recording", func(t *testing.T) {
+		pm := &mocks.MockPlaybackManagerService{IsPlayingResult: true}
+		assert.Contains(t, commands(playingModel(pm)), "r: record")
+
+		pm.IsRecordingResult = true
+		assert.Contains(t, commands(playingModel(pm)), "r: stop rec")
+	})
+
+	t.Run("shows both sleep timer keys while playing", func(t *testing.T) {
+		assert.Contains(t, commands(playingModel(&mocks.MockPlaybackManagerService{IsPlayingResult: true})), "z/Z: sleep timer")
+	})
+
+	t.Run("shows the alarms key in every list", func(t *testing.T) {
+		for _, viewMode := range []stationsViewMode{viewModeSearchResults, viewModeBookmarks, viewModeCustom} {
+			model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+			model.viewMode = viewMode
+			msg := model.updateCommandsCmd()()
+			assert.Contains(t, msg.(bottomBarUpdateMsg).secondaryCommands, "alt+a: alarms")
+		}
+	})
+
+	t.Run("shows the recordings key in every list", func(t *testing.T) {
+		for _, viewMode := range []stationsViewMode{viewModeSearchResults, viewModeBookmarks, viewModeCustom} {
+			model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+			model.viewMode = viewMode
+			msg := model.updateCommandsCmd()()
+			assert.Contains(t, msg.(bottomBarUpdateMsg).secondaryCommands, "alt+r: recordings")
+		}
+	})
+
+	t.Run("the station can be paused by players that pause or through the timeshift buffer", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+
+		model.playbackManager = &mocks.MockPlaybackManagerService{IsPlayingResult: true}
+		assert.False(t, model.canPause())
+
+		model.playbackManager = &mocks.MockLivePlaybackManagerService{MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true}}
+		assert.True(t, model.canPause())
+	})
+
+	t.Run("the station is timeshifting while played through the buffer", func(t *testing.T) {
+		model := createTestStationsModel(createTestStations(1), defaultStationsKeybindings)
+		pm := &mocks.MockLivePlaybackManagerService{MockPlaybackManagerService: mocks.MockPlaybackManagerService{IsPlayingResult: true}}
+		model.playbackManager = pm
+		assert.False(t, model.isTimeshifting())
+
+		pm.TimeshiftingResult = true
+		assert.True(t, model.isTimeshifting())
+	})
+}
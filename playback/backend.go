@@ -0,0 +1,208 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+// AutoBackend is the backend name that selects the first installed player.
+const AutoBackend = "auto"
+
+// Backend is a player that RadioGoGo can use for playback.
+type Backend struct {
+	// Name selects the player in playerPreferences.backend.
+	Name string
+	// InstallHintKey is the i18n key of a short note on how to install the player.
+	InstallHintKey string
+	// New creates a playback manager for the player with the given default volume.
+	New func(defaultVolume int) PlaybackManagerService
+}
+
+// DefaultBackends returns the supported players in autodetection priority order.
+func DefaultBackends() []Backend {
+	return []Backend{
+		{Name: "ffplay", InstallHintKey: "install_hint_ffplay", New: NewFFPlaybackManager},
+		{Name: "mpv", InstallHintKey: "install_hint_mpv", New: NewMPVPlaybackManager},
+		{Name: "cvlc", InstallHintKey: "install_hint_cvlc", New: NewCVLCPlaybackManager},
+		{Name: "mplayer", InstallHintKey: "install_hint_mplayer", New: NewMPlayerPlaybackManager},
+	}
+}
+
+// BackendRegistry picks the playback manager for the configured backend.
+type BackendRegistry struct {
+	backends []Backend
+}
+
+// NewBackendRegistry creates a registry of the given backends, in autodetection priority order.
+func NewBackendRegistry(backends []Backend) *BackendRegistry {
+	return &BackendRegistry{backends: backends}
+}
+
+// NewDefaultBackendRegistry creates a registry of all the supported players.
+func NewDefaultBackendRegistry() *BackendRegistry {
+	return NewBackendRegistry(DefaultBackends())
+}
+
+// Names returns the names of the registered backends in priority order.
+func (r *BackendRegistry) Names() []string {
+	names := make([]string, len(r.backends))
+	for i, backend := range r.backends {
+		names[i] = backend.Name
+	}
+	return names
+}
+
+// Select returns the playback manager for the named backend. AutoBackend, or a name
+// that isn't registered, picks the first installed backend in priority order.
+// When none of the candidates is installed, the returned manager isn't available
+// and its NotAvailableErrorString lists every backend tried and how to install them.
+func (r *BackendRegistry) Select(name string, defaultVolume int) PlaybackManagerService {
+	candidates := r.backends
+	for _, backend := range r.backends {
+		if backend.Name == name {
+			candidates = []Backend{backend}
+			break
+		}
+	}
+
+	var first PlaybackManagerService
+	for _, backend := range candidates {
+		manager := backend.New(defaultVolume)
+		if manager.IsAvailable() {
+			return manager
+		}
+		if first == nil {
+			first = manager
+		}
+	}
+	if first == nil {
+		// An empty registry has no player to stand in for
+		first = noPlaybackManager{defaultVolume: clampVolume(defaultVolume)}
+	}
+	return unavailablePlaybackManager{PlaybackManagerService: first, tried: candidates}
+}
+
+// unavailablePlaybackManager stands in for a player that isn't installed, so the
+// boot check can explain which players were tried.
+type unavailablePlaybackManager struct {
+	PlaybackManagerService
+	tried []Backend
+}
+
+func (m unavailablePlaybackManager) IsAvailable() bool {
+	return false
+}
+
+func (m unavailablePlaybackManager) NotAvailableErrorString() string {
+	if len(m.tried) == 0 {
+		return m.PlaybackManagerService.NotAvailableErrorString()
+	}
+	names := make([]string, len(m.tried))
+	for i, backend := range m.tried {
+		names[i] = backend.Name
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.Tf("error_no_player", map[string]interface{}{"Players": strings.Join(names, ", ")}))
+	b.WriteString("\n\n")
+	b.WriteString(i18n.T("error_no_player_install"))
+	for _, backend := range m.tried {
+		b.WriteString("\n  • " + backend.Name + ": " + i18n.T(backend.InstallHintKey))
+	}
+	return b.String()
+}
+
+// noPlaybackManager is the player of a registry without backends: it never plays,
+// and stopping it does nothing, so quitting still works.
+type noPlaybackManager struct {
+	defaultVolume int
+}
+
+func (m noPlaybackManager) Name() string {
+	return "none"
+}
+
+func (m noPlaybackManager) IsAvailable() bool {
+	return false
+}
+
+func (m noPlaybackManager) NotAvailableErrorString() string {
+	return i18n.Tf("error_no_player", map[string]interface{}{"Players": m.Name()})
+}
+
+func (m noPlaybackManager) IsPlaying() bool {
+	return false
+}
+
+func (m noPlaybackManager) PlayStation(station common.Station, volume int) error {
+	return errors.New(m.NotAvailableErrorString())
+}
+
+func (m noPlaybackManager) StopStation() error {
+	return nil
+}
+
+func (m noPlaybackManager) VolumeMin() int {
+	return 0
+}
+
+func (m noPlaybackManager) VolumeDefault() int {
+	return m.defaultVolume
+}
+
+func (m noPlaybackManager) VolumeMax() int {
+	return 100
+}
+
+func (m noPlaybackManager) VolumeIsPercentage() bool {
+	return true
+}
+
+func (m noPlaybackManager) CurrentStation() common.Station {
+	return common.Station{}
+}
+
+func (m noPlaybackManager) IsRecordingAvailable() bool {
+	return false
+}
+
+func (m noPlaybackManager) RecordingNotAvailableErrorString() string {
+	return i18n.T("error_ffmpeg_required")
+}
+
+func (m noPlaybackManager) IsRecording() bool {
+	return false
+}
+
+func (m noPlaybackManager) StartRecording(outputPath string) error {
+	return errors.New(i18n.T("error_no_station_playing"))
+}
+
+func (m noPlaybackManager) StopRecording() (string, error) {
+	return "", nil
+}
+
+func (m noPlaybackManager) CurrentRecordingPath() string {
+	return ""
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+// testBackend returns a backend whose player is installed if installed is true.
+func testBackend(name string, installed bool) Backend {
+	return Backend{
+		Name:           name,
+		InstallHintKey: "install_hint_" + name,
+		New: func(defaultVolume int) PlaybackManagerService {
+			executor := newMockExecutor()
+			if !installed {
+				executor.lookPathResults[name] = errors.New("not found")
+			}
+			player := playerCommand{name: name, args: ffplayCommand.args, notAvailableKey: "error_ffplay_required"}
+			manager := newCommandPlaybackManager(player, executor, defaultVolume)
+			return &manager
+		},
+	}
+}
+
+func TestBackendRegistry(t *testing.T) {
+
+	_ = i18n.Init("en")
+
+	t.Run("default backends match the configurable ones", func(t *testing.T) {
+		assert.Equal(t, config.PlayerBackends[1:], NewDefaultBackendRegistry().Names())
+	})
+
+	t.Run("auto picks the first installed backend", func(t *testing.T) {
+		registry := NewBackendRegistry([]Backend{
+			testBackend("ffplay", false),
+			testBackend("mpv", true),
+			testBackend("cvlc", true),
+		})
+
+		manager := registry.Select(AutoBackend, 60)
+
+		assert.Equal(t, "mpv", manager.Name())
+		assert.True(t, manager.IsAvailable())
+		assert.Equal(t, 60, manager.VolumeDefault())
+	})
+
+	t.Run("a named backend is used even if another has priority", func(t *testing.T) {
+		registry := NewBackendRegistry([]Backend{
+			testBackend("ffplay", true),
+			testBackend("mpv", true),
+		})
+
+		manager := registry.Select("mpv", 60)
+
+		assert.Equal(t, "mpv", manager.Name())
+	})
+
+	t.Run("an unknown name falls back to auto", func(t *testing.T) {
+		registry := NewBackendRegistry([]Backend{
+			testBackend("ffplay", true),
+		})
+
+		manager := registry.Select("winamp", 60)
+
+		assert.Equal(t, "ffplay", manager.Name())
+	})
+
+	t.Run("a missing named backend reports only that player", func(t *testing.T) {
+		registry := NewBackendRegistry([]Backend{
+			testBackend("ffplay", true),
+			testBackend("mpv", false),
+		})
+
+		manager := registry.Select("mpv", 60)
+
+		assert.False(t, manager.IsAvailable())
+		assert.Equal(t, "mpv", manager.Name())
+		assert.Contains(t, manager.NotAvailableErrorString(), "(tried: mpv)")
+		assert.Contains(t, manager.NotAvailableErrorString(), "• mpv: https://mpv.io")
+		assert.NotContains(t, manager.NotAvailableErrorString(), "ffplay")
+	})
+
+	t.Run("lists every backend tried when none is installed", func(t *testing.T) {
+		registry := NewBackendRegistry([]Backend{
+			testBackend("ffplay", false),
+			testBackend("mpv", false),
+			testBackend("cvlc", false),
+			testBackend("mplayer", false),
+		})
+
+		manager := registry.Select(AutoBackend, 60)
+
+		assert.False(t, manager.IsAvailable())
+		assert.Equal(t, "ffplay", manager.Name())
+		assert.Equal(t, "No supported audio player was found (tried: ffplay, mpv, cvlc, mplayer).\n\n"+
+			"Install one of them and make sure it's in your PATH, or pick another player with playerPreferences.backend in the config file:\n"+
+			"  • ffplay: part of FFmpeg, https://ffmpeg.org\n"+
+			"  • mpv: https://mpv.io\n"+
+			"  • cvlc: part of VLC, https://www.videolan.org\n"+
+			"  • mplayer: https://mplayerhq.hu", manager.NotAvailableErrorString())
+	})
+
+	t.Run("a registry without backends selects a player that does nothing", func(t *testing.T) {
+		manager := NewBackendRegistry(nil).Select(AutoBackend, 60)
+
+		assert.False(t, manager.IsAvailable())
+		assert.False(t, manager.IsPlaying())
+		assert.Equal(t, 60, manager.VolumeDefault())
+		assert.NoError(t, manager.StopStation())
+		assert.Error(t, manager.PlayStation(testStation("http://example.com/stream"), 60))
+		assert.Equal(t, "No supported audio player was found (tried: none).", manager.NotAvailableErrorString())
+	})
+}
+
+func TestCommandPlaybackManager_Players(t *testing.T) {
+	station := testStation("http://example.com/stream")
+
+	t.Run("cvlc plays without an interface at the volume as gain", func(t *testing.T) {
+		executor := newMockExecutor()
+		manager := NewCVLCPlaybackManagerWithExecutor(executor)
+
+		assert.NoError(t, manager.PlayStation(station, 80))
+
+		assert.Equal(t, "cvlc", manager.Name())
+		assert.Equal(t, []string{"cvlc", "--intf", "dummy", "--no-video", "--quiet", "--play-and-exit", "--gain", "0.80", "http://example.com/stream"}, executor.commandCalls[0])
+	})
+
+	t.Run("mplayer plays with software volume", func(t *testing.T) {
+		executor := newMockExecutor()
+		manager := NewMPlayerPlaybackManagerWithExecutor(executor)
+
+		assert.NoError(t, manager.PlayStation(station, 80))
+
+		assert.Equal(t, "mplayer", manager.Name())
+		assert.Equal(t, []string{"mplayer", "-really-quiet", "-nolirc", "-novideo", "-softvol", "-volume", "80", "http://example.com/stream"}, executor.commandCalls[0])
+	})
+
+	t.Run("availability checks the player's own executable", func(t *testing.T) {
+		executor := newMockExecutor()
+		executor.lookPathResults["cvlc"] = errors.New("not found")
+
+		assert.False(t, NewCVLCPlaybackManagerWithExecutor(executor).IsAvailable())
+		assert.True(t, NewMPlayerPlaybackManagerWithExecutor(executor).IsAvailable())
+	})
+
+	t.Run("constructors clamp the default volume", func(t *testing.T) {
+		assert.Equal(t, 100, NewCVLCPlaybackManager(150).VolumeDefault())
+		assert.Equal(t, 0, NewMPlayerPlaybackManager(-5).VolumeDefault())
+	})
+}
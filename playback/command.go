@@ -0,0 +1,354 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+// playerCommand describes a command-line player that plays a single stream
+// until it is killed.
+type playerCommand struct {
+	// name is the player's executable, also used as the playback manager's name.
+	name string
+	// args builds the arguments that play streamURL at volume (0-100).
+	args func(streamURL string, volume int) []string
+	// notAvailableKey is the i18n key of the error shown when the player isn't installed.
+	notAvailableKey string
+}
+
+// CommandPlaybackManager plays stations by starting one player process per station.
+// Players driven this way can't change volume on the fly, so a volume change
+// restarts the player.
+type CommandPlaybackManager struct {
+	player         playerCommand
+	nowPlaying     Cmd
+	supervisor     *supervisedProcess // Watches nowPlaying, when supervise is set
+	supervise      bool
+	exits          <-chan PlayerExit
+	currentStation common.Station
+	streamURL      string // The URL currentStation is actually streamed from
+	recorder       ffmpegRecorder
+	executor       CommandExecutor
+	resolver       StreamResolver // nil plays the station's URL as is
+	timeshift      timeshiftPlayer
+	suspended      bool // The player process is held while timeshift is paused
+	defaultVolume  int  // Configured default volume (0-100)
+}
+
+func newCommandPlaybackManager(player playerCommand, executor CommandExecutor, defaultVolume int) CommandPlaybackManager {
+	return CommandPlaybackManager{
+		player:        player,
+		executor:      executor,
+		recorder:      ffmpegRecorder{executor: executor},
+		defaultVolume: defaultVolume,
+	}
+}
+
+// newSystemCommandPlaybackManager returns a manager running the installed player,
+// which resolves station URLs and notices when the player stops by itself.
+func newSystemCommandPlaybackManager(player playerCommand, defaultVolume int) CommandPlaybackManager {
+	manager := newCommandPlaybackManager(player, &realCommandExecutor{}, clampVolume(defaultVolume))
+	manager.resolver = NewStreamResolver()
+	manager.supervise = true
+	return manager
+}
+
+// clampVolume clamps a configured default volume to the 0-100 range.
+func clampVolume(volume int) int {
+	if volume < 0 {
+		return 0
+	} else if volume > 100 {
+		return 100
+	}
+	return volume
+}
+
+func (d CommandPlaybackManager) Name() string {
+	return d.player.name
+}
+
+// IsPlaying returns true if a station is playing. A player that stopped by
+// itself no longer counts as playing.
+func (d CommandPlaybackManager) IsPlaying() bool {
+	return d.nowPlaying != nil && !d.playerExited()
+}
+
+func (d CommandPlaybackManager) IsAvailable() bool {
+	_, err := d.executor.LookPath(d.player.name)
+	return err == nil
+}
+
+func (d CommandPlaybackManager) NotAvailableErrorString() string {
+	return i18n.T(d.player.notAvailableKey)
+}
+
+// PlayStation resolves the URL the station streams from, then plays it, through
+// the timeshift buffer when enabled. The current station keeps playing if the
+// new one can't be resolved.
+func (d *CommandPlaybackManager) PlayStation(station common.Station, volume int) error {
+	streamURL, err := resolveStream(d.resolver, station)
+	if err != nil {
+		return err
+	}
+	err = d.StopStation()
+	if err != nil {
+		return err
+	}
+	playURL := d.timeshift.open(streamURL)
+	if err := d.startPlayer(playURL, volume); err != nil {
+		d.timeshift.close()
+		return err
+	}
+	d.currentStation = station
+	d.streamURL = streamURL
+	return nil
+}
+
+// RestartPlayer restarts the player of the playing station at volume. The
+// recording and the timeshift buffer carry on: the new player continues from
+// where the previous one was in the buffer.
+func (d *CommandPlaybackManager) RestartPlayer(volume int) error {
+	if d.nowPlaying == nil {
+		return errors.New(i18n.T("error_no_station_playing"))
+	}
+	if err := d.stopPlayer(); err != nil {
+		return err
+	}
+	playURL := d.streamURL
+	if d.timeshift.isTimeshifting() {
+		playURL = d.timeshift.current.URL()
+	}
+	if err := d.startPlayer(playURL, volume); err != nil {
+		_, _ = d.StopRecording()
+		d.timeshift.close()
+		d.currentStation = common.Station{}
+		d.streamURL = ""
+		return err
+	}
+	return nil
+}
+
+// startPlayer starts a player process playing playURL.
+func (d *CommandPlaybackManager) startPlayer(playURL string, volume int) error {
+	cmd := d.executor.Command(d.player.name, d.player.args(playURL, volume)...)
+	if d.supervise {
+		supervisor, err := startSupervised(cmd)
+		if err != nil {
+			return err
+		}
+		d.supervisor = supervisor
+		d.exits = supervisor.watch()
+	} else if err := cmd.Start(); err != nil {
+		return err
+	}
+	d.nowPlaying = cmd
+	return nil
+}
+
+// StopStation stops the currently playing station and any active recording.
+func (d *CommandPlaybackManager) StopStation() error {
+	// Stop recording first if active
+	if _, err := d.StopRecording(); err != nil {
+		return err
+	}
+
+	if d.nowPlaying != nil {
+		if err := d.stopPlayer(); err != nil {
+			return err
+		}
+		d.timeshift.close()
+		d.currentStation = common.Station{}
+		d.streamURL = ""
+	}
+	return nil
+}
+
+// stopPlayer terminates the player process, leaving the station's recording
+// and timeshift buffer alone.
+// Platform-specific behavior:
+//   - Windows: Uses taskkill with /T (tree kill) and /F (force) flags to kill
+//     the player process and all its child processes. This is necessary because
+//     Windows doesn't propagate signals to child processes like Unix does.
+//   - Unix/macOS: Uses SIGKILL via Process.Kill() which immediately terminates
+//     the process, even while suspended. This is sufficient as the players don't
+//     spawn child processes on these platforms.
+func (d *CommandPlaybackManager) stopPlayer() error {
+	if d.supervisor != nil {
+		d.supervisor.stop()
+	}
+	// A player that already stopped by itself can't be killed, only cleaned up
+	if err := d.killPlayer(); err != nil && !d.playerExited() {
+		return err
+	}
+
+	// Wait for process to be reaped to avoid zombie processes
+	var err error
+	if d.supervisor != nil {
+		// The supervisor is already waiting on it
+		err = d.supervisor.wait()
+	} else {
+		_, err = d.nowPlaying.Process().Wait()
+	}
+	if err != nil {
+		return err
+	}
+	d.nowPlaying = nil
+	d.supervisor = nil
+	d.exits = nil
+	d.suspended = false
+	return nil
+}
+
+// killPlayer terminates the player process.
+func (d *CommandPlaybackManager) killPlayer() error {
+	if runtime.GOOS == "windows" {
+		// Windows: taskkill /T kills entire process tree, /F forces termination
+		killCmd := d.executor.Command("taskkill", "/T", "/F", "/PID", fmt.Sprintf("%d", d.nowPlaying.Process().Pid()))
+		return killCmd.Run()
+	}
+	// Unix/macOS: SIGKILL is sufficient for single-process termination
+	return d.nowPlaying.Process().Kill()
+}
+
+// playerExited returns true if the supervised player stopped by itself.
+func (d CommandPlaybackManager) playerExited() bool {
+	return d.supervisor != nil && d.supervisor.hasExited()
+}
+
+func (d CommandPlaybackManager) VolumeMin() int {
+	return 0
+}
+
+func (d CommandPlaybackManager) VolumeDefault() int {
+	return d.defaultVolume
+}
+
+func (d CommandPlaybackManager) VolumeMax() int {
+	return 100
+}
+
+func (d CommandPlaybackManager) VolumeIsPercentage() bool {
+	return false
+}
+
+func (d CommandPlaybackManager) CurrentStation() common.Station {
+	return d.currentStation
+}
+
+// PlayerExited returns a channel receiving the player's exit, should it stop by itself.
+func (d CommandPlaybackManager) PlayerExited() <-chan PlayerExit {
+	return d.exits
+}
+
+// StreamURL returns the URL the playing station is streamed from.
+func (d CommandPlaybackManager) StreamURL() string {
+	return d.streamURL
+}
+
+// EnableTimeshift plays the stations played from now on through a buffer of
+// bufferSize bytes, or directly if bufferSize is 0.
+func (d *CommandPlaybackManager) EnableTimeshift(bufferSize int) {
+	d.timeshift.bufferSize = bufferSize
+}
+
+func (d CommandPlaybackManager) IsTimeshifting() bool {
+	return d.IsPlaying() && d.timeshift.isTimeshifting()
+}
+
+// PauseLive stops feeding the player and suspends it, so that it doesn't play
+// what it had already received. The station keeps being buffered meanwhile.
+// Where processes can't be suspended (Windows), the player falls silent once it
+// has played what it had.
+func (d *CommandPlaybackManager) PauseLive() error {
+	if err := d.timeshift.pause(); err != nil {
+		return err
+	}
+	if suspendSignal != nil && d.nowPlaying != nil && !d.suspended {
+		if err := d.nowPlaying.Process().Signal(suspendSignal); err == nil {
+			d.suspended = true
+		}
+	}
+	return nil
+}
+
+// ResumeLive lets the player continue, fed from where it was paused.
+func (d *CommandPlaybackManager) ResumeLive() error {
+	if err := d.timeshift.resume(); err != nil {
+		return err
+	}
+	d.resumePlayer()
+	return nil
+}
+
+// JumpToLive drops the backlog, so the player continues with the live stream.
+// The player still plays the little it had already received.
+func (d *CommandPlaybackManager) JumpToLive() error {
+	if err := d.timeshift.jumpToLive(); err != nil {
+		return err
+	}
+	d.resumePlayer()
+	return nil
+}
+
+// resumePlayer lets a suspended player continue.
+func (d *CommandPlaybackManager) resumePlayer() {
+	if d.suspended && d.nowPlaying != nil {
+		_ = d.nowPlaying.Process().Signal(resumeSignal)
+	}
+	d.suspended = false
+}
+
+func (d CommandPlaybackManager) TimeshiftState() TimeshiftState {
+	return d.timeshift.state()
+}
+
+func (d CommandPlaybackManager) IsRecordingAvailable() bool {
+	return d.recorder.isAvailable()
+}
+
+func (d CommandPlaybackManager) RecordingNotAvailableErrorString() string {
+	return i18n.T("error_ffmpeg_required")
+}
+
+func (d CommandPlaybackManager) IsRecording() bool {
+	return d.recorder.isRecording()
+}
+
+func (d *CommandPlaybackManager) StartRecording(outputPath string) error {
+	if !d.IsPlaying() {
+		return errors.New(i18n.T("error_no_station_playing"))
+	}
+	return d.recorder.start(d.streamURL, outputPath)
+}
+
+// StopRecording stops the current recording and returns the output file path.
+// See ffmpegRecorder.stop for the platform-specific behavior.
+func (d *CommandPlaybackManager) StopRecording() (string, error) {
+	return d.recorder.stop()
+}
+
+func (d CommandPlaybackManager) CurrentRecordingPath() string {
+	return d.recorder.path
+}
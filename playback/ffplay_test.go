@@ -23,6 +23,7 @@ import (
 	"errors"
 	"net/url"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/google/uuid"
@@ -38,19 +39,37 @@ type mockProcess struct {
 	waitErr    error
 	killCalled bool
 	signalSig  os.Signal
+	// When set, Wait blocks until the process exits: when killed or interrupted, or when exit is called.
+	exited   chan struct{}
+	exitOnce sync.Once
+}
+
+// exit ends a process created with exited, as if it stopped by itself.
+func (p *mockProcess) exit() {
+	p.exitOnce.Do(func() { close(p.exited) })
 }
 
 func (p *mockProcess) Kill() error {
 	p.killCalled = true
+	if p.exited != nil {
+		p.exit()
+	}
 	return p.killErr
 }
 
 func (p *mockProcess) Signal(sig os.Signal) error {
 	p.signalSig = sig
+	// Suspending and resuming leave the process running
+	if p.exited != nil && p.signalErr == nil && sig == os.Interrupt {
+		p.exit()
+	}
 	return p.signalErr
 }
 
 func (p *mockProcess) Wait() (*os.ProcessState, error) {
+	if p.exited != nil {
+		<-p.exited
+	}
 	return nil, p.waitErr
 }
 
@@ -63,9 +82,15 @@ type mockCmd struct {
 	startErr error
 	runErr   error
 	process  *mockProcess
+	// stderr is written to the standard error set with SetStderr on Start.
+	stderr    string
+	stderrOut *os.File
 }
 
 func (c *mockCmd) Start() error {
+	if c.startErr == nil && c.stderrOut != nil {
+		_, _ = c.stderrOut.WriteString(c.stderr)
+	}
 	return c.startErr
 }
 
@@ -77,7 +102,7 @@ func (c *mockCmd) Process() Process {
 	return c.process
 }
 
-func (c *mockCmd) SetStderr(w *os.File) {}
+func (c *mockCmd) SetStderr(w *os.File) { c.stderrOut = w }
 func (c *mockCmd) SetStdout(w *os.File) {}
 
 // mockExecutor implements CommandExecutor for testing.
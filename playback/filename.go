@@ -20,10 +20,19 @@
 package playback
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
 )
 
 // SanitizeFilename converts a station name to a safe filename component.
@@ -33,6 +42,19 @@ import (
 // - Handles unicode by removing non-ASCII characters
 // - Limits length to prevent filesystem issues
 func SanitizeFilename(name string) string {
+	name = sanitizeComponent(name)
+
+	// Fallback if empty
+	if name == "" {
+		name = "recording"
+	}
+
+	return name
+}
+
+// sanitizeComponent is SanitizeFilename without the fallback: it returns an
+// empty string if nothing of name is safe to keep.
+func sanitizeComponent(name string) string {
 	// Convert to lowercase
 	name = strings.ToLower(name)
 
@@ -63,11 +85,6 @@ func SanitizeFilename(name string) string {
 		name = name[:100]
 	}
 
-	// Fallback if empty
-	if name == "" {
-		name = "recording"
-	}
-
 	return name
 }
 
@@ -122,3 +139,126 @@ func GenerateRecordingFilename(stationName string, codec string) string {
 
 	return sanitized + "-" + timestamp + "." + extension
 }
+
+// DefaultRecordingFilenameTemplate names recordings like GenerateRecordingFilename does.
+const DefaultRecordingFilenameTemplate = "{station}-{date}-{time}"
+
+// maxRecordingPathAttempts bounds the numbered names tried when a recording file already exists.
+const maxRecordingPathAttempts = 1000
+
+// RecordingLocation is where recordings are saved, and how they're named.
+// The zero value saves them in the current directory, named by DefaultRecordingFilenameTemplate.
+type RecordingLocation struct {
+	// Directory holds the recordings. Empty means the current directory.
+	Directory string
+	// FilenameTemplate is the name of a recording, without its extension. It may
+	// contain subdirectories ("/") and these placeholders:
+	//   {station}, {country} (its code), {codec}, {title} (the song playing),
+	//   {date} (2006-01-02), {time} (15-04-05), {year}, {month}, {day}, {hour}, {minute}, {second}
+	FilenameTemplate string
+}
+
+// Filename expands the filename template for a recording of station, started at t
+// while title was playing (it may be empty), and adds the extension of the station codec.
+// The result is relative to the directory: templates leading outside of it are rejected.
+func (l RecordingLocation) Filename(station common.Station, title string, t time.Time) (string, error) {
+	template := l.FilenameTemplate
+	if strings.TrimSpace(template) == "" {
+		template = DefaultRecordingFilenameTemplate
+	}
+
+	values := map[string]string{
+		"station": SanitizeFilename(station.Name),
+		"country": sanitizeComponent(station.CountryCode),
+		"codec":   NormalizeCodec(station.Codec),
+		"title":   sanitizeComponent(title),
+		"date":    t.Format("2006-01-02"),
+		"time":    t.Format("15-04-05"),
+		"year":    t.Format("2006"),
+		"month":   t.Format("01"),
+		"day":     t.Format("02"),
+		"hour":    t.Format("15"),
+		"minute":  t.Format("04"),
+		"second":  t.Format("05"),
+	}
+
+	var name strings.Builder
+	for rest := template; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		end := strings.IndexByte(rest[max(open, 0):], '}')
+		if open < 0 || end < 0 {
+			name.WriteString(rest)
+			break
+		}
+		end += open
+		placeholder := rest[open+1 : end]
+		value, ok := values[strings.ToLower(placeholder)]
+		if !ok {
+			return "", errors.New(i18n.Tf("error_recording_placeholder", map[string]interface{}{"Placeholder": "{" + placeholder + "}"}))
+		}
+		name.WriteString(rest[:open])
+		name.WriteString(value)
+		rest = rest[end+1:]
+	}
+
+	// e.g. "{title}" while no title is known, or a template ending with "/"
+	expanded := name.String()
+	slash := strings.LastIndexByte(expanded, '/')
+	if strings.Trim(expanded[slash+1:], "_-. ") == "" {
+		expanded = expanded[:slash+1] + "recording"
+	}
+
+	filename := filepath.Clean(filepath.FromSlash(expanded))
+	// Values can't contain separators, but the template itself could climb out of the directory
+	if !filepath.IsLocal(filename) {
+		return "", errors.New(i18n.T("error_recording_outside_directory"))
+	}
+
+	return filename + "." + NormalizeCodec(station.Codec), nil
+}
+
+// Path returns a new file to record station into, creating the directories it's in.
+// If a file with the expanded name already exists, a number is appended to it (e.g. "-2").
+// The file is created empty, so that recordings starting at once don't pick the same name;
+// DiscardRecordingPath removes it if the recording doesn't start.
+func (l RecordingLocation) Path(station common.Station, title string, t time.Time) (string, error) {
+	filename, err := l.Filename(station, title, t)
+	if err != nil {
+		return "", err
+	}
+
+	directory := l.Directory
+	if directory == "" {
+		directory = "."
+	}
+	path := filepath.Join(directory, filename)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	extension := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, extension)
+	for attempt := 1; attempt <= maxRecordingPathAttempts; attempt++ {
+		candidate := path
+		if attempt > 1 {
+			candidate = stem + "-" + strconv.Itoa(attempt) + extension
+		}
+		file, err := os.OpenFile(candidate, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		file.Close()
+		return candidate, nil
+	}
+	return "", fmt.Errorf("%s: %w", path, fs.ErrExist)
+}
+
+// DiscardRecordingPath removes a file returned by RecordingLocation.Path, if nothing was recorded into it.
+func DiscardRecordingPath(path string) {
+	if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Size() == 0 {
+		_ = os.Remove(path)
+	}
+}
@@ -20,11 +20,14 @@
 package playback
 
 import (
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/common"
 )
 
 func TestSanitizeFilename(t *testing.T) {
@@ -399,3 +402,130 @@ func TestGenerateRecordingFilename_EdgeCases(t *testing.T) {
 		assert.Contains(t, filename, ".aac")
 	})
 }
+
+func TestRecordingLocation_Filename(t *testing.T) {
+	station := common.Station{Name: "BBC Radio 1", CountryCode: "GB", Codec: "AAC+"}
+	at := time.Date(2026, 3, 7, 9, 5, 1, 0, time.Local)
+
+	t.Run("zero value names recordings like GenerateRecordingFilename", func(t *testing.T) {
+		filename, err := RecordingLocation{}.Filename(station, "", at)
+		assert.NoError(t, err)
+		assert.Equal(t, "bbc_radio_1-2026-03-07-09-05-01.aac", filename)
+	})
+
+	t.Run("expands every placeholder", func(t *testing.T) {
+		location := RecordingLocation{FilenameTemplate: "{country}/{station}/{year}{month}{day}_{hour}{minute}{second}_{codec}_{title}_{date}_{time}"}
+		filename, err := location.Filename(station, "Artist - Song", at)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("gb", "bbc_radio_1", "20260307_090501_aac_artist_-_song_2026-03-07_09-05-01.aac"), filename)
+	})
+
+	t.Run("placeholders are case insensitive", func(t *testing.T) {
+		filename, err := RecordingLocation{FilenameTemplate: "{Station}"}.Filename(station, "", at)
+		assert.NoError(t, err)
+		assert.Equal(t, "bbc_radio_1.aac", filename)
+	})
+
+	t.Run("values can't add directories", func(t *testing.T) {
+		filename, err := RecordingLocation{FilenameTemplate: "{title}"}.Filename(station, "../../etc/passwd", at)
+		assert.NoError(t, err)
+		assert.Equal(t, "etcpasswd.aac", filename)
+	})
+
+	t.Run("falls back when the name is empty", func(t *testing.T) {
+		filename, err := RecordingLocation{FilenameTemplate: "shows/{title}-"}.Filename(station, "", at)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("shows", "recording.aac"), filename)
+	})
+
+	t.Run("unclosed braces are kept", func(t *testing.T) {
+		filename, err := RecordingLocation{FilenameTemplate: "{station}-{"}.Filename(station, "", at)
+		assert.NoError(t, err)
+		assert.Equal(t, "bbc_radio_1-{.aac", filename)
+	})
+
+	t.Run("rejects unknown placeholders", func(t *testing.T) {
+		_, err := RecordingLocation{FilenameTemplate: "{station}-{genre}"}.Filename(station, "", at)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects templates leading outside the directory", func(t *testing.T) {
+		for _, template := range []string{"../{station}", "shows/../../{station}", "/tmp/{station}"} {
+			_, err := RecordingLocation{FilenameTemplate: template}.Filename(station, "", at)
+			assert.Error(t, err, template)
+		}
+	})
+
+	t.Run("allows climbing back into the directory", func(t *testing.T) {
+		filename, err := RecordingLocation{FilenameTemplate: "shows/../{station}"}.Filename(station, "", at)
+		assert.NoError(t, err)
+		assert.Equal(t, "bbc_radio_1.aac", filename)
+	})
+}
+
+func TestRecordingLocation_Path(t *testing.T) {
+	station := common.Station{Name: "Jazz FM", Codec: "MP3"}
+	at := time.Date(2026, 3, 7, 9, 5, 1, 0, time.Local)
+
+	t.Run("creates missing directories and reserves the file", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "recordings")
+		location := RecordingLocation{Directory: dir, FilenameTemplate: "{station}/{date}"}
+
+		path, err := location.Path(station, "", at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "jazz_fm", "2026-03-07.mp3"), path)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("numbers names already taken", func(t *testing.T) {
+		dir := t.TempDir()
+		location := RecordingLocation{Directory: dir, FilenameTemplate: "{station}"}
+
+		first, err := location.Path(station, "", at)
+		assert.NoError(t, err)
+		second, err := location.Path(station, "", at)
+		assert.NoError(t, err)
+		third, err := location.Path(station, "", at)
+		assert.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(dir, "jazz_fm.mp3"), first)
+		assert.Equal(t, filepath.Join(dir, "jazz_fm-2.mp3"), second)
+		assert.Equal(t, filepath.Join(dir, "jazz_fm-3.mp3"), third)
+	})
+
+	t.Run("returns template errors", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := RecordingLocation{Directory: dir, FilenameTemplate: "../{station}"}.Path(station, "", at)
+		assert.Error(t, err)
+		_, statErr := os.Stat(filepath.Join(filepath.Dir(dir), "jazz_fm.mp3"))
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("fails if the directory can't be created", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		assert.NoError(t, os.WriteFile(file, nil, 0o644))
+		_, err := RecordingLocation{Directory: file}.Path(station, "", at)
+		assert.Error(t, err)
+	})
+}
+
+func TestDiscardRecordingPath(t *testing.T) {
+	t.Run("removes empty files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.mp3")
+		assert.NoError(t, os.WriteFile(path, nil, 0o644))
+		DiscardRecordingPath(path)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("keeps recorded files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "recorded.mp3")
+		assert.NoError(t, os.WriteFile(path, []byte("audio"), 0o644))
+		DiscardRecordingPath(path)
+		assert.FileExists(t, path)
+	})
+
+	t.Run("ignores missing files", func(t *testing.T) {
+		DiscardRecordingPath(filepath.Join(t.TempDir(), "missing.mp3"))
+	})
+}
@@ -64,3 +64,84 @@ type PlaybackManagerService interface {
 	// CurrentRecordingPath returns the path of the current recording, or empty if not recording.
 	CurrentRecordingPath() string
 }
+
+// VolumeController is implemented by playback managers that can change the volume
+// of the playing station instantly, without restarting the player.
+type VolumeController interface {
+	// SetVolume changes the volume of the playing station.
+	SetVolume(volume int) error
+}
+
+// PlayerRestarter is implemented by playback managers that restart their player to
+// change volume, and can do so without losing the recording or timeshift buffer
+// of the playing station.
+type PlayerRestarter interface {
+	// RestartPlayer restarts the player of the playing station at volume.
+	RestartPlayer(volume int) error
+}
+
+// Pauser is implemented by playback managers that can pause and resume playback.
+type Pauser interface {
+	// Pause pauses playback, keeping the station loaded.
+	Pause() error
+	// Resume resumes paused playback.
+	Resume() error
+	// IsPaused returns true if playback is currently paused.
+	IsPaused() bool
+}
+
+// MetadataReader is implemented by playback managers whose player can report the
+// metadata of the playing stream.
+type MetadataReader interface {
+	// MediaTitle returns the title the player shows for the stream, usually the
+	// current song when the station sends ICY metadata.
+	MediaTitle() (string, error)
+	// Metadata returns all the metadata tags of the stream.
+	Metadata() (map[string]string, error)
+}
+
+// StreamURLReporter is implemented by playback managers that resolve station URLs
+// before playing them, e.g. following redirects or opening playlists.
+type StreamURLReporter interface {
+	// StreamURL returns the URL the playing station is actually streamed from,
+	// or empty if nothing is playing.
+	StreamURL() string
+}
+
+// PlayerExit describes a player that stopped by itself, e.g. because the stream
+// ended or the player crashed.
+type PlayerExit struct {
+	// Reason is how the player ended, e.g. "exit status 1".
+	Reason string
+	// Stderr holds the last lines the player wrote to its standard error.
+	Stderr string
+}
+
+// ExitWatcher is implemented by playback managers that notice when their player
+// stops by itself.
+type ExitWatcher interface {
+	// PlayerExited returns a channel that receives a PlayerExit if the player of the
+	// playing station stops by itself. The channel is closed without a value once the
+	// station is stopped or replaced. Returns nil if nothing is playing.
+	PlayerExited() <-chan PlayerExit
+}
+
+// Timeshifter is implemented by playback managers that can play stations through a
+// timeshift buffer: the station keeps being downloaded while playback is paused,
+// so it resumes from where it was paused instead of from the live stream.
+type Timeshifter interface {
+	// EnableTimeshift buffers up to bufferSize bytes of the stations played from
+	// now on. A bufferSize of 0 disables timeshifting.
+	EnableTimeshift(bufferSize int)
+	// IsTimeshifting returns true if the playing station goes through the buffer.
+	// Some streams, e.g. HLS, are always played directly.
+	IsTimeshifting() bool
+	// PauseLive holds playback while the station keeps being buffered.
+	PauseLive() error
+	// ResumeLive continues playback from where it was paused.
+	ResumeLive() error
+	// JumpToLive drops the buffered backlog and continues with the live stream.
+	JumpToLive() error
+	// TimeshiftState describes the buffer of the playing station.
+	TimeshiftState() TimeshiftState
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import "fmt"
+
+// mplayerCommand plays a stream with MPlayer. Software volume makes -volume
+// independent of the system mixer.
+var mplayerCommand = playerCommand{
+	name: "mplayer",
+	args: func(streamURL string, volume int) []string {
+		return []string{"-really-quiet", "-nolirc", "-novideo", "-softvol", "-volume", fmt.Sprintf("%d", volume), streamURL}
+	},
+	notAvailableKey: "error_mplayer_required",
+}
+
+// NewMPlayerPlaybackManager creates a playback manager that plays stations with
+// MPlayer at the specified default volume (0-100).
+func NewMPlayerPlaybackManager(defaultVolume int) PlaybackManagerService {
+	manager := newSystemCommandPlaybackManager(mplayerCommand, defaultVolume)
+	return &manager
+}
+
+// NewMPlayerPlaybackManagerWithExecutor creates an MPlayer playback manager with a custom command executor.
+// This is primarily useful for testing. Uses default volume of 80.
+func NewMPlayerPlaybackManagerWithExecutor(executor CommandExecutor) *CommandPlaybackManager {
+	manager := newCommandPlaybackManager(mplayerCommand, executor, 80)
+	return &manager
+}
@@ -0,0 +1,682 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+const (
+	// mpvConnectTimeout is how long to wait for a freshly started mpv to create its IPC socket.
+	mpvConnectTimeout = 5 * time.Second
+	// mpvConnectRetryDelay is the delay between attempts to connect to the IPC socket.
+	mpvConnectRetryDelay = 50 * time.Millisecond
+	// mpvRequestTimeout bounds how long a single IPC request may take.
+	mpvRequestTimeout = 5 * time.Second
+)
+
+// IPCDialer opens a connection to mpv's JSON IPC server at the given address.
+// This allows tests to talk to a fake IPC socket.
+type IPCDialer func(address string) (io.ReadWriteCloser, error)
+
+// dialMPV connects to mpv's IPC server: a unix socket, or a named pipe on Windows.
+func dialMPV(address string) (io.ReadWriteCloser, error) {
+	if runtime.GOOS == "windows" {
+		return os.OpenFile(address, os.O_RDWR, 0)
+	}
+	return net.Dial("unix", address)
+}
+
+// mpvSocketPath returns the IPC address used by this process's mpv instance.
+func mpvSocketPath() string {
+	name := fmt.Sprintf("radiogogo-mpv-%d", os.Getpid())
+	if runtime.GOOS == "windows" {
+		return `\\.\pipe\` + name
+	}
+	return filepath.Join(os.TempDir(), name+".sock")
+}
+
+// mpvRequest is a command sent over mpv's JSON IPC protocol.
+type mpvRequest struct {
+	Command   []interface{} `json:"command"`
+	RequestID int           `json:"request_id"`
+}
+
+// mpvResponse is either a reply to a request or an event broadcast by mpv.
+type mpvResponse struct {
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+	Error     string          `json:"error"`
+	RequestID int             `json:"request_id"`
+	// Name is the property of a property-change event.
+	Name string `json:"name"`
+	// Reason and FileError tell why an end-file event's file ended.
+	Reason    string `json:"reason"`
+	FileError string `json:"file_error"`
+}
+
+// mpvIPC is a client for mpv's JSON IPC protocol. A goroutine reads what mpv
+// sends, handing replies to the requests waiting for them (matched by request_id)
+// and events to onEvent. It is safe to use from multiple goroutines.
+type mpvIPC struct {
+	writeMu sync.Mutex
+	conn    io.ReadWriteCloser
+	onEvent func(event mpvResponse) // Called from the reading goroutine, may be nil
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan mpvResponse
+
+	done chan struct{} // Closed once reading stopped
+	err  error         // Why reading stopped, set before done is closed
+}
+
+func newMPVIPC(conn io.ReadWriteCloser, onEvent func(event mpvResponse)) *mpvIPC {
+	c := &mpvIPC{
+		conn:    conn,
+		onEvent: onEvent,
+		pending: map[int]chan mpvResponse{},
+		done:    make(chan struct{}),
+	}
+	go c.read()
+	return c
+}
+
+// read dispatches everything mpv sends until the connection is closed.
+func (c *mpvIPC) read() {
+	reader := bufio.NewReader(c.conn)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			c.err = err
+			close(c.done)
+			return
+		}
+		var response mpvResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			continue
+		}
+		if response.Event != "" {
+			if c.onEvent != nil {
+				c.onEvent(response)
+			}
+			continue
+		}
+		c.mu.Lock()
+		reply, ok := c.pending[response.RequestID]
+		delete(c.pending, response.RequestID)
+		c.mu.Unlock()
+		if ok {
+			reply <- response
+		}
+	}
+}
+
+// command sends a command to mpv and waits for its reply.
+func (c *mpvIPC) command(args ...interface{}) (json.RawMessage, error) {
+	reply := make(chan mpvResponse, 1)
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	payload, err := json.Marshal(mpvRequest{Command: args, RequestID: id})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.write(append(payload, '\n')); err != nil {
+		return nil, err
+	}
+
+	// A hung mpv can't block us forever
+	timeout := time.NewTimer(mpvRequestTimeout)
+	defer timeout.Stop()
+	var response mpvResponse
+	select {
+	case response = <-reply:
+	case <-c.done:
+		// The reply may have arrived right before the connection closed
+		select {
+		case response = <-reply:
+		default:
+			return nil, c.err
+		}
+	case <-timeout.C:
+		return nil, fmt.Errorf("mpv %v: %w", args[0], os.ErrDeadlineExceeded)
+	}
+	if response.Error != "success" {
+		return nil, fmt.Errorf("mpv %v: %s", args[0], response.Error)
+	}
+	return response.Data, nil
+}
+
+func (c *mpvIPC) write(payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	// Unix sockets support deadlines
+	if conn, ok := c.conn.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		_ = conn.SetWriteDeadline(time.Now().Add(mpvRequestTimeout))
+	}
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+func (c *mpvIPC) close() error {
+	return c.conn.Close()
+}
+
+// mpvIdleObserver is the id idle-active changes are observed with.
+const mpvIdleObserver = 1
+
+// mpvStreamEvents turns what mpv says about its stream into player exits. With
+// --idle, mpv doesn't exit when the stream drops or ends: it ends the file and goes
+// idle instead, which is reported like the other players' exits.
+type mpvStreamEvents struct {
+	supervisor *supervisedProcess
+
+	mu      sync.Mutex
+	started bool // mpv left idle since the station was loaded
+}
+
+// load is called before a station is loaded: going idle counts once mpv started playing it.
+func (e *mpvStreamEvents) load() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.started = false
+}
+
+func (e *mpvStreamEvents) handle(event mpvResponse) {
+	switch event.Event {
+	case "end-file":
+		// Files replaced by the next station, or stopped, end with other reasons
+		switch event.Reason {
+		case "eof":
+			e.supervisor.report(PlayerExit{Reason: "end of stream"})
+		case "error":
+			reason := event.FileError
+			if reason == "" {
+				reason = "error"
+			}
+			e.supervisor.report(PlayerExit{Reason: reason})
+		}
+	case "property-change":
+		if event.Name != "idle-active" {
+			return
+		}
+		var idle bool
+		if err := json.Unmarshal(event.Data, &idle); err != nil {
+			return
+		}
+		e.mu.Lock()
+		ended := idle && e.started
+		e.started = e.started || !idle
+		e.mu.Unlock()
+		if ended {
+			e.supervisor.report(PlayerExit{Reason: "idle"})
+		}
+	}
+}
+
+// MPVPlaybackManager represents a playback manager for mpv.
+// A single idle mpv process is kept running while a station is playing and is
+// controlled over its JSON IPC socket, so volume changes, pausing and switching
+// stations don't restart the player.
+type MPVPlaybackManager struct {
+	player         Cmd
+	supervisor     *supervisedProcess // Watches player, when supervise is set
+	events         *mpvStreamEvents   // Reports the stream ending to supervisor
+	supervise      bool
+	exits          <-chan PlayerExit
+	ipc            *mpvIPC
+	playing        bool
+	paused         bool
+	currentStation common.Station
+	streamURL      string // The URL currentStation is actually streamed from
+	recorder       ffmpegRecorder
+	executor       CommandExecutor
+	resolver       StreamResolver // nil plays the station's URL as is
+	timeshift      timeshiftPlayer
+	dial           IPCDialer
+	socketPath     string
+	connectTimeout time.Duration
+	defaultVolume  int // Configured default volume (0-100)
+}
+
+// NewMPVPlaybackManager creates a new MPVPlaybackManager with the default command executor
+// and the specified default volume. The volume should be in the range 0-100.
+func NewMPVPlaybackManager(defaultVolume int) PlaybackManagerService {
+	executor := &realCommandExecutor{}
+	return &MPVPlaybackManager{
+		executor:       executor,
+		recorder:       ffmpegRecorder{executor: executor},
+		resolver:       NewStreamResolver(),
+		supervise:      true,
+		dial:           dialMPV,
+		socketPath:     mpvSocketPath(),
+		connectTimeout: mpvConnectTimeout,
+		defaultVolume:  clampVolume(defaultVolume),
+	}
+}
+
+// NewMPVPlaybackManagerWithExecutor creates a new MPVPlaybackManager with a custom command
+// executor and IPC dialer, connecting to socketPath. This is primarily useful for testing.
+// Uses default volume of 80.
+func NewMPVPlaybackManagerWithExecutor(executor CommandExecutor, dial IPCDialer, socketPath string) *MPVPlaybackManager {
+	return &MPVPlaybackManager{
+		executor:       executor,
+		recorder:       ffmpegRecorder{executor: executor},
+		dial:           dial,
+		socketPath:     socketPath,
+		connectTimeout: mpvConnectTimeout,
+		defaultVolume:  80,
+	}
+}
+
+func (d MPVPlaybackManager) Name() string {
+	return "mpv"
+}
+
+// IsPlaying returns true if a station is playing. An mpv that stopped by itself,
+// or whose stream ended, no longer counts as playing.
+func (d MPVPlaybackManager) IsPlaying() bool {
+	return d.playing && !d.playerExited() && !d.streamEnded()
+}
+
+// playerExited returns true if the supervised mpv stopped by itself.
+func (d MPVPlaybackManager) playerExited() bool {
+	return d.supervisor != nil && d.supervisor.hasExited()
+}
+
+// streamEnded returns true if the supervised mpv is idle because the station's stream ended.
+func (d MPVPlaybackManager) streamEnded() bool {
+	return d.supervisor != nil && d.supervisor.hasEnded()
+}
+
+func (d MPVPlaybackManager) IsAvailable() bool {
+	_, err := d.executor.LookPath("mpv")
+	return err == nil
+}
+
+func (d MPVPlaybackManager) NotAvailableErrorString() string {
+	return i18n.T("error_mpv_required")
+}
+
+// PlayStation starts playing the station, launching mpv if it isn't running yet.
+// When mpv is already running the new station replaces the current one in place.
+// The current station keeps playing if the new one can't be resolved.
+// With timeshifting enabled, mpv plays the station through the buffer.
+func (d *MPVPlaybackManager) PlayStation(station common.Station, volume int) error {
+	streamURL, err := resolveStream(d.resolver, station)
+	if err != nil {
+		return err
+	}
+
+	// An mpv that stopped by itself is started again
+	if d.playerExited() {
+		_ = d.stopPlayer()
+	}
+
+	// A recording belongs to the station it was started on
+	if _, err := d.StopRecording(); err != nil {
+		return err
+	}
+
+	if d.ipc == nil {
+		if err := d.startPlayer(volume); err != nil {
+			return err
+		}
+	}
+
+	// The previous station's buffer is kept until mpv lets go of it
+	previous := d.timeshift.detach()
+	if previous != nil {
+		defer previous.close()
+	}
+	playURL := d.timeshift.open(streamURL)
+
+	if d.supervisor != nil {
+		// Exits are reported to whoever watches the new station, including
+		// the end of its stream, which mpv reports once the file is loaded
+		d.exits = d.supervisor.watch()
+		d.events.load()
+	}
+	commands := [][]interface{}{
+		{"set_property", "volume", volume},
+		{"set_property", "pause", false},
+		{"loadfile", playURL, "replace"},
+	}
+	for _, command := range commands {
+		if _, err := d.ipc.command(command...); err != nil {
+			_ = d.stopPlayer()
+			return err
+		}
+	}
+
+	d.playing = true
+	d.paused = false
+	d.currentStation = station
+	d.streamURL = streamURL
+	return nil
+}
+
+// startPlayer launches mpv in idle mode and connects to its IPC socket.
+func (d *MPVPlaybackManager) startPlayer(volume int) error {
+	cmd := d.executor.Command("mpv",
+		"--idle=yes",
+		"--no-video",
+		"--no-terminal",
+		"--input-ipc-server="+d.socketPath,
+		fmt.Sprintf("--volume=%d", volume),
+	)
+	var supervisor *supervisedProcess
+	if d.supervise {
+		var err error
+		if supervisor, err = startSupervised(cmd); err != nil {
+			return err
+		}
+	} else if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		_ = cmd.Process().Kill()
+		if supervisor != nil {
+			supervisor.stop()
+			_ = supervisor.wait()
+		} else {
+			_, _ = cmd.Process().Wait()
+		}
+		return fmt.Errorf("%s: %w", i18n.T("error_mpv_ipc"), err)
+	}
+
+	d.player = cmd
+	d.supervisor = supervisor
+	var onEvent func(mpvResponse)
+	if supervisor != nil {
+		d.events = &mpvStreamEvents{supervisor: supervisor}
+		onEvent = d.events.handle
+	}
+	d.ipc = newMPVIPC(conn, onEvent)
+	if supervisor != nil {
+		if _, err := d.ipc.command("observe_property", mpvIdleObserver, "idle-active"); err != nil {
+			_ = d.stopPlayer()
+			return err
+		}
+	}
+	return nil
+}
+
+// connect dials the IPC socket, retrying while mpv is still starting up.
+func (d *MPVPlaybackManager) connect() (io.ReadWriteCloser, error) {
+	deadline := time.Now().Add(d.connectTimeout)
+	for {
+		conn, err := d.dial(d.socketPath)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(mpvConnectRetryDelay)
+	}
+}
+
+// StopStation stops the currently playing station and any active recording,
+// and shuts mpv down.
+func (d *MPVPlaybackManager) StopStation() error {
+	// Stop recording first if active
+	if _, err := d.StopRecording(); err != nil {
+		return err
+	}
+	return d.stopPlayer()
+}
+
+// stopPlayer asks mpv to quit, killing it if it doesn't answer over IPC.
+func (d *MPVPlaybackManager) stopPlayer() error {
+	if d.player == nil {
+		return nil
+	}
+
+	if d.supervisor != nil {
+		d.supervisor.stop()
+	}
+	// mpv may close the socket before replying to quit, which is fine.
+	// An mpv that already stopped by itself only needs cleaning up.
+	if !d.playerExited() {
+		if _, err := d.ipc.command("quit"); err != nil && !errors.Is(err, io.EOF) {
+			if err := d.player.Process().Kill(); err != nil && !d.playerExited() {
+				return err
+			}
+		}
+	}
+	_ = d.ipc.close()
+
+	// Wait for process to be reaped to avoid zombie processes
+	var err error
+	if d.supervisor != nil {
+		// The supervisor is already waiting on it
+		err = d.supervisor.wait()
+	} else {
+		_, err = d.player.Process().Wait()
+	}
+
+	d.timeshift.close()
+	d.player = nil
+	d.supervisor = nil
+	d.events = nil
+	d.exits = nil
+	d.ipc = nil
+	d.playing = false
+	d.paused = false
+	d.currentStation = common.Station{}
+	d.streamURL = ""
+	return err
+}
+
+// SetVolume changes the volume of the playing station instantly.
+func (d *MPVPlaybackManager) SetVolume(volume int) error {
+	if !d.IsPlaying() {
+		return errors.New(i18n.T("error_nothing_playing"))
+	}
+	_, err := d.ipc.command("set_property", "volume", volume)
+	return err
+}
+
+// Pause pauses playback. mpv keeps the stream loaded while paused.
+func (d *MPVPlaybackManager) Pause() error {
+	return d.setPaused(true)
+}
+
+// Resume resumes paused playback.
+func (d *MPVPlaybackManager) Resume() error {
+	return d.setPaused(false)
+}
+
+func (d *MPVPlaybackManager) setPaused(paused bool) error {
+	if !d.IsPlaying() {
+		return errors.New(i18n.T("error_nothing_playing"))
+	}
+	if _, err := d.ipc.command("set_property", "pause", paused); err != nil {
+		return err
+	}
+	d.paused = paused
+	return nil
+}
+
+func (d MPVPlaybackManager) IsPaused() bool {
+	return d.paused
+}
+
+// EnableTimeshift plays the stations played from now on through a buffer of
+// bufferSize bytes, or directly if bufferSize is 0.
+func (d *MPVPlaybackManager) EnableTimeshift(bufferSize int) {
+	d.timeshift.bufferSize = bufferSize
+}
+
+func (d MPVPlaybackManager) IsTimeshifting() bool {
+	return d.IsPlaying() && d.timeshift.isTimeshifting()
+}
+
+// PauseLive pauses mpv and stops feeding it, while the station keeps being buffered.
+func (d *MPVPlaybackManager) PauseLive() error {
+	if err := d.timeshift.pause(); err != nil {
+		return err
+	}
+	return d.setPaused(true)
+}
+
+// ResumeLive resumes mpv from where it was paused.
+func (d *MPVPlaybackManager) ResumeLive() error {
+	if err := d.timeshift.resume(); err != nil {
+		return err
+	}
+	return d.setPaused(false)
+}
+
+// JumpToLive drops the backlog and reloads the buffer's stream, so that mpv
+// doesn't keep playing from its own cache.
+func (d *MPVPlaybackManager) JumpToLive() error {
+	if err := d.timeshift.jumpToLive(); err != nil {
+		return err
+	}
+	commands := [][]interface{}{
+		{"loadfile", d.timeshift.current.URL(), "replace"},
+		{"set_property", "pause", false},
+	}
+	for _, command := range commands {
+		if _, err := d.ipc.command(command...); err != nil {
+			return err
+		}
+	}
+	d.paused = false
+	return nil
+}
+
+func (d MPVPlaybackManager) TimeshiftState() TimeshiftState {
+	return d.timeshift.state()
+}
+
+// MediaTitle returns mpv's media-title property, which follows the stream's
+// ICY title when the station sends one.
+func (d *MPVPlaybackManager) MediaTitle() (string, error) {
+	var title string
+	if err := d.getProperty("media-title", &title); err != nil {
+		return "", err
+	}
+	return title, nil
+}
+
+// Metadata returns the metadata tags mpv has read from the stream.
+func (d *MPVPlaybackManager) Metadata() (map[string]string, error) {
+	metadata := map[string]string{}
+	if err := d.getProperty("metadata", &metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (d *MPVPlaybackManager) getProperty(name string, value interface{}) error {
+	if !d.IsPlaying() {
+		return errors.New(i18n.T("error_nothing_playing"))
+	}
+	data, err := d.ipc.command("get_property", name)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func (d MPVPlaybackManager) VolumeMin() int {
+	return 0
+}
+
+func (d MPVPlaybackManager) VolumeDefault() int {
+	return d.defaultVolume
+}
+
+func (d MPVPlaybackManager) VolumeMax() int {
+	return 100
+}
+
+func (d MPVPlaybackManager) VolumeIsPercentage() bool {
+	return true
+}
+
+func (d MPVPlaybackManager) CurrentStation() common.Station {
+	return d.currentStation
+}
+
+// PlayerExited returns a channel receiving mpv's exit, should it stop by itself.
+func (d MPVPlaybackManager) PlayerExited() <-chan PlayerExit {
+	return d.exits
+}
+
+// StreamURL returns the URL the playing station is streamed from.
+func (d MPVPlaybackManager) StreamURL() string {
+	return d.streamURL
+}
+
+func (d MPVPlaybackManager) IsRecordingAvailable() bool {
+	return d.recorder.isAvailable()
+}
+
+func (d MPVPlaybackManager) RecordingNotAvailableErrorString() string {
+	return i18n.T("error_ffmpeg_required")
+}
+
+func (d MPVPlaybackManager) IsRecording() bool {
+	return d.recorder.isRecording()
+}
+
+// StartRecording records the playing stream with ffmpeg, independently of mpv.
+func (d *MPVPlaybackManager) StartRecording(outputPath string) error {
+	if !d.IsPlaying() {
+		return errors.New(i18n.T("error_no_station_playing"))
+	}
+	return d.recorder.start(d.streamURL, outputPath)
+}
+
+// StopRecording stops the current recording and returns the output file path.
+func (d *MPVPlaybackManager) StopRecording() (string, error) {
+	return d.recorder.stop()
+}
+
+func (d MPVPlaybackManager) CurrentRecordingPath() string {
+	return d.recorder.path
+}
@@ -0,0 +1,599 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMPV serves mpv's JSON IPC protocol on a local unix socket.
+type fakeMPV struct {
+	listener   net.Listener
+	socketPath string
+
+	mu         sync.Mutex
+	commands   [][]interface{}
+	properties map[string]interface{}
+	failing    map[string]bool
+	conn       net.Conn // The last client connected
+
+	writeMu sync.Mutex
+}
+
+func newFakeMPV(t *testing.T) *fakeMPV {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "mpv.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen on fake IPC socket: %v", err)
+	}
+	f := &fakeMPV{
+		listener:   listener,
+		socketPath: socketPath,
+		properties: map[string]interface{}{},
+		failing:    map[string]bool{},
+	}
+	t.Cleanup(func() { listener.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeMPV) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeMPV) handle(conn net.Conn) {
+	defer conn.Close()
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var request mpvRequest
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, request.Command)
+		name := fmt.Sprint(request.Command[0])
+		response := map[string]interface{}{"request_id": request.RequestID, "error": "success", "data": nil}
+		if f.failing[name] {
+			response["error"] = "error running command"
+		} else if name == "get_property" {
+			if value, ok := f.properties[fmt.Sprint(request.Command[1])]; ok {
+				response["data"] = value
+			} else {
+				response["error"] = "property unavailable"
+			}
+		}
+		f.mu.Unlock()
+
+		// mpv interleaves events with replies, the client must skip them
+		reply, _ := json.Marshal(response)
+		f.writeMu.Lock()
+		fmt.Fprintln(conn, `{"event":"playback-restart"}`)
+		fmt.Fprintln(conn, string(reply))
+		f.writeMu.Unlock()
+
+		if name == "quit" && !f.failing[name] {
+			return
+		}
+	}
+}
+
+// sendEvent broadcasts an event to the last client connected, as mpv does.
+func (f *fakeMPV) sendEvent(event string) {
+	f.mu.Lock()
+	conn := f.conn
+	f.mu.Unlock()
+	f.writeMu.Lock()
+	defer f.writeMu.Unlock()
+	fmt.Fprintln(conn, event)
+}
+
+func (f *fakeMPV) receivedCommands() [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]interface{}{}, f.commands...)
+}
+
+func (f *fakeMPV) lastCommand() []interface{} {
+	commands := f.receivedCommands()
+	if len(commands) == 0 {
+		return nil
+	}
+	return commands[len(commands)-1]
+}
+
+func dialUnix(address string) (io.ReadWriteCloser, error) {
+	return net.Dial("unix", address)
+}
+
+func newTestMPVManager(t *testing.T) (*MPVPlaybackManager, *fakeMPV, *mockExecutor) {
+	fake := newFakeMPV(t)
+	executor := newMockExecutor()
+	return NewMPVPlaybackManagerWithExecutor(executor, dialUnix, fake.socketPath), fake, executor
+}
+
+func TestMPVPlaybackManager(t *testing.T) {
+	t.Run("NewMPVPlaybackManager uses configured default volume", func(t *testing.T) {
+		manager := NewMPVPlaybackManager(50)
+		assert.Equal(t, 50, manager.VolumeDefault())
+	})
+
+	t.Run("NewMPVPlaybackManager clamps volume", func(t *testing.T) {
+		assert.Equal(t, 0, NewMPVPlaybackManager(-10).VolumeDefault())
+		assert.Equal(t, 100, NewMPVPlaybackManager(150).VolumeDefault())
+	})
+
+	t.Run("reports name and volume range", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+
+		assert.Equal(t, "mpv", manager.Name())
+		assert.Equal(t, 0, manager.VolumeMin())
+		assert.Equal(t, 100, manager.VolumeMax())
+		assert.Equal(t, 80, manager.VolumeDefault())
+		assert.True(t, manager.VolumeIsPercentage())
+	})
+
+	t.Run("implements the optional playback capabilities", func(t *testing.T) {
+		var manager PlaybackManagerService = NewMPVPlaybackManager(80)
+
+		_, isVolumeController := manager.(VolumeController)
+		_, isPauser := manager.(Pauser)
+		_, isMetadataReader := manager.(MetadataReader)
+
+		assert.True(t, isVolumeController)
+		assert.True(t, isPauser)
+		assert.True(t, isMetadataReader)
+	})
+
+	t.Run("ffplay doesn't support live control", func(t *testing.T) {
+		var manager PlaybackManagerService = NewFFPlaybackManager(80)
+
+		_, isVolumeController := manager.(VolumeController)
+		_, isPauser := manager.(Pauser)
+
+		assert.False(t, isVolumeController)
+		assert.False(t, isPauser)
+	})
+}
+
+func TestMPVPlaybackManager_IsAvailable(t *testing.T) {
+	t.Run("returns true when mpv is in PATH", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.True(t, manager.IsAvailable())
+	})
+
+	t.Run("returns false when mpv is missing", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		executor.lookPathResults["mpv"] = errors.New("not found")
+
+		assert.False(t, manager.IsAvailable())
+		assert.NotEmpty(t, manager.NotAvailableErrorString())
+	})
+}
+
+func TestMPVPlaybackManager_PlayStation(t *testing.T) {
+	t.Run("starts idle mpv and loads the station over IPC", func(t *testing.T) {
+		manager, fake, executor := newTestMPVManager(t)
+		station := testStation("http://example.com/stream")
+
+		err := manager.PlayStation(station, 70)
+
+		assert.NoError(t, err)
+		assert.True(t, manager.IsPlaying())
+		assert.Equal(t, station, manager.CurrentStation())
+		assert.Equal(t, [][]string{{
+			"mpv", "--idle=yes", "--no-video", "--no-terminal",
+			"--input-ipc-server=" + fake.socketPath, "--volume=70",
+		}}, executor.commandCalls)
+		assert.Equal(t, [][]interface{}{
+			{"set_property", "volume", float64(70)},
+			{"set_property", "pause", false},
+			{"loadfile", "http://example.com/stream", "replace"},
+		}, fake.receivedCommands())
+	})
+
+	t.Run("switching stations reuses the running player", func(t *testing.T) {
+		manager, fake, executor := newTestMPVManager(t)
+		first := testStation("http://example.com/first")
+		second := testStation("http://example.com/second")
+
+		assert.NoError(t, manager.PlayStation(first, 70))
+		assert.NoError(t, manager.PlayStation(second, 70))
+
+		assert.Len(t, executor.commandCalls, 1)
+		assert.Equal(t, []interface{}{"loadfile", "http://example.com/second", "replace"}, fake.lastCommand())
+		assert.Equal(t, second, manager.CurrentStation())
+	})
+
+	t.Run("returns error when mpv fails to start", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{startErr: errors.New("exec failed"), process: &mockProcess{}}
+		}
+
+		err := manager.PlayStation(testStation("http://example.com/stream"), 70)
+
+		assert.Error(t, err)
+		assert.False(t, manager.IsPlaying())
+	})
+
+	t.Run("kills mpv when the IPC socket never appears", func(t *testing.T) {
+		process := &mockProcess{pid: 1}
+		executor := newMockExecutor()
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		manager := NewMPVPlaybackManagerWithExecutor(executor, dialUnix, filepath.Join(t.TempDir(), "missing.sock"))
+		manager.connectTimeout = 100 * time.Millisecond
+
+		err := manager.PlayStation(testStation("http://example.com/stream"), 70)
+
+		assert.Error(t, err)
+		assert.True(t, process.killCalled)
+		assert.False(t, manager.IsPlaying())
+	})
+
+	t.Run("stops mpv when the station can't be loaded", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		fake.failing["loadfile"] = true
+
+		err := manager.PlayStation(testStation("http://example.com/stream"), 70)
+
+		assert.Error(t, err)
+		assert.False(t, manager.IsPlaying())
+		assert.Equal(t, []interface{}{"quit"}, fake.lastCommand())
+	})
+
+	t.Run("loads the resolved stream URL", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		manager.resolver = mockStreamResolver{streamURL: "http://cdn.example.com/live.aac"}
+
+		err := manager.PlayStation(testStation("http://example.com/listen.m3u"), 70)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"loadfile", "http://cdn.example.com/live.aac", "replace"}, fake.lastCommand())
+		assert.Equal(t, "http://cdn.example.com/live.aac", manager.StreamURL())
+	})
+}
+
+func TestMPVPlaybackManager_StopStation(t *testing.T) {
+	t.Run("does nothing when idle", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+
+		assert.NoError(t, manager.StopStation())
+		assert.Empty(t, fake.receivedCommands())
+	})
+
+	t.Run("asks mpv to quit", func(t *testing.T) {
+		process := &mockProcess{pid: 1}
+		manager, fake, executor := newTestMPVManager(t)
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		err := manager.StopStation()
+
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"quit"}, fake.lastCommand())
+		assert.False(t, process.killCalled)
+		assert.False(t, manager.IsPlaying())
+		assert.Equal(t, "", manager.CurrentStation().Name)
+	})
+
+	t.Run("kills mpv when quit fails", func(t *testing.T) {
+		process := &mockProcess{pid: 1}
+		manager, fake, executor := newTestMPVManager(t)
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		fake.failing["quit"] = true
+
+		err := manager.StopStation()
+
+		assert.NoError(t, err)
+		assert.True(t, process.killCalled)
+		assert.False(t, manager.IsPlaying())
+	})
+
+	t.Run("stops an active recording", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		assert.NoError(t, manager.StartRecording("out.mp3"))
+
+		assert.NoError(t, manager.StopStation())
+
+		assert.False(t, manager.IsRecording())
+		assert.Equal(t, "ffmpeg", executor.commandCalls[1][0])
+	})
+}
+
+func TestMPVPlaybackManager_LiveControl(t *testing.T) {
+	t.Run("SetVolume changes volume without restarting mpv", func(t *testing.T) {
+		manager, fake, executor := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		err := manager.SetVolume(40)
+
+		assert.NoError(t, err)
+		assert.Len(t, executor.commandCalls, 1)
+		assert.Equal(t, []interface{}{"set_property", "volume", float64(40)}, fake.lastCommand())
+	})
+
+	t.Run("SetVolume fails when nothing is playing", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.Error(t, manager.SetVolume(40))
+	})
+
+	t.Run("Pause and Resume toggle the pause property", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		assert.NoError(t, manager.Pause())
+		assert.True(t, manager.IsPaused())
+		assert.True(t, manager.IsPlaying())
+		assert.Equal(t, []interface{}{"set_property", "pause", true}, fake.lastCommand())
+
+		assert.NoError(t, manager.Resume())
+		assert.False(t, manager.IsPaused())
+		assert.Equal(t, []interface{}{"set_property", "pause", false}, fake.lastCommand())
+	})
+
+	t.Run("failed pause keeps state", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		fake.failing["set_property"] = true
+
+		assert.Error(t, manager.Pause())
+		assert.False(t, manager.IsPaused())
+	})
+
+	t.Run("playing a new station resumes playback", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		assert.NoError(t, manager.Pause())
+
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/other"), 70))
+
+		assert.False(t, manager.IsPaused())
+	})
+
+	t.Run("Pause fails when nothing is playing", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.Error(t, manager.Pause())
+	})
+}
+
+func TestMPVPlaybackManager_Metadata(t *testing.T) {
+	t.Run("MediaTitle returns the stream title", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		fake.properties["media-title"] = "Artist - Song"
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		title, err := manager.MediaTitle()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Artist - Song", title)
+		assert.Equal(t, []interface{}{"get_property", "media-title"}, fake.lastCommand())
+	})
+
+	t.Run("Metadata returns the stream tags", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		fake.properties["metadata"] = map[string]string{"icy-title": "Artist - Song", "icy-name": "Test FM"}
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		metadata, err := manager.Metadata()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"icy-title": "Artist - Song", "icy-name": "Test FM"}, metadata)
+	})
+
+	t.Run("returns error when the property is unavailable", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		_, err := manager.MediaTitle()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error when nothing is playing", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+
+		_, err := manager.Metadata()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMPVPlaybackManager_Recording(t *testing.T) {
+	t.Run("records the playing stream with ffmpeg", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		err := manager.StartRecording("out.mp3")
+
+		assert.NoError(t, err)
+		assert.True(t, manager.IsRecording())
+		assert.Equal(t, "out.mp3", manager.CurrentRecordingPath())
+		assert.Equal(t, []string{"ffmpeg", "-y", "-i", "http://example.com/stream", "-c", "copy", "out.mp3"}, executor.commandCalls[1])
+
+		path, err := manager.StopRecording()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "out.mp3", path)
+		assert.False(t, manager.IsRecording())
+		assert.True(t, manager.IsPlaying())
+	})
+
+	t.Run("fails when nothing is playing", func(t *testing.T) {
+		manager, _, _ := newTestMPVManager(t)
+		assert.Error(t, manager.StartRecording("out.mp3"))
+	})
+
+	t.Run("reports ffmpeg availability", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		assert.True(t, manager.IsRecordingAvailable())
+
+		executor.lookPathResults["ffmpeg"] = errors.New("not found")
+		assert.False(t, manager.IsRecordingAvailable())
+		assert.NotEmpty(t, manager.RecordingNotAvailableErrorString())
+	})
+}
+
+func TestMPVPlaybackManager_StreamEvents(t *testing.T) {
+	// newSupervisedMPV returns a playing mpv manager watching its player, whose process keeps running.
+	newSupervisedMPV := func(t *testing.T) (*MPVPlaybackManager, *fakeMPV, *mockExecutor) {
+		manager, fake, executor := newTestMPVManager(t)
+		process := &mockProcess{pid: 1, exited: make(chan struct{})}
+		t.Cleanup(process.exit)
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		manager.supervise = true
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		return manager, fake, executor
+	}
+
+	t.Run("observes whether mpv is idle", func(t *testing.T) {
+		_, fake, _ := newSupervisedMPV(t)
+
+		assert.Equal(t, []interface{}{"observe_property", float64(mpvIdleObserver), "idle-active"}, fake.receivedCommands()[0])
+	})
+
+	t.Run("reports the stream failing while mpv stays idle", func(t *testing.T) {
+		manager, fake, executor := newSupervisedMPV(t)
+		exits := manager.PlayerExited()
+
+		fake.sendEvent(`{"event":"property-change","id":1,"name":"idle-active","data":false}`)
+		fake.sendEvent(`{"event":"end-file","reason":"error","file_error":"loading failed"}`)
+		exit, ok := receiveExit(t, exits)
+
+		assert.True(t, ok)
+		assert.Equal(t, "loading failed", exit.Reason)
+		assert.False(t, manager.IsPlaying())
+
+		// The idle mpv plays the next station
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		assert.Len(t, executor.commandCalls, 1)
+		assert.True(t, manager.IsPlaying())
+	})
+
+	t.Run("reports the stream ending", func(t *testing.T) {
+		manager, fake, _ := newSupervisedMPV(t)
+		exits := manager.PlayerExited()
+
+		fake.sendEvent(`{"event":"end-file","reason":"eof"}`)
+		exit, ok := receiveExit(t, exits)
+
+		assert.True(t, ok)
+		assert.Equal(t, "end of stream", exit.Reason)
+	})
+
+	t.Run("reports mpv going idle after playing", func(t *testing.T) {
+		manager, fake, _ := newSupervisedMPV(t)
+		exits := manager.PlayerExited()
+
+		fake.sendEvent(`{"event":"property-change","id":1,"name":"idle-active","data":false}`)
+		fake.sendEvent(`{"event":"property-change","id":1,"name":"idle-active","data":true}`)
+		exit, ok := receiveExit(t, exits)
+
+		assert.True(t, ok)
+		assert.Equal(t, "idle", exit.Reason)
+		assert.False(t, manager.IsPlaying())
+	})
+
+	t.Run("ignores replaced files and the idle state before playing", func(t *testing.T) {
+		manager, fake, _ := newSupervisedMPV(t)
+
+		fake.sendEvent(`{"event":"property-change","id":1,"name":"idle-active","data":true}`)
+		fake.sendEvent(`{"event":"end-file","reason":"stop"}`)
+		// Events are handled in order, before the reply to a later request
+		assert.NoError(t, manager.SetVolume(50))
+
+		assert.True(t, manager.IsPlaying())
+		select {
+		case <-manager.PlayerExited():
+			t.Fatal("no exit should have been reported")
+		default:
+		}
+	})
+}
+
+func TestMPVIPC(t *testing.T) {
+	t.Run("matches concurrent replies to their requests", func(t *testing.T) {
+		manager, fake, _ := newTestMPVManager(t)
+		fake.properties["media-title"] = "Song"
+		fake.properties["metadata"] = map[string]string{"icy-title": "Song"}
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				title, err := manager.MediaTitle()
+				assert.NoError(t, err)
+				assert.Equal(t, "Song", title)
+			}()
+			go func() {
+				defer wg.Done()
+				metadata, err := manager.Metadata()
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]string{"icy-title": "Song"}, metadata)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("fails pending requests when mpv closes the connection", func(t *testing.T) {
+		client, server := net.Pipe()
+		ipc := newMPVIPC(client, nil)
+		go func() {
+			_, _ = bufio.NewReader(server).ReadBytes('\n')
+			server.Close()
+		}()
+
+		_, err := ipc.command("quit")
+
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+// ffmpegRecorder records a stream to disk with a separate ffmpeg process,
+// independently of the player. It is shared by all playback managers.
+type ffmpegRecorder struct {
+	executor CommandExecutor
+	cmd      Cmd
+	path     string
+}
+
+func (r *ffmpegRecorder) isAvailable() bool {
+	_, err := r.executor.LookPath("ffmpeg")
+	return err == nil
+}
+
+func (r *ffmpegRecorder) isRecording() bool {
+	return r.cmd != nil
+}
+
+// start begins recording streamURL to outputPath, stopping any previous recording first.
+func (r *ffmpegRecorder) start(streamURL string, outputPath string) error {
+	if _, err := r.stop(); err != nil {
+		return err
+	}
+
+	// Start ffmpeg recording: ffmpeg -i <stream_url> -c copy output.ext
+	// Use -y to overwrite existing files without prompting
+	cmd := r.executor.Command("ffmpeg", "-y", "-i", streamURL, "-c", "copy", outputPath)
+
+	// Suppress ffmpeg's stderr output (it's verbose)
+	cmd.SetStderr(nil)
+	cmd.SetStdout(nil)
+
+	err := cmd.Start()
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("error_start_recording"), err)
+	}
+
+	r.cmd = cmd
+	r.path = outputPath
+	return nil
+}
+
+// stop stops the current recording and returns the output file path.
+// Platform-specific behavior:
+//   - Windows: Uses taskkill to force-terminate ffmpeg. This may result in
+//     slightly corrupted file endings, but is the most reliable cross-platform
+//     approach on Windows.
+//   - Unix/macOS: Sends SIGINT (Ctrl+C) to ffmpeg, allowing it to gracefully
+//     finalize the output file (write proper headers/trailers). Falls back to
+//     SIGKILL if SIGINT fails.
+func (r *ffmpegRecorder) stop() (string, error) {
+	if r.cmd == nil {
+		return "", nil
+	}
+
+	filePath := r.path
+
+	if err := interruptFFmpeg(r.executor, r.cmd); err != nil {
+		return "", err
+	}
+
+	// Wait for process to be reaped (ignore errors as process may have already exited)
+	_, _ = r.cmd.Process().Wait()
+
+	r.cmd = nil
+	r.path = ""
+
+	return filePath, nil
+}
+
+// interruptFFmpeg asks a running ffmpeg to end its recording, see stop.
+func interruptFFmpeg(executor CommandExecutor, cmd Cmd) error {
+	if runtime.GOOS == "windows" {
+		// Windows: Force kill - ffmpeg doesn't handle signals well on Windows
+		killCmd := executor.Command("taskkill", "/T", "/F", "/PID", fmt.Sprintf("%d", cmd.Process().Pid()))
+		return killCmd.Run()
+	}
+	// Unix/macOS: SIGINT allows ffmpeg to finalize the output file properly
+	if err := cmd.Process().Signal(os.Interrupt); err != nil {
+		// Fallback to SIGKILL if SIGINT fails (process may be unresponsive)
+		return cmd.Process().Kill()
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package playback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/data"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
+	"github.com/zi0p4tch0/radiogogo/playlist"
+)
+
+const (
+	// resolverTimeout bounds each URL tried when resolving a stream.
+	resolverTimeout = 10 * time.Second
+	// maxPlaylistSize is the largest playlist read; longer ones are cut.
+	maxPlaylistSize = 64 * 1024
+	// maxPlaylistDepth is how many playlists deep (playlists linking to playlists) resolution goes.
+	maxPlaylistDepth = 3
+	// sniffSize is how many bytes are read to tell a playlist from a stream.
+	sniffSize = 512
+)
+
+// StreamResolver finds the URL a station can be streamed from.
+type StreamResolver interface {
+	// Resolve returns the URL to hand the player for station.
+	Resolve(station common.Station) (string, error)
+}
+
+// HTTPClient makes the requests of an HTTPStreamResolver. *http.Client satisfies it.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// HTTPStreamResolver resolves stations by connecting to their URLs: the resolved URL
+// first, then the URL provided by the station, then (when they turn out to be
+// playlists) every entry of the playlist. Redirects are followed, and the first URL
+// sending audio wins.
+type HTTPStreamResolver struct {
+	client  HTTPClient
+	timeout time.Duration
+}
+
+// NewStreamResolver returns a resolver that also understands Shoutcast v1 servers.
+func NewStreamResolver() *HTTPStreamResolver {
+	return NewStreamResolverWithClient(icy.NewHTTPClient(), resolverTimeout)
+}
+
+// NewStreamResolverWithClient returns a resolver making its requests with client,
+// giving up on each URL after timeout.
+func NewStreamResolverWithClient(client HTTPClient, timeout time.Duration) *HTTPStreamResolver {
+	return &HTTPStreamResolver{client: client, timeout: timeout}
+}
+
+// streamCandidates returns the URLs a station can be streamed from, best first.
+func streamCandidates(station common.Station) []string {
+	var candidates []string
+	for _, u := range []string{station.UrlResolved.URL.String(), station.Url.URL.String()} {
+		if u != "" && (len(candidates) == 0 || candidates[0] != u) {
+			candidates = append(candidates, u)
+		}
+	}
+	return candidates
+}
+
+// Resolve returns the first URL of the station that sends audio.
+func (r *HTTPStreamResolver) Resolve(station common.Station) (string, error) {
+	candidates := streamCandidates(station)
+	if len(candidates) == 0 {
+		return "", errors.New(i18n.T("error_no_stream_url"))
+	}
+	seen := make(map[string]bool)
+	var lastErr error
+	for _, candidate := range candidates {
+		streamURL, err := r.resolve(candidate, 0, seen)
+		if err == nil {
+			return streamURL, nil
+		}
+		lastErr = err
+	}
+	return "", errors.New(i18n.Tf("error_stream_unplayable", map[string]interface{}{"Error": lastErr.Error()}))
+}
+
+// resolve connects to rawURL and returns where its audio comes from: the URL itself
+// (after redirects) if it's a stream, or the first working entry if it's a playlist.
+func (r *HTTPStreamResolver) resolve(rawURL string, depth int, seen map[string]bool) (string, error) {
+	if seen[rawURL] {
+		return "", fmt.Errorf("%s was already tried", rawURL)
+	}
+	seen[rawURL] = true
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		// Players connect to other protocols (mms, rtsp, rtmp...) themselves
+		return rawURL, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", data.UserAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("%s answered %s", rawURL, resp.Status)
+	}
+	// Where redirects led
+	finalURL := u
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	if n == 0 {
+		return "", fmt.Errorf("%s sent no data", rawURL)
+	}
+
+	format := playlist.Detect(finalURL.Path, resp.Header.Get("Content-Type"), head)
+	if format == "" {
+		return finalURL.String(), nil
+	}
+	if depth >= maxPlaylistDepth {
+		return "", fmt.Errorf("%s: too many nested playlists", rawURL)
+	}
+
+	rest, err := io.ReadAll(io.LimitReader(resp.Body, maxPlaylistSize-int64(n)))
+	if err != nil {
+		return "", err
+	}
+	entries, err := playlist.Parse("playlist"+format, append(head, rest...))
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("%s is an empty playlist", rawURL)
+	}
+
+	var lastErr error
+	for _, entry := range entries {
+		// Entries may be relative to the playlist
+		entryURL, err := finalURL.Parse(entry.URL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		streamURL, err := r.resolve(entryURL.String(), depth+1, seen)
+		if err == nil {
+			return streamURL, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// resolveStream returns the URL to stream station from. Without a resolver the
+// station's URL is used as is.
+func resolveStream(resolver StreamResolver, station common.Station) (string, error) {
+	if resolver == nil {
+		if streamURL := station.StreamURL(); streamURL != "" {
+			return streamURL, nil
+		}
+		return "", errors.New(i18n.T("error_no_stream_url"))
+	}
+	return resolver.Resolve(station)
+}
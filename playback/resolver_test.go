@@ -0,0 +1,290 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package playback
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// streamServer serves an endless-looking MP3 stream at /stream, plus whatever
+// playlists the test registers.
+func streamServer(t *testing.T) (*httptest.Server, *http.ServeMux) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		_, _ = w.Write(make([]byte, 4096))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, mux
+}
+
+func servePlaylist(mux *http.ServeMux, path, contentType, body string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = fmt.Fprint(w, body)
+	})
+}
+
+func resolverStation(rawURL, rawResolved string) common.Station {
+	station := common.Station{Name: "Test Station"}
+	if rawURL != "" {
+		u, _ := url.Parse(rawURL)
+		station.Url = common.RadioGoGoURL{URL: *u}
+	}
+	if rawResolved != "" {
+		u, _ := url.Parse(rawResolved)
+		station.UrlResolved = common.RadioGoGoURL{URL: *u}
+	}
+	return station
+}
+
+func newTestResolver() *HTTPStreamResolver {
+	return NewStreamResolverWithClient(http.DefaultClient, 5*time.Second)
+}
+
+func TestHTTPStreamResolver_Resolve(t *testing.T) {
+	t.Run("returns a direct stream URL", func(t *testing.T) {
+		server, _ := streamServer(t)
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/stream", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("prefers the resolved URL", func(t *testing.T) {
+		server, mux := streamServer(t)
+		mux.HandleFunc("/other", func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Url should not be requested when UrlResolved works")
+		})
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/other", server.URL+"/stream"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("falls back to Url when the resolved URL fails", func(t *testing.T) {
+		server, mux := streamServer(t)
+		mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/stream", server.URL+"/dead"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("follows redirects", func(t *testing.T) {
+		server, mux := streamServer(t)
+		mux.Handle("/hop1", http.RedirectHandler("/hop2", http.StatusFound))
+		mux.Handle("/hop2", http.RedirectHandler("/stream", http.StatusMovedPermanently))
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/hop1", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("opens PLS playlists", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/listen.pls", "audio/x-scpls",
+			"[playlist]\nNumberOfEntries=1\nFile1="+server.URL+"/stream\nTitle1=Test\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen.pls", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("opens M3U playlists with relative entries", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/listen.m3u", "audio/x-mpegurl", "#EXTM3U\n#EXTINF:-1,Test\n/stream\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen.m3u", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("opens ASX playlists", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/listen.asx", "video/x-ms-asf",
+			`<asx version="3.0"><entry><ref href="`+server.URL+`/stream"/></entry></asx>`)
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen.asx", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("opens XSPF playlists", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/listen.xspf", "application/xspf+xml",
+			`<?xml version="1.0"?><playlist version="1" xmlns="http://xspf.org/ns/0/"><trackList><track><location>`+
+				server.URL+`/stream</location></track></trackList></playlist>`)
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen.xspf", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("detects playlists served without a playlist content type", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/listen", "text/plain",
+			"[playlist]\nNumberOfEntries=1\nFile1="+server.URL+"/stream\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("tries each playlist entry until one works", func(t *testing.T) {
+		server, mux := streamServer(t)
+		mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		servePlaylist(mux, "/listen.m3u", "audio/x-mpegurl",
+			server.URL+"/dead\n"+server.URL+"/stream\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/listen.m3u", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("follows nested playlists", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/inner.m3u", "audio/x-mpegurl", "/stream\n")
+		servePlaylist(mux, "/outer.pls", "audio/x-scpls", "[playlist]\nFile1=/inner.m3u\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/outer.pls", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/stream", streamURL)
+	})
+
+	t.Run("stops on playlists that link to themselves", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/loop.m3u", "audio/x-mpegurl", "/loop.m3u\n")
+
+		_, err := newTestResolver().Resolve(resolverStation(server.URL+"/loop.m3u", ""))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("leaves HLS playlists to the player", func(t *testing.T) {
+		server, mux := streamServer(t)
+		servePlaylist(mux, "/live.m3u8", "application/vnd.apple.mpegurl",
+			"#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:10\nsegment0.ts\n")
+
+		streamURL, err := newTestResolver().Resolve(resolverStation(server.URL+"/live.m3u8", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, server.URL+"/live.m3u8", streamURL)
+	})
+
+	t.Run("returns non-HTTP URLs as is", func(t *testing.T) {
+		streamURL, err := newTestResolver().Resolve(resolverStation("mms://example.com/live", ""))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "mms://example.com/live", streamURL)
+	})
+
+	t.Run("returns an error when every URL fails", func(t *testing.T) {
+		server, mux := streamServer(t)
+		mux.HandleFunc("/dead", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := newTestResolver().Resolve(resolverStation(server.URL+"/dead", server.URL+"/dead"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("returns an error when the station has no URL", func(t *testing.T) {
+		_, err := newTestResolver().Resolve(common.Station{})
+
+		assert.Error(t, err)
+	})
+}
+
+// mockStreamResolver resolves every station to a fixed URL or error.
+type mockStreamResolver struct {
+	streamURL string
+	err       error
+}
+
+func (r mockStreamResolver) Resolve(station common.Station) (string, error) {
+	return r.streamURL, r.err
+}
+
+func TestCommandPlaybackManager_StreamResolution(t *testing.T) {
+	t.Run("plays and records the resolved URL", func(t *testing.T) {
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.resolver = mockStreamResolver{streamURL: "http://cdn.example.com/live.mp3"}
+
+		err := manager.PlayStation(testStation("http://example.com/listen.pls"), 80)
+		assert.NoError(t, err)
+		err = manager.StartRecording("/tmp/out.mp3")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "http://cdn.example.com/live.mp3", manager.StreamURL())
+		assert.Contains(t, executor.commandCalls[0], "http://cdn.example.com/live.mp3")
+		assert.Contains(t, executor.commandCalls[1], "http://cdn.example.com/live.mp3")
+	})
+
+	t.Run("keeps the current station when resolution fails", func(t *testing.T) {
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		current := testStation("http://example.com/stream")
+		assert.NoError(t, manager.PlayStation(current, 80))
+
+		manager.resolver = mockStreamResolver{err: errors.New("unplayable")}
+		err := manager.PlayStation(testStation("http://example.com/broken"), 80)
+
+		assert.Error(t, err)
+		assert.True(t, manager.IsPlaying())
+		assert.Equal(t, current.StationUuid, manager.CurrentStation().StationUuid)
+	})
+
+	t.Run("clears the stream URL on stop", func(t *testing.T) {
+		manager := NewFFPlaybackManagerWithExecutor(newMockExecutor())
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 80))
+
+		assert.NoError(t, manager.StopStation())
+
+		assert.Empty(t, manager.StreamURL())
+	})
+}
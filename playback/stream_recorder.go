@@ -0,0 +1,200 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+)
+
+// RecordingExit reports a background recording that ended by itself.
+type RecordingExit struct {
+	// ID is the identifier the recording was started with.
+	ID int64
+	// Path is the recording's output file.
+	Path string
+	// Err tells why ffmpeg failed, nil if the recording finished.
+	Err error
+}
+
+// StreamRecorderService records stations in the background, each with its own
+// ffmpeg process, independently of what is playing.
+type StreamRecorderService interface {
+	// IsAvailable returns true if ffmpeg is installed.
+	IsAvailable() bool
+	// Start records station to outputPath for duration, replacing any recording with the same id.
+	Start(id int64, station common.Station, outputPath string, duration time.Duration) error
+	// Stop ends a recording early and returns its output path.
+	// Recordings that are stopped aren't reported by Exits.
+	Stop(id int64) (string, error)
+	// StopAll ends every recording.
+	StopAll()
+	// IsRecording returns true while the recording with the given id runs.
+	IsRecording(id int64) bool
+	// Exits receives the recordings that ended by themselves, finished or failed.
+	Exits() <-chan RecordingExit
+}
+
+// recordingExitsBuffer is how many exits are kept until Exits is read.
+const recordingExitsBuffer = 16
+
+// StreamRecorder is the ffmpeg implementation of StreamRecorderService.
+type StreamRecorder struct {
+	executor CommandExecutor
+	resolver StreamResolver // nil records the station's URL as is
+	exits    chan RecordingExit
+
+	mu         sync.Mutex
+	recordings map[int64]*backgroundRecording
+}
+
+// backgroundRecording is a running ffmpeg process of a StreamRecorder.
+type backgroundRecording struct {
+	cmd     Cmd
+	process *supervisedProcess
+	path    string
+}
+
+// NewStreamRecorder creates a recorder running ffmpeg from PATH.
+func NewStreamRecorder() *StreamRecorder {
+	return newStreamRecorder(&realCommandExecutor{}, NewStreamResolver())
+}
+
+func newStreamRecorder(executor CommandExecutor, resolver StreamResolver) *StreamRecorder {
+	return &StreamRecorder{
+		executor:   executor,
+		resolver:   resolver,
+		exits:      make(chan RecordingExit, recordingExitsBuffer),
+		recordings: make(map[int64]*backgroundRecording),
+	}
+}
+
+func (r *StreamRecorder) IsAvailable() bool {
+	_, err := r.executor.LookPath("ffmpeg")
+	return err == nil
+}
+
+func (r *StreamRecorder) Start(id int64, station common.Station, outputPath string, duration time.Duration) error {
+	if _, err := r.Stop(id); err != nil {
+		return err
+	}
+
+	streamURL, err := resolveStream(r.resolver, station)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("error_start_recording"), err)
+	}
+
+	// ffmpeg ends the recording by itself after -t seconds
+	seconds := strconv.FormatInt(int64(duration.Round(time.Second)/time.Second), 10)
+	cmd := r.executor.Command("ffmpeg", "-y", "-i", streamURL, "-t", seconds, "-c", "copy", outputPath)
+	cmd.SetStdout(nil)
+
+	process, err := startSupervised(cmd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", i18n.T("error_start_recording"), err)
+	}
+
+	recording := &backgroundRecording{cmd: cmd, process: process, path: outputPath}
+	exits := process.watch()
+
+	r.mu.Lock()
+	r.recordings[id] = recording
+	r.mu.Unlock()
+
+	go func() {
+		exit, ok := <-exits
+		if !ok {
+			// Stopped on purpose
+			return
+		}
+		r.mu.Lock()
+		if r.recordings[id] == recording {
+			delete(r.recordings, id)
+		}
+		r.mu.Unlock()
+
+		result := RecordingExit{ID: id, Path: outputPath}
+		if !process.succeeded() {
+			result.Err = recordingError(exit)
+		}
+		r.exits <- result
+	}()
+	return nil
+}
+
+// recordingError describes a failed ffmpeg with its last line of output.
+func recordingError(exit PlayerExit) error {
+	lines := strings.Split(exit.Stderr, "\n")
+	if last := lines[len(lines)-1]; last != "" {
+		return fmt.Errorf("%s: %s", exit.Reason, last)
+	}
+	return errors.New(exit.Reason)
+}
+
+// Stop ends a recording the same way manual recordings are stopped, so that
+// ffmpeg can finalize the file.
+func (r *StreamRecorder) Stop(id int64) (string, error) {
+	r.mu.Lock()
+	recording, ok := r.recordings[id]
+	delete(r.recordings, id)
+	r.mu.Unlock()
+	if !ok {
+		return "", nil
+	}
+
+	recording.process.stop()
+	if !recording.process.hasExited() {
+		if err := interruptFFmpeg(r.executor, recording.cmd); err != nil {
+			return "", err
+		}
+	}
+	// Exit errors don't matter once stopped
+	_ = recording.process.wait()
+	return recording.path, nil
+}
+
+func (r *StreamRecorder) StopAll() {
+	r.mu.Lock()
+	ids := make([]int64, 0, len(r.recordings))
+	for id := range r.recordings {
+		ids = append(ids, id)
+	}
+	r.mu.Unlock()
+	for _, id := range ids {
+		_, _ = r.Stop(id)
+	}
+}
+
+func (r *StreamRecorder) IsRecording(id int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.recordings[id]
+	return ok
+}
+
+func (r *StreamRecorder) Exits() <-chan RecordingExit {
+	return r.exits
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStreamRecorder returns a recorder whose ffmpeg processes only exit
+// when interrupted, killed or told to.
+func newTestStreamRecorder(process *mockProcess, stderr string) (*StreamRecorder, *mockExecutor) {
+	executor := newMockExecutor()
+	executor.commandFunc = func(name string, args ...string) Cmd {
+		return &mockCmd{process: process, stderr: stderr}
+	}
+	return newStreamRecorder(executor, nil), executor
+}
+
+func receiveRecordingExit(t *testing.T, exits <-chan RecordingExit) RecordingExit {
+	t.Helper()
+	select {
+	case exit := <-exits:
+		return exit
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the recording's exit")
+		return RecordingExit{}
+	}
+}
+
+func TestStreamRecorder(t *testing.T) {
+	t.Run("is available when ffmpeg is installed", func(t *testing.T) {
+		recorder, executor := newTestStreamRecorder(&mockProcess{}, "")
+		assert.True(t, recorder.IsAvailable())
+
+		executor.lookPathResults["ffmpeg"] = errors.New("not found")
+		assert.False(t, recorder.IsAvailable())
+	})
+
+	t.Run("records for the given duration", func(t *testing.T) {
+		process := &mockProcess{pid: 1, exited: make(chan struct{})}
+		recorder, executor := newTestStreamRecorder(process, "")
+
+		err := recorder.Start(7, testStation("http://example.com/stream"), "/tmp/show.mp3", 90*time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, recorder.IsRecording(7))
+		assert.False(t, recorder.IsRecording(8))
+		assert.Equal(t, [][]string{{"ffmpeg", "-y", "-i", "http://example.com/stream", "-t", "5400", "-c", "copy", "/tmp/show.mp3"}}, executor.commandCalls)
+
+		process.exit()
+		exit := receiveRecordingExit(t, recorder.Exits())
+
+		assert.Equal(t, RecordingExit{ID: 7, Path: "/tmp/show.mp3"}, exit)
+		assert.False(t, recorder.IsRecording(7))
+	})
+
+	t.Run("reports ffmpeg failing with its last output", func(t *testing.T) {
+		process := &mockProcess{pid: 1, exited: make(chan struct{}), waitErr: errors.New("exit status 1")}
+		recorder, _ := newTestStreamRecorder(process, "Input #0\nhttp://example.com/stream: Server returned 404 Not Found\n")
+
+		assert.NoError(t, recorder.Start(1, testStation("http://example.com/stream"), "/tmp/show.mp3", time.Hour))
+		process.exit()
+		exit := receiveRecordingExit(t, recorder.Exits())
+
+		assert.Equal(t, int64(1), exit.ID)
+		assert.EqualError(t, exit.Err, "exit status 1: http://example.com/stream: Server returned 404 Not Found")
+	})
+
+	t.Run("fails when ffmpeg can't start", func(t *testing.T) {
+		executor := newMockExecutor()
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{startErr: errors.New("exec failed"), process: &mockProcess{}}
+		}
+		recorder := newStreamRecorder(executor, nil)
+
+		err := recorder.Start(1, testStation("http://example.com/stream"), "/tmp/show.mp3", time.Hour)
+
+		assert.Error(t, err)
+		assert.False(t, recorder.IsRecording(1))
+	})
+
+	t.Run("stopping interrupts ffmpeg without reporting an exit", func(t *testing.T) {
+		process := &mockProcess{pid: 1, exited: make(chan struct{})}
+		recorder, _ := newTestStreamRecorder(process, "")
+		assert.NoError(t, recorder.Start(1, testStation("http://example.com/stream"), "/tmp/show.mp3", time.Hour))
+
+		path, err := recorder.Stop(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/tmp/show.mp3", path)
+		assert.Equal(t, os.Interrupt, process.signalSig)
+		assert.False(t, recorder.IsRecording(1))
+		select {
+		case exit := <-recorder.Exits():
+			t.Fatalf("unexpected exit %v", exit)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("stopping an unknown recording does nothing", func(t *testing.T) {
+		recorder, _ := newTestStreamRecorder(&mockProcess{}, "")
+
+		path, err := recorder.Stop(1)
+
+		assert.NoError(t, err)
+		assert.Empty(t, path)
+	})
+
+	t.Run("stops every recording", func(t *testing.T) {
+		first := &mockProcess{pid: 1, exited: make(chan struct{})}
+		second := &mockProcess{pid: 2, exited: make(chan struct{})}
+		processes := []*mockProcess{first, second}
+		executor := newMockExecutor()
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			process := processes[0]
+			processes = processes[1:]
+			return &mockCmd{process: process}
+		}
+		recorder := newStreamRecorder(executor, nil)
+		assert.NoError(t, recorder.Start(1, testStation("http://example.com/one"), "/tmp/one.mp3", time.Hour))
+		assert.NoError(t, recorder.Start(2, testStation("http://example.com/two"), "/tmp/two.mp3", time.Hour))
+
+		recorder.StopAll()
+
+		assert.False(t, recorder.IsRecording(1))
+		assert.False(t, recorder.IsRecording(2))
+		assert.Equal(t, os.Interrupt, first.signalSig)
+		assert.Equal(t, os.Interrupt, second.signalSig)
+	})
+}
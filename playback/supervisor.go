@@ -0,0 +1,229 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// stderrTailSize is how much of a player's standard error is kept.
+	stderrTailSize = 4096
+	// stderrTailLines is how many lines of standard error a PlayerExit carries.
+	stderrTailLines = 5
+	// stderrDrainTimeout bounds how long a dead player's last output is waited for.
+	stderrDrainTimeout = time.Second
+)
+
+// tailBuffer keeps the last bytes written to it.
+type tailBuffer struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > stderrTailSize {
+		b.data = b.data[len(b.data)-stderrTailSize:]
+	}
+	return len(p), nil
+}
+
+// lines returns the last n non-empty lines written. Players redraw their status
+// line with \r, so each redraw counts as a line.
+func (b *tailBuffer) lines(n int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var lines []string
+	for _, line := range strings.FieldsFunc(string(b.data), func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// supervisedProcess is a player process with a goroutine waiting on it, so that
+// the player stopping by itself is noticed and reported with its last output.
+type supervisedProcess struct {
+	stderr *tailBuffer
+	done   chan struct{} // Closed once the process has exited
+	err    error         // Wait's error, set before done is closed
+	state  *os.ProcessState
+
+	mu      sync.Mutex
+	stopped bool            // The process was stopped on purpose
+	exited  bool            // The exit has been handled
+	ended   bool            // The process reported the end of what the current watcher plays
+	exits   chan PlayerExit // Receives the exit of the current watcher, if any
+}
+
+// startSupervised starts cmd, capturing its standard error, and watches it until it exits.
+func startSupervised(cmd Cmd) (*supervisedProcess, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.SetStderr(w)
+	if err := cmd.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return nil, err
+	}
+	// The player has its own copy of the write end
+	w.Close()
+
+	p := &supervisedProcess{
+		stderr: &tailBuffer{},
+		done:   make(chan struct{}),
+	}
+	drained := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(p.stderr, r)
+		r.Close()
+		close(drained)
+	}()
+	go func() {
+		state, err := cmd.Process().Wait()
+		p.err = err
+		p.state = state
+		close(p.done)
+		// Give the last lines a moment to arrive
+		select {
+		case <-drained:
+		case <-time.After(stderrDrainTimeout):
+		}
+		p.finish(PlayerExit{Reason: exitReason(state, err), Stderr: p.stderr.lines(stderrTailLines)})
+	}()
+	return p, nil
+}
+
+// exitReason describes how a process ended.
+func exitReason(state *os.ProcessState, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if state != nil {
+		return state.String()
+	}
+	return "exited"
+}
+
+// finish reports exit to the current watcher, unless the process was stopped on purpose.
+func (p *supervisedProcess) finish(exit PlayerExit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exited = true
+	if p.exits == nil {
+		return
+	}
+	if !p.stopped {
+		p.exits <- exit
+	}
+	close(p.exits)
+	p.exits = nil
+}
+
+// watch returns a channel receiving the exit of the process, should it stop by
+// itself. Earlier channels are closed without a value.
+func (p *supervisedProcess) watch() <-chan PlayerExit {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.exits != nil {
+		close(p.exits)
+	}
+	p.ended = false
+	exits := make(chan PlayerExit, 1)
+	if p.exited {
+		close(exits)
+		p.exits = nil
+		return exits
+	}
+	p.exits = exits
+	return exits
+}
+
+// report tells the current watcher that playback ended while the process keeps
+// running, e.g. an idle mpv whose stream dropped, as if the process had exited.
+// Later watchers aren't affected.
+func (p *supervisedProcess) report(exit PlayerExit) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped || p.exited || p.ended {
+		return
+	}
+	p.ended = true
+	if p.exits == nil {
+		return
+	}
+	exit.Stderr = p.stderr.lines(stderrTailLines)
+	p.exits <- exit
+	close(p.exits)
+	p.exits = nil
+}
+
+// hasEnded returns true if the process reported the end of what the current watcher plays.
+func (p *supervisedProcess) hasEnded() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ended
+}
+
+// stop marks the process as stopped on purpose, so its exit isn't reported.
+// Watchers are told right away.
+func (p *supervisedProcess) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+	if p.exits != nil {
+		close(p.exits)
+		p.exits = nil
+	}
+}
+
+// hasExited returns true once the process has exited.
+func (p *supervisedProcess) hasExited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// succeeded returns true if the process exited with status 0.
+func (p *supervisedProcess) succeeded() bool {
+	<-p.done
+	return p.err == nil && (p.state == nil || p.state.Success())
+}
+
+// wait waits for the process to exit and returns Wait's error.
+func (p *supervisedProcess) wait() error {
+	<-p.done
+	return p.err
+}
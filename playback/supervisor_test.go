@@ -0,0 +1,190 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receiveExit waits for a value or the close of exits.
+func receiveExit(t *testing.T, exits <-chan PlayerExit) (PlayerExit, bool) {
+	t.Helper()
+	select {
+	case exit, ok := <-exits:
+		return exit, ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the player's exit")
+		return PlayerExit{}, false
+	}
+}
+
+// newSupervisedManager returns an ffplay manager watching its player, whose
+// process only exits when killed or told to.
+func newSupervisedManager(stderr string) (*FFPlayPlaybackManager, *mockProcess) {
+	process := &mockProcess{pid: 12345, exited: make(chan struct{})}
+	executor := newMockExecutor()
+	executor.commandFunc = func(name string, args ...string) Cmd {
+		return &mockCmd{process: process, stderr: stderr}
+	}
+	manager := NewFFPlaybackManagerWithExecutor(executor)
+	manager.supervise = true
+	return manager, process
+}
+
+func TestTailBuffer(t *testing.T) {
+	t.Run("returns the last lines", func(t *testing.T) {
+		buffer := &tailBuffer{}
+		_, _ = buffer.Write([]byte("one\ntwo\n\nthree\nfour\n"))
+
+		assert.Equal(t, "three\nfour", buffer.lines(2))
+		assert.Equal(t, "one\ntwo\nthree\nfour", buffer.lines(10))
+	})
+
+	t.Run("counts status line redraws as lines", func(t *testing.T) {
+		buffer := &tailBuffer{}
+		_, _ = buffer.Write([]byte("  1.00 M-A: 0.000\r  2.00 M-A: 0.000\rhttp://example.com: Connection reset by peer\n"))
+
+		assert.Equal(t, "2.00 M-A: 0.000\nhttp://example.com: Connection reset by peer", buffer.lines(2))
+	})
+
+	t.Run("keeps only the end of long output", func(t *testing.T) {
+		buffer := &tailBuffer{}
+		_, _ = buffer.Write([]byte(strings.Repeat("x", stderrTailSize)))
+		_, _ = buffer.Write([]byte("\nlast line\n"))
+
+		assert.Len(t, buffer.data, stderrTailSize)
+		assert.Equal(t, "last line", buffer.lines(1))
+	})
+}
+
+func TestCommandPlaybackManager_Supervision(t *testing.T) {
+	t.Run("reports a player that stops by itself", func(t *testing.T) {
+		manager, process := newSupervisedManager("Opening stream...\nhttp://example.com/stream: Connection reset by peer\n")
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 80))
+		exits := manager.PlayerExited()
+		assert.True(t, manager.IsPlaying())
+
+		process.exit()
+		exit, ok := receiveExit(t, exits)
+
+		assert.True(t, ok)
+		assert.Equal(t, "exited", exit.Reason)
+		assert.Equal(t, "Opening stream...\nhttp://example.com/stream: Connection reset by peer", exit.Stderr)
+		assert.False(t, manager.IsPlaying())
+	})
+
+	t.Run("cleans up a player that stopped by itself", func(t *testing.T) {
+		manager, process := newSupervisedManager("")
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 80))
+		exits := manager.PlayerExited()
+		process.exit()
+		receiveExit(t, exits)
+		// A dead process can't be killed
+		process.killErr = errors.New("os: process already finished")
+
+		err := manager.StopStation()
+
+		assert.NoError(t, err)
+		assert.Empty(t, manager.CurrentStation().Name)
+		assert.Nil(t, manager.PlayerExited())
+	})
+
+	t.Run("doesn't report players that are stopped", func(t *testing.T) {
+		manager, process := newSupervisedManager("")
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 80))
+		exits := manager.PlayerExited()
+
+		assert.NoError(t, manager.StopStation())
+		_, ok := receiveExit(t, exits)
+
+		assert.False(t, ok)
+		assert.True(t, process.killCalled)
+	})
+
+	t.Run("doesn't report players replaced by another station", func(t *testing.T) {
+		manager, _ := newSupervisedManager("")
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/first"), 80))
+		first := manager.PlayerExited()
+
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/second"), 80))
+		_, ok := receiveExit(t, first)
+
+		assert.False(t, ok)
+		assert.NotNil(t, manager.PlayerExited())
+	})
+
+	t.Run("isn't watched without supervision", func(t *testing.T) {
+		manager := NewFFPlaybackManagerWithExecutor(newMockExecutor())
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 80))
+
+		assert.Nil(t, manager.PlayerExited())
+	})
+}
+
+func TestMPVPlaybackManager_Supervision(t *testing.T) {
+	t.Run("reports mpv stopping by itself and restarts it for the next station", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		process := &mockProcess{pid: 1, exited: make(chan struct{})}
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process, stderr: "[ffmpeg] tcp: Connection refused\n"}
+		}
+		manager.supervise = true
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		exits := manager.PlayerExited()
+
+		process.exit()
+		exit, ok := receiveExit(t, exits)
+
+		assert.True(t, ok)
+		assert.Equal(t, "[ffmpeg] tcp: Connection refused", exit.Stderr)
+		assert.False(t, manager.IsPlaying())
+
+		// The next station starts a new mpv
+		process = &mockProcess{pid: 2, exited: make(chan struct{})}
+		t.Cleanup(process.exit)
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/stream"), 70))
+		assert.Len(t, executor.commandCalls, 2)
+		assert.True(t, manager.IsPlaying())
+	})
+
+	t.Run("reports only to the watcher of the playing station", func(t *testing.T) {
+		manager, _, executor := newTestMPVManager(t)
+		process := &mockProcess{pid: 1, exited: make(chan struct{})}
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		manager.supervise = true
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/first"), 70))
+		first := manager.PlayerExited()
+		assert.NoError(t, manager.PlayStation(testStation("http://example.com/second"), 70))
+		second := manager.PlayerExited()
+
+		process.exit()
+
+		_, ok := receiveExit(t, first)
+		assert.False(t, ok)
+		_, ok = receiveExit(t, second)
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/data"
+	"github.com/zi0p4tch0/radiogogo/i18n"
+	"github.com/zi0p4tch0/radiogogo/icy"
+)
+
+// errNotTimeshiftable is returned for streams that can't go through a timeshift
+// buffer, such as HLS playlists, which the player has to fetch itself.
+var errNotTimeshiftable = errors.New("stream can't be timeshifted")
+
+const (
+	// timeshiftChunkSize is how much is read from the station, or sent to the player, at a time.
+	timeshiftChunkSize = 16 * 1024
+	// timeshiftConnectTimeout bounds how long the station may take to start answering.
+	timeshiftConnectTimeout = 10 * time.Second
+)
+
+// TimeshiftState describes the timeshift buffer of the playing station.
+type TimeshiftState struct {
+	// Paused is true while the player is held, the station still being buffered.
+	Paused bool
+	// Behind is how many bytes the player is behind the live stream.
+	Behind int64
+	// Capacity is the size of the buffer: the most the player can fall behind.
+	Capacity int64
+}
+
+// ringBuffer holds the last bytes received from a station, and where the player
+// is in them. Once the player falls further behind than the buffer holds, the
+// oldest bytes are dropped.
+type ringBuffer struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	data    []byte
+	written int64 // Bytes received so far
+	read    int64 // Bytes sent to the player so far, including dropped ones
+	paused  bool
+	err     error // Why the station stopped sending, once it has
+	closed  bool
+	reader  int // Increases with each player connection, to retire the previous one
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	b := &ringBuffer{data: make([]byte, capacity)}
+	b.changed = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *ringBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	capacity := int64(len(b.data))
+	for written := 0; written < len(p); {
+		offset := int(b.written % capacity)
+		n := copy(b.data[offset:], p[written:])
+		written += n
+		b.written += int64(n)
+	}
+	if b.written-b.read > capacity {
+		b.read = b.written - capacity
+	}
+	b.changed.Broadcast()
+	return len(p), nil
+}
+
+// finish records why the station stopped sending. The player gets what's left, then err.
+func (b *ringBuffer) finish(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+	b.changed.Broadcast()
+}
+
+// newReader retires the current player connection and returns the id of the next one.
+func (b *ringBuffer) newReader() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reader++
+	b.changed.Broadcast()
+	return b.reader
+}
+
+// readAs waits for bytes the player hasn't had yet, while not paused, and copies
+// them into p. Returns io.EOF once the reader was retired or the buffer closed.
+func (b *ringBuffer) readAs(reader int, p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		if b.closed || reader != b.reader {
+			return 0, io.EOF
+		}
+		if !b.paused && b.read < b.written {
+			break
+		}
+		if !b.paused && b.err != nil {
+			return 0, b.err
+		}
+		b.changed.Wait()
+	}
+	capacity := int64(len(b.data))
+	offset := int(b.read % capacity)
+	available := b.written - b.read
+	if int64(len(p)) > available {
+		p = p[:available]
+	}
+	// Up to the end of the ring, the rest comes with the next read
+	n := copy(p, b.data[offset:])
+	b.read += int64(n)
+	return n, nil
+}
+
+func (b *ringBuffer) setPaused(paused bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.paused = paused
+	b.changed.Broadcast()
+}
+
+// skipToLive drops everything the player hasn't had yet.
+func (b *ringBuffer) skipToLive() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.read = b.written
+	b.changed.Broadcast()
+}
+
+func (b *ringBuffer) state() TimeshiftState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return TimeshiftState{Paused: b.paused, Behind: b.written - b.read, Capacity: int64(len(b.data))}
+}
+
+func (b *ringBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.changed.Broadcast()
+}
+
+// timeshift is an in-process proxy between a station and the player: it keeps
+// downloading the station into a ring buffer and serves it to the player on a
+// loopback URL, so that output can be held and resumed without losing anything.
+type timeshift struct {
+	buffer      *ringBuffer
+	contentType string
+	listener    net.Listener
+	server      *http.Server
+	cancel      context.CancelFunc
+	url         string
+}
+
+// startTimeshift connects to streamURL and starts buffering it, in a buffer of
+// bufferSize bytes. Returns errNotTimeshiftable for streams the player must open itself.
+func startTimeshift(client HTTPClient, streamURL string, bufferSize int) (*timeshift, error) {
+	u, err := url.Parse(streamURL)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || strings.HasSuffix(strings.ToLower(u.Path), ".m3u8") {
+		return nil, errNotTimeshiftable
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("User-Agent", data.UserAgent)
+	// The request lives as long as the timeshift, only connecting is bounded
+	timer := time.AfterFunc(timeshiftConnectTimeout, cancel)
+	resp, err := client.Do(req)
+	timer.Stop()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || strings.Contains(strings.ToLower(contentType), "mpegurl") {
+		resp.Body.Close()
+		cancel()
+		if strings.Contains(strings.ToLower(contentType), "mpegurl") {
+			return nil, errNotTimeshiftable
+		}
+		return nil, fmt.Errorf("%s answered %s", streamURL, resp.Status)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+
+	t := &timeshift{
+		buffer:      newRingBuffer(bufferSize),
+		contentType: contentType,
+		listener:    listener,
+		cancel:      cancel,
+		url:         "http://" + listener.Addr().String() + "/stream",
+	}
+	t.server = &http.Server{Handler: http.HandlerFunc(t.serve)}
+
+	go func() {
+		defer resp.Body.Close()
+		_, err := io.CopyBuffer(t.buffer, resp.Body, make([]byte, timeshiftChunkSize))
+		if err == nil {
+			err = io.EOF
+		}
+		t.buffer.finish(err)
+	}()
+	go func() { _ = t.server.Serve(listener) }()
+	return t, nil
+}
+
+// serve sends the buffered station to the player. A new connection takes over
+// from the previous one, at the same position.
+func (t *timeshift) serve(w http.ResponseWriter, r *http.Request) {
+	reader := t.buffer.newReader()
+	if t.contentType != "" {
+		w.Header().Set("Content-Type", t.contentType)
+	}
+	w.WriteHeader(http.StatusOK)
+	// Players wait for the headers before they start reading
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	chunk := make([]byte, timeshiftChunkSize)
+	for {
+		n, err := t.buffer.readAs(reader, chunk)
+		if n > 0 {
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			// The player sees the stream end when the station's does
+			return
+		}
+	}
+}
+
+// URL returns the loopback URL the player plays the station from.
+func (t *timeshift) URL() string {
+	return t.url
+}
+
+// pause holds the player, while the station keeps being buffered.
+func (t *timeshift) pause() {
+	t.buffer.setPaused(true)
+}
+
+// resume lets the player continue from where it was held.
+func (t *timeshift) resume() {
+	t.buffer.setPaused(false)
+}
+
+// jumpToLive drops the backlog, so the player continues with what the station sends now.
+func (t *timeshift) jumpToLive() {
+	t.buffer.skipToLive()
+	t.buffer.setPaused(false)
+}
+
+func (t *timeshift) state() TimeshiftState {
+	return t.buffer.state()
+}
+
+// close disconnects from the station and the player.
+func (t *timeshift) close() {
+	t.cancel()
+	t.buffer.close()
+	_ = t.server.Close()
+}
+
+// timeshiftPlayer plays stations through a timeshift, for the playback managers
+// that embed it. Stations that can't be timeshifted are played directly.
+type timeshiftPlayer struct {
+	bufferSize int        // 0 disables timeshifting
+	client     HTTPClient // nil uses a client for station streams
+	current    *timeshift
+}
+
+// open starts buffering streamURL and returns the URL the player should play:
+// the timeshift's, or streamURL itself when it isn't timeshifted.
+func (p *timeshiftPlayer) open(streamURL string) string {
+	if p.bufferSize <= 0 {
+		return streamURL
+	}
+	client := p.client
+	if client == nil {
+		client = icy.NewHTTPClient()
+	}
+	t, err := startTimeshift(client, streamURL, p.bufferSize)
+	if err != nil {
+		// The player may still manage, e.g. with HLS or a protocol of its own
+		return streamURL
+	}
+	p.current = t
+	return t.URL()
+}
+
+// detach returns the current timeshift, which the caller now has to close.
+func (p *timeshiftPlayer) detach() *timeshift {
+	current := p.current
+	p.current = nil
+	return current
+}
+
+func (p *timeshiftPlayer) close() {
+	if current := p.detach(); current != nil {
+		current.close()
+	}
+}
+
+func (p *timeshiftPlayer) isTimeshifting() bool {
+	return p.current != nil
+}
+
+func (p *timeshiftPlayer) pause() error {
+	if p.current == nil {
+		return errors.New(i18n.T("error_timeshift_unavailable"))
+	}
+	p.current.pause()
+	return nil
+}
+
+func (p *timeshiftPlayer) resume() error {
+	if p.current == nil {
+		return errors.New(i18n.T("error_timeshift_unavailable"))
+	}
+	p.current.resume()
+	return nil
+}
+
+func (p *timeshiftPlayer) jumpToLive() error {
+	if p.current == nil {
+		return errors.New(i18n.T("error_timeshift_unavailable"))
+	}
+	p.current.jumpToLive()
+	return nil
+}
+
+func (p *timeshiftPlayer) state() TimeshiftState {
+	if p.current == nil {
+		return TimeshiftState{}
+	}
+	return p.current.state()
+}
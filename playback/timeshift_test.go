@@ -0,0 +1,573 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// liveStation serves a never-ending stream, sending whatever is put on its chunks
+// channel. Closing chunks ends the stream.
+type liveStation struct {
+	server *httptest.Server
+	chunks chan string
+}
+
+func newLiveStation(t *testing.T, contentType string) *liveStation {
+	t.Helper()
+	station := &liveStation{chunks: make(chan string, 16)}
+	stop := make(chan struct{})
+	station.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case chunk, ok := <-station.chunks:
+				if !ok {
+					return
+				}
+				_, _ = io.WriteString(w, chunk)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}))
+	t.Cleanup(func() {
+		close(stop)
+		station.server.Close()
+	})
+	return station
+}
+
+// connectPlayer reads the stream at url like a player would, sending what it
+// receives on the returned channel, which is closed when the stream ends.
+func connectPlayer(t *testing.T, url string) <-chan string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	received := make(chan string, 16)
+	go func() {
+		defer close(received)
+		buf := make([]byte, 1024)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				received <- string(buf[:n])
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return received
+}
+
+// receive collects n bytes from the player, or what arrived before the timeout.
+func receive(received <-chan string, n int) string {
+	var b strings.Builder
+	timeout := time.After(2 * time.Second)
+	for b.Len() < n {
+		select {
+		case chunk, ok := <-received:
+			if !ok {
+				return b.String()
+			}
+			b.WriteString(chunk)
+		case <-timeout:
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+// receivesNothing returns true if the player gets no data for a while.
+func receivesNothing(received <-chan string) bool {
+	select {
+	case <-received:
+		return false
+	case <-time.After(100 * time.Millisecond):
+		return true
+	}
+}
+
+// ended returns true once the player's stream has ended.
+func ended(received <-chan string) bool {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-received:
+			if !ok {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func startTestTimeshift(t *testing.T, station *liveStation, bufferSize int) *timeshift {
+	t.Helper()
+	ts, err := startTimeshift(http.DefaultClient, station.server.URL+"/live", bufferSize)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(ts.close)
+	return ts
+}
+
+func TestRingBuffer(t *testing.T) {
+	read := func(b *ringBuffer, reader int) string {
+		p := make([]byte, 64)
+		n, err := b.readAs(reader, p)
+		assert.NoError(t, err)
+		return string(p[:n])
+	}
+
+	t.Run("returns what was written", func(t *testing.T) {
+		b := newRingBuffer(8)
+		reader := b.newReader()
+		_, _ = b.Write([]byte("abc"))
+
+		assert.Equal(t, "abc", read(b, reader))
+		assert.Equal(t, TimeshiftState{Capacity: 8}, b.state())
+	})
+
+	t.Run("wraps around the end of the buffer", func(t *testing.T) {
+		b := newRingBuffer(4)
+		reader := b.newReader()
+		_, _ = b.Write([]byte("abc"))
+		assert.Equal(t, "abc", read(b, reader))
+
+		_, _ = b.Write([]byte("def"))
+		assert.Equal(t, "d", read(b, reader))
+		assert.Equal(t, "ef", read(b, reader))
+	})
+
+	t.Run("drops the oldest bytes when the reader falls too far behind", func(t *testing.T) {
+		b := newRingBuffer(4)
+		reader := b.newReader()
+		_, _ = b.Write([]byte("123456"))
+
+		assert.Equal(t, int64(4), b.state().Behind)
+		assert.Equal(t, "34", read(b, reader))
+		assert.Equal(t, "56", read(b, reader))
+	})
+
+	t.Run("holds the reader while paused", func(t *testing.T) {
+		b := newRingBuffer(8)
+		reader := b.newReader()
+		b.setPaused(true)
+		_, _ = b.Write([]byte("abc"))
+
+		done := make(chan string)
+		go func() { done <- read(b, reader) }()
+		select {
+		case <-done:
+			t.Fatal("read while paused")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		b.setPaused(false)
+		assert.Equal(t, "abc", <-done)
+	})
+
+	t.Run("skips to live", func(t *testing.T) {
+		b := newRingBuffer(8)
+		reader := b.newReader()
+		_, _ = b.Write([]byte("old"))
+		b.skipToLive()
+		_, _ = b.Write([]byte("new"))
+
+		assert.Equal(t, "new", read(b, reader))
+	})
+
+	t.Run("returns what's left, then why the station ended", func(t *testing.T) {
+		b := newRingBuffer(8)
+		reader := b.newReader()
+		_, _ = b.Write([]byte("end"))
+		b.finish(io.EOF)
+
+		assert.Equal(t, "end", read(b, reader))
+		_, err := b.readAs(reader, make([]byte, 8))
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("retires the previous reader", func(t *testing.T) {
+		b := newRingBuffer(8)
+		first := b.newReader()
+		second := b.newReader()
+		_, _ = b.Write([]byte("abc"))
+
+		_, err := b.readAs(first, make([]byte, 8))
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, "abc", read(b, second))
+	})
+}
+
+func TestTimeshift(t *testing.T) {
+	t.Run("passes the stream through to the player", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+
+		assert.True(t, strings.HasPrefix(ts.URL(), "http://127.0.0.1:"))
+		resp, err := http.Get(ts.URL())
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "audio/mpeg", resp.Header.Get("Content-Type"))
+
+		station.chunks <- "abc"
+		buf := make([]byte, 3)
+		_, err = io.ReadFull(resp.Body, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(buf))
+	})
+
+	t.Run("keeps buffering while paused and resumes where it left off", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+		player := connectPlayer(t, ts.URL())
+
+		station.chunks <- "abc"
+		assert.Equal(t, "abc", receive(player, 3))
+
+		ts.pause()
+		station.chunks <- "def"
+		station.chunks <- "ghi"
+		assert.Eventually(t, func() bool { return ts.state().Behind == 6 }, 2*time.Second, 10*time.Millisecond)
+		assert.True(t, ts.state().Paused)
+		assert.True(t, receivesNothing(player))
+
+		ts.resume()
+		assert.Equal(t, "defghi", receive(player, 6))
+		assert.Equal(t, int64(0), ts.state().Behind)
+	})
+
+	t.Run("jumps to live dropping the backlog", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+		player := connectPlayer(t, ts.URL())
+
+		ts.pause()
+		station.chunks <- "old"
+		assert.Eventually(t, func() bool { return ts.state().Behind == 3 }, 2*time.Second, 10*time.Millisecond)
+
+		ts.jumpToLive()
+		station.chunks <- "new"
+		assert.Equal(t, "new", receive(player, 3))
+		assert.False(t, ts.state().Paused)
+	})
+
+	t.Run("keeps only the most recent audio when paused for too long", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 4)
+		player := connectPlayer(t, ts.URL())
+
+		ts.pause()
+		station.chunks <- "123456"
+		assert.Eventually(t, func() bool { return ts.state().Behind == 4 }, 2*time.Second, 10*time.Millisecond)
+
+		ts.resume()
+		assert.Equal(t, "3456", receive(player, 4))
+	})
+
+	t.Run("a new player connection takes over", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+		first := connectPlayer(t, ts.URL())
+
+		ts.pause()
+		station.chunks <- "xyz"
+		assert.Eventually(t, func() bool { return ts.state().Behind == 3 }, 2*time.Second, 10*time.Millisecond)
+		second := connectPlayer(t, ts.URL())
+
+		assert.True(t, ended(first))
+		ts.resume()
+		assert.Equal(t, "xyz", receive(second, 3))
+	})
+
+	t.Run("ends the player's stream after the station's", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+		player := connectPlayer(t, ts.URL())
+
+		station.chunks <- "bye"
+		close(station.chunks)
+
+		assert.Equal(t, "bye", receive(player, 3))
+		assert.True(t, ended(player))
+	})
+
+	t.Run("close disconnects the player", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		ts := startTestTimeshift(t, station, 1024)
+		player := connectPlayer(t, ts.URL())
+
+		ts.close()
+
+		assert.True(t, ended(player))
+	})
+
+	t.Run("leaves HLS streams to the player", func(t *testing.T) {
+		station := newLiveStation(t, "application/vnd.apple.mpegurl")
+
+		_, err := startTimeshift(http.DefaultClient, station.server.URL+"/live", 1024)
+		assert.Equal(t, errNotTimeshiftable, err)
+		_, err = startTimeshift(http.DefaultClient, "http://example.com/live/index.m3u8", 1024)
+		assert.Equal(t, errNotTimeshiftable, err)
+	})
+
+	t.Run("leaves non-HTTP streams to the player", func(t *testing.T) {
+		_, err := startTimeshift(http.DefaultClient, "mms://example.com/live", 1024)
+		assert.Equal(t, errNotTimeshiftable, err)
+	})
+
+	t.Run("fails when the station doesn't answer with a stream", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := startTimeshift(http.DefaultClient, server.URL+"/live", 1024)
+		assert.Error(t, err)
+	})
+}
+
+func TestCommandPlaybackManager_Timeshift(t *testing.T) {
+	playedURL := func(executor *mockExecutor) string {
+		args := executor.commandCalls[len(executor.commandCalls)-1]
+		return args[len(args)-1]
+	}
+
+	t.Run("plays stations directly by default", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+
+		assert.Equal(t, station.server.URL+"/live", playedURL(executor))
+		assert.False(t, manager.IsTimeshifting())
+		assert.Error(t, manager.PauseLive())
+	})
+
+	t.Run("plays stations through the buffer when enabled", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.EnableTimeshift(1024)
+
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+
+		assert.True(t, strings.HasPrefix(playedURL(executor), "http://127.0.0.1:"))
+		assert.True(t, manager.IsTimeshifting())
+		assert.Equal(t, int64(1024), manager.TimeshiftState().Capacity)
+
+		// Recordings still come from the station
+		assert.NoError(t, manager.StartRecording("/tmp/out.mp3"))
+		assert.Contains(t, executor.commandCalls[1], station.server.URL+"/live")
+	})
+
+	t.Run("pauses, resumes and jumps to live", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		manager := NewFFPlaybackManagerWithExecutor(newMockExecutor())
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+
+		assert.NoError(t, manager.PauseLive())
+		assert.True(t, manager.TimeshiftState().Paused)
+		assert.NoError(t, manager.ResumeLive())
+		assert.False(t, manager.TimeshiftState().Paused)
+		assert.NoError(t, manager.PauseLive())
+		assert.NoError(t, manager.JumpToLive())
+		assert.False(t, manager.TimeshiftState().Paused)
+	})
+
+	t.Run("pausing suspends the player until it resumes", func(t *testing.T) {
+		if suspendSignal == nil {
+			t.Skip("processes can't be suspended on this platform")
+		}
+		station := newLiveStation(t, "audio/mpeg")
+		process := &mockProcess{pid: 1}
+		executor := newMockExecutor()
+		executor.commandFunc = func(name string, args ...string) Cmd {
+			return &mockCmd{process: process}
+		}
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+
+		assert.NoError(t, manager.PauseLive())
+		assert.Equal(t, suspendSignal, process.signalSig)
+		assert.NoError(t, manager.ResumeLive())
+		assert.Equal(t, resumeSignal, process.signalSig)
+
+		assert.NoError(t, manager.PauseLive())
+		process.signalSig = nil
+		assert.NoError(t, manager.JumpToLive())
+		assert.Equal(t, resumeSignal, process.signalSig)
+
+		// A player that isn't suspended isn't resumed
+		process.signalSig = nil
+		assert.NoError(t, manager.ResumeLive())
+		assert.Nil(t, process.signalSig)
+	})
+
+	t.Run("restarting the player keeps the buffer and the recording", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+		bufferURL := playedURL(executor)
+		assert.NoError(t, manager.StartRecording("/tmp/out.mp3"))
+		assert.NoError(t, manager.PauseLive())
+
+		assert.NoError(t, manager.RestartPlayer(40))
+
+		assert.Equal(t, []string{"ffplay", "-nodisp", "-autoexit", "-volume", "40", bufferURL}, executor.commandCalls[len(executor.commandCalls)-1])
+		assert.True(t, manager.IsPlaying())
+		assert.True(t, manager.IsTimeshifting())
+		assert.True(t, manager.TimeshiftState().Paused)
+		assert.True(t, manager.IsRecording())
+	})
+
+	t.Run("restarting needs a playing station", func(t *testing.T) {
+		manager := NewFFPlaybackManagerWithExecutor(newMockExecutor())
+
+		assert.Error(t, manager.RestartPlayer(40))
+	})
+
+	t.Run("stopping closes the buffer", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		localURL := playedURL(executor)
+
+		assert.NoError(t, manager.StopStation())
+
+		assert.False(t, manager.IsTimeshifting())
+		assert.Equal(t, TimeshiftState{}, manager.TimeshiftState())
+		_, err := http.Get(localURL)
+		assert.Error(t, err)
+	})
+
+	t.Run("plays streams that can't be timeshifted directly", func(t *testing.T) {
+		station := newLiveStation(t, "application/vnd.apple.mpegurl")
+		executor := newMockExecutor()
+		manager := NewFFPlaybackManagerWithExecutor(executor)
+		manager.EnableTimeshift(1024)
+
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+
+		assert.Equal(t, station.server.URL+"/live", playedURL(executor))
+		assert.False(t, manager.IsTimeshifting())
+	})
+}
+
+func TestMPVPlaybackManager_Timeshift(t *testing.T) {
+	loadedURL := func(fake *fakeMPV) string {
+		var url string
+		for _, command := range fake.receivedCommands() {
+			if command[0] == "loadfile" {
+				url = command[1].(string)
+			}
+		}
+		return url
+	}
+
+	t.Run("loads the buffer's URL", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		manager, fake, _ := newTestMPVManager(t)
+		manager.EnableTimeshift(1024)
+
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+
+		assert.True(t, strings.HasPrefix(loadedURL(fake), "http://127.0.0.1:"))
+		assert.True(t, manager.IsTimeshifting())
+	})
+
+	t.Run("pauses mpv along with the buffer", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		manager, fake, _ := newTestMPVManager(t)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+
+		assert.NoError(t, manager.PauseLive())
+		assert.Equal(t, []interface{}{"set_property", "pause", true}, fake.lastCommand())
+		assert.True(t, manager.IsPaused())
+		assert.True(t, manager.TimeshiftState().Paused)
+
+		assert.NoError(t, manager.ResumeLive())
+		assert.Equal(t, []interface{}{"set_property", "pause", false}, fake.lastCommand())
+		assert.False(t, manager.IsPaused())
+	})
+
+	t.Run("jumping to live reloads the buffer's URL", func(t *testing.T) {
+		station := newLiveStation(t, "audio/mpeg")
+		manager, fake, _ := newTestMPVManager(t)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(station.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+		localURL := loadedURL(fake)
+		assert.NoError(t, manager.PauseLive())
+
+		assert.NoError(t, manager.JumpToLive())
+
+		commands := fake.receivedCommands()
+		assert.Equal(t, []interface{}{"loadfile", localURL, "replace"}, commands[len(commands)-2])
+		assert.Equal(t, []interface{}{"set_property", "pause", false}, commands[len(commands)-1])
+		assert.False(t, manager.IsPaused())
+	})
+
+	t.Run("switching stations closes the previous buffer", func(t *testing.T) {
+		first := newLiveStation(t, "audio/mpeg")
+		second := newLiveStation(t, "audio/mpeg")
+		manager, fake, _ := newTestMPVManager(t)
+		manager.EnableTimeshift(1024)
+		assert.NoError(t, manager.PlayStation(testStation(first.server.URL+"/live"), 80))
+		t.Cleanup(func() { _ = manager.StopStation() })
+		firstURL := loadedURL(fake)
+
+		assert.NoError(t, manager.PlayStation(testStation(second.server.URL+"/live"), 80))
+
+		assert.NotEqual(t, firstURL, loadedURL(fake))
+		_, err := http.Get(firstURL)
+		assert.Error(t, err)
+	})
+}
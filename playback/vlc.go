@@ -0,0 +1,46 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playback
+
+import "fmt"
+
+// cvlcCommand plays a stream with VLC's console player, without any interface.
+// VLC's gain is a multiplier, so volume 100 plays at the stream's own level.
+// VLC exits when the stream ends, so a dropped stream can be noticed.
+var cvlcCommand = playerCommand{
+	name: "cvlc",
+	args: func(streamURL string, volume int) []string {
+		return []string{"--intf", "dummy", "--no-video", "--quiet", "--play-and-exit", "--gain", fmt.Sprintf("%.2f", float64(volume)/100), streamURL}
+	},
+	notAvailableKey: "error_cvlc_required",
+}
+
+// NewCVLCPlaybackManager creates a playback manager that plays stations with VLC's
+// console player (cvlc) at the specified default volume (0-100).
+func NewCVLCPlaybackManager(defaultVolume int) PlaybackManagerService {
+	manager := newSystemCommandPlaybackManager(cvlcCommand, defaultVolume)
+	return &manager
+}
+
+// NewCVLCPlaybackManagerWithExecutor creates a cvlc playback manager with a custom command executor.
+// This is primarily useful for testing. Uses default volume of 80.
+func NewCVLCPlaybackManagerWithExecutor(executor CommandExecutor) *CommandPlaybackManager {
+	manager := newCommandPlaybackManager(cvlcCommand, executor, 80)
+	return &manager
+}
@@ -0,0 +1,306 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package playlist parses the playlist formats radio streams are commonly
+// published in: M3U (and extended M3U/M3U8), PLS, ASX and XSPF.
+package playlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Entry is a stream listed in a playlist.
+type Entry struct {
+	// Title is the title given to the entry, if any.
+	Title string
+	// URL is the location of the stream, as written in the playlist.
+	URL string
+}
+
+// ErrUnknownFormat is returned by Parse for files that aren't M3U, PLS, ASX or XSPF playlists.
+var ErrUnknownFormat = errors.New("unknown playlist format")
+
+// IsPlaylistFile reports whether name has the extension of a supported playlist format.
+func IsPlaylistFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u", ".m3u8", ".pls", ".asx", ".xspf":
+		return true
+	}
+	return false
+}
+
+// Parse parses a playlist, picking the format from the extension of name.
+func Parse(name string, data []byte) ([]Entry, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".m3u", ".m3u8":
+		return ParseM3U(data), nil
+	case ".pls":
+		return ParsePLS(data), nil
+	case ".asx":
+		return ParseASX(data)
+	case ".xspf":
+		return ParseXSPF(data)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// playlistContentTypes maps the content types servers use for playlists to their extension.
+var playlistContentTypes = map[string]string{
+	"audio/x-mpegurl":       ".m3u",
+	"audio/mpegurl":         ".m3u",
+	"application/x-mpegurl": ".m3u",
+	"audio/m3u":             ".m3u",
+	"audio/x-scpls":         ".pls",
+	"application/pls+xml":   ".pls",
+	"video/x-ms-asx":        ".asx",
+	"audio/x-ms-asx":        ".asx",
+	"application/xspf+xml":  ".xspf",
+}
+
+// Detect tells whether a downloaded resource is a playlist, from its name (or URL path),
+// its content type and the first bytes of its data. It returns the extension of the
+// playlist's format, to be passed to Parse as part of a name, or "" for anything else.
+// HLS playlists (M3U8 with #EXT-X- tags) are reported as "": players stream them directly.
+func Detect(name, contentType string, head []byte) string {
+	format := sniff(head)
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		format = playlistContentTypes[strings.ToLower(mediaType)]
+		// Playlists are also served as plain text or without a type; audio served
+		// from a URL that happens to end in .m3u is still audio
+		if format == "" && (mediaType == "" || strings.HasPrefix(mediaType, "text/") || mediaType == "application/octet-stream") &&
+			IsPlaylistFile(name) {
+			format = strings.ToLower(filepath.Ext(name))
+		}
+	}
+	if (format == ".m3u" || format == ".m3u8") && bytes.Contains(head, []byte("#EXT-X-")) {
+		return ""
+	}
+	if format == ".m3u8" {
+		format = ".m3u"
+	}
+	return format
+}
+
+// sniff recognizes a playlist from the signature its data starts with.
+func sniff(head []byte) string {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	start := strings.ToLower(strings.TrimSpace(string(head[:min(len(head), 512)])))
+	switch {
+	case strings.HasPrefix(start, "#extm3u"):
+		return ".m3u"
+	case strings.HasPrefix(start, "[playlist]"):
+		return ".pls"
+	case strings.HasPrefix(start, "<asx"):
+		return ".asx"
+	case strings.HasPrefix(start, "<?xml") && strings.Contains(start, "<playlist") && strings.Contains(start, "xspf"):
+		return ".xspf"
+	}
+	return ""
+}
+
+// ParseM3U parses a plain or extended M3U playlist. Titles come from #EXTINF lines.
+func ParseM3U(data []byte) []Entry {
+	var entries []Entry
+	title := ""
+	for _, line := range lines(data) {
+		if strings.HasPrefix(line, "#EXTINF:") {
+			title = extinfTitle(strings.TrimPrefix(line, "#EXTINF:"))
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, Entry{Title: title, URL: line})
+		title = ""
+	}
+	return entries
+}
+
+// extinfTitle returns the title of an #EXTINF line, which follows the first comma
+// that isn't inside a quoted attribute (e.g. `-1 tvg-name="A, B",Title`).
+func extinfTitle(info string) string {
+	quoted := false
+	for i, r := range info {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return strings.TrimSpace(info[i+1:])
+			}
+		}
+	}
+	return ""
+}
+
+// ParsePLS parses a PLS playlist. Entries are returned in the order of their numbers.
+func ParsePLS(data []byte) []Entry {
+	files := make(map[int]string)
+	titles := make(map[int]string)
+	for _, line := range lines(data) {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(key, "file"):
+			if n, err := strconv.Atoi(strings.TrimPrefix(key, "file")); err == nil {
+				files[n] = value
+			}
+		case strings.HasPrefix(key, "title"):
+			if n, err := strconv.Atoi(strings.TrimPrefix(key, "title")); err == nil {
+				titles[n] = value
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(files))
+	for n := range files {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	entries := make([]Entry, 0, len(numbers))
+	for _, n := range numbers {
+		if files[n] != "" {
+			entries = append(entries, Entry{Title: titles[n], URL: files[n]})
+		}
+	}
+	return entries
+}
+
+type xspfPlaylist struct {
+	Tracks []struct {
+		Location string `xml:"location"`
+		Title    string `xml:"title"`
+		Creator  string `xml:"creator"`
+	} `xml:"trackList>track"`
+}
+
+// ParseXSPF parses an XSPF playlist. Tracks without a title are named after their creator.
+func ParseXSPF(data []byte) ([]Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.Unmarshal(data, &playlist); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		location := strings.TrimSpace(track.Location)
+		if location == "" {
+			continue
+		}
+		title := strings.TrimSpace(track.Title)
+		if title == "" {
+			title = strings.TrimSpace(track.Creator)
+		}
+		entries = append(entries, Entry{Title: title, URL: location})
+	}
+	return entries, nil
+}
+
+// ParseASX parses an ASX (Windows Media) playlist. Element and attribute names are
+// matched regardless of case, as ASX files are rarely well-formed XML. Every REF of an
+// ENTRY becomes an entry with the ENTRY's title; ENTRYREFs link to further playlists.
+func ParseASX(data []byte) ([]Entry, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var entries []Entry
+	var title string
+	var refs []string
+	inEntry := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(entries) > 0 {
+				return entries, nil
+			}
+			return nil, err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch strings.ToLower(element.Name.Local) {
+			case "entry":
+				inEntry, title, refs = true, "", nil
+			case "title":
+				if inEntry {
+					var text string
+					if err := decoder.DecodeElement(&text, &element); err == nil {
+						title = strings.TrimSpace(text)
+					}
+				}
+			case "ref", "entryref":
+				if href := asxAttr(element, "href"); href != "" {
+					if inEntry {
+						refs = append(refs, href)
+					} else {
+						entries = append(entries, Entry{URL: href})
+					}
+				}
+			}
+		case xml.EndElement:
+			if strings.EqualFold(element.Name.Local, "entry") {
+				for _, ref := range refs {
+					entries = append(entries, Entry{Title: title, URL: ref})
+				}
+				inEntry, refs = false, nil
+			}
+		}
+	}
+	return entries, nil
+}
+
+// asxAttr returns the trimmed value of an attribute, matching its name regardless of case.
+func asxAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if strings.EqualFold(attr.Name.Local, name) {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// lines returns the trimmed, non-empty lines of data (ignoring a UTF-8 byte order mark).
+func lines(data []byte) []string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
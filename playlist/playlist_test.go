@@ -0,0 +1,184 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package playlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseM3U(t *testing.T) {
+
+	t.Run("parses a plain playlist", func(t *testing.T) {
+		data := "http://a.example.com/live\n\nhttp://b.example.com/live\n"
+
+		assert.Equal(t, []Entry{
+			{URL: "http://a.example.com/live"},
+			{URL: "http://b.example.com/live"},
+		}, ParseM3U([]byte(data)))
+	})
+
+	t.Run("takes titles from EXTINF lines", func(t *testing.T) {
+		data := "\xef\xbb\xbf#EXTM3U\r\n" +
+			"#EXTINF:-1 tvg-name=\"Jazz, Smooth\" tvg-logo=\"logo.png\",Smooth Jazz FM\r\n" +
+			"http://jazz.example.com/stream\r\n" +
+			"#EXTVLCOPT:network-caching=1000\r\n" +
+			"http://untitled.example.com/stream\r\n"
+
+		assert.Equal(t, []Entry{
+			{Title: "Smooth Jazz FM", URL: "http://jazz.example.com/stream"},
+			{URL: "http://untitled.example.com/stream"},
+		}, ParseM3U([]byte(data)))
+	})
+}
+
+func TestParsePLS(t *testing.T) {
+
+	data := `[playlist]
+NumberOfEntries=3
+File2=http://b.example.com/live
+Title2=Second
+file1=http://a.example.com/live
+Title1=First
+File3=
+Length1=-1
+Version=2
+`
+
+	assert.Equal(t, []Entry{
+		{Title: "First", URL: "http://a.example.com/live"},
+		{Title: "Second", URL: "http://b.example.com/live"},
+	}, ParsePLS([]byte(data)))
+}
+
+func TestParseXSPF(t *testing.T) {
+
+	t.Run("parses tracks", func(t *testing.T) {
+		data := `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <trackList>
+    <track><location>http://a.example.com/live</location><title>First</title></track>
+    <track><location>http://b.example.com/live</location><creator>Second</creator></track>
+    <track><title>No location</title></track>
+  </trackList>
+</playlist>`
+
+		entries, err := ParseXSPF([]byte(data))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Title: "First", URL: "http://a.example.com/live"},
+			{Title: "Second", URL: "http://b.example.com/live"},
+		}, entries)
+	})
+
+	t.Run("fails on malformed XML", func(t *testing.T) {
+		_, err := ParseXSPF([]byte("<playlist><trackList>"))
+		assert.Error(t, err)
+	})
+}
+
+func TestParseASX(t *testing.T) {
+
+	t.Run("parses entries regardless of case", func(t *testing.T) {
+		data := []byte(`<ASX version="3.0">
+			<TITLE>Jazz Radio</TITLE>
+			<Entry>
+				<Title>Jazz FM</Title>
+				<Ref HREF="http://a.example.com/live" />
+				<ref href="mms://b.example.com/live" />
+			</Entry>
+			<entry><ref href="http://c.example.com/live?a=1&b=2"/></entry>
+			<ENTRYREF HREF="http://d.example.com/more.asx" />
+		</ASX>`)
+
+		entries, err := ParseASX(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Title: "Jazz FM", URL: "http://a.example.com/live"},
+			{Title: "Jazz FM", URL: "mms://b.example.com/live"},
+			{URL: "http://c.example.com/live?a=1&b=2"},
+			{URL: "http://d.example.com/more.asx"},
+		}, entries)
+	})
+
+	t.Run("returns nothing for an empty playlist", func(t *testing.T) {
+		entries, err := ParseASX([]byte(`<asx version="3.0"></asx>`))
+
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestDetect(t *testing.T) {
+
+	t.Run("recognizes playlists from their data", func(t *testing.T) {
+		assert.Equal(t, ".m3u", Detect("/live", "audio/mpeg", []byte("#EXTM3U\nhttp://a.example.com/live")))
+		assert.Equal(t, ".pls", Detect("/live", "", []byte("\xef\xbb\xbf[Playlist]\nFile1=http://a.example.com/live")))
+		assert.Equal(t, ".asx", Detect("/live", "video/x-ms-asf", []byte("  <ASX version=\"3.0\">")))
+		assert.Equal(t, ".xspf", Detect("/live", "", []byte(`<?xml version="1.0"?><playlist version="1" xmlns="http://xspf.org/ns/0/">`)))
+	})
+
+	t.Run("recognizes playlists from their content type", func(t *testing.T) {
+		assert.Equal(t, ".m3u", Detect("/live", "audio/x-mpegurl; charset=utf-8", []byte("http://a.example.com/live")))
+		assert.Equal(t, ".pls", Detect("/live", "audio/x-scpls", []byte("File1=http://a.example.com/live")))
+	})
+
+	t.Run("recognizes untyped playlists from their extension", func(t *testing.T) {
+		assert.Equal(t, ".m3u", Detect("/listen.m3u8", "text/plain", []byte("http://a.example.com/live")))
+		assert.Equal(t, ".pls", Detect("/listen.pls", "", []byte("File1=http://a.example.com/live")))
+	})
+
+	t.Run("leaves streams and HLS to the player", func(t *testing.T) {
+		assert.Equal(t, "", Detect("/live.mp3", "audio/mpeg", []byte{0xff, 0xfb, 0x90, 0x64}))
+		assert.Equal(t, "", Detect("/listen.m3u", "audio/mpeg", []byte{0xff, 0xfb, 0x90, 0x64}))
+		assert.Equal(t, "", Detect("/live.m3u8", "application/vnd.apple.mpegurl", []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:10")))
+		assert.Equal(t, "", Detect("/asf", "video/x-ms-asf", []byte{0x30, 0x26, 0xb2, 0x75}))
+	})
+}
+
+func TestParse(t *testing.T) {
+
+	t.Run("picks the format from the extension", func(t *testing.T) {
+		m3u, err := Parse("Jazz.M3U8", []byte("http://a.example.com/live"))
+		assert.NoError(t, err)
+		assert.Len(t, m3u, 1)
+
+		pls, err := Parse("jazz.pls", []byte("[playlist]\nFile1=http://a.example.com/live"))
+		assert.NoError(t, err)
+		assert.Len(t, pls, 1)
+
+		asx, err := Parse("jazz.asx", []byte(`<asx><entry><ref href="http://a.example.com/live"/></entry></asx>`))
+		assert.NoError(t, err)
+		assert.Len(t, asx, 1)
+
+		xspf, err := Parse("jazz.xspf", []byte("<playlist><trackList><track><location>http://a.example.com/live</location></track></trackList></playlist>"))
+		assert.NoError(t, err)
+		assert.Len(t, xspf, 1)
+	})
+
+	t.Run("rejects other files", func(t *testing.T) {
+		_, err := Parse("notes.txt", []byte("http://a.example.com/live"))
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+		assert.False(t, IsPlaylistFile("notes.txt"))
+		assert.True(t, IsPlaylistFile("radio.Pls"))
+	})
+}
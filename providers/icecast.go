@@ -0,0 +1,185 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/data"
+)
+
+// icecastDownloadTimeout bounds the download of a directory listing, which can be a few megabytes.
+const icecastDownloadTimeout = 60 * time.Second
+
+// IcecastDirectoryProvider lists the streams of an Icecast YP directory listing
+// (such as https://dir.xiph.org/yp.xml), read from a file or downloaded over HTTP.
+// The listing is loaded once per session, or again when the search context asks
+// for a forced refresh (see api.WithForceRefresh).
+type IcecastDirectoryProvider struct {
+	location string
+	client   api.HTTPClientService
+
+	mu       sync.Mutex
+	stations []common.Station // nil until loaded
+}
+
+// NewIcecastDirectoryProvider returns a provider for the listing at location, a file path or an http(s) URL.
+func NewIcecastDirectoryProvider(location string) *IcecastDirectoryProvider {
+	return NewIcecastDirectoryProviderWithClient(location, &http.Client{Timeout: icecastDownloadTimeout})
+}
+
+// NewIcecastDirectoryProviderWithClient returns a provider downloading the listing with client.
+func NewIcecastDirectoryProviderWithClient(location string, client api.HTTPClientService) *IcecastDirectoryProvider {
+	return &IcecastDirectoryProvider{location: location, client: client}
+}
+
+func (p *IcecastDirectoryProvider) Source() common.StationSource {
+	return common.StationSourceIcecast
+}
+
+func (p *IcecastDirectoryProvider) Search(
+	ctx context.Context,
+	query common.StationQuery,
+	queryText string,
+	order common.StationOrder,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+) ([]common.Station, error) {
+	stations, err := p.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return searchStations(stations, query, queryText, order, reverse, offset, limit), nil
+}
+
+// load returns the stations of the listing, reading it unless it was already read.
+func (p *IcecastDirectoryProvider) load(ctx context.Context) ([]common.Station, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stations != nil && !api.IsForceRefresh(ctx) {
+		return p.stations, nil
+	}
+
+	listing, err := p.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading Icecast directory: %w", err)
+	}
+	stations, err := parseYellowPages(listing)
+	if err != nil {
+		return nil, fmt.Errorf("reading Icecast directory: %w", err)
+	}
+	p.stations = stations
+	return stations, nil
+}
+
+// read returns the raw listing from the file or URL.
+func (p *IcecastDirectoryProvider) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(p.location, "http://") && !strings.HasPrefix(p.location, "https://") {
+		return os.ReadFile(p.location)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.location, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", data.UserAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", p.location, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+type ypDirectory struct {
+	Entries []ypEntry `xml:"entry"`
+}
+
+type ypEntry struct {
+	ServerName string `xml:"server_name"`
+	ServerType string `xml:"server_type"`
+	Bitrate    string `xml:"bitrate"`
+	ListenURL  string `xml:"listen_url"`
+	Genre      string `xml:"genre"`
+}
+
+// parseYellowPages parses an Icecast YP listing. Genres become tags.
+// Streams listed more than once (e.g. by several relays) are kept once.
+func parseYellowPages(data []byte) ([]common.Station, error) {
+	var directory ypDirectory
+	if err := xml.Unmarshal(data, &directory); err != nil {
+		return nil, err
+	}
+
+	stations := make([]common.Station, 0, len(directory.Entries))
+	seen := make(map[string]bool)
+	for _, entry := range directory.Entries {
+		station, ok := newLocalStation(common.StationSourceIcecast, entry.ServerName, entry.ListenURL)
+		if !ok || seen[station.StationUuid.String()] {
+			continue
+		}
+		seen[station.StationUuid.String()] = true
+
+		station.Codec = codecForContentType(entry.ServerType)
+		// Bitrates are sometimes given as e.g. "Quality 0" for Ogg streams
+		if bitrate, err := strconv.ParseUint(strings.TrimSpace(entry.Bitrate), 10, 64); err == nil {
+			station.Bitrate = bitrate
+		}
+		station.Tags = strings.Join(strings.Fields(entry.Genre), ",")
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
+
+// codecForContentType returns the codec name RadioBrowser would use for a stream content type.
+func codecForContentType(contentType string) string {
+	switch strings.ToLower(strings.TrimSpace(contentType)) {
+	case "audio/mpeg", "audio/mp3":
+		return "MP3"
+	case "audio/aac":
+		return "AAC"
+	case "audio/aacp":
+		return "AAC+"
+	case "application/ogg", "audio/ogg", "audio/vorbis":
+		return "OGG"
+	case "audio/opus":
+		return "OPUS"
+	case "audio/flac":
+		return "FLAC"
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/data"
+	"github.com/zi0p4tch0/radiogogo/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testYellowPages = `<?xml version="1.0" encoding="UTF-8"?>
+<directory>
+  <entry>
+    <server_name>Rock Radio</server_name>
+    <server_type>audio/mpeg</server_type>
+    <bitrate>192</bitrate>
+    <listen_url>http://rock.example.com:8000/live</listen_url>
+    <genre>rock  classic</genre>
+  </entry>
+  <entry>
+    <server_name>Rock Radio (relay)</server_name>
+    <server_type>audio/mpeg</server_type>
+    <bitrate>192</bitrate>
+    <listen_url>http://rock.example.com:8000/live</listen_url>
+    <genre>rock</genre>
+  </entry>
+  <entry>
+    <server_name>Ambient</server_name>
+    <server_type>application/ogg</server_type>
+    <bitrate>Quality 0</bitrate>
+    <listen_url>http://ambient.example.com/stream.ogg</listen_url>
+    <genre>ambient</genre>
+  </entry>
+  <entry>
+    <server_name>No URL</server_name>
+  </entry>
+</directory>`
+
+func TestIcecastDirectoryProvider(t *testing.T) {
+
+	searchAll := func(ctx context.Context, p *IcecastDirectoryProvider) ([]common.Station, error) {
+		return p.Search(ctx, common.StationQueryAll, "", common.StationOrderVotes, true, 0, 100)
+	}
+
+	t.Run("reads a listing from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "yp.xml")
+		assert.NoError(t, os.WriteFile(path, []byte(testYellowPages), 0644))
+
+		stations, err := searchAll(context.Background(), NewIcecastDirectoryProvider(path))
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 2)
+		assert.Equal(t, "Rock Radio", stations[0].Name)
+		assert.Equal(t, "MP3", stations[0].Codec)
+		assert.Equal(t, uint64(192), stations[0].Bitrate)
+		assert.Equal(t, "rock,classic", stations[0].Tags)
+		assert.Equal(t, common.StationSourceIcecast, stations[0].Source)
+		assert.Equal(t, "OGG", stations[1].Codec)
+		assert.Equal(t, uint64(0), stations[1].Bitrate)
+	})
+
+	t.Run("downloads a listing once per session unless refreshing", func(t *testing.T) {
+		requests := 0
+		client := &mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				requests++
+				assert.Equal(t, "https://dir.example.com/yp.xml", req.URL.String())
+				assert.Equal(t, data.UserAgent, req.Header.Get("User-Agent"))
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(testYellowPages))}, nil
+			},
+		}
+		p := NewIcecastDirectoryProviderWithClient("https://dir.example.com/yp.xml", client)
+
+		_, err := searchAll(context.Background(), p)
+		assert.NoError(t, err)
+		stations, err := p.Search(context.Background(), common.StationQueryByTagExact, "ambient", "", false, 0, 100)
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+		assert.Equal(t, 1, requests)
+
+		_, err = searchAll(api.WithForceRefresh(context.Background()), p)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("fails on an unexpected status", func(t *testing.T) {
+		client := &mocks.MockHttpClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+			},
+		}
+
+		_, err := searchAll(context.Background(), NewIcecastDirectoryProviderWithClient("http://dir.example.com/yp.xml", client))
+
+		assert.ErrorContains(t, err, "404")
+	})
+
+	t.Run("fails on a malformed listing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "yp.xml")
+		assert.NoError(t, os.WriteFile(path, []byte("<directory><entry>"), 0644))
+
+		_, err := searchAll(context.Background(), NewIcecastDirectoryProvider(path))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCodecForContentType(t *testing.T) {
+	assert.Equal(t, "MP3", codecForContentType("audio/mpeg"))
+	assert.Equal(t, "AAC+", codecForContentType(" AUDIO/AACP "))
+	assert.Equal(t, "OPUS", codecForContentType("audio/opus"))
+	assert.Equal(t, "", codecForContentType("video/mp4"))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+
+	"github.com/google/uuid"
+)
+
+// localNamespace is the namespace of the UUIDs given to stations found outside RadioBrowser.
+// UUIDs are derived from the stream URL, so bookmarks and hidden stations stick across sessions.
+var localNamespace = uuid.MustParse("4a3c1a40-9d2e-4c36-8f57-4e0d6a3f2b91")
+
+// newLocalStation returns the station for a stream found in a local source.
+// ok is false if streamURL isn't an absolute URL. Unnamed streams are named after their host.
+func newLocalStation(source common.StationSource, name string, streamURL string) (station common.Station, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(streamURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return common.Station{}, false
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = u.Host
+	}
+	return common.Station{
+		StationUuid: uuid.NewSHA1(localNamespace, []byte(u.String())),
+		Name:        name,
+		Url:         common.RadioGoGoURL{URL: *u},
+		UrlResolved: common.RadioGoGoURL{URL: *u},
+		Source:      source,
+	}, true
+}
+
+// searchStations filters, sorts and pages stations the way RadioBrowser would.
+// Sort fields local sources know nothing about (votes, clicks...) keep the listing order.
+func searchStations(
+	stations []common.Station,
+	query common.StationQuery,
+	queryText string,
+	order common.StationOrder,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+) []common.Station {
+	matches := make([]common.Station, 0, len(stations))
+	for _, station := range stations {
+		if matchesQuery(station, query, queryText) {
+			matches = append(matches, station)
+		}
+	}
+
+	if less := lessFunc(order); less != nil {
+		sort.SliceStable(matches, func(i, j int) bool {
+			if reverse {
+				return less(matches[j], matches[i])
+			}
+			return less(matches[i], matches[j])
+		})
+	}
+
+	if offset >= uint64(len(matches)) {
+		return []common.Station{}
+	}
+	matches = matches[offset:]
+	if limit < uint64(len(matches)) {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// matchesQuery reports whether station matches a single-filter search. Comparisons ignore case.
+func matchesQuery(station common.Station, query common.StationQuery, queryText string) bool {
+	text := strings.ToLower(strings.TrimSpace(queryText))
+	contains := func(value string) bool {
+		return strings.Contains(strings.ToLower(value), text)
+	}
+	equals := func(value string) bool {
+		return strings.ToLower(value) == text
+	}
+
+	switch query {
+	case common.StationQueryByUuid:
+		return equals(station.StationUuid.String())
+	case common.StationQueryByName:
+		return contains(station.Name)
+	case common.StationQueryByNameExact:
+		return equals(station.Name)
+	case common.StationQueryByCodec:
+		return contains(station.Codec)
+	case common.StationQueryByCodecExact:
+		return equals(station.Codec)
+	case common.StationQueryByCountry:
+		return contains(station.CountryCode)
+	case common.StationQueryByCountryExact, common.StationQueryByCountryCodeExact:
+		return equals(station.CountryCode)
+	case common.StationQueryByState:
+		return contains(station.State)
+	case common.StationQueryByStateExact:
+		return equals(station.State)
+	case common.StationQueryByLanguage:
+		return contains(station.Languages)
+	case common.StationQueryByLanguageExact:
+		return equals(station.Languages)
+	case common.StationQueryByTag:
+		return anyTag(station, contains)
+	case common.StationQueryByTagExact:
+		return anyTag(station, equals)
+	}
+	return true
+}
+
+// anyTag reports whether match is true for any of the station's tags.
+func anyTag(station common.Station, match func(tag string) bool) bool {
+	for _, tag := range strings.Split(station.Tags, ",") {
+		if match(strings.TrimSpace(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lessFunc returns how stations compare in order, or nil if local sources can't sort by it.
+func lessFunc(order common.StationOrder) func(a, b common.Station) bool {
+	switch order {
+	case common.StationOrderName:
+		return func(a, b common.Station) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case common.StationOrderBitrate:
+		return func(a, b common.Station) bool { return a.Bitrate < b.Bitrate }
+	case common.StationOrderCodec:
+		return func(a, b common.Station) bool { return strings.ToLower(a.Codec) < strings.ToLower(b.Codec) }
+	case common.StationOrderCountry:
+		return func(a, b common.Station) bool { return a.CountryCode < b.CountryCode }
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocalStation(t *testing.T) {
+
+	t.Run("derives a stable UUID from the stream URL", func(t *testing.T) {
+		a, ok := newLocalStation(common.StationSourcePlaylists, " Jazz FM ", "http://jazz.example.com/live")
+		assert.True(t, ok)
+		b, _ := newLocalStation(common.StationSourceIcecast, "Other name", "http://jazz.example.com/live")
+
+		assert.Equal(t, a.StationUuid, b.StationUuid)
+		assert.Equal(t, "Jazz FM", a.Name)
+		assert.Equal(t, "http://jazz.example.com/live", a.Url.URL.String())
+		assert.Equal(t, a.Url, a.UrlResolved)
+		assert.Equal(t, common.StationSourcePlaylists, a.Source)
+	})
+
+	t.Run("names unnamed streams after their host", func(t *testing.T) {
+		station, ok := newLocalStation(common.StationSourcePlaylists, "", "https://radio.example.com:8000/stream")
+		assert.True(t, ok)
+		assert.Equal(t, "radio.example.com:8000", station.Name)
+	})
+
+	t.Run("rejects relative paths", func(t *testing.T) {
+		_, ok := newLocalStation(common.StationSourcePlaylists, "Local", "music/song.mp3")
+		assert.False(t, ok)
+	})
+}
+
+func TestSearchStations(t *testing.T) {
+
+	station := func(name, tags, codec string, bitrate uint64) common.Station {
+		s, _ := newLocalStation(common.StationSourcePlaylists, name, "http://"+name+".example.com/live")
+		s.Tags = tags
+		s.Codec = codec
+		s.Bitrate = bitrate
+		return s
+	}
+	jazz := station("jazz", "jazz,smooth jazz", "MP3", 128)
+	rock := station("Rock", "rock", "AAC", 64)
+	blues := station("blues", "blues,jazz", "MP3", 320)
+	stations := []common.Station{jazz, rock, blues}
+
+	t.Run("filters by the query", func(t *testing.T) {
+		assert.Equal(t, []common.Station{rock}, searchStations(stations, common.StationQueryByName, "ROC", "", false, 0, 10))
+		assert.Equal(t, []common.Station{jazz, blues}, searchStations(stations, common.StationQueryByTagExact, "jazz", "", false, 0, 10))
+		assert.Equal(t, []common.Station{jazz}, searchStations(stations, common.StationQueryByTag, "smooth", "", false, 0, 10))
+		assert.Equal(t, []common.Station{jazz, blues}, searchStations(stations, common.StationQueryByCodecExact, "mp3", "", false, 0, 10))
+		assert.Empty(t, searchStations(stations, common.StationQueryByCountryCodeExact, "DE", "", false, 0, 10))
+		assert.Equal(t, stations, searchStations(stations, common.StationQueryByName, "", "", false, 0, 10))
+	})
+
+	t.Run("sorts by the fields local sources know", func(t *testing.T) {
+		assert.Equal(t, []common.Station{blues, jazz, rock}, searchStations(stations, common.StationQueryAll, "", common.StationOrderName, false, 0, 10))
+		assert.Equal(t, []common.Station{blues, jazz, rock}, searchStations(stations, common.StationQueryAll, "", common.StationOrderBitrate, true, 0, 10))
+		assert.Equal(t, stations, searchStations(stations, common.StationQueryAll, "", common.StationOrderVotes, true, 0, 10))
+	})
+
+	t.Run("pages the results", func(t *testing.T) {
+		assert.Equal(t, []common.Station{rock}, searchStations(stations, common.StationQueryAll, "", "", false, 1, 1))
+		assert.Equal(t, []common.Station{blues}, searchStations(stations, common.StationQueryAll, "", "", false, 2, 10))
+		assert.Equal(t, []common.Station{}, searchStations(stations, common.StationQueryAll, "", "", false, 3, 10))
+	})
+}
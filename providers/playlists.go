@@ -0,0 +1,104 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/playlist"
+)
+
+// PlaylistDirProvider lists the streams of the M3U, PLS and XSPF playlists in a directory.
+// Each station is tagged with the name of its playlist (e.g. "jazz" for jazz.m3u).
+// The directory is read again on every search, so edits show up right away.
+type PlaylistDirProvider struct {
+	dir string
+}
+
+func NewPlaylistDirProvider(dir string) *PlaylistDirProvider {
+	return &PlaylistDirProvider{dir: dir}
+}
+
+func (p *PlaylistDirProvider) Source() common.StationSource {
+	return common.StationSourcePlaylists
+}
+
+func (p *PlaylistDirProvider) Search(
+	ctx context.Context,
+	query common.StationQuery,
+	queryText string,
+	order common.StationOrder,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+) ([]common.Station, error) {
+	stations, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	return searchStations(stations, query, queryText, order, reverse, offset, limit), nil
+}
+
+// load reads every playlist in the directory, in file name order. Files that can't be
+// read or parsed are skipped. A stream listed in several playlists gets all their tags.
+func (p *PlaylistDirProvider) load() ([]common.Station, error) {
+	files, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading playlist directory: %w", err)
+	}
+
+	var stations []common.Station
+	index := make(map[string]int)
+	for _, file := range files {
+		if file.IsDir() || !playlist.IsPlaylistFile(file.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(p.dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		entries, err := playlist.Parse(file.Name(), data)
+		if err != nil {
+			continue
+		}
+		tag := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		for _, entry := range entries {
+			station, ok := newLocalStation(common.StationSourcePlaylists, entry.Title, entry.URL)
+			if !ok {
+				continue
+			}
+			key := station.StationUuid.String()
+			if i, seen := index[key]; seen {
+				if !anyTag(stations[i], func(t string) bool { return t == tag }) {
+					stations[i].Tags += "," + tag
+				}
+				continue
+			}
+			station.Tags = tag
+			index[key] = len(stations)
+			stations = append(stations, station)
+		}
+	}
+	return stations, nil
+}
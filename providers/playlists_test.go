@@ -0,0 +1,94 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaylistDirProvider(t *testing.T) {
+
+	writeFile := func(t *testing.T, dir, name, content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	search := func(p *PlaylistDirProvider, query common.StationQuery, text string) ([]common.Station, error) {
+		return p.Search(context.Background(), query, text, common.StationOrderName, false, 0, 100)
+	}
+
+	t.Run("lists the streams of every playlist tagged with its name", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "jazz.m3u", "#EXTM3U\n#EXTINF:-1,Smooth Jazz\nhttp://jazz.example.com/live\n")
+		writeFile(t, dir, "news.pls", "[playlist]\nFile1=http://news.example.com/live\nTitle1=All News\n")
+		writeFile(t, dir, "late.xspf", `<playlist><trackList><track><location>http://jazz.example.com/live</location></track></trackList></playlist>`)
+		writeFile(t, dir, "notes.txt", "http://ignored.example.com/live")
+		writeFile(t, dir, "broken.xspf", "<playlist>")
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested.m3u"), 0755))
+
+		p := NewPlaylistDirProvider(dir)
+		stations, err := search(p, common.StationQueryAll, "")
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 2)
+		assert.Equal(t, "All News", stations[0].Name)
+		assert.Equal(t, "news", stations[0].Tags)
+		assert.Equal(t, "Smooth Jazz", stations[1].Name)
+		assert.Equal(t, "jazz,late", stations[1].Tags)
+		assert.Equal(t, common.StationSourcePlaylists, stations[1].Source)
+	})
+
+	t.Run("searches by tag", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, dir, "jazz.m3u", "http://jazz.example.com/live\n")
+		writeFile(t, dir, "news.m3u", "http://news.example.com/live\n")
+
+		stations, err := search(NewPlaylistDirProvider(dir), common.StationQueryByTagExact, "jazz")
+
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+		assert.Equal(t, "jazz.example.com", stations[0].Name)
+	})
+
+	t.Run("picks up changes on the next search", func(t *testing.T) {
+		dir := t.TempDir()
+		p := NewPlaylistDirProvider(dir)
+
+		stations, err := search(p, common.StationQueryAll, "")
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+
+		writeFile(t, dir, "jazz.m3u", "http://jazz.example.com/live\n")
+
+		stations, err = search(p, common.StationQueryAll, "")
+		assert.NoError(t, err)
+		assert.Len(t, stations, 1)
+	})
+
+	t.Run("fails if the directory can't be read", func(t *testing.T) {
+		_, err := search(NewPlaylistDirProvider(filepath.Join(t.TempDir(), "missing")), common.StationQueryAll, "")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
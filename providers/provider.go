@@ -0,0 +1,44 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+// Package providers implements the sources RadioGoGo can search for stations:
+// RadioBrowser, a directory of local playlist files and an Icecast directory listing.
+package providers
+
+import (
+	"context"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// StationProvider is a source of stations that can be searched with a single filter.
+type StationProvider interface {
+	// Source is the source of the stations it returns.
+	Source() common.StationSource
+	// Search returns the stations matching query and queryText sorted by order,
+	// skipping the first offset ones. At most limit stations are returned.
+	Search(
+		ctx context.Context,
+		query common.StationQuery,
+		queryText string,
+		order common.StationOrder,
+		reverse bool,
+		offset uint64,
+		limit uint64,
+	) ([]common.Station, error)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package providers
+
+import (
+	"context"
+
+	"github.com/zi0p4tch0/radiogogo/api"
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// RadioBrowserProvider searches RadioBrowser. Stations whose stream RadioBrowser
+// found broken are left out.
+type RadioBrowserProvider struct {
+	browser api.RadioBrowserService
+}
+
+func NewRadioBrowserProvider(browser api.RadioBrowserService) *RadioBrowserProvider {
+	return &RadioBrowserProvider{browser: browser}
+}
+
+func (p *RadioBrowserProvider) Source() common.StationSource {
+	return common.StationSourceRadioBrowser
+}
+
+func (p *RadioBrowserProvider) Search(
+	ctx context.Context,
+	query common.StationQuery,
+	queryText string,
+	order common.StationOrder,
+	reverse bool,
+	offset uint64,
+	limit uint64,
+) ([]common.Station, error) {
+	return p.browser.GetStations(ctx, query, queryText, string(order), reverse, offset, limit, true)
+}
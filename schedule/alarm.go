@@ -0,0 +1,125 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// Weekdays is a set of days of the week.
+type Weekdays uint8
+
+// EveryDay is the set of all days of the week.
+const EveryDay Weekdays = 1<<7 - 1
+
+// Has returns true if day is in the set.
+func (d Weekdays) Has(day time.Weekday) bool {
+	return d&(1<<uint(day)) != 0
+}
+
+// Toggle returns the set with day added, or removed if it was already in.
+func (d Weekdays) Toggle(day time.Weekday) Weekdays {
+	return d ^ (1 << uint(day))
+}
+
+// Alarm starts playing a station at a time of day.
+type Alarm struct {
+	// ID identifies a saved alarm (0 until it's saved).
+	ID      int64
+	Station common.Station
+	// Hour and Minute are the local time of day the alarm rings at.
+	Hour   int
+	Minute int
+	// Days are the weekdays the alarm rings on. With none, it rings once and then turns itself off.
+	Days Weekdays
+	// Volume is the volume the station plays at, reached after RampUp.
+	Volume int
+	// RampUp is how long the volume takes to rise to Volume (0 starts at Volume).
+	RampUp  time.Duration
+	Enabled bool
+}
+
+// Once returns true if the alarm rings once rather than on weekdays.
+func (a Alarm) Once() bool {
+	return a.Days == 0
+}
+
+// TimeOfDay returns the time the alarm rings at, e.g. "07:30".
+func (a Alarm) TimeOfDay() string {
+	return fmt.Sprintf("%02d:%02d", a.Hour, a.Minute)
+}
+
+// NextRing returns the first time after after that the alarm rings at, in after's location.
+// Whether the alarm is enabled doesn't matter.
+func (a Alarm) NextRing(after time.Time) time.Time {
+	year, month, day := after.Date()
+	// A week and a day covers every weekday, even when today's time has passed
+	for i := 0; i <= 7; i++ {
+		ring := time.Date(year, month, day+i, a.Hour, a.Minute, 0, 0, after.Location())
+		if ring.After(after) && (a.Once() || a.Days.Has(ring.Weekday())) {
+			return ring
+		}
+	}
+	return time.Time{}
+}
+
+// DueAlarms returns the enabled alarms that ring after from, up to and including to,
+// in the order they ring.
+func DueAlarms(alarms []Alarm, from, to time.Time) []Alarm {
+	due := []Alarm{}
+	for _, alarm := range alarms {
+		if !alarm.Enabled {
+			continue
+		}
+		if ring := alarm.NextRing(from); !ring.IsZero() && !ring.After(to) {
+			due = append(due, alarm)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].NextRing(from).Before(due[j].NextRing(from))
+	})
+	return due
+}
+
+// ParseTimeOfDay parses a time of day written as "7:30", "07:30" or "0730".
+func ParseTimeOfDay(s string) (hour, minute int, err error) {
+	s = strings.TrimSpace(s)
+	hours, minutes, found := strings.Cut(s, ":")
+	if !found {
+		if len(s) != 3 && len(s) != 4 {
+			return 0, 0, fmt.Errorf("invalid time of day %q", s)
+		}
+		hours, minutes = s[:len(s)-2], s[len(s)-2:]
+	}
+	hour, err = strconv.Atoi(hours)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	minute, err = strconv.Atoi(minutes)
+	if err != nil || len(minutes) != 2 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return hour, minute, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// monday is a Monday, 2026-03-02 at 08:00 UTC.
+var monday = time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+func TestWeekdays(t *testing.T) {
+	var days Weekdays
+	assert.False(t, days.Has(time.Monday))
+
+	days = days.Toggle(time.Monday).Toggle(time.Friday)
+	assert.True(t, days.Has(time.Monday))
+	assert.True(t, days.Has(time.Friday))
+	assert.False(t, days.Has(time.Sunday))
+
+	days = days.Toggle(time.Monday)
+	assert.False(t, days.Has(time.Monday))
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		assert.True(t, EveryDay.Has(day))
+	}
+}
+
+func TestAlarm_NextRing(t *testing.T) {
+	t.Run("rings once later today", func(t *testing.T) {
+		alarm := Alarm{Hour: 9, Minute: 30}
+
+		assert.Equal(t, time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC), alarm.NextRing(monday))
+	})
+
+	t.Run("rings once tomorrow when today's time has passed", func(t *testing.T) {
+		alarm := Alarm{Hour: 7, Minute: 0}
+
+		assert.Equal(t, time.Date(2026, 3, 3, 7, 0, 0, 0, time.UTC), alarm.NextRing(monday))
+	})
+
+	t.Run("doesn't ring again at the time it rang", func(t *testing.T) {
+		alarm := Alarm{Hour: 8, Minute: 0}
+
+		assert.Equal(t, time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC), alarm.NextRing(monday))
+	})
+
+	t.Run("rings on the next chosen weekday", func(t *testing.T) {
+		alarm := Alarm{Hour: 7, Minute: 15, Days: Weekdays(0).Toggle(time.Saturday).Toggle(time.Sunday)}
+
+		assert.Equal(t, time.Date(2026, 3, 7, 7, 15, 0, 0, time.UTC), alarm.NextRing(monday))
+	})
+
+	t.Run("rings a week later on the only chosen day", func(t *testing.T) {
+		alarm := Alarm{Hour: 7, Minute: 0, Days: Weekdays(0).Toggle(time.Monday)}
+
+		assert.Equal(t, time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC), alarm.NextRing(monday))
+	})
+
+	t.Run("follows the local time across daylight saving changes", func(t *testing.T) {
+		rome, err := time.LoadLocation("Europe/Rome")
+		if err != nil {
+			t.Skip("time zone database not available")
+		}
+		// Clocks go forward on the night of 2026-03-29
+		saturday := time.Date(2026, 3, 28, 12, 0, 0, 0, rome)
+		alarm := Alarm{Hour: 7, Minute: 0, Days: EveryDay}
+
+		ring := alarm.NextRing(saturday)
+		ring = alarm.NextRing(ring)
+
+		assert.Equal(t, time.Date(2026, 3, 30, 7, 0, 0, 0, rome), ring)
+		assert.Equal(t, 7, ring.Hour())
+	})
+}
+
+func TestDueAlarms(t *testing.T) {
+	first := Alarm{ID: 1, Hour: 8, Minute: 1, Enabled: true}
+	second := Alarm{ID: 2, Hour: 8, Minute: 0, Days: EveryDay, Enabled: true}
+	later := Alarm{ID: 3, Hour: 9, Minute: 0, Enabled: true}
+	off := Alarm{ID: 4, Hour: 8, Minute: 0, Enabled: false}
+	alarms := []Alarm{first, second, later, off}
+
+	t.Run("returns the enabled alarms ringing in the period, in order", func(t *testing.T) {
+		due := DueAlarms(alarms, monday.Add(-time.Second), monday.Add(2*time.Minute))
+
+		assert.Equal(t, []Alarm{second, first}, due)
+	})
+
+	t.Run("includes the end of the period but not its start", func(t *testing.T) {
+		assert.Equal(t, []Alarm{second}, DueAlarms(alarms, monday.Add(-time.Second), monday))
+		assert.Empty(t, DueAlarms(alarms, monday, monday.Add(30*time.Second)))
+	})
+
+	t.Run("returns nothing for an empty period", func(t *testing.T) {
+		assert.Empty(t, DueAlarms(alarms, monday.Add(-time.Second), monday.Add(-time.Second)))
+	})
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	for input, want := range map[string][2]int{
+		"07:30":  {7, 30},
+		"7:30":   {7, 30},
+		" 0730 ": {7, 30},
+		"730":    {7, 30},
+		"23:59":  {23, 59},
+		"0:00":   {0, 0},
+	} {
+		hour, minute, err := ParseTimeOfDay(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, [2]int{hour, minute}, input)
+	}
+
+	for _, input := range []string{"", "7", "24:00", "7:60", "7:5", "seven", "7:30pm", "-1:30"} {
+		_, _, err := ParseTimeOfDay(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestAlarm_TimeOfDay(t *testing.T) {
+	assert.Equal(t, "07:05", Alarm{Hour: 7, Minute: 5}.TimeOfDay())
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/zi0p4tch0/radiogogo/common"
+)
+
+// MaxRecordingDuration is the longest a scheduled recording lasts.
+const MaxRecordingDuration = 24 * time.Hour
+
+// Recording records a station for a while, once or every week on some days.
+type Recording struct {
+	// ID identifies a saved recording (0 until it's saved).
+	ID      int64
+	Station common.Station
+	// Start is when the recording starts. A repeated recording starts at its time of day,
+	// on the days it repeats on from its date onwards.
+	Start    time.Time
+	Duration time.Duration
+	// Days are the weekdays the recording repeats on. With none, it records once.
+	Days    Weekdays
+	Enabled bool
+}
+
+// Once returns true if the recording happens once rather than on weekdays.
+func (r Recording) Once() bool {
+	return r.Days == 0
+}
+
+// Occurrences returns the starts of the recording's occurrences that are under way
+// at from or start before to, in order. Whether the recording is enabled doesn't matter.
+func (r Recording) Occurrences(from, to time.Time) []time.Time {
+	starts := []time.Time{}
+	if r.Once() {
+		if r.Start.Add(r.Duration).After(from) && r.Start.Before(to) {
+			starts = append(starts, r.Start)
+		}
+		return starts
+	}
+
+	// An occurrence under way at from started at most Duration earlier
+	first := from.Add(-r.Duration)
+	if first.Before(r.Start) {
+		first = r.Start
+	}
+	year, month, day := first.In(r.Start.Location()).Date()
+	for i := 0; ; i++ {
+		start := time.Date(year, month, day+i, r.Start.Hour(), r.Start.Minute(), 0, 0, r.Start.Location())
+		if !start.Before(to) {
+			return starts
+		}
+		if r.Days.Has(start.Weekday()) && !start.Before(r.Start) && start.Add(r.Duration).After(from) {
+			starts = append(starts, start)
+		}
+	}
+}
+
+// Current returns the start of the occurrence under way at t, if there's one.
+func (r Recording) Current(t time.Time) (time.Time, bool) {
+	starts := r.Occurrences(t, t.Add(time.Nanosecond))
+	if len(starts) == 0 {
+		return time.Time{}, false
+	}
+	return starts[len(starts)-1], true
+}
+
+// NextStart returns the first start of the recording after after, if it starts again.
+func (r Recording) NextStart(after time.Time) (time.Time, bool) {
+	// A week and a day from its first start covers every weekday
+	to := after
+	if r.Start.After(to) {
+		to = r.Start
+	}
+	for _, start := range r.Occurrences(after, to.AddDate(0, 0, 8)) {
+		if start.After(after) {
+			return start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Overlap returns the start of an occurrence of a that overlaps one of b from now on,
+// if there's one.
+func Overlap(a, b Recording, now time.Time) (time.Time, bool) {
+	// Weekly occurrences repeat every 7 days, so a week (and the longest occurrence)
+	// after the later start covers every way they can overlap
+	later := now
+	for _, start := range []time.Time{a.Start, b.Start} {
+		if start.After(later) {
+			later = start
+		}
+	}
+	to := later.AddDate(0, 0, 8).Add(MaxRecordingDuration)
+
+	others := b.Occurrences(now, to)
+	for _, start := range a.Occurrences(now, to) {
+		end := start.Add(a.Duration)
+		for _, other := range others {
+			if start.Before(other.Add(b.Duration)) && other.Before(end) {
+				return start, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// Conflicts returns the enabled recordings, other than recording itself, that overlap it
+// from now on, in the order of their next start.
+func Conflicts(recordings []Recording, recording Recording, now time.Time) []Recording {
+	conflicts := []Recording{}
+	for _, other := range recordings {
+		if !other.Enabled || (recording.ID != 0 && other.ID == recording.ID) {
+			continue
+		}
+		if _, ok := Overlap(recording, other, now); ok {
+			conflicts = append(conflicts, other)
+		}
+	}
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		a, _ := conflicts[i].NextStart(now)
+		b, _ := conflicts[j].NextStart(now)
+		return a.Before(b)
+	})
+	return conflicts
+}
+
+// RecordingRun is a recording made by a scheduled Recording.
+type RecordingRun struct {
+	// ID identifies a saved run (0 until it's saved).
+	ID          int64
+	RecordingID int64
+	// Scheduled is the start of the occurrence it records. A run starting late,
+	// e.g. because the app wasn't running, records the rest of it.
+	Scheduled time.Time
+	Start     time.Time
+	// End is when the run ended, zero while it's under way.
+	End  time.Time
+	Path string
+	// Err tells why the run failed, empty if it didn't.
+	Err string
+}
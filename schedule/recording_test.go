@@ -0,0 +1,185 @@
+// Copyright (c) 2023-2026 Matteo Pacini
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+}
+
+var weekend = Weekdays(0).Toggle(time.Saturday).Toggle(time.Sunday)
+
+func TestRecording_Occurrences(t *testing.T) {
+	t.Run("a one-off recording happens once", func(t *testing.T) {
+		recording := Recording{Start: at(3, 21, 0), Duration: time.Hour}
+
+		assert.Equal(t, []time.Time{at(3, 21, 0)}, recording.Occurrences(monday, at(10, 0, 0)))
+		assert.Empty(t, recording.Occurrences(monday, at(3, 21, 0)))
+		// Still under way
+		assert.Equal(t, []time.Time{at(3, 21, 0)}, recording.Occurrences(at(3, 21, 59), at(4, 0, 0)))
+		assert.Empty(t, recording.Occurrences(at(3, 22, 0), at(4, 0, 0)))
+	})
+
+	t.Run("a repeated recording happens on its weekdays from its date", func(t *testing.T) {
+		// Starts on Friday the 6th, then every weekend
+		recording := Recording{Start: at(6, 21, 0), Duration: time.Hour, Days: weekend}
+
+		assert.Equal(t, []time.Time{at(7, 21, 0), at(8, 21, 0), at(14, 21, 0)}, recording.Occurrences(monday, at(15, 0, 0)))
+	})
+
+	t.Run("includes a repeated recording under way", func(t *testing.T) {
+		recording := Recording{Start: at(1, 23, 30), Duration: time.Hour, Days: EveryDay}
+
+		assert.Equal(t, []time.Time{at(1, 23, 30), at(2, 23, 30)}, recording.Occurrences(at(2, 0, 15), at(3, 0, 0)))
+	})
+
+	t.Run("keeps the time of day across daylight saving changes", func(t *testing.T) {
+		rome, err := time.LoadLocation("Europe/Rome")
+		if err != nil {
+			t.Skip("time zone data not available")
+		}
+		recording := Recording{Start: time.Date(2026, 3, 28, 8, 0, 0, 0, rome), Duration: time.Hour, Days: EveryDay}
+
+		starts := recording.Occurrences(time.Date(2026, 3, 28, 0, 0, 0, 0, rome), time.Date(2026, 3, 30, 0, 0, 0, 0, rome))
+		assert.Equal(t, []time.Time{time.Date(2026, 3, 28, 8, 0, 0, 0, rome), time.Date(2026, 3, 29, 8, 0, 0, 0, rome)}, starts)
+	})
+}
+
+func TestRecording_Current(t *testing.T) {
+	recording := Recording{Start: at(2, 9, 0), Duration: 90 * time.Minute, Days: EveryDay}
+
+	start, ok := recording.Current(at(3, 10, 0))
+	assert.True(t, ok)
+	assert.Equal(t, at(3, 9, 0), start)
+
+	_, ok = recording.Current(at(3, 10, 30))
+	assert.False(t, ok)
+}
+
+func TestRecording_NextStart(t *testing.T) {
+	t.Run("a one-off recording starts once", func(t *testing.T) {
+		recording := Recording{Start: at(20, 21, 0), Duration: time.Hour}
+
+		start, ok := recording.NextStart(monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(20, 21, 0), start)
+
+		_, ok = recording.NextStart(at(20, 21, 0))
+		assert.False(t, ok)
+	})
+
+	t.Run("a repeated recording starts on its next weekday", func(t *testing.T) {
+		recording := Recording{Start: at(1, 21, 0), Duration: time.Hour, Days: weekend}
+
+		start, ok := recording.NextStart(monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(7, 21, 0), start)
+	})
+
+	t.Run("a repeated recording starting weeks later", func(t *testing.T) {
+		recording := Recording{Start: at(23, 21, 0), Duration: time.Hour, Days: weekend}
+
+		start, ok := recording.NextStart(monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(28, 21, 0), start)
+	})
+}
+
+func TestOverlap(t *testing.T) {
+	t.Run("one-off recordings sharing some time", func(t *testing.T) {
+		a := Recording{Start: at(3, 21, 0), Duration: time.Hour}
+		b := Recording{Start: at(3, 21, 30), Duration: time.Hour}
+
+		start, ok := Overlap(a, b, monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(3, 21, 0), start)
+	})
+
+	t.Run("back to back recordings don't overlap", func(t *testing.T) {
+		a := Recording{Start: at(3, 21, 0), Duration: time.Hour}
+		b := Recording{Start: at(3, 22, 0), Duration: time.Hour}
+
+		_, ok := Overlap(a, b, monday)
+		assert.False(t, ok)
+	})
+
+	t.Run("a one-off recording on a day a repeated one happens", func(t *testing.T) {
+		a := Recording{Start: at(1, 21, 0), Duration: time.Hour, Days: weekend}
+		b := Recording{Start: at(21, 20, 30), Duration: time.Hour}
+
+		start, ok := Overlap(a, b, monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(21, 21, 0), start)
+
+		// Not on a weekday it skips
+		b.Start = at(20, 20, 30)
+		_, ok = Overlap(a, b, monday)
+		assert.False(t, ok)
+	})
+
+	t.Run("repeated recordings sharing a weekday", func(t *testing.T) {
+		a := Recording{Start: at(1, 21, 0), Duration: time.Hour, Days: weekend}
+		b := Recording{Start: at(1, 21, 45), Duration: time.Hour, Days: Weekdays(0).Toggle(time.Sunday).Toggle(time.Monday)}
+
+		start, ok := Overlap(a, b, monday)
+		assert.True(t, ok)
+		assert.Equal(t, at(8, 21, 0), start)
+	})
+
+	t.Run("an occurrence running past midnight", func(t *testing.T) {
+		a := Recording{Start: at(6, 23, 0), Duration: 2 * time.Hour}
+		b := Recording{Start: at(1, 0, 30), Duration: time.Hour, Days: Weekdays(0).Toggle(time.Saturday)}
+
+		_, ok := Overlap(a, b, monday)
+		assert.True(t, ok)
+	})
+
+	t.Run("past occurrences don't count", func(t *testing.T) {
+		a := Recording{Start: at(1, 21, 0), Duration: time.Hour}
+		b := Recording{Start: at(1, 21, 0), Duration: time.Hour}
+
+		_, ok := Overlap(a, b, monday)
+		assert.False(t, ok)
+	})
+}
+
+func TestConflicts(t *testing.T) {
+	recording := Recording{ID: 1, Start: at(7, 21, 0), Duration: time.Hour}
+	recordings := []Recording{
+		recording,
+		{ID: 2, Start: at(1, 21, 30), Duration: time.Hour, Days: weekend, Enabled: true},
+		{ID: 3, Start: at(7, 20, 30), Duration: time.Hour, Enabled: true},
+		{ID: 4, Start: at(7, 21, 30), Duration: time.Hour},
+		{ID: 5, Start: at(7, 22, 0), Duration: time.Hour, Enabled: true},
+	}
+
+	conflicts := Conflicts(recordings, recording, monday)
+
+	// Disabled ones and the recording itself are left out, the next to start comes first
+	assert.Len(t, conflicts, 2)
+	assert.Equal(t, int64(3), conflicts[0].ID)
+	assert.Equal(t, int64(2), conflicts[1].ID)
+}
@@ -21,20 +21,30 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
 	"github.com/zi0p4tch0/radiogogo/config"
+	"github.com/zi0p4tch0/radiogogo/health"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 	_ "modernc.org/sqlite"
 )
 
 const (
-	currentSchemaVersion = 3
+	currentSchemaVersion = 9
 	databaseFileName     = "radiogogo.db"
+	// responseCacheMaxAge is how long cached API responses are kept at most.
+	// Older ones are deleted when the database is opened.
+	responseCacheMaxAge = 30 * 24 * time.Hour
 )
 
 // SQLiteStorage implements StationStorageService using SQLite.
@@ -42,16 +52,28 @@ type SQLiteStorage struct {
 	mu           sync.RWMutex
 	db           *sql.DB
 	bookmarks    map[uuid.UUID]bool
+	snapshots    map[uuid.UUID]common.Station
+	custom       map[uuid.UUID]common.Station
 	hidden       map[uuid.UUID]bool
+	health       map[uuid.UUID]health.Result
+	alarms       map[int64]schedule.Alarm
+	recordings   map[int64]schedule.Recording
 	lastVoteTime time.Time
 	hasLastVote  bool
+	// openSong is the row of the song logged last in this session, until it ends (0 if none)
+	openSong int64
 }
 
 // NewSQLiteStorage creates a new SQLiteStorage instance.
 func NewSQLiteStorage() (*SQLiteStorage, error) {
 	s := &SQLiteStorage{
-		bookmarks: make(map[uuid.UUID]bool),
-		hidden:    make(map[uuid.UUID]bool),
+		bookmarks:  make(map[uuid.UUID]bool),
+		snapshots:  make(map[uuid.UUID]common.Station),
+		custom:     make(map[uuid.UUID]common.Station),
+		hidden:     make(map[uuid.UUID]bool),
+		health:     make(map[uuid.UUID]health.Result),
+		alarms:     make(map[int64]schedule.Alarm),
+		recordings: make(map[int64]schedule.Recording),
 	}
 
 	// Ensure config directory exists
@@ -89,6 +111,12 @@ func NewSQLiteStorage() (*SQLiteStorage, error) {
 		return nil, err
 	}
 
+	// The response cache is disposable: it lives outside the versioned schema
+	if err := s.initResponseCache(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	// Load data into memory cache
 	if err := s.loadCaches(); err != nil {
 		db.Close()
@@ -137,7 +165,9 @@ func (s *SQLiteStorage) initSchema() error {
 		_, err = s.db.Exec(`
 			CREATE TABLE IF NOT EXISTS bookmarks (
 				station_uuid TEXT PRIMARY KEY,
-				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP,
+				station_snapshot TEXT,
+				snapshot_updated_at TEXT
 			);
 
 			CREATE TABLE IF NOT EXISTS hidden (
@@ -145,11 +175,77 @@ func (s *SQLiteStorage) initSchema() error {
 				created_at TEXT DEFAULT CURRENT_TIMESTAMP
 			);
 
+			CREATE TABLE IF NOT EXISTS custom_stations (
+				station_uuid TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				url TEXT NOT NULL,
+				codec TEXT NOT NULL DEFAULT '',
+				bitrate INTEGER NOT NULL DEFAULT 0,
+				tags TEXT NOT NULL DEFAULT '',
+				country_code TEXT NOT NULL DEFAULT '',
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+
 			CREATE TABLE IF NOT EXISTS last_vote (
 				id INTEGER PRIMARY KEY CHECK (id = 1),
 				voted_at TEXT NOT NULL
 			);
 
+			CREATE TABLE IF NOT EXISTS station_health (
+				station_uuid TEXT PRIMARY KEY,
+				status TEXT NOT NULL,
+				status_code INTEGER NOT NULL DEFAULT 0,
+				content_type TEXT NOT NULL DEFAULT '',
+				icy_name TEXT NOT NULL DEFAULT '',
+				icy_bitrate TEXT NOT NULL DEFAULT '',
+				latency_ms INTEGER NOT NULL DEFAULT 0,
+				error TEXT NOT NULL DEFAULT '',
+				checked_at TEXT NOT NULL
+			);
+
+			CREATE TABLE IF NOT EXISTS song_history (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_uuid TEXT NOT NULL,
+				station_name TEXT NOT NULL,
+				title TEXT NOT NULL,
+				started_at TEXT NOT NULL,
+				ended_at TEXT
+			);
+			CREATE INDEX IF NOT EXISTS song_history_started_at ON song_history (started_at);
+
+			CREATE TABLE IF NOT EXISTS alarms (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_snapshot TEXT NOT NULL,
+				hour INTEGER NOT NULL,
+				minute INTEGER NOT NULL,
+				days INTEGER NOT NULL DEFAULT 0,
+				volume INTEGER NOT NULL,
+				ramp_up_seconds INTEGER NOT NULL DEFAULT 0,
+				enabled INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE IF NOT EXISTS scheduled_recordings (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_snapshot TEXT NOT NULL,
+				start_at TEXT NOT NULL,
+				duration_seconds INTEGER NOT NULL,
+				days INTEGER NOT NULL DEFAULT 0,
+				enabled INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE IF NOT EXISTS recording_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				recording_id INTEGER NOT NULL,
+				scheduled_at TEXT NOT NULL,
+				started_at TEXT NOT NULL,
+				ended_at TEXT,
+				path TEXT NOT NULL,
+				error TEXT NOT NULL DEFAULT ''
+			);
+			CREATE INDEX IF NOT EXISTS recording_runs_recording_id ON recording_runs (recording_id);
+
 			INSERT INTO schema_version (version) VALUES (?);
 		`, currentSchemaVersion)
 		return err
@@ -187,15 +283,165 @@ func (s *SQLiteStorage) initSchema() error {
 		if err != nil {
 			return err
 		}
+		version = 3
+	}
+
+	if version < 4 {
+		// Migration from v3 to v4: store a station snapshot (JSON-encoded common.Station)
+		// with each bookmark so that bookmarks work without network access
+		_, err = s.db.Exec(`
+			ALTER TABLE bookmarks ADD COLUMN station_snapshot TEXT;
+			ALTER TABLE bookmarks ADD COLUMN snapshot_updated_at TEXT;
+			UPDATE schema_version SET version = 4;
+		`)
+		if err != nil {
+			return err
+		}
+		version = 4
+	}
+
+	if version < 5 {
+		// Migration from v4 to v5: add user-defined stations that aren't on RadioBrowser
+		_, err = s.db.Exec(`
+			CREATE TABLE IF NOT EXISTS custom_stations (
+				station_uuid TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				url TEXT NOT NULL,
+				codec TEXT NOT NULL DEFAULT '',
+				bitrate INTEGER NOT NULL DEFAULT 0,
+				tags TEXT NOT NULL DEFAULT '',
+				country_code TEXT NOT NULL DEFAULT '',
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+			UPDATE schema_version SET version = 5;
+		`)
+		if err != nil {
+			return err
+		}
+		version = 5
+	}
+
+	if version < 6 {
+		// Migration from v5 to v6: add the results of local stream health checks
+		_, err = s.db.Exec(`
+			CREATE TABLE IF NOT EXISTS station_health (
+				station_uuid TEXT PRIMARY KEY,
+				status TEXT NOT NULL,
+				status_code INTEGER NOT NULL DEFAULT 0,
+				content_type TEXT NOT NULL DEFAULT '',
+				icy_name TEXT NOT NULL DEFAULT '',
+				icy_bitrate TEXT NOT NULL DEFAULT '',
+				latency_ms INTEGER NOT NULL DEFAULT 0,
+				error TEXT NOT NULL DEFAULT '',
+				checked_at TEXT NOT NULL
+			);
+			UPDATE schema_version SET version = 6;
+		`)
+		if err != nil {
+			return err
+		}
+		version = 6
+	}
+
+	if version < 7 {
+		// Migration from v6 to v7: add the history of songs heard, read from stream metadata
+		_, err = s.db.Exec(`
+			CREATE TABLE IF NOT EXISTS song_history (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_uuid TEXT NOT NULL,
+				station_name TEXT NOT NULL,
+				title TEXT NOT NULL,
+				started_at TEXT NOT NULL,
+				ended_at TEXT
+			);
+			CREATE INDEX IF NOT EXISTS song_history_started_at ON song_history (started_at);
+			UPDATE schema_version SET version = 7;
+		`)
+		if err != nil {
+			return err
+		}
+		version = 7
+	}
+
+	if version < 8 {
+		// Migration from v7 to v8: add alarms that start playing a station at a time of day.
+		// The station is stored as a snapshot (JSON-encoded common.Station), like bookmarks.
+		_, err = s.db.Exec(`
+			CREATE TABLE IF NOT EXISTS alarms (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_snapshot TEXT NOT NULL,
+				hour INTEGER NOT NULL,
+				minute INTEGER NOT NULL,
+				days INTEGER NOT NULL DEFAULT 0,
+				volume INTEGER NOT NULL,
+				ramp_up_seconds INTEGER NOT NULL DEFAULT 0,
+				enabled INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+			UPDATE schema_version SET version = 8;
+		`)
+		if err != nil {
+			return err
+		}
+		version = 8
+	}
+
+	if version < 9 {
+		// Migration from v8 to v9: add scheduled recordings, and the runs that recorded them.
+		// Start times are RFC3339, the station is a snapshot like for alarms.
+		_, err = s.db.Exec(`
+			CREATE TABLE IF NOT EXISTS scheduled_recordings (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				station_snapshot TEXT NOT NULL,
+				start_at TEXT NOT NULL,
+				duration_seconds INTEGER NOT NULL,
+				days INTEGER NOT NULL DEFAULT 0,
+				enabled INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE IF NOT EXISTS recording_runs (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				recording_id INTEGER NOT NULL,
+				scheduled_at TEXT NOT NULL,
+				started_at TEXT NOT NULL,
+				ended_at TEXT,
+				path TEXT NOT NULL,
+				error TEXT NOT NULL DEFAULT ''
+			);
+			CREATE INDEX IF NOT EXISTS recording_runs_recording_id ON recording_runs (recording_id);
+			UPDATE schema_version SET version = 9;
+		`)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// initResponseCache creates the API response cache table if needed and
+// drops responses older than responseCacheMaxAge.
+func (s *SQLiteStorage) initResponseCache() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS response_cache (
+			cache_key TEXT PRIMARY KEY,
+			body BLOB NOT NULL,
+			stored_at TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("DELETE FROM response_cache WHERE stored_at < ?",
+		time.Now().Add(-responseCacheMaxAge).UTC().Format(time.RFC3339))
+	return err
+}
+
 // loadCaches loads bookmarks, hidden stations, and vote timestamps into memory.
 func (s *SQLiteStorage) loadCaches() error {
-	// Load bookmarks into cache
-	rows, err := s.db.Query("SELECT station_uuid FROM bookmarks")
+	// Load bookmarks (and their station snapshots) into cache
+	rows, err := s.db.Query("SELECT station_uuid, station_snapshot FROM bookmarks")
 	if err != nil {
 		return err
 	}
@@ -203,11 +449,57 @@ func (s *SQLiteStorage) loadCaches() error {
 
 	for rows.Next() {
 		var uuidStr string
-		if err := rows.Scan(&uuidStr); err != nil {
+		var snapshot sql.NullString
+		if err := rows.Scan(&uuidStr, &snapshot); err != nil {
 			continue
 		}
-		if id, err := uuid.Parse(uuidStr); err == nil {
-			s.bookmarks[id] = true
+		id, err := uuid.Parse(uuidStr)
+		if err != nil {
+			continue
+		}
+		s.bookmarks[id] = true
+		if snapshot.Valid {
+			var station common.Station
+			if err := json.Unmarshal([]byte(snapshot.String), &station); err == nil {
+				s.snapshots[id] = station
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Load custom stations into cache
+	rows, err = s.db.Query("SELECT station_uuid, name, url, codec, bitrate, tags, country_code FROM custom_stations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uuidStr, name, rawURL, codec, tags, countryCode string
+		var bitrate uint64
+		if err := rows.Scan(&uuidStr, &name, &rawURL, &codec, &bitrate, &tags, &countryCode); err != nil {
+			continue
+		}
+		id, err := uuid.Parse(uuidStr)
+		if err != nil {
+			continue
+		}
+		streamURL, err := url.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+		s.custom[id] = common.Station{
+			StationUuid: id,
+			Name:        name,
+			Url:         common.RadioGoGoURL{URL: *streamURL},
+			UrlResolved: common.RadioGoGoURL{URL: *streamURL},
+			Codec:       codec,
+			Bitrate:     bitrate,
+			Tags:        tags,
+			CountryCode: countryCode,
+			Source:      common.StationSourceCustom,
 		}
 	}
 	if err := rows.Err(); err != nil {
@@ -234,6 +526,97 @@ func (s *SQLiteStorage) loadCaches() error {
 		return err
 	}
 
+	// Load stream health check results into cache
+	rows, err = s.db.Query(`SELECT station_uuid, status, status_code, content_type, icy_name, icy_bitrate,
+		latency_ms, error, checked_at FROM station_health`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var uuidStr, checkedAt string
+		var result health.Result
+		var latencyMs int64
+		if err := rows.Scan(&uuidStr, &result.Status, &result.StatusCode, &result.ContentType, &result.IcyName,
+			&result.IcyBitrate, &latencyMs, &result.Error, &checkedAt); err != nil {
+			continue
+		}
+		id, err := uuid.Parse(uuidStr)
+		if err != nil {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, checkedAt)
+		if err != nil {
+			continue
+		}
+		result.StationUuid = id
+		result.Latency = time.Duration(latencyMs) * time.Millisecond
+		result.CheckedAt = t
+		s.health[id] = result
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Load alarms into cache
+	rows, err = s.db.Query(`SELECT id, station_snapshot, hour, minute, days, volume, ramp_up_seconds, enabled
+		FROM alarms`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alarm schedule.Alarm
+		var snapshot string
+		var rampUpSeconds int64
+		if err := rows.Scan(&alarm.ID, &snapshot, &alarm.Hour, &alarm.Minute, &alarm.Days, &alarm.Volume,
+			&rampUpSeconds, &alarm.Enabled); err != nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(snapshot), &alarm.Station); err != nil {
+			continue
+		}
+		alarm.RampUp = time.Duration(rampUpSeconds) * time.Second
+		s.alarms[alarm.ID] = alarm
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Load scheduled recordings into cache
+	rows, err = s.db.Query(`SELECT id, station_snapshot, start_at, duration_seconds, days, enabled
+		FROM scheduled_recordings`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recording schedule.Recording
+		var snapshot, startAt string
+		var durationSeconds int64
+		if err := rows.Scan(&recording.ID, &snapshot, &startAt, &durationSeconds, &recording.Days,
+			&recording.Enabled); err != nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(snapshot), &recording.Station); err != nil {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, startAt)
+		if err != nil {
+			continue
+		}
+		// Repeated recordings follow the local time of day, across daylight saving changes
+		recording.Start = start.Local()
+		recording.Duration = time.Duration(durationSeconds) * time.Second
+		s.recordings[recording.ID] = recording
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
 	// Load last vote timestamp into cache
 	var votedAt string
 	err = s.db.QueryRow("SELECT voted_at FROM last_vote WHERE id = 1").Scan(&votedAt)
@@ -295,6 +678,7 @@ func (s *SQLiteStorage) RemoveBookmark(stationUUID uuid.UUID) error {
 		return err
 	}
 	delete(s.bookmarks, stationUUID)
+	delete(s.snapshots, stationUUID)
 	return nil
 }
 
@@ -305,6 +689,118 @@ func (s *SQLiteStorage) IsBookmarked(stationUUID uuid.UUID) bool {
 	return s.bookmarks[stationUUID]
 }
 
+// GetBookmarkedStations returns the stored snapshot of every bookmarked station.
+func (s *SQLiteStorage) GetBookmarkedStations() ([]common.Station, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]common.Station, 0, len(s.snapshots))
+	for _, station := range s.snapshots {
+		result = append(result, station)
+	}
+	return result, nil
+}
+
+// SaveBookmarkSnapshots stores the given stations as the snapshots of their bookmarks.
+func (s *SQLiteStorage) SaveBookmarkSnapshots(stations []common.Station) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updatedAt := time.Now().Format(time.RFC3339)
+	for _, station := range stations {
+		if !s.bookmarks[station.StationUuid] {
+			continue
+		}
+		snapshot, err := json.Marshal(station)
+		if err != nil {
+			return err
+		}
+		_, err = s.db.Exec("UPDATE bookmarks SET station_snapshot = ?, snapshot_updated_at = ? WHERE station_uuid = ?",
+			string(snapshot), updatedAt, station.StationUuid.String())
+		if err != nil {
+			return err
+		}
+		s.snapshots[station.StationUuid] = station
+	}
+	return nil
+}
+
+// GetCustomStations returns the stations added by the user, sorted by name.
+func (s *SQLiteStorage) GetCustomStations() ([]common.Station, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]common.Station, 0, len(s.custom))
+	for _, station := range s.custom {
+		result = append(result, station)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := strings.ToLower(result[i].Name), strings.ToLower(result[j].Name)
+		if a != b {
+			return a < b
+		}
+		return result[i].StationUuid.String() < result[j].StationUuid.String()
+	})
+	return result, nil
+}
+
+// SaveCustomStation adds a custom station, or updates the one with the same UUID.
+// Only the fields the user can edit are stored; the stream URL doubles as the resolved one.
+func (s *SQLiteStorage) SaveCustomStation(station common.Station) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`INSERT INTO custom_stations (station_uuid, name, url, codec, bitrate, tags, country_code)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(station_uuid) DO UPDATE SET
+			name = excluded.name, url = excluded.url, codec = excluded.codec, bitrate = excluded.bitrate,
+			tags = excluded.tags, country_code = excluded.country_code`,
+		station.StationUuid.String(), station.Name, station.Url.URL.String(), station.Codec,
+		station.Bitrate, station.Tags, station.CountryCode)
+	if err != nil {
+		return err
+	}
+	s.custom[station.StationUuid] = common.Station{
+		StationUuid: station.StationUuid,
+		Name:        station.Name,
+		Url:         station.Url,
+		UrlResolved: station.Url,
+		Codec:       station.Codec,
+		Bitrate:     station.Bitrate,
+		Tags:        station.Tags,
+		CountryCode: station.CountryCode,
+		Source:      common.StationSourceCustom,
+	}
+	return nil
+}
+
+// DeleteCustomStation removes a custom station, along with its bookmark.
+func (s *SQLiteStorage) DeleteCustomStation(stationUUID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("DELETE FROM custom_stations WHERE station_uuid = ?", stationUUID.String())
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec("DELETE FROM bookmarks WHERE station_uuid = ?", stationUUID.String())
+	if err != nil {
+		return err
+	}
+	delete(s.custom, stationUUID)
+	delete(s.bookmarks, stationUUID)
+	delete(s.snapshots, stationUUID)
+	return nil
+}
+
+// IsCustomStation returns true if the station was added by the user.
+func (s *SQLiteStorage) IsCustomStation(stationUUID uuid.UUID) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.custom[stationUUID]
+	return ok
+}
+
 // GetHidden returns all hidden station UUIDs.
 func (s *SQLiteStorage) GetHidden() ([]uuid.UUID, error) {
 	s.mu.RLock()
@@ -352,6 +848,317 @@ func (s *SQLiteStorage) IsHidden(stationUUID uuid.UUID) bool {
 	return s.hidden[stationUUID]
 }
 
+// GetHealthResult returns the latest local health check of a station's stream.
+func (s *SQLiteStorage) GetHealthResult(stationUUID uuid.UUID) (health.Result, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result, ok := s.health[stationUUID]
+	return result, ok
+}
+
+// SaveHealthResults stores the given health checks, replacing older ones of the same stations.
+func (s *SQLiteStorage) SaveHealthResults(results []health.Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, result := range results {
+		_, err := s.db.Exec(`INSERT OR REPLACE INTO station_health
+			(station_uuid, status, status_code, content_type, icy_name, icy_bitrate, latency_ms, error, checked_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			result.StationUuid.String(), string(result.Status), result.StatusCode, result.ContentType,
+			result.IcyName, result.IcyBitrate, result.Latency.Milliseconds(), result.Error,
+			result.CheckedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		// Stored with second precision, so keep the same in memory
+		result.CheckedAt = result.CheckedAt.Truncate(time.Second)
+		s.health[result.StationUuid] = result
+	}
+	return nil
+}
+
+// StartSong logs a song heard on a station, ending the song logged before it.
+func (s *SQLiteStorage) StartSong(entry common.SongHistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.endOpenSong(entry.StartedAt); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`INSERT INTO song_history (station_uuid, station_name, title, started_at)
+		VALUES (?, ?, ?, ?)`,
+		entry.StationUuid.String(), entry.StationName, entry.Title, entry.StartedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	s.openSong, err = result.LastInsertId()
+	return err
+}
+
+// EndSong ends the song logged last, if it hasn't ended yet.
+func (s *SQLiteStorage) EndSong(endedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.endOpenSong(endedAt)
+}
+
+// endOpenSong records when the song logged last ended. Callers must hold the write lock.
+func (s *SQLiteStorage) endOpenSong(endedAt time.Time) error {
+	if s.openSong == 0 {
+		return nil
+	}
+	_, err := s.db.Exec("UPDATE song_history SET ended_at = ? WHERE id = ?",
+		endedAt.UTC().Format(time.RFC3339), s.openSong)
+	if err != nil {
+		return err
+	}
+	s.openSong = 0
+	return nil
+}
+
+// GetSongHistory returns the songs heard, newest first, optionally filtered by a search text.
+func (s *SQLiteStorage) GetSongHistory(search string) ([]common.SongHistoryEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`SELECT station_uuid, station_name, title, started_at, ended_at
+		FROM song_history ORDER BY started_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Matched here rather than with LIKE, which only ignores the case of ASCII letters
+	search = strings.ToLower(strings.TrimSpace(search))
+
+	entries := []common.SongHistoryEntry{}
+	for rows.Next() {
+		var uuidStr, stationName, title, startedAt string
+		var endedAt sql.NullString
+		if err := rows.Scan(&uuidStr, &stationName, &title, &startedAt, &endedAt); err != nil {
+			return nil, err
+		}
+		if search != "" && !strings.Contains(strings.ToLower(title), search) &&
+			!strings.Contains(strings.ToLower(stationName), search) {
+			continue
+		}
+		entry := common.SongHistoryEntry{
+			StationName: stationName,
+			Title:       title,
+		}
+		entry.StationUuid, _ = uuid.Parse(uuidStr)
+		entry.StartedAt, _ = time.Parse(time.RFC3339, startedAt)
+		if endedAt.Valid {
+			entry.EndedAt, _ = time.Parse(time.RFC3339, endedAt.String)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// GetAlarms returns the alarms, sorted by the time of day they ring at.
+func (s *SQLiteStorage) GetAlarms() ([]schedule.Alarm, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]schedule.Alarm, 0, len(s.alarms))
+	for _, alarm := range s.alarms {
+		result = append(result, alarm)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Hour != b.Hour {
+			return a.Hour < b.Hour
+		}
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		return a.ID < b.ID
+	})
+	return result, nil
+}
+
+// SaveAlarm adds an alarm (when its ID is 0), or updates the one with the same ID.
+func (s *SQLiteStorage) SaveAlarm(alarm schedule.Alarm) (schedule.Alarm, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := json.Marshal(alarm.Station)
+	if err != nil {
+		return schedule.Alarm{}, err
+	}
+	rampUpSeconds := int64(alarm.RampUp / time.Second)
+
+	if alarm.ID == 0 {
+		result, err := s.db.Exec(`INSERT INTO alarms (station_snapshot, hour, minute, days, volume, ramp_up_seconds, enabled)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			string(snapshot), alarm.Hour, alarm.Minute, alarm.Days, alarm.Volume, rampUpSeconds, alarm.Enabled)
+		if err != nil {
+			return schedule.Alarm{}, err
+		}
+		if alarm.ID, err = result.LastInsertId(); err != nil {
+			return schedule.Alarm{}, err
+		}
+	} else {
+		_, err := s.db.Exec(`UPDATE alarms SET station_snapshot = ?, hour = ?, minute = ?, days = ?, volume = ?,
+			ramp_up_seconds = ?, enabled = ? WHERE id = ?`,
+			string(snapshot), alarm.Hour, alarm.Minute, alarm.Days, alarm.Volume, rampUpSeconds, alarm.Enabled, alarm.ID)
+		if err != nil {
+			return schedule.Alarm{}, err
+		}
+	}
+	// Stored with second precision, so keep the same in memory
+	alarm.RampUp = time.Duration(rampUpSeconds) * time.Second
+	s.alarms[alarm.ID] = alarm
+	return alarm, nil
+}
+
+// DeleteAlarm removes an alarm.
+func (s *SQLiteStorage) DeleteAlarm(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.db.Exec("DELETE FROM alarms WHERE id = ?", id); err != nil {
+		return err
+	}
+	delete(s.alarms, id)
+	return nil
+}
+
+// GetScheduledRecordings returns the scheduled recordings, sorted by their first start.
+func (s *SQLiteStorage) GetScheduledRecordings() ([]schedule.Recording, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]schedule.Recording, 0, len(s.recordings))
+	for _, recording := range s.recordings {
+		result = append(result, recording)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.ID < b.ID
+	})
+	return result, nil
+}
+
+// SaveScheduledRecording adds a scheduled recording (when its ID is 0), or updates the one with the same ID.
+func (s *SQLiteStorage) SaveScheduledRecording(recording schedule.Recording) (schedule.Recording, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := json.Marshal(recording.Station)
+	if err != nil {
+		return schedule.Recording{}, err
+	}
+	startAt := recording.Start.Format(time.RFC3339)
+	durationSeconds := int64(recording.Duration / time.Second)
+
+	if recording.ID == 0 {
+		result, err := s.db.Exec(`INSERT INTO scheduled_recordings (station_snapshot, start_at, duration_seconds, days, enabled)
+			VALUES (?, ?, ?, ?, ?)`,
+			string(snapshot), startAt, durationSeconds, recording.Days, recording.Enabled)
+		if err != nil {
+			return schedule.Recording{}, err
+		}
+		if recording.ID, err = result.LastInsertId(); err != nil {
+			return schedule.Recording{}, err
+		}
+	} else {
+		_, err := s.db.Exec(`UPDATE scheduled_recordings SET station_snapshot = ?, start_at = ?, duration_seconds = ?,
+			days = ?, enabled = ? WHERE id = ?`,
+			string(snapshot), startAt, durationSeconds, recording.Days, recording.Enabled, recording.ID)
+		if err != nil {
+			return schedule.Recording{}, err
+		}
+	}
+	// Stored with second precision, so keep the same in memory
+	recording.Start = recording.Start.Truncate(time.Second)
+	recording.Duration = time.Duration(durationSeconds) * time.Second
+	s.recordings[recording.ID] = recording
+	return recording, nil
+}
+
+// DeleteScheduledRecording removes a scheduled recording and the record of its runs.
+func (s *SQLiteStorage) DeleteScheduledRecording(id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec(`
+		DELETE FROM recording_runs WHERE recording_id = ?;
+		DELETE FROM scheduled_recordings WHERE id = ?;
+	`, id, id)
+	if err != nil {
+		return err
+	}
+	delete(s.recordings, id)
+	return nil
+}
+
+// GetRecordingRuns returns the runs of a scheduled recording, newest first.
+func (s *SQLiteStorage) GetRecordingRuns(recordingID int64) ([]schedule.RecordingRun, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.Query(`SELECT id, scheduled_at, started_at, ended_at, path, error FROM recording_runs
+		WHERE recording_id = ? ORDER BY started_at DESC, id DESC`, recordingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []schedule.RecordingRun{}
+	for rows.Next() {
+		var scheduledAt, startedAt string
+		var endedAt sql.NullString
+		run := schedule.RecordingRun{RecordingID: recordingID}
+		if err := rows.Scan(&run.ID, &scheduledAt, &startedAt, &endedAt, &run.Path, &run.Err); err != nil {
+			return nil, err
+		}
+		run.Scheduled, _ = time.Parse(time.RFC3339, scheduledAt)
+		run.Start, _ = time.Parse(time.RFC3339, startedAt)
+		if endedAt.Valid {
+			run.End, _ = time.Parse(time.RFC3339, endedAt.String)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// SaveRecordingRun adds a run of a scheduled recording (when its ID is 0), or updates the one with the same ID.
+func (s *SQLiteStorage) SaveRecordingRun(run schedule.RecordingRun) (schedule.RecordingRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var endedAt sql.NullString
+	if !run.End.IsZero() {
+		endedAt = sql.NullString{String: run.End.Format(time.RFC3339), Valid: true}
+	}
+
+	if run.ID == 0 {
+		result, err := s.db.Exec(`INSERT INTO recording_runs (recording_id, scheduled_at, started_at, ended_at, path, error)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			run.RecordingID, run.Scheduled.Format(time.RFC3339), run.Start.Format(time.RFC3339), endedAt, run.Path, run.Err)
+		if err != nil {
+			return schedule.RecordingRun{}, err
+		}
+		if run.ID, err = result.LastInsertId(); err != nil {
+			return schedule.RecordingRun{}, err
+		}
+		return run, nil
+	}
+	_, err := s.db.Exec(`UPDATE recording_runs SET recording_id = ?, scheduled_at = ?, started_at = ?, ended_at = ?,
+		path = ?, error = ? WHERE id = ?`,
+		run.RecordingID, run.Scheduled.Format(time.RFC3339), run.Start.Format(time.RFC3339), endedAt, run.Path, run.Err, run.ID)
+	if err != nil {
+		return schedule.RecordingRun{}, err
+	}
+	return run, nil
+}
+
 // GetLastVoteTimestamp returns the last global vote timestamp.
 // Returns the timestamp and true if found, zero time and false if not.
 func (s *SQLiteStorage) GetLastVoteTimestamp() (time.Time, bool) {
@@ -374,3 +1181,32 @@ func (s *SQLiteStorage) SetLastVoteTimestamp(timestamp time.Time) error {
 	s.hasLastVote = true
 	return nil
 }
+
+// GetCachedResponse returns the API response stored under key and when it was stored.
+func (s *SQLiteStorage) GetCachedResponse(key string) ([]byte, time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var body []byte
+	var storedAt string
+	err := s.db.QueryRow("SELECT body, stored_at FROM response_cache WHERE cache_key = ?", key).
+		Scan(&body, &storedAt)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, storedAt)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return body, t, true
+}
+
+// PutCachedResponse stores an API response under key, replacing any previous one.
+func (s *SQLiteStorage) PutCachedResponse(key string, data []byte, storedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.Exec("INSERT OR REPLACE INTO response_cache (cache_key, body, stored_at) VALUES (?, ?, ?)",
+		key, data, storedAt.UTC().Format(time.RFC3339))
+	return err
+}
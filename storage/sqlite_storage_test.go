@@ -20,6 +20,9 @@
 package storage
 
 import (
+	"database/sql"
+	"encoding/json"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
@@ -28,6 +31,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/health"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 )
 
 func TestSQLiteStorage_Bookmarks(t *testing.T) {
@@ -577,3 +583,779 @@ func TestSQLiteStorage_NilUUID(t *testing.T) {
 		assert.False(t, s.IsHidden(uuid.Nil))
 	})
 }
+
+func TestSQLiteStorage_ResponseCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	t.Run("returns false when nothing is cached", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		_, _, found := s.GetCachedResponse("stations/bytag/jazz")
+		assert.False(t, found)
+	})
+
+	t.Run("stores, replaces and persists responses", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		storedAt := time.Now().Truncate(time.Second)
+		assert.NoError(t, s.PutCachedResponse("stations/bytag/jazz", []byte(`[1]`), storedAt.Add(-time.Minute)))
+		assert.NoError(t, s.PutCachedResponse("stations/bytag/jazz", []byte(`[1,2]`), storedAt))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		data, gotStoredAt, found := s.GetCachedResponse("stations/bytag/jazz")
+		assert.True(t, found)
+		assert.Equal(t, []byte(`[1,2]`), data)
+		assert.True(t, storedAt.Equal(gotStoredAt))
+	})
+
+	t.Run("drops responses older than the maximum age on open", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		assert.NoError(t, s.PutCachedResponse("old", []byte(`[]`), time.Now().Add(-responseCacheMaxAge-time.Hour)))
+		assert.NoError(t, s.PutCachedResponse("recent", []byte(`[]`), time.Now().Add(-time.Hour)))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		_, _, found := s.GetCachedResponse("old")
+		assert.False(t, found)
+		_, _, found = s.GetCachedResponse("recent")
+		assert.True(t, found)
+	})
+}
+
+func TestSQLiteStorage_BookmarkSnapshots(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	newStation := func(name string) common.Station {
+		var station common.Station
+		assert.NoError(t, json.Unmarshal([]byte(`{"name":"`+name+`","url":"http://example.com/`+name+`","codec":"MP3","bitrate":128,"tags":"jazz,smooth","countrycode":"DE"}`), &station))
+		station.StationUuid = uuid.New()
+		return station
+	}
+
+	t.Run("stores snapshots of bookmarked stations only", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		bookmarked := newStation("jazz")
+		other := newStation("rock")
+		assert.NoError(t, s.AddBookmark(bookmarked.StationUuid))
+
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{bookmarked, other}))
+
+		stations, err := s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{bookmarked}, stations)
+	})
+
+	t.Run("persists snapshots across reload", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		station := newStation("jazz")
+		assert.NoError(t, s.AddBookmark(station.StationUuid))
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{station}))
+		station.Name = "Jazz (renamed)"
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{station}))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		stations, err := s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{station}, stations)
+		assert.Equal(t, "http://example.com/jazz", stations[0].Url.URL.String())
+	})
+
+	t.Run("removing a bookmark drops its snapshot", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		station := newStation("jazz")
+		assert.NoError(t, s.AddBookmark(station.StationUuid))
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{station}))
+		assert.NoError(t, s.RemoveBookmark(station.StationUuid))
+
+		stations, err := s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+
+		// Bookmarking it again doesn't bring back the old snapshot
+		assert.NoError(t, s.AddBookmark(station.StationUuid))
+		stations, err = s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+	})
+
+	t.Run("migrates a v3 database keeping its bookmarks", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		id := uuid.New()
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			INSERT INTO schema_version (version) VALUES (3);
+		`)
+		assert.NoError(t, err)
+		_, err = db.Exec("INSERT INTO bookmarks (station_uuid) VALUES (?)", id.String())
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		assert.True(t, s.IsBookmarked(id))
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		station := newStation("jazz")
+		station.StationUuid = id
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{station}))
+		stations, err := s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{station}, stations)
+	})
+}
+
+func TestSQLiteStorage_CustomStations(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	newStation := func(name string) common.Station {
+		u, err := url.Parse("http://stream.example.com/" + name)
+		assert.NoError(t, err)
+		return common.Station{
+			StationUuid: uuid.New(),
+			Name:        name,
+			Url:         common.RadioGoGoURL{URL: *u},
+			UrlResolved: common.RadioGoGoURL{URL: *u},
+			Codec:       "MP3",
+			Bitrate:     128,
+			Tags:        "team,internal",
+			CountryCode: "IT",
+			Source:      common.StationSourceCustom,
+		}
+	}
+
+	t.Run("adds and lists custom stations sorted by name", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		office := newStation("office")
+		lobby := newStation("Lobby")
+		assert.NoError(t, s.SaveCustomStation(office))
+		assert.NoError(t, s.SaveCustomStation(lobby))
+
+		stations, err := s.GetCustomStations()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{lobby, office}, stations)
+		assert.True(t, s.IsCustomStation(office.StationUuid))
+		assert.False(t, s.IsCustomStation(uuid.New()))
+	})
+
+	t.Run("updates a station and persists it across reload", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		station := newStation("office")
+		assert.NoError(t, s.SaveCustomStation(station))
+		station.Name = "Office (HQ)"
+		station.Bitrate = 320
+		assert.NoError(t, s.SaveCustomStation(station))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		stations, err := s.GetCustomStations()
+		assert.NoError(t, err)
+		assert.Equal(t, []common.Station{station}, stations)
+		assert.Equal(t, "http://stream.example.com/office", stations[0].Url.URL.String())
+	})
+
+	t.Run("only stores the editable fields", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		station := newStation("office")
+		station.Votes = 42
+		station.LastCheckOk = true
+		assert.NoError(t, s.SaveCustomStation(station))
+
+		stations, err := s.GetCustomStations()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), stations[0].Votes)
+		assert.False(t, bool(stations[0].LastCheckOk))
+	})
+
+	t.Run("deleting a station removes its bookmark", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		station := newStation("office")
+		assert.NoError(t, s.SaveCustomStation(station))
+		assert.NoError(t, s.AddBookmark(station.StationUuid))
+		assert.NoError(t, s.SaveBookmarkSnapshots([]common.Station{station}))
+
+		assert.NoError(t, s.DeleteCustomStation(station.StationUuid))
+
+		assert.False(t, s.IsCustomStation(station.StationUuid))
+		assert.False(t, s.IsBookmarked(station.StationUuid))
+		snapshots, err := s.GetBookmarkedStations()
+		assert.NoError(t, err)
+		assert.Empty(t, snapshots)
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		stations, err := s.GetCustomStations()
+		assert.NoError(t, err)
+		assert.Empty(t, stations)
+		assert.False(t, s.IsBookmarked(station.StationUuid))
+	})
+
+	t.Run("migrates a v4 database", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		id := uuid.New()
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP, station_snapshot TEXT, snapshot_updated_at TEXT);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			INSERT INTO schema_version (version) VALUES (4);
+		`)
+		assert.NoError(t, err)
+		_, err = db.Exec("INSERT INTO bookmarks (station_uuid) VALUES (?)", id.String())
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		assert.True(t, s.IsBookmarked(id))
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		station := newStation("office")
+		assert.NoError(t, s.SaveCustomStation(station))
+		assert.True(t, s.IsCustomStation(station.StationUuid))
+	})
+}
+
+func TestSQLiteStorage_Health(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	checkedAt := time.Date(2026, 3, 1, 12, 30, 15, 0, time.UTC)
+
+	t.Run("returns false for stations never checked", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		_, found := s.GetHealthResult(uuid.New())
+		assert.False(t, found)
+	})
+
+	t.Run("stores, replaces and persists results", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		id := uuid.New()
+		failed := health.Result{
+			StationUuid: id,
+			Status:      health.StatusHTTPError,
+			StatusCode:  404,
+			Error:       "server answered 404 Not Found",
+			CheckedAt:   checkedAt,
+		}
+		ok := health.Result{
+			StationUuid: id,
+			Status:      health.StatusOK,
+			StatusCode:  200,
+			ContentType: "audio/mpeg",
+			IcyName:     "Test FM",
+			IcyBitrate:  "128",
+			Latency:     250 * time.Millisecond,
+			CheckedAt:   checkedAt.Add(time.Hour),
+		}
+
+		assert.NoError(t, s.SaveHealthResults([]health.Result{failed}))
+		result, found := s.GetHealthResult(id)
+		assert.True(t, found)
+		assert.Equal(t, failed, result)
+
+		assert.NoError(t, s.SaveHealthResults([]health.Result{ok}))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		result, found = s.GetHealthResult(id)
+		assert.True(t, found)
+		assert.Equal(t, ok, result)
+	})
+
+	t.Run("migrates a v5 database", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP, station_snapshot TEXT, snapshot_updated_at TEXT);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE custom_stations (station_uuid TEXT PRIMARY KEY, name TEXT NOT NULL, url TEXT NOT NULL, codec TEXT NOT NULL DEFAULT '', bitrate INTEGER NOT NULL DEFAULT 0, tags TEXT NOT NULL DEFAULT '', country_code TEXT NOT NULL DEFAULT '', created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			INSERT INTO schema_version (version) VALUES (5);
+		`)
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		result := health.Result{StationUuid: uuid.New(), Status: health.StatusOK, CheckedAt: checkedAt}
+		assert.NoError(t, s.SaveHealthResults([]health.Result{result}))
+		stored, found := s.GetHealthResult(result.StationUuid)
+		assert.True(t, found)
+		assert.Equal(t, result, stored)
+	})
+}
+
+func TestSQLiteStorage_SongHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	station := uuid.New()
+	startedAt := time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC)
+
+	t.Run("logs songs, ending each when the next starts", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		first := common.SongHistoryEntry{StationUuid: station, StationName: "Team Radio", Title: "Daft Punk - One More Time", StartedAt: startedAt}
+		second := common.SongHistoryEntry{StationUuid: station, StationName: "Team Radio", Title: "Air - Playground Love", StartedAt: startedAt.Add(5 * time.Minute)}
+		assert.NoError(t, s.StartSong(first))
+		assert.NoError(t, s.StartSong(second))
+
+		history, err := s.GetSongHistory("")
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, second, history[0])
+		first.EndedAt = second.StartedAt
+		assert.Equal(t, first, history[1])
+
+		// Ending twice only ends the open song
+		assert.NoError(t, s.EndSong(startedAt.Add(9*time.Minute)))
+		assert.NoError(t, s.EndSong(startedAt.Add(20*time.Minute)))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		history, err = s.GetSongHistory("")
+		assert.NoError(t, err)
+		assert.Equal(t, startedAt.Add(9*time.Minute), history[0].EndedAt)
+		assert.Equal(t, first, history[1])
+	})
+
+	t.Run("searches titles and station names ignoring case", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		assert.NoError(t, s.StartSong(common.SongHistoryEntry{StationUuid: station, StationName: "Team Radio", Title: "Björk - Jóga", StartedAt: startedAt}))
+		assert.NoError(t, s.StartSong(common.SongHistoryEntry{StationUuid: uuid.New(), StationName: "Jazz FM", Title: "Miles Davis - So What", StartedAt: startedAt.Add(time.Minute)}))
+
+		history, err := s.GetSongHistory("JÓGA")
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+		assert.Equal(t, "Björk - Jóga", history[0].Title)
+
+		history, err = s.GetSongHistory(" jazz ")
+		assert.NoError(t, err)
+		assert.Len(t, history, 1)
+		assert.Equal(t, "Miles Davis - So What", history[0].Title)
+
+		history, err = s.GetSongHistory("nothing like this")
+		assert.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("migrates a v6 database", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP, station_snapshot TEXT, snapshot_updated_at TEXT);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE custom_stations (station_uuid TEXT PRIMARY KEY, name TEXT NOT NULL, url TEXT NOT NULL, codec TEXT NOT NULL DEFAULT '', bitrate INTEGER NOT NULL DEFAULT 0, tags TEXT NOT NULL DEFAULT '', country_code TEXT NOT NULL DEFAULT '', created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			CREATE TABLE station_health (station_uuid TEXT PRIMARY KEY, status TEXT NOT NULL, status_code INTEGER NOT NULL DEFAULT 0, content_type TEXT NOT NULL DEFAULT '', icy_name TEXT NOT NULL DEFAULT '', icy_bitrate TEXT NOT NULL DEFAULT '', latency_ms INTEGER NOT NULL DEFAULT 0, error TEXT NOT NULL DEFAULT '', checked_at TEXT NOT NULL);
+			INSERT INTO schema_version (version) VALUES (6);
+		`)
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		entry := common.SongHistoryEntry{StationUuid: station, StationName: "Team Radio", Title: "Song", StartedAt: startedAt}
+		assert.NoError(t, s.StartSong(entry))
+		history, err := s.GetSongHistory("")
+		assert.NoError(t, err)
+		assert.Equal(t, []common.SongHistoryEntry{entry}, history)
+	})
+}
+
+func TestSQLiteStorage_Alarms(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	streamURL, _ := url.Parse("https://stream.example.com/live.mp3")
+	station := common.Station{
+		StationUuid: uuid.New(),
+		Name:        "Morning FM",
+		Url:         common.RadioGoGoURL{URL: *streamURL},
+		UrlResolved: common.RadioGoGoURL{URL: *streamURL},
+		Codec:       "MP3",
+		Bitrate:     128,
+	}
+	weekdays := schedule.Weekdays(0).Toggle(time.Monday).Toggle(time.Friday)
+
+	t.Run("adds alarms and lists them by time of day", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		late, err := s.SaveAlarm(schedule.Alarm{Station: station, Hour: 9, Minute: 0, Volume: 60, Enabled: true})
+		assert.NoError(t, err)
+		early, err := s.SaveAlarm(schedule.Alarm{Station: station, Hour: 6, Minute: 45, Days: weekdays, Volume: 40, RampUp: 5 * time.Minute, Enabled: true})
+		assert.NoError(t, err)
+		assert.NotZero(t, late.ID)
+		assert.NotEqual(t, late.ID, early.ID)
+
+		alarms, err := s.GetAlarms()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Alarm{early, late}, alarms)
+	})
+
+	t.Run("updates, deletes and persists alarms across reload", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		alarm, err := s.SaveAlarm(schedule.Alarm{Station: station, Hour: 7, Minute: 30, Days: weekdays, Volume: 50, RampUp: 90 * time.Second, Enabled: true})
+		assert.NoError(t, err)
+		deleted, err := s.SaveAlarm(schedule.Alarm{Station: station, Hour: 8, Minute: 0, Volume: 50, Enabled: true})
+		assert.NoError(t, err)
+
+		alarm.Enabled = false
+		alarm.Hour = 6
+		_, err = s.SaveAlarm(alarm)
+		assert.NoError(t, err)
+		assert.NoError(t, s.DeleteAlarm(deleted.ID))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		alarms, err := s.GetAlarms()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Alarm{alarm}, alarms)
+	})
+
+	t.Run("migrates a v7 database", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP, station_snapshot TEXT, snapshot_updated_at TEXT);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE custom_stations (station_uuid TEXT PRIMARY KEY, name TEXT NOT NULL, url TEXT NOT NULL, codec TEXT NOT NULL DEFAULT '', bitrate INTEGER NOT NULL DEFAULT 0, tags TEXT NOT NULL DEFAULT '', country_code TEXT NOT NULL DEFAULT '', created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			CREATE TABLE station_health (station_uuid TEXT PRIMARY KEY, status TEXT NOT NULL, status_code INTEGER NOT NULL DEFAULT 0, content_type TEXT NOT NULL DEFAULT '', icy_name TEXT NOT NULL DEFAULT '', icy_bitrate TEXT NOT NULL DEFAULT '', latency_ms INTEGER NOT NULL DEFAULT 0, error TEXT NOT NULL DEFAULT '', checked_at TEXT NOT NULL);
+			CREATE TABLE song_history (id INTEGER PRIMARY KEY AUTOINCREMENT, station_uuid TEXT NOT NULL, station_name TEXT NOT NULL, title TEXT NOT NULL, started_at TEXT NOT NULL, ended_at TEXT);
+			INSERT INTO schema_version (version) VALUES (7);
+		`)
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		alarm, err := s.SaveAlarm(schedule.Alarm{Station: station, Hour: 7, Minute: 0, Volume: 80, Enabled: true})
+		assert.NoError(t, err)
+		alarms, err := s.GetAlarms()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Alarm{alarm}, alarms)
+	})
+}
+
+func TestSQLiteStorage_ScheduledRecordings(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	os.Setenv("HOME", tmpDir)
+	defer os.Setenv("HOME", origHome)
+
+	configDir := filepath.Join(tmpDir, ".config", "radiogogo")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	streamURL, _ := url.Parse("https://stream.example.com/live.mp3")
+	station := common.Station{
+		StationUuid: uuid.New(),
+		Name:        "Jazz FM",
+		Url:         common.RadioGoGoURL{URL: *streamURL},
+		UrlResolved: common.RadioGoGoURL{URL: *streamURL},
+		Codec:       "MP3",
+		Bitrate:     128,
+	}
+	weekend := schedule.Weekdays(0).Toggle(time.Saturday).Toggle(time.Sunday)
+	saturday := time.Date(2026, 3, 7, 21, 0, 0, 0, time.Local)
+
+	t.Run("adds recordings and lists them by start", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		late, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday, Duration: time.Hour, Enabled: true})
+		assert.NoError(t, err)
+		early, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday.Add(-24 * time.Hour), Duration: 2 * time.Hour, Days: weekend, Enabled: true})
+		assert.NoError(t, err)
+		assert.NotZero(t, late.ID)
+		assert.NotEqual(t, late.ID, early.ID)
+
+		recordings, err := s.GetScheduledRecordings()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Recording{early, late}, recordings)
+	})
+
+	t.Run("updates, deletes and persists recordings across reload", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+
+		recording, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday, Duration: 90 * time.Minute, Days: weekend, Enabled: true})
+		assert.NoError(t, err)
+		deleted, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday, Duration: time.Hour, Enabled: true})
+		assert.NoError(t, err)
+
+		recording.Enabled = false
+		recording.Start = saturday.Add(30 * time.Minute)
+		_, err = s.SaveScheduledRecording(recording)
+		assert.NoError(t, err)
+		assert.NoError(t, s.DeleteScheduledRecording(deleted.ID))
+		s.Close()
+
+		s, err = NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		recordings, err := s.GetScheduledRecordings()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Recording{recording}, recordings)
+	})
+
+	t.Run("records runs, newest first", func(t *testing.T) {
+		os.Remove(filepath.Join(configDir, databaseFileName))
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		recording, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday, Duration: time.Hour, Days: weekend, Enabled: true})
+		assert.NoError(t, err)
+
+		first, err := s.SaveRecordingRun(schedule.RecordingRun{RecordingID: recording.ID, Scheduled: saturday, Start: saturday, Path: "jazz_fm-1.mp3"})
+		assert.NoError(t, err)
+		assert.NotZero(t, first.ID)
+		first.End = saturday.Add(time.Hour)
+		_, err = s.SaveRecordingRun(first)
+		assert.NoError(t, err)
+		second, err := s.SaveRecordingRun(schedule.RecordingRun{RecordingID: recording.ID, Scheduled: saturday.Add(24 * time.Hour), Start: saturday.Add(24*time.Hour + time.Minute), Path: "jazz_fm-2.mp3"})
+		assert.NoError(t, err)
+
+		runs, err := s.GetRecordingRuns(recording.ID)
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+		assert.Equal(t, second.ID, runs[0].ID)
+		assert.True(t, runs[0].End.IsZero())
+		assert.Equal(t, "jazz_fm-1.mp3", runs[1].Path)
+		assert.True(t, first.End.Equal(runs[1].End))
+		assert.True(t, saturday.Equal(runs[1].Scheduled))
+
+		// Deleting the recording forgets its runs
+		assert.NoError(t, s.DeleteScheduledRecording(recording.ID))
+		runs, err = s.GetRecordingRuns(recording.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, runs)
+	})
+
+	t.Run("migrates a v8 database", func(t *testing.T) {
+		dbPath := filepath.Join(configDir, databaseFileName)
+		os.Remove(dbPath)
+
+		db, err := sql.Open("sqlite", dbPath)
+		assert.NoError(t, err)
+		_, err = db.Exec(`
+			CREATE TABLE schema_version (version INTEGER PRIMARY KEY);
+			CREATE TABLE bookmarks (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP, station_snapshot TEXT, snapshot_updated_at TEXT);
+			CREATE TABLE hidden (station_uuid TEXT PRIMARY KEY, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE custom_stations (station_uuid TEXT PRIMARY KEY, name TEXT NOT NULL, url TEXT NOT NULL, codec TEXT NOT NULL DEFAULT '', bitrate INTEGER NOT NULL DEFAULT 0, tags TEXT NOT NULL DEFAULT '', country_code TEXT NOT NULL DEFAULT '', created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			CREATE TABLE last_vote (id INTEGER PRIMARY KEY CHECK (id = 1), voted_at TEXT NOT NULL);
+			CREATE TABLE station_health (station_uuid TEXT PRIMARY KEY, status TEXT NOT NULL, status_code INTEGER NOT NULL DEFAULT 0, content_type TEXT NOT NULL DEFAULT '', icy_name TEXT NOT NULL DEFAULT '', icy_bitrate TEXT NOT NULL DEFAULT '', latency_ms INTEGER NOT NULL DEFAULT 0, error TEXT NOT NULL DEFAULT '', checked_at TEXT NOT NULL);
+			CREATE TABLE song_history (id INTEGER PRIMARY KEY AUTOINCREMENT, station_uuid TEXT NOT NULL, station_name TEXT NOT NULL, title TEXT NOT NULL, started_at TEXT NOT NULL, ended_at TEXT);
+			CREATE TABLE alarms (id INTEGER PRIMARY KEY AUTOINCREMENT, station_snapshot TEXT NOT NULL, hour INTEGER NOT NULL, minute INTEGER NOT NULL, days INTEGER NOT NULL DEFAULT 0, volume INTEGER NOT NULL, ramp_up_seconds INTEGER NOT NULL DEFAULT 0, enabled INTEGER NOT NULL DEFAULT 1, created_at TEXT DEFAULT CURRENT_TIMESTAMP);
+			INSERT INTO schema_version (version) VALUES (8);
+		`)
+		assert.NoError(t, err)
+		db.Close()
+
+		s, err := NewSQLiteStorage()
+		assert.NoError(t, err)
+		defer s.Close()
+
+		var version int
+		assert.NoError(t, s.db.QueryRow("SELECT version FROM schema_version").Scan(&version))
+		assert.Equal(t, currentSchemaVersion, version)
+
+		recording, err := s.SaveScheduledRecording(schedule.Recording{Station: station, Start: saturday, Duration: time.Hour, Enabled: true})
+		assert.NoError(t, err)
+		recordings, err := s.GetScheduledRecordings()
+		assert.NoError(t, err)
+		assert.Equal(t, []schedule.Recording{recording}, recordings)
+	})
+}
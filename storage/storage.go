@@ -23,9 +23,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zi0p4tch0/radiogogo/common"
+	"github.com/zi0p4tch0/radiogogo/health"
+	"github.com/zi0p4tch0/radiogogo/schedule"
 )
 
-// StationStorageService defines operations for persistent station lists (bookmarks and hidden).
+// StationStorageService defines operations for persistent station lists (bookmarks, custom and hidden).
 type StationStorageService interface {
 	// GetBookmarks returns all bookmarked station UUIDs.
 	GetBookmarks() ([]uuid.UUID, error)
@@ -35,6 +38,24 @@ type StationStorageService interface {
 	RemoveBookmark(stationUUID uuid.UUID) error
 	// IsBookmarked returns true if the station is bookmarked.
 	IsBookmarked(stationUUID uuid.UUID) bool
+	// GetBookmarkedStations returns the stored snapshot of every bookmarked station,
+	// so bookmarks can be listed and played without network access.
+	// Bookmarks whose snapshot was never saved are not included.
+	GetBookmarkedStations() ([]common.Station, error)
+	// SaveBookmarkSnapshots stores the given stations as the snapshots of their bookmarks,
+	// replacing older ones. Stations that aren't bookmarked are ignored.
+	SaveBookmarkSnapshots(stations []common.Station) error
+
+	// GetCustomStations returns the stations added by the user (not listed on RadioBrowser), sorted by name.
+	// Their source is common.StationSourceCustom.
+	GetCustomStations() ([]common.Station, error)
+	// SaveCustomStation adds a custom station, or updates the one with the same UUID.
+	SaveCustomStation(station common.Station) error
+	// DeleteCustomStation removes a custom station, along with its bookmark.
+	DeleteCustomStation(stationUUID uuid.UUID) error
+	// IsCustomStation returns true if the station was added by the user.
+	// Custom stations are unknown to RadioBrowser, so they can't be clicked or voted for.
+	IsCustomStation(stationUUID uuid.UUID) bool
 
 	// GetHidden returns all hidden station UUIDs.
 	GetHidden() ([]uuid.UUID, error)
@@ -45,6 +66,43 @@ type StationStorageService interface {
 	// IsHidden returns true if the station is hidden.
 	IsHidden(stationUUID uuid.UUID) bool
 
+	// GetHealthResult returns the latest local health check of a station's stream.
+	// Returns false if the station was never checked.
+	GetHealthResult(stationUUID uuid.UUID) (health.Result, bool)
+	// SaveHealthResults stores the given health checks, replacing older ones of the same stations.
+	SaveHealthResults(results []health.Result) error
+
+	// StartSong logs a song heard on a station. The song logged before it ends
+	// when this one starts, if it hasn't ended yet.
+	StartSong(entry common.SongHistoryEntry) error
+	// EndSong ends the song logged last, if it hasn't ended yet.
+	EndSong(endedAt time.Time) error
+	// GetSongHistory returns the songs heard, newest first. With a search text, only
+	// the songs whose title or station name contain it (ignoring case) are returned.
+	GetSongHistory(search string) ([]common.SongHistoryEntry, error)
+
+	// GetAlarms returns the alarms, sorted by the time of day they ring at.
+	GetAlarms() ([]schedule.Alarm, error)
+	// SaveAlarm adds an alarm (when its ID is 0), or updates the one with the same ID.
+	// Returns the alarm as saved, with its ID.
+	SaveAlarm(alarm schedule.Alarm) (schedule.Alarm, error)
+	// DeleteAlarm removes an alarm.
+	DeleteAlarm(id int64) error
+
+	// GetScheduledRecordings returns the scheduled recordings, sorted by their first start.
+	GetScheduledRecordings() ([]schedule.Recording, error)
+	// SaveScheduledRecording adds a scheduled recording (when its ID is 0), or updates the one
+	// with the same ID. Returns the recording as saved, with its ID.
+	SaveScheduledRecording(recording schedule.Recording) (schedule.Recording, error)
+	// DeleteScheduledRecording removes a scheduled recording, and the record of its runs.
+	// The files it recorded are kept.
+	DeleteScheduledRecording(id int64) error
+	// GetRecordingRuns returns the runs of a scheduled recording, newest first.
+	GetRecordingRuns(recordingID int64) ([]schedule.RecordingRun, error)
+	// SaveRecordingRun adds a run (when its ID is 0), or updates the one with the same ID.
+	// Returns the run as saved, with its ID.
+	SaveRecordingRun(run schedule.RecordingRun) (schedule.RecordingRun, error)
+
 	// GetLastVoteTimestamp returns the last global vote timestamp.
 	// Returns the timestamp and true if found, zero time and false if not.
 	// RadioBrowser API enforces a 10-minute cooldown per IP for all votes.